@@ -9,7 +9,6 @@ import (
 
 	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg"
-	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/reconciler"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/config"
 	v2 "github.com/cloudogu/k8s-dogu-lib/v2/api/v2"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -86,7 +85,7 @@ func startOperator(
 		return fmt.Errorf("unable to bootstrap application context: %w", err)
 	}
 
-	err = configureManager(k8sManager, bootstrap.BlueprintReconciler)
+	err = configureManager(k8sManager, bootstrap)
 	if err != nil {
 		return fmt.Errorf("unable to configure manager: %w", err)
 	}
@@ -107,12 +106,19 @@ func NewK8sManager(
 	return ctrl.NewManager(restConfig, options)
 }
 
-func configureManager(k8sManager controllerManager, blueprintReconciler *reconciler.BlueprintReconciler) error {
-	err := blueprintReconciler.SetupWithManager(k8sManager)
+func configureManager(k8sManager controllerManager, applicationContext *pkg.ApplicationContext) error {
+	err := applicationContext.BlueprintReconciler.SetupWithManager(k8sManager)
 	if err != nil {
 		return fmt.Errorf("unable to configure blueprint reconciler: %w", err)
 	}
 
+	if applicationContext.RegistryAccessReconciler != nil {
+		err = applicationContext.RegistryAccessReconciler.SetupWithManager(k8sManager)
+		if err != nil {
+			return fmt.Errorf("unable to configure registry access reconciler: %w", err)
+		}
+	}
+
 	err = addChecks(k8sManager)
 	if err != nil {
 		return fmt.Errorf("unable to add checks to the manager: %w", err)
@@ -196,6 +196,7 @@ func Test_startOperator(t *testing.T) {
 		ctrlManMock.EXPECT().GetControllerOptions().Return(config.Controller{})
 		ctrlManMock.EXPECT().GetScheme().Return(runtime.NewScheme())
 		ctrlManMock.EXPECT().GetCache().Return(nil)
+		ctrlManMock.EXPECT().Add(mock.Anything).Return(nil)
 
 		ctrl.NewManager = func(config *rest.Config, options manager.Options) (manager.Manager, error) {
 			return ctrlManMock, nil
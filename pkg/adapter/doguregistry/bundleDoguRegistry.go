@@ -0,0 +1,88 @@
+package doguregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// BundleDoguRegistry provides dogu descriptors from a local dogu bundle instead of a remote dogu registry.
+// It is used on air-gapped sites without access to a dogu registry.
+type BundleDoguRegistry struct {
+	dogus map[cescommons.QualifiedName]map[string]*core.Dogu
+}
+
+// NewBundleDoguRegistry loads all dogu descriptors from the bundle at bundlePath, which is either a directory or
+// a gzipped tarball. The bundle must contain an index.json listing all descriptors and an index.json.sig with the
+// ed25519 signature of the index, which gets verified with the PEM encoded public key at publicKeyPath.
+func NewBundleDoguRegistry(bundlePath string, publicKeyPath string) (*BundleDoguRegistry, error) {
+	pemBytes, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read public key of dogu bundle: %w", err)
+	}
+	publicKey, err := parseBundlePublicKey(pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of dogu bundle: %w", err)
+	}
+
+	readFile, err := openBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	dogus, err := loadBundle(readFile, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load dogu bundle %q: %w", bundlePath, err)
+	}
+	return &BundleDoguRegistry{dogus: dogus}, nil
+}
+
+func (r *BundleDoguRegistry) GetDogu(ctx context.Context, qualifiedDoguVersion cescommons.QualifiedVersion) (*core.Dogu, error) {
+	dogu, found := r.dogus[qualifiedDoguVersion.Name][qualifiedDoguVersion.Version.Raw]
+	if !found {
+		return nil, domainservice.NewNotFoundError(
+			nil,
+			"dogu %q with version %q could not be found in the dogu bundle",
+			qualifiedDoguVersion.Name, qualifiedDoguVersion.Version.Raw,
+		)
+	}
+	log.FromContext(ctx).WithName("BundleDoguRegistry.GetDogu").
+		V(2).Info("loaded dogu descriptor from bundle", "dogu", qualifiedDoguVersion.Name.SimpleName)
+	return dogu, nil
+}
+
+func (r *BundleDoguRegistry) GetDogus(ctx context.Context, dogusToLoad []cescommons.QualifiedVersion) (map[cescommons.QualifiedName]*core.Dogu, error) {
+	dogus := make(map[cescommons.QualifiedName]*core.Dogu)
+
+	var errs []error
+	for _, doguRef := range dogusToLoad {
+		dogu, err := r.GetDogu(ctx, doguRef)
+		errs = append(errs, err)
+
+		dogus[doguRef.Name] = dogu
+	}
+
+	return dogus, errors.Join(errs...)
+}
+
+func (r *BundleDoguRegistry) GetVersionsOf(_ context.Context, doguName cescommons.QualifiedName) ([]core.Version, error) {
+	var versions []core.Version
+	for _, dogu := range r.dogus[doguName] {
+		version, err := dogu.GetVersion()
+		if err != nil {
+			return nil, domainservice.NewInternalError(err, "failed to get versions of dogu %q", doguName)
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, domainservice.NewNotFoundError(nil, "no versions of dogu %q could be found in the dogu bundle", doguName)
+	}
+	sort.Sort(core.ByVersion(versions))
+	return versions, nil
+}
@@ -0,0 +1,266 @@
+package doguregistry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	bundleLdapName = cescommons.QualifiedName{Namespace: "official", SimpleName: "ldap"}
+	bundleLdap1    = `{"Name":"official/ldap","Version":"2.6.7-1"}`
+	bundleLdap2    = `{"Name":"official/ldap","Version":"2.6.8-1"}`
+)
+
+type testBundle struct {
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+	files      map[string][]byte
+}
+
+func newTestBundle(t *testing.T, descriptors map[string]string) *testBundle {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	bundle := &testBundle{publicKey: publicKey, privateKey: privateKey, files: map[string][]byte{}}
+	var index bundleIndex
+	for descriptorPath, descriptor := range descriptors {
+		dogu, _, err := core.ReadDoguFromString(descriptor)
+		require.NoError(t, err)
+		checksum := sha256.Sum256([]byte(descriptor))
+		index.Dogus = append(index.Dogus, bundleIndexEntry{
+			Name:    dogu.Name,
+			Version: dogu.Version,
+			Path:    descriptorPath,
+			SHA256:  hex.EncodeToString(checksum[:]),
+		})
+		bundle.files[descriptorPath] = []byte(descriptor)
+	}
+	rawIndex, err := json.Marshal(index)
+	require.NoError(t, err)
+	bundle.files[bundleIndexFile] = rawIndex
+	bundle.files[bundleIndexSignatureFile] = ed25519.Sign(privateKey, rawIndex)
+	return bundle
+}
+
+func (b *testBundle) writeDirectory(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range b.files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+		require.NoError(t, os.WriteFile(filePath, content, 0o644))
+	}
+	return dir
+}
+
+func (b *testBundle) writeTarball(t *testing.T) string {
+	t.Helper()
+	tarballPath := filepath.Join(t.TempDir(), "dogus.tar.gz")
+	file, err := os.Create(tarballPath)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range b.files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "./" + name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err = tarWriter.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, file.Close())
+	return tarballPath
+}
+
+func (b *testBundle) writePublicKey(t *testing.T) string {
+	t.Helper()
+	derBytes, err := x509.MarshalPKIXPublicKey(b.publicKey)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "public-key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: derBytes}), 0o644))
+	return keyPath
+}
+
+func Test_openBundleTarball(t *testing.T) {
+	bundle := newTestBundle(t, map[string]string{"official/ldap/2.6.7-1.json": bundleLdap1})
+	tarballPath := bundle.writeTarball(t)
+	totalSize := int64(0)
+	for _, content := range bundle.files {
+		totalSize += int64(len(content))
+	}
+
+	t.Run("should read files within the limits", func(t *testing.T) {
+		readFile, err := openBundleTarball(tarballPath, len(bundle.files), totalSize)
+
+		require.NoError(t, err)
+		content, err := readFile("official/ldap/2.6.7-1.json")
+		require.NoError(t, err)
+		assert.Equal(t, bundleLdap1, string(content))
+	})
+	t.Run("should fail on too many entries", func(t *testing.T) {
+		_, err := openBundleTarball(tarballPath, len(bundle.files)-1, totalSize)
+
+		assert.ErrorContains(t, err, fmt.Sprintf("exceeds the maximum of %d entries", len(bundle.files)-1))
+	})
+	t.Run("should fail on too large files", func(t *testing.T) {
+		_, err := openBundleTarball(tarballPath, len(bundle.files), totalSize-1)
+
+		assert.ErrorContains(t, err, fmt.Sprintf("exceed the maximum total size of %d bytes", totalSize-1))
+	})
+}
+
+func TestNewBundleDoguRegistry(t *testing.T) {
+	descriptors := map[string]string{
+		"official/ldap/2.6.7-1.json": bundleLdap1,
+		"official/ldap/2.6.8-1.json": bundleLdap2,
+	}
+
+	t.Run("should load bundle from directory", func(t *testing.T) {
+		// given
+		bundle := newTestBundle(t, descriptors)
+
+		// when
+		registry, err := NewBundleDoguRegistry(bundle.writeDirectory(t), bundle.writePublicKey(t))
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, registry.dogus[bundleLdapName], 2)
+	})
+	t.Run("should load bundle from tarball", func(t *testing.T) {
+		// given
+		bundle := newTestBundle(t, descriptors)
+
+		// when
+		registry, err := NewBundleDoguRegistry(bundle.writeTarball(t), bundle.writePublicKey(t))
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, registry.dogus[bundleLdapName], 2)
+	})
+	t.Run("should fail on invalid signature", func(t *testing.T) {
+		// given
+		bundle := newTestBundle(t, descriptors)
+		otherBundle := newTestBundle(t, descriptors)
+
+		// when
+		_, err := NewBundleDoguRegistry(bundle.writeDirectory(t), otherBundle.writePublicKey(t))
+
+		// then
+		assert.ErrorContains(t, err, "signature of bundle index is invalid")
+	})
+	t.Run("should fail on modified descriptor", func(t *testing.T) {
+		// given
+		bundle := newTestBundle(t, descriptors)
+		bundle.files["official/ldap/2.6.7-1.json"] = []byte(`{"Name":"official/ldap","Version":"2.6.7-1","Image":"evil/ldap"}`)
+
+		// when
+		_, err := NewBundleDoguRegistry(bundle.writeDirectory(t), bundle.writePublicKey(t))
+
+		// then
+		assert.ErrorContains(t, err, "cannot load dogu \"official/ldap\" with version \"2.6.7-1\" from bundle")
+		assert.ErrorContains(t, err, "checksum of \"official/ldap/2.6.7-1.json\" does not match the bundle index")
+	})
+	t.Run("should fail on missing descriptor", func(t *testing.T) {
+		// given
+		bundle := newTestBundle(t, descriptors)
+		delete(bundle.files, "official/ldap/2.6.8-1.json")
+
+		// when
+		_, err := NewBundleDoguRegistry(bundle.writeTarball(t), bundle.writePublicKey(t))
+
+		// then
+		assert.ErrorContains(t, err, "cannot load dogu \"official/ldap\" with version \"2.6.8-1\" from bundle")
+	})
+	t.Run("should fail on missing bundle", func(t *testing.T) {
+		// given
+		bundle := newTestBundle(t, descriptors)
+
+		// when
+		_, err := NewBundleDoguRegistry(filepath.Join(t.TempDir(), "missing"), bundle.writePublicKey(t))
+
+		// then
+		assert.ErrorContains(t, err, "cannot open dogu bundle")
+	})
+	t.Run("should fail on invalid public key", func(t *testing.T) {
+		// given
+		bundle := newTestBundle(t, descriptors)
+		keyPath := filepath.Join(t.TempDir(), "public-key.pem")
+		require.NoError(t, os.WriteFile(keyPath, []byte("no key"), 0o644))
+
+		// when
+		_, err := NewBundleDoguRegistry(bundle.writeDirectory(t), keyPath)
+
+		// then
+		assert.ErrorContains(t, err, "invalid public key of dogu bundle: public key is not PEM encoded")
+	})
+}
+
+func TestBundleDoguRegistry_GetDogu(t *testing.T) {
+	ldap, _, err := core.ReadDoguFromString(bundleLdap1)
+	require.NoError(t, err)
+	sut := &BundleDoguRegistry{dogus: map[cescommons.QualifiedName]map[string]*core.Dogu{
+		bundleLdapName: {"2.6.7-1": ldap},
+	}}
+
+	t.Run("should return dogu", func(t *testing.T) {
+		actual, err := sut.GetDogu(context.TODO(), cescommons.QualifiedVersion{Name: bundleLdapName, Version: core.Version{Raw: "2.6.7-1"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, ldap, actual)
+	})
+	t.Run("should return not found error for unknown version", func(t *testing.T) {
+		_, err := sut.GetDogu(context.TODO(), cescommons.QualifiedVersion{Name: bundleLdapName, Version: core.Version{Raw: "2.6.8-1"}})
+
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "dogu \"official/ldap\" with version \"2.6.8-1\" could not be found in the dogu bundle")
+	})
+	t.Run("should return all dogus", func(t *testing.T) {
+		actual, err := sut.GetDogus(context.TODO(), []cescommons.QualifiedVersion{{Name: bundleLdapName, Version: core.Version{Raw: "2.6.7-1"}}})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[cescommons.QualifiedName]*core.Dogu{bundleLdapName: ldap}, actual)
+	})
+}
+
+func TestBundleDoguRegistry_GetVersionsOf(t *testing.T) {
+	ldap1, _, err := core.ReadDoguFromString(bundleLdap1)
+	require.NoError(t, err)
+	ldap2, _, err := core.ReadDoguFromString(bundleLdap2)
+	require.NoError(t, err)
+	sut := &BundleDoguRegistry{dogus: map[cescommons.QualifiedName]map[string]*core.Dogu{
+		bundleLdapName: {"2.6.7-1": ldap1, "2.6.8-1": ldap2},
+	}}
+
+	t.Run("should return versions", func(t *testing.T) {
+		versions, err := sut.GetVersionsOf(context.TODO(), bundleLdapName)
+
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		assert.Equal(t, "2.6.8-1", versions[0].Raw)
+		assert.Equal(t, "2.6.7-1", versions[1].Raw)
+	})
+	t.Run("should return not found error for unknown dogu", func(t *testing.T) {
+		_, err := sut.GetVersionsOf(context.TODO(), cescommons.QualifiedName{Namespace: "official", SimpleName: "redmine"})
+
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "no versions of dogu \"official/redmine\" could be found in the dogu bundle")
+	})
+}
@@ -0,0 +1,185 @@
+package doguregistry
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// maxConcurrentDescriptorRequests limits the parallel requests to the wrapped dogu registry in GetDogus.
+	maxConcurrentDescriptorRequests = 8
+	// maxCachedDescriptors limits the memory of the cache. It is far above the dogus of a single ecosystem,
+	// so that only descriptors of outdated versions get evicted.
+	maxCachedDescriptors = 500
+)
+
+type descriptorCacheEntry struct {
+	key  cescommons.QualifiedVersion
+	dogu *core.Dogu
+}
+
+// CachingDoguRegistry keeps the dogu descriptors of the wrapped registry in memory, so that the validations of all
+// reconciliations share them instead of requesting them again. A descriptor never changes for a released version,
+// so the cache needs no expiry. Instead, the least recently used descriptor gets evicted if the cache is full.
+// The available versions are not cached, as new versions can be released anytime.
+type CachingDoguRegistry struct {
+	registry   domainservice.RemoteDoguRegistry
+	maxEntries int
+	// mutex guards entries and recentlyUsed, which also changes on reads.
+	mutex sync.Mutex
+	// elements contains the elements of recentlyUsed by their key.
+	elements map[cescommons.QualifiedVersion]*list.Element
+	// recentlyUsed contains the descriptorCacheEntry values, starting with the most recently used one.
+	recentlyUsed *list.List
+
+	hits    prometheus.Counter
+	misses  prometheus.Counter
+	entries prometheus.Gauge
+}
+
+// NewCachingDoguRegistry creates a CachingDoguRegistry which loads missing descriptors from the given registry.
+// The metrics of the cache are registered at the given registerer. Already registered metrics are reused.
+func NewCachingDoguRegistry(registry domainservice.RemoteDoguRegistry, registerer prometheus.Registerer) (*CachingDoguRegistry, error) {
+	hits, err := registerCollector(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "blueprint_operator_dogu_descriptor_cache_hits_total",
+		Help: "Number of dogu descriptors which were loaded from the in-memory cache.",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := registerCollector(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "blueprint_operator_dogu_descriptor_cache_misses_total",
+		Help: "Number of dogu descriptors which were not in the in-memory cache and were loaded from the dogu registry.",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := registerCollector(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "blueprint_operator_dogu_descriptor_cache_entries",
+		Help: "Number of dogu descriptors in the in-memory cache.",
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachingDoguRegistry{
+		registry:     registry,
+		maxEntries:   maxCachedDescriptors,
+		elements:     map[cescommons.QualifiedVersion]*list.Element{},
+		recentlyUsed: list.New(),
+		hits:         hits,
+		misses:       misses,
+		entries:      entries,
+	}, nil
+}
+
+func registerCollector[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	err := registerer.Register(collector)
+	if err != nil {
+		var alreadyRegisteredErr prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegisteredErr) {
+			if existingCollector, ok := alreadyRegisteredErr.ExistingCollector.(T); ok {
+				return existingCollector, nil
+			}
+		}
+		var noCollector T
+		return noCollector, fmt.Errorf("cannot register metrics of the dogu descriptor cache: %w", err)
+	}
+	return collector, nil
+}
+
+// GetDogu returns the cached descriptor or loads it from the wrapped registry. Errors are not cached.
+func (r *CachingDoguRegistry) GetDogu(ctx context.Context, qualifiedDoguVersion cescommons.QualifiedVersion) (*core.Dogu, error) {
+	key := cacheKey(qualifiedDoguVersion)
+	dogu, found := r.get(key)
+	if found {
+		r.hits.Inc()
+		return dogu, nil
+	}
+
+	r.misses.Inc()
+	dogu, err := r.registry.GetDogu(ctx, qualifiedDoguVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	r.add(key, dogu)
+	return dogu, nil
+}
+
+func (r *CachingDoguRegistry) get(key cescommons.QualifiedVersion) (*core.Dogu, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	element, found := r.elements[key]
+	if !found {
+		return nil, false
+	}
+	r.recentlyUsed.MoveToFront(element)
+	return element.Value.(descriptorCacheEntry).dogu, true
+}
+
+func (r *CachingDoguRegistry) add(key cescommons.QualifiedVersion, dogu *core.Dogu) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if element, found := r.elements[key]; found {
+		// loaded concurrently by another reconciliation
+		r.recentlyUsed.MoveToFront(element)
+		return
+	}
+
+	r.elements[key] = r.recentlyUsed.PushFront(descriptorCacheEntry{key: key, dogu: dogu})
+	for r.recentlyUsed.Len() > r.maxEntries {
+		oldest := r.recentlyUsed.Back()
+		r.recentlyUsed.Remove(oldest)
+		delete(r.elements, oldest.Value.(descriptorCacheEntry).key)
+	}
+	r.entries.Set(float64(r.recentlyUsed.Len()))
+}
+
+// GetDogus loads the requested descriptors in parallel. Like the DoguDescriptorRepository, it returns all descriptors
+// which could be loaded together with the joined errors of the others.
+func (r *CachingDoguRegistry) GetDogus(ctx context.Context, dogusToLoad []cescommons.QualifiedVersion) (map[cescommons.QualifiedName]*core.Dogu, error) {
+	// collect the results by index to keep the order of the errors stable
+	loadedDogus := make([]*core.Dogu, len(dogusToLoad))
+	errs := make([]error, len(dogusToLoad))
+
+	var waitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentDescriptorRequests)
+	for i, doguRef := range dogusToLoad {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			loadedDogus[i], errs[i] = r.GetDogu(ctx, doguRef)
+		}()
+	}
+	waitGroup.Wait()
+
+	dogus := make(map[cescommons.QualifiedName]*core.Dogu, len(dogusToLoad))
+	for i, doguRef := range dogusToLoad {
+		dogus[doguRef.Name] = loadedDogus[i]
+	}
+	return dogus, errors.Join(errs...)
+}
+
+// GetVersionsOf always requests the wrapped registry, as new versions can be released anytime.
+func (r *CachingDoguRegistry) GetVersionsOf(ctx context.Context, doguName cescommons.QualifiedName) ([]core.Version, error) {
+	return r.registry.GetVersionsOf(ctx, doguName)
+}
+
+// cacheKey uses only the raw version, as the parsed fields of a version are not always set.
+func cacheKey(qualifiedDoguVersion cescommons.QualifiedVersion) cescommons.QualifiedVersion {
+	return cescommons.QualifiedVersion{
+		Name:    qualifiedDoguVersion.Name,
+		Version: core.Version{Raw: qualifiedDoguVersion.Version.Raw},
+	}
+}
@@ -0,0 +1,184 @@
+package doguregistry
+
+import (
+	"context"
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	cachedLdapVersion       = cescommons.QualifiedVersion{Name: failoverLdapName, Version: core.Version{Raw: "2.6.7-1"}}
+	cachedPostfixName       = cescommons.QualifiedName{Namespace: "official", SimpleName: "postfix"}
+	cachedPostfixVersion    = cescommons.QualifiedVersion{Name: cachedPostfixName, Version: core.Version{Raw: "3.9.0-1"}}
+	cachedPostfix           = &core.Dogu{Name: "official/postfix", Version: "3.9.0-1"}
+	cachedParsedLdapVersion = func() cescommons.QualifiedVersion {
+		version, _ := core.ParseVersion("2.6.7-1")
+		return cescommons.QualifiedVersion{Name: failoverLdapName, Version: version}
+	}()
+)
+
+func newTestCachingDoguRegistry(t *testing.T, registry domainservice.RemoteDoguRegistry) *CachingDoguRegistry {
+	sut, err := NewCachingDoguRegistry(registry, prometheus.NewRegistry())
+	require.NoError(t, err)
+	return sut
+}
+
+func TestNewCachingDoguRegistry(t *testing.T) {
+	t.Run("should register metrics", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+
+		_, err := NewCachingDoguRegistry(newMockRemoteDoguRegistry(t), registry)
+
+		require.NoError(t, err)
+		count, err := testutil.GatherAndCount(registry)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+	t.Run("should reuse already registered metrics", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		first, err := NewCachingDoguRegistry(newMockRemoteDoguRegistry(t), registry)
+		require.NoError(t, err)
+
+		second, err := NewCachingDoguRegistry(newMockRemoteDoguRegistry(t), registry)
+
+		require.NoError(t, err)
+		assert.Same(t, first.hits, second.hits)
+	})
+	t.Run("should fail to register conflicting metrics", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "blueprint_operator_dogu_descriptor_cache_hits_total",
+			Help: "other help",
+		}))
+
+		_, err := NewCachingDoguRegistry(newMockRemoteDoguRegistry(t), registry)
+
+		assert.ErrorContains(t, err, "cannot register metrics of the dogu descriptor cache")
+	})
+}
+
+func TestCachingDoguRegistry_GetDogu(t *testing.T) {
+	t.Run("should load dogu only once", func(t *testing.T) {
+		// given
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogu(context.TODO(), cachedLdapVersion).Return(failoverLdap, nil).Once()
+		sut := newTestCachingDoguRegistry(t, registryMock)
+
+		// when
+		_, err := sut.GetDogu(context.TODO(), cachedLdapVersion)
+		require.NoError(t, err)
+		actual, err := sut.GetDogu(context.TODO(), cachedParsedLdapVersion)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+		assert.Equal(t, float64(1), testutil.ToFloat64(sut.hits))
+		assert.Equal(t, float64(1), testutil.ToFloat64(sut.misses))
+		assert.Equal(t, float64(1), testutil.ToFloat64(sut.entries))
+	})
+	t.Run("should evict least recently used dogu if cache is full", func(t *testing.T) {
+		// given
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogu(context.TODO(), cachedLdapVersion).Return(failoverLdap, nil).Once()
+		registryMock.EXPECT().GetDogu(context.TODO(), cachedPostfixVersion).Return(cachedPostfix, nil).Twice()
+		sut := newTestCachingDoguRegistry(t, registryMock)
+		sut.maxEntries = 1
+		_, err := sut.GetDogu(context.TODO(), cachedPostfixVersion)
+		require.NoError(t, err)
+
+		// when
+		_, err = sut.GetDogu(context.TODO(), cachedLdapVersion)
+		require.NoError(t, err)
+		_, err = sut.GetDogu(context.TODO(), cachedLdapVersion)
+		require.NoError(t, err)
+		actual, err := sut.GetDogu(context.TODO(), cachedPostfixVersion)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, cachedPostfix, actual)
+		assert.Equal(t, float64(1), testutil.ToFloat64(sut.entries))
+		assert.Len(t, sut.elements, 1)
+	})
+	t.Run("should not cache errors", func(t *testing.T) {
+		// given
+		notFoundErr := domainservice.NewNotFoundError(nil, "not found")
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogu(context.TODO(), cachedLdapVersion).Return(nil, notFoundErr).Once()
+		registryMock.EXPECT().GetDogu(context.TODO(), cachedLdapVersion).Return(failoverLdap, nil).Once()
+		sut := newTestCachingDoguRegistry(t, registryMock)
+
+		// when
+		_, err := sut.GetDogu(context.TODO(), cachedLdapVersion)
+		require.ErrorIs(t, err, notFoundErr)
+		actual, err := sut.GetDogu(context.TODO(), cachedLdapVersion)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+	})
+}
+
+func TestCachingDoguRegistry_GetDogus(t *testing.T) {
+	t.Run("should load all dogus", func(t *testing.T) {
+		// given
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogu(mock.Anything, cachedLdapVersion).Return(failoverLdap, nil)
+		registryMock.EXPECT().GetDogu(mock.Anything, cachedPostfixVersion).Return(cachedPostfix, nil)
+		sut := newTestCachingDoguRegistry(t, registryMock)
+
+		// when
+		actual, err := sut.GetDogus(context.TODO(), []cescommons.QualifiedVersion{cachedLdapVersion, cachedPostfixVersion})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[cescommons.QualifiedName]*core.Dogu{
+			failoverLdapName:  failoverLdap,
+			cachedPostfixName: cachedPostfix,
+		}, actual)
+		assert.Len(t, sut.elements, 2)
+	})
+	t.Run("should return loaded dogus and joined errors", func(t *testing.T) {
+		// given
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogu(mock.Anything, cachedLdapVersion).Return(nil, assert.AnError)
+		registryMock.EXPECT().GetDogu(mock.Anything, cachedPostfixVersion).Return(cachedPostfix, nil)
+		sut := newTestCachingDoguRegistry(t, registryMock)
+
+		// when
+		actual, err := sut.GetDogus(context.TODO(), []cescommons.QualifiedVersion{cachedLdapVersion, cachedPostfixVersion})
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, map[cescommons.QualifiedName]*core.Dogu{
+			failoverLdapName:  nil,
+			cachedPostfixName: cachedPostfix,
+		}, actual)
+	})
+}
+
+func TestCachingDoguRegistry_GetVersionsOf(t *testing.T) {
+	t.Run("should always request wrapped registry", func(t *testing.T) {
+		// given
+		versions := []core.Version{{Raw: "2.6.7-1"}}
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(context.TODO(), failoverLdapName).Return(versions, nil).Twice()
+		sut := newTestCachingDoguRegistry(t, registryMock)
+
+		// when
+		_, err := sut.GetVersionsOf(context.TODO(), failoverLdapName)
+		require.NoError(t, err)
+		actual, err := sut.GetVersionsOf(context.TODO(), failoverLdapName)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, versions, actual)
+	})
+}
@@ -0,0 +1,68 @@
+package doguregistry
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops requests to an endpoint after consecutive failures.
+// The circuit stays open for the open duration. Afterwards, a single trial request is allowed to check
+// if the endpoint is reachable again. A successful request closes the circuit, a failed one opens it again.
+type circuitBreaker struct {
+	mutex               sync.Mutex
+	failureThreshold    int
+	openDuration        time.Duration
+	now                 func() time.Time
+	consecutiveFailures int
+	open                bool
+	openedAt            time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+		now:              time.Now,
+	}
+}
+
+// allow returns true if a request may be sent to the endpoint.
+func (b *circuitBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if b.now().Sub(b.openedAt) < b.openDuration {
+		return false
+	}
+	// let only this trial request through, concurrent requests fail fast until it succeeds
+	b.openedAt = b.now()
+	return true
+}
+
+// recordSuccess closes the circuit.
+func (b *circuitBreaker) recordSuccess() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.consecutiveFailures = 0
+	b.open = false
+}
+
+// recordFailure counts the failure and opens the circuit if the failure threshold is reached.
+// It returns true if the circuit was closed before.
+func (b *circuitBreaker) recordFailure() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.consecutiveFailures++
+	if b.consecutiveFailures < b.failureThreshold {
+		return false
+	}
+	wasClosed := !b.open
+	b.open = true
+	b.openedAt = b.now()
+	return wasClosed
+}
@@ -0,0 +1,87 @@
+package doguregistry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newTestBreaker := func(now *time.Time) *circuitBreaker {
+		breaker := newCircuitBreaker(2, time.Minute)
+		breaker.now = func() time.Time { return *now }
+		return breaker
+	}
+
+	t.Run("should stay closed below the failure threshold", func(t *testing.T) {
+		now := start
+		breaker := newTestBreaker(&now)
+
+		opened := breaker.recordFailure()
+
+		assert.False(t, opened)
+		assert.True(t, breaker.allow())
+	})
+	t.Run("should open at the failure threshold", func(t *testing.T) {
+		now := start
+		breaker := newTestBreaker(&now)
+
+		breaker.recordFailure()
+		opened := breaker.recordFailure()
+
+		assert.True(t, opened)
+		assert.False(t, breaker.allow())
+	})
+	t.Run("should reset failures on success", func(t *testing.T) {
+		now := start
+		breaker := newTestBreaker(&now)
+
+		breaker.recordFailure()
+		breaker.recordSuccess()
+		opened := breaker.recordFailure()
+
+		assert.False(t, opened)
+		assert.True(t, breaker.allow())
+	})
+	t.Run("should allow a single trial request after the open duration", func(t *testing.T) {
+		now := start
+		breaker := newTestBreaker(&now)
+		breaker.recordFailure()
+		breaker.recordFailure()
+
+		now = start.Add(time.Minute)
+
+		assert.True(t, breaker.allow())
+		assert.False(t, breaker.allow())
+	})
+	t.Run("should close after successful trial request", func(t *testing.T) {
+		now := start
+		breaker := newTestBreaker(&now)
+		breaker.recordFailure()
+		breaker.recordFailure()
+		now = start.Add(time.Minute)
+		breaker.allow()
+
+		breaker.recordSuccess()
+
+		assert.True(t, breaker.allow())
+		assert.True(t, breaker.allow())
+	})
+	t.Run("should open again after failed trial request", func(t *testing.T) {
+		now := start
+		breaker := newTestBreaker(&now)
+		breaker.recordFailure()
+		breaker.recordFailure()
+		now = start.Add(time.Minute)
+		breaker.allow()
+
+		opened := breaker.recordFailure()
+
+		assert.False(t, opened, "circuit was not closed before")
+		assert.False(t, breaker.allow())
+		now = start.Add(2 * time.Minute)
+		assert.True(t, breaker.allow())
+	})
+}
@@ -0,0 +1,212 @@
+package doguregistry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+const (
+	bundleIndexFile          = "index.json"
+	bundleIndexSignatureFile = "index.json.sig"
+	// maxBundleFileSize limits the size of each file in a bundle tarball, as dogu descriptors are small.
+	maxBundleFileSize = 10 << 20
+	// maxBundleSize limits the size of all files in a bundle tarball, as they are kept in memory.
+	maxBundleSize = 256 << 20
+	// maxBundleEntries limits the entries of a bundle tarball, so that a tarball of countless tiny or empty entries
+	// is rejected as well.
+	maxBundleEntries = 10000
+)
+
+// bundleIndex lists all dogu descriptors of a dogu bundle.
+// The index is signed, and it contains the checksum of each descriptor, so that the whole bundle can be verified.
+type bundleIndex struct {
+	Dogus []bundleIndexEntry `json:"dogus"`
+}
+
+type bundleIndexEntry struct {
+	// Name is the qualified name of the dogu, e.g. "official/ldap".
+	Name string `json:"name"`
+	// Version is the version of the dogu, e.g. "2.6.7-1".
+	Version string `json:"version"`
+	// Path is the slash separated path of the dogu descriptor relative to the bundle root.
+	Path string `json:"path"`
+	// SHA256 is the hex encoded checksum of the dogu descriptor.
+	SHA256 string `json:"sha256"`
+}
+
+// readBundleFile returns the content of a file in the bundle by its slash separated path relative to the bundle root.
+type readBundleFile func(name string) ([]byte, error)
+
+// openBundle opens a bundle, which is either a directory or a gzipped tarball.
+func openBundle(bundlePath string) (readBundleFile, error) {
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open dogu bundle %q: %w", bundlePath, err)
+	}
+	if info.IsDir() {
+		bundleFS := os.DirFS(bundlePath)
+		return func(name string) ([]byte, error) {
+			return fs.ReadFile(bundleFS, name)
+		}, nil
+	}
+	return openBundleTarball(bundlePath, maxBundleEntries, maxBundleSize)
+}
+
+// openBundleTarball reads all files of the tarball into memory. The tarball is rejected if it contains more than
+// maxEntries entries or if its files are larger than maxSize bytes in total.
+func openBundleTarball(tarballPath string, maxEntries int, maxSize int64) (readBundleFile, error) {
+	file, err := os.Open(tarballPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open dogu bundle %q: %w", tarballPath, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress dogu bundle %q: %w", tarballPath, err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	files := map[string][]byte{}
+	entries := 0
+	var totalSize int64
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read dogu bundle %q: %w", tarballPath, err)
+		}
+		entries++
+		if entries > maxEntries {
+			return nil, fmt.Errorf("dogu bundle %q exceeds the maximum of %d entries", tarballPath, maxEntries)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxBundleFileSize {
+			return nil, fmt.Errorf("file %q in dogu bundle %q exceeds the maximum size of %d bytes", header.Name, tarballPath, maxBundleFileSize)
+		}
+		totalSize += header.Size
+		if totalSize > maxSize {
+			return nil, fmt.Errorf("files in dogu bundle %q exceed the maximum total size of %d bytes", tarballPath, maxSize)
+		}
+		content, err := io.ReadAll(io.LimitReader(tarReader, maxBundleFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read file %q in dogu bundle %q: %w", header.Name, tarballPath, err)
+		}
+		files[path.Clean(header.Name)] = content
+	}
+
+	return func(name string) ([]byte, error) {
+		content, found := files[path.Clean(name)]
+		if !found {
+			return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+		}
+		return content, nil
+	}, nil
+}
+
+// loadBundle verifies the signature of the bundle index and loads all dogu descriptors listed in it.
+// Every descriptor must match its checksum, name and version in the index.
+func loadBundle(readFile readBundleFile, publicKey ed25519.PublicKey) (map[cescommons.QualifiedName]map[string]*core.Dogu, error) {
+	rawIndex, err := readFile(bundleIndexFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read bundle index: %w", err)
+	}
+	signature, err := readFile(bundleIndexSignatureFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read signature of bundle index: %w", err)
+	}
+	if !ed25519.Verify(publicKey, rawIndex, signature) {
+		return nil, fmt.Errorf("signature of bundle index is invalid")
+	}
+
+	var index bundleIndex
+	err = json.Unmarshal(rawIndex, &index)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse bundle index: %w", err)
+	}
+
+	dogus := map[cescommons.QualifiedName]map[string]*core.Dogu{}
+	var errs []error
+	for _, entry := range index.Dogus {
+		dogu, err := loadBundleEntry(readFile, entry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot load dogu %q with version %q from bundle: %w", entry.Name, entry.Version, err))
+			continue
+		}
+		name, _ := cescommons.QualifiedNameFromString(dogu.Name)
+		if dogus[name] == nil {
+			dogus[name] = map[string]*core.Dogu{}
+		}
+		dogus[name][dogu.Version] = dogu
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return dogus, nil
+}
+
+func loadBundleEntry(readFile readBundleFile, entry bundleIndexEntry) (*core.Dogu, error) {
+	content, err := readFile(entry.Path)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(content)
+	if !strings.EqualFold(hex.EncodeToString(checksum[:]), entry.SHA256) {
+		return nil, fmt.Errorf("checksum of %q does not match the bundle index", entry.Path)
+	}
+
+	dogu, _, err := core.ReadDoguFromString(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q: %w", entry.Path, err)
+	}
+	if dogu.Name != entry.Name || dogu.Version != entry.Version {
+		return nil, fmt.Errorf("%q contains dogu %q with version %q instead", entry.Path, dogu.Name, dogu.Version)
+	}
+	_, err = cescommons.QualifiedNameFromString(dogu.Name)
+	if err != nil {
+		return nil, err
+	}
+	_, err = dogu.GetVersion()
+	if err != nil {
+		return nil, err
+	}
+	return dogu, nil
+}
+
+// parseBundlePublicKey parses a PEM encoded ed25519 public key in the PKIX format as generated by
+// "openssl pkey -pubout".
+func parseBundlePublicKey(pemBytes []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse public key: %w", err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is of type %T instead of ed25519", key)
+	}
+	return publicKey, nil
+}
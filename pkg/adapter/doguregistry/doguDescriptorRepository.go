@@ -15,10 +15,11 @@ import (
 type DoguDescriptorRepository struct {
 	remoteRepository remoteDoguDescriptorRepository
 	localRepository  localDoguDescriptorRepository
+	remoteRegistry   cesappLibRemoteRegistry
 }
 
-func NewDoguDescriptorRepository(remoteRepository remoteDoguDescriptorRepository, localRepository localDoguDescriptorRepository) *DoguDescriptorRepository {
-	return &DoguDescriptorRepository{remoteRepository: remoteRepository, localRepository: localRepository}
+func NewDoguDescriptorRepository(remoteRepository remoteDoguDescriptorRepository, localRepository localDoguDescriptorRepository, remoteRegistry cesappLibRemoteRegistry) *DoguDescriptorRepository {
+	return &DoguDescriptorRepository{remoteRepository: remoteRepository, localRepository: localRepository, remoteRegistry: remoteRegistry}
 }
 
 func (r *DoguDescriptorRepository) GetDogu(ctx context.Context, qualifiedDoguVersion cescommons.QualifiedVersion) (*core.Dogu, error) {
@@ -59,6 +60,12 @@ func (r *DoguDescriptorRepository) getRemoteDogu(ctx context.Context, qualifiedD
 				"dogu %q with version %q could not be found",
 				qualifiedDoguVersion.Name, qualifiedDoguVersion.Version.Raw,
 			)
+		} else if domainservice.IsRegistryAuthenticationError(err) {
+			return nil, domainservice.NewRegistryAuthenticationError(
+				err,
+				"failed to get dogu %q with version %q",
+				qualifiedDoguVersion.Name, qualifiedDoguVersion.Version.Raw,
+			)
 		} else {
 			return nil, domainservice.NewInternalError(
 				err,
@@ -94,3 +101,25 @@ func (r *DoguDescriptorRepository) GetDogus(ctx context.Context, dogusToLoad []c
 
 	return dogus, errors.Join(errs...)
 }
+
+func (r *DoguDescriptorRepository) GetVersionsOf(ctx context.Context, doguName cescommons.QualifiedName) ([]core.Version, error) {
+	logger := log.FromContext(ctx).
+		WithName("DoguDescriptorRepository.GetVersionsOf").
+		WithValues("dogu", doguName.SimpleName)
+
+	versions, err := r.remoteRegistry.GetVersionsOf(doguName.String())
+	if domainservice.IsRegistryAuthenticationError(err) {
+		return nil, domainservice.NewRegistryAuthenticationError(err, "failed to get versions of dogu %q", doguName)
+	}
+	if err != nil && isVersionsNotFoundError(err) {
+		return nil, domainservice.NewNotFoundError(err, "dogu %q could not be found", doguName)
+	}
+	if err != nil {
+		return nil, domainservice.NewInternalError(err, "failed to get versions of dogu %q", doguName)
+	}
+	if len(versions) == 0 {
+		return nil, domainservice.NewNotFoundError(nil, "no versions of dogu %q could be found", doguName)
+	}
+	logger.V(2).Info("loaded available dogu versions", "count", len(versions))
+	return versions, nil
+}
@@ -3,24 +3,39 @@ package doguregistry
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	cloudoguerrors "github.com/cloudogu/ces-commons-lib/errors"
 	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/cesapp-lib/remote"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+// newTestRemoteRegistry creates a cesapp-lib registry like in the bootstrap, which sends its requests to the given handler.
+func newTestRemoteRegistry(t *testing.T, handler http.Handler) remote.Registry {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	registry, err := remote.New(&core.Remote{Endpoint: server.URL, URLSchema: "default", CacheDir: t.TempDir()}, &core.Credentials{})
+	require.NoError(t, err)
+	registry.SetUseCache(false)
+	return registry
+}
+
 func TestNewRemote(t *testing.T) {
 	// given
 	remoteRepoMock := newMockRemoteDoguDescriptorRepository(t)
 	localRepoMock := newMockLocalDoguDescriptorRepository(t)
+	remoteRegistryMock := newMockCesappLibRemoteRegistry(t)
 
 	// when
-	actual := NewDoguDescriptorRepository(remoteRepoMock, localRepoMock)
+	actual := NewDoguDescriptorRepository(remoteRepoMock, localRepoMock, remoteRegistryMock)
 
 	// then
 	assert.NotEmpty(t, actual)
@@ -42,7 +57,7 @@ func TestRemote_GetDogu(t *testing.T) {
 		}
 		remoteRepoMock.EXPECT().Get(context.TODO(), qDoguVersion).Return(nil, cloudoguerrors.NewNotFoundError(cloudoguerrors.Error{}))
 
-		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock}
+		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock, nil}
 
 		// when
 		actual, err := sut.GetDogu(context.TODO(), qDoguVersion)
@@ -70,7 +85,7 @@ func TestRemote_GetDogu(t *testing.T) {
 		}
 		remoteRepoMock.EXPECT().Get(context.TODO(), qDoguVersion).Return(nil, assert.AnError)
 
-		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock}
+		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock, nil}
 
 		// when
 		actual, err := sut.GetDogu(context.TODO(), qDoguVersion)
@@ -83,6 +98,33 @@ func TestRemote_GetDogu(t *testing.T) {
 		expectedErr := &domainservice.InternalError{}
 		assert.ErrorAs(t, err, &expectedErr)
 	})
+	t.Run("should return authentication error if credentials are rejected", func(t *testing.T) {
+		// given
+		remoteRepoMock := newMockRemoteDoguDescriptorRepository(t)
+		localRepoMock := newMockLocalDoguDescriptorRepository(t)
+
+		version, err := core.ParseVersion("1.2.3")
+		require.NoError(t, err)
+		// no local hit
+		qSimpleDoguVersion := cescommons.SimpleNameVersion{Name: "my-dogu", Version: version}
+		localRepoMock.EXPECT().Get(context.TODO(), qSimpleDoguVersion).Return(nil, assert.AnError)
+		qDoguVersion := cescommons.QualifiedVersion{
+			Name:    cescommons.QualifiedName{Namespace: "testing", SimpleName: "my-dogu"},
+			Version: version,
+		}
+		remoteRepoMock.EXPECT().Get(context.TODO(), qDoguVersion).Return(nil, domainservice.NewRegistryAuthenticationError(assert.AnError, "rejected"))
+
+		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock, nil}
+
+		// when
+		_, err = sut.GetDogu(context.TODO(), qDoguVersion)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get dogu \"testing/my-dogu\" with version \"1.2.3\"")
+		assert.True(t, domainservice.IsRegistryAuthenticationError(err))
+		assert.False(t, domainservice.IsInternalError(err))
+	})
 	t.Run("should return dogu on remote hit", func(t *testing.T) {
 		// given
 		expectedDogu := core.Dogu{Name: "testing/my-dogu", Version: "1.2.3"}
@@ -101,7 +143,7 @@ func TestRemote_GetDogu(t *testing.T) {
 		remoteRepoMock.EXPECT().Get(context.TODO(), qDoguVersion).Return(&expectedDogu, nil)
 		localRepoMock.EXPECT().Add(context.TODO(), qDoguVersion.Name.SimpleName, &expectedDogu).Return(nil)
 
-		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock}
+		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock, nil}
 
 		// when
 		actual, err := sut.GetDogu(context.TODO(), qDoguVersion)
@@ -128,7 +170,7 @@ func TestRemote_GetDogu(t *testing.T) {
 		remoteRepoMock.EXPECT().Get(context.TODO(), qDoguVersion).Return(&expectedDogu, nil)
 		localRepoMock.EXPECT().Add(context.TODO(), qDoguVersion.Name.SimpleName, &expectedDogu).Return(assert.AnError)
 
-		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock}
+		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock, nil}
 
 		// when
 		actual, err := sut.GetDogu(context.TODO(), qDoguVersion)
@@ -148,7 +190,7 @@ func TestRemote_GetDogu(t *testing.T) {
 		qSimpleDoguVersion := cescommons.SimpleNameVersion{Name: "my-dogu", Version: version}
 		localRepoMock.EXPECT().Get(context.TODO(), qSimpleDoguVersion).Return(&expectedDogu, nil)
 
-		sut := &DoguDescriptorRepository{nil, localRepoMock}
+		sut := &DoguDescriptorRepository{nil, localRepoMock, nil}
 
 		// when
 		qDoguVersion := cescommons.QualifiedVersion{
@@ -195,7 +237,7 @@ func TestRemote_GetDogus(t *testing.T) {
 		remoteRepoMock.EXPECT().Get(context.TODO(), qOtherErrorDoguVersion).Return(nil, assert.AnError)
 		localRepoMock.EXPECT().Add(context.TODO(), qGoodDoguVersion.Name.SimpleName, &expectedDogu).Return(nil)
 
-		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock}
+		sut := &DoguDescriptorRepository{remoteRepoMock, localRepoMock, nil}
 		dogusToLoad := []cescommons.QualifiedVersion{
 			qGoodDoguVersion,
 			qOtherErrorDoguVersion,
@@ -225,3 +267,103 @@ func TestRemote_GetDogus(t *testing.T) {
 		assert.Equal(t, expectedDogus, actual)
 	})
 }
+
+func TestRemote_GetVersionsOf(t *testing.T) {
+	doguName := cescommons.QualifiedName{Namespace: "official", SimpleName: "ldap"}
+
+	t.Run("should return versions of dogu", func(t *testing.T) {
+		// given
+		version1, _ := core.ParseVersion("2.4.48-3")
+		version2, _ := core.ParseVersion("2.4.49-1")
+		remoteRegistryMock := newMockCesappLibRemoteRegistry(t)
+		remoteRegistryMock.EXPECT().GetVersionsOf("official/ldap").Return([]core.Version{version1, version2}, nil)
+		sut := &DoguDescriptorRepository{remoteRegistry: remoteRegistryMock}
+
+		// when
+		versions, err := sut.GetVersionsOf(context.TODO(), doguName)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []core.Version{version1, version2}, versions)
+	})
+
+	t.Run("should return not found error if there are no versions", func(t *testing.T) {
+		// given
+		remoteRegistryMock := newMockCesappLibRemoteRegistry(t)
+		remoteRegistryMock.EXPECT().GetVersionsOf("official/ldap").Return([]core.Version{}, nil)
+		sut := &DoguDescriptorRepository{remoteRegistry: remoteRegistryMock}
+
+		// when
+		_, err := sut.GetVersionsOf(context.TODO(), doguName)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "no versions of dogu \"official/ldap\" could be found")
+	})
+
+	t.Run("should return not found error if the registry does not know the dogu", func(t *testing.T) {
+		// given
+		failoverRemote := NewFailoverRemote([]RegistryEndpoint{{URL: "primary", Registry: newTestRemoteRegistry(t, http.NotFoundHandler())}}, time.Minute)
+		sut := &DoguDescriptorRepository{remoteRegistry: failoverRemote}
+
+		// when
+		_, err := sut.GetVersionsOf(context.TODO(), doguName)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "dogu \"official/ldap\" could not be found")
+	})
+
+	t.Run("should return not found error only for dogus unknown to the registry", func(t *testing.T) {
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("/dogus/official/ldap/_versions", func(writer http.ResponseWriter, _ *http.Request) {
+			_, _ = writer.Write([]byte(`["3.2.1-1","3.2.1-2"]`))
+		})
+		mux.Handle("/", http.NotFoundHandler())
+		failoverRemote := NewFailoverRemote([]RegistryEndpoint{{URL: "primary", Registry: newTestRemoteRegistry(t, mux)}}, time.Minute)
+		sut := NewDoguDescriptorRepository(nil, nil, failoverRemote)
+
+		// when
+		versions, err := sut.GetVersionsOf(context.TODO(), doguName)
+		_, unknownErr := sut.GetVersionsOf(context.TODO(), cescommons.QualifiedName{Namespace: "official", SimpleName: "postgresql"})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+		require.Error(t, unknownErr)
+		assert.True(t, domainservice.IsNotFoundError(unknownErr))
+		assert.ErrorContains(t, unknownErr, "dogu \"official/postgresql\" could not be found")
+	})
+
+	t.Run("should return internal error on remote error", func(t *testing.T) {
+		// given
+		remoteRegistryMock := newMockCesappLibRemoteRegistry(t)
+		remoteRegistryMock.EXPECT().GetVersionsOf("official/ldap").Return(nil, assert.AnError)
+		sut := &DoguDescriptorRepository{remoteRegistry: remoteRegistryMock}
+
+		// when
+		_, err := sut.GetVersionsOf(context.TODO(), doguName)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "failed to get versions of dogu \"official/ldap\"")
+	})
+	t.Run("should return authentication error if credentials are rejected", func(t *testing.T) {
+		// given
+		remoteRegistryMock := newMockCesappLibRemoteRegistry(t)
+		remoteRegistryMock.EXPECT().GetVersionsOf("official/ldap").Return(nil, domainservice.NewRegistryAuthenticationError(assert.AnError, "rejected"))
+		sut := &DoguDescriptorRepository{remoteRegistry: remoteRegistryMock}
+
+		// when
+		_, err := sut.GetVersionsOf(context.TODO(), doguName)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsRegistryAuthenticationError(err))
+		assert.False(t, domainservice.IsInternalError(err))
+	})
+}
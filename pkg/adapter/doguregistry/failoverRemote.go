@@ -0,0 +1,168 @@
+package doguregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	cloudoguerrors "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// circuitBreakerFailureThreshold is the amount of consecutive failures after which an endpoint gets skipped.
+const circuitBreakerFailureThreshold = 3
+
+// RegistryEndpoint is a single endpoint of the remote dogu registry, e.g. a mirror or the upstream registry.
+type RegistryEndpoint struct {
+	// URL identifies the endpoint in logs and errors.
+	URL                  string
+	DescriptorRepository remoteDoguDescriptorRepository
+	Registry             cesappLibRemoteRegistry
+}
+
+type failoverEndpoint struct {
+	RegistryEndpoint
+	breaker *circuitBreaker
+}
+
+// FailoverRemote sends each request to the first reachable endpoint of an ordered list of registry endpoints.
+// Every endpoint has a circuit breaker, so that an unreachable endpoint gets skipped for the open duration
+// instead of being requested again and again.
+// FailoverRemote can be used as remote repository and as remote registry of the DoguDescriptorRepository.
+type FailoverRemote struct {
+	mutex     sync.RWMutex
+	endpoints []*failoverEndpoint
+	// accessErr is the reason why there are no endpoints, e.g. because the credentials could not be read.
+	accessErr    error
+	openDuration time.Duration
+}
+
+// NewFailoverRemote creates a FailoverRemote for the given endpoints, which are requested in the given order.
+func NewFailoverRemote(endpoints []RegistryEndpoint, openDuration time.Duration) *FailoverRemote {
+	remote := &FailoverRemote{openDuration: openDuration}
+	remote.setEndpoints(endpoints)
+	return remote
+}
+
+// setEndpoints replaces the endpoints, e.g. after the credentials changed. The circuit breakers start closed again.
+// Requests which are already running finish with the previous endpoints.
+func (f *FailoverRemote) setEndpoints(endpoints []RegistryEndpoint) {
+	failoverEndpoints := make([]*failoverEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		failoverEndpoints = append(failoverEndpoints, &failoverEndpoint{
+			RegistryEndpoint: endpoint,
+			breaker:          newCircuitBreaker(circuitBreakerFailureThreshold, f.openDuration),
+		})
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.endpoints = failoverEndpoints
+	f.accessErr = nil
+}
+
+// setAccessError sets the reason why there are no endpoints yet. Requests fail with this reason
+// until endpoints are set.
+func (f *FailoverRemote) setAccessError(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.accessErr = err
+}
+
+func (f *FailoverRemote) currentEndpoints() ([]*failoverEndpoint, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.endpoints, f.accessErr
+}
+
+func (f *FailoverRemote) GetLatest(ctx context.Context, name cescommons.QualifiedName) (*core.Dogu, error) {
+	logger := log.FromContext(ctx).WithName("FailoverRemote.GetLatest")
+	return failover(f, logger, cloudoguerrors.IsNotFoundError, func(endpoint RegistryEndpoint) (*core.Dogu, error) {
+		return endpoint.DescriptorRepository.GetLatest(ctx, name)
+	})
+}
+
+func (f *FailoverRemote) Get(ctx context.Context, qualifiedDoguVersion cescommons.QualifiedVersion) (*core.Dogu, error) {
+	logger := log.FromContext(ctx).WithName("FailoverRemote.Get")
+	return failover(f, logger, cloudoguerrors.IsNotFoundError, func(endpoint RegistryEndpoint) (*core.Dogu, error) {
+		return endpoint.DescriptorRepository.Get(ctx, qualifiedDoguVersion)
+	})
+}
+
+func (f *FailoverRemote) GetVersionsOf(name string) ([]core.Version, error) {
+	logger := log.Log.WithName("FailoverRemote.GetVersionsOf")
+	return failover(f, logger, isVersionsNotFoundError, func(endpoint RegistryEndpoint) ([]core.Version, error) {
+		return endpoint.Registry.GetVersionsOf(name)
+	})
+}
+
+// cesappLibNotFoundMessage is the message of the not found error of the cesapp-lib for a 404 status code.
+const cesappLibNotFoundMessage = "404 not found"
+
+// isVersionsNotFoundError checks for a typed not found error or the not found error of the cesapp-lib.
+// The cesapp-lib does not export its error, so it is detected by its exact message in the error chain
+// instead of any error mentioning a 404 in its message.
+func isVersionsNotFoundError(err error) bool {
+	if cloudoguerrors.IsNotFoundError(err) {
+		return true
+	}
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if current.Error() == cesappLibNotFoundMessage {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuthenticationError checks for rejected credentials. The cesapp-lib does not export its errors,
+// so they are detected by their message.
+func isAuthenticationError(err error) bool {
+	return cloudoguerrors.IsUnauthorizedError(err) || cloudoguerrors.IsForbiddenError(err) ||
+		strings.Contains(err.Error(), "401 unauthorized") || strings.Contains(err.Error(), "403 forbidden")
+}
+
+// failover sends the request to the endpoints in their order until an endpoint answers.
+// An error for which isAnswer returns true, e.g. a not found error, is an answer of a reachable endpoint.
+// Returns a domainservice.RegistryAuthenticationError without failing over if an endpoint rejects the credentials,
+// as all endpoints share the same credentials.
+// Returns a domainservice.RegistryUnreachableError if no endpoint answered.
+func failover[T any](f *FailoverRemote, logger logr.Logger, isAnswer func(error) bool, request func(RegistryEndpoint) (T, error)) (T, error) {
+	var noResult T
+	endpoints, accessErr := f.currentEndpoints()
+	if len(endpoints) == 0 && accessErr != nil {
+		return noResult, domainservice.NewRegistryUnreachableError(accessErr, "no access to the remote dogu registry")
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		if !endpoint.breaker.allow() {
+			errs = append(errs, fmt.Errorf("endpoint %q is skipped after repeated failures", endpoint.URL))
+			continue
+		}
+
+		result, err := request(endpoint.RegistryEndpoint)
+		if err == nil || isAnswer(err) {
+			endpoint.breaker.recordSuccess()
+			return result, err
+		}
+		if isAuthenticationError(err) {
+			// the endpoint is reachable, so the circuit stays closed
+			endpoint.breaker.recordSuccess()
+			return noResult, domainservice.NewRegistryAuthenticationError(err, "endpoint %q of the remote dogu registry rejected the credentials", endpoint.URL)
+		}
+
+		if endpoint.breaker.recordFailure() {
+			logger.Info("endpoint of the remote dogu registry failed repeatedly and is skipped for a while", "endpoint", endpoint.URL, "error", err.Error())
+		}
+		errs = append(errs, fmt.Errorf("endpoint %q: %w", endpoint.URL, err))
+	}
+
+	return noResult, domainservice.NewRegistryUnreachableError(errors.Join(errs...), "no endpoint of the remote dogu registry is reachable")
+}
@@ -0,0 +1,260 @@
+package doguregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	cloudoguerrors "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	failoverLdapName    = cescommons.QualifiedName{Namespace: "official", SimpleName: "ldap"}
+	failoverLdapVersion = cescommons.QualifiedVersion{Name: failoverLdapName, Version: core.Version{Raw: "2.6.7-1"}}
+	failoverLdap        = &core.Dogu{Name: "official/ldap", Version: "2.6.7-1"}
+)
+
+func TestFailoverRemote_Get(t *testing.T) {
+	t.Run("should use primary endpoint", func(t *testing.T) {
+		// given
+		primaryMock := newMockRemoteDoguDescriptorRepository(t)
+		primaryMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(failoverLdap, nil)
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", DescriptorRepository: primaryMock},
+			{URL: "failover", DescriptorRepository: newMockRemoteDoguDescriptorRepository(t)},
+		}, time.Minute)
+
+		// when
+		actual, err := sut.Get(context.TODO(), failoverLdapVersion)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+	})
+	t.Run("should fail over to next endpoint", func(t *testing.T) {
+		// given
+		primaryMock := newMockRemoteDoguDescriptorRepository(t)
+		primaryMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(nil, assert.AnError)
+		failoverMock := newMockRemoteDoguDescriptorRepository(t)
+		failoverMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(failoverLdap, nil)
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", DescriptorRepository: primaryMock},
+			{URL: "failover", DescriptorRepository: failoverMock},
+		}, time.Minute)
+
+		// when
+		actual, err := sut.Get(context.TODO(), failoverLdapVersion)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+	})
+	t.Run("should not fail over on not found error", func(t *testing.T) {
+		// given
+		notFoundErr := cloudoguerrors.NewNotFoundError(assert.AnError)
+		primaryMock := newMockRemoteDoguDescriptorRepository(t)
+		primaryMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(nil, notFoundErr)
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", DescriptorRepository: primaryMock},
+			{URL: "failover", DescriptorRepository: newMockRemoteDoguDescriptorRepository(t)},
+		}, time.Minute)
+
+		// when
+		_, err := sut.Get(context.TODO(), failoverLdapVersion)
+
+		// then
+		assert.True(t, cloudoguerrors.IsNotFoundError(err))
+		assert.Equal(t, 0, sut.endpoints[0].breaker.consecutiveFailures)
+	})
+	t.Run("should return RegistryUnreachableError if all endpoints fail", func(t *testing.T) {
+		// given
+		primaryMock := newMockRemoteDoguDescriptorRepository(t)
+		primaryMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(nil, assert.AnError)
+		failoverMock := newMockRemoteDoguDescriptorRepository(t)
+		failoverMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(nil, errors.New("connection refused"))
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", DescriptorRepository: primaryMock},
+			{URL: "failover", DescriptorRepository: failoverMock},
+		}, time.Minute)
+
+		// when
+		_, err := sut.Get(context.TODO(), failoverLdapVersion)
+
+		// then
+		assert.True(t, domainservice.IsRegistryUnreachableError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "no endpoint of the remote dogu registry is reachable")
+		assert.ErrorContains(t, err, "endpoint \"failover\": connection refused")
+	})
+	t.Run("should not fail over if credentials are rejected", func(t *testing.T) {
+		// given
+		unauthorizedErr := cloudoguerrors.NewUnauthorizedError(errors.New("401 unauthorized, please login to proceed"))
+		primaryMock := newMockRemoteDoguDescriptorRepository(t)
+		primaryMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(nil, unauthorizedErr)
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", DescriptorRepository: primaryMock},
+			{URL: "failover", DescriptorRepository: newMockRemoteDoguDescriptorRepository(t)},
+		}, time.Minute)
+
+		// when
+		_, err := sut.Get(context.TODO(), failoverLdapVersion)
+
+		// then
+		assert.True(t, domainservice.IsRegistryAuthenticationError(err))
+		assert.False(t, domainservice.IsRegistryUnreachableError(err))
+		assert.ErrorContains(t, err, "endpoint \"primary\" of the remote dogu registry rejected the credentials")
+		assert.Equal(t, 0, sut.endpoints[0].breaker.consecutiveFailures)
+	})
+	t.Run("should skip endpoint with open circuit", func(t *testing.T) {
+		// given
+		primaryMock := newMockRemoteDoguDescriptorRepository(t)
+		primaryMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(nil, assert.AnError).Times(circuitBreakerFailureThreshold)
+		failoverMock := newMockRemoteDoguDescriptorRepository(t)
+		failoverMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(failoverLdap, nil).Times(circuitBreakerFailureThreshold + 1)
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", DescriptorRepository: primaryMock},
+			{URL: "failover", DescriptorRepository: failoverMock},
+		}, time.Minute)
+		for range circuitBreakerFailureThreshold {
+			_, err := sut.Get(context.TODO(), failoverLdapVersion)
+			require.NoError(t, err)
+		}
+
+		// when
+		actual, err := sut.Get(context.TODO(), failoverLdapVersion)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+	})
+}
+
+func TestFailoverRemote_GetLatest(t *testing.T) {
+	t.Run("should fail over to next endpoint", func(t *testing.T) {
+		// given
+		primaryMock := newMockRemoteDoguDescriptorRepository(t)
+		primaryMock.EXPECT().GetLatest(context.TODO(), failoverLdapName).Return(nil, assert.AnError)
+		failoverMock := newMockRemoteDoguDescriptorRepository(t)
+		failoverMock.EXPECT().GetLatest(context.TODO(), failoverLdapName).Return(failoverLdap, nil)
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", DescriptorRepository: primaryMock},
+			{URL: "failover", DescriptorRepository: failoverMock},
+		}, time.Minute)
+
+		// when
+		actual, err := sut.GetLatest(context.TODO(), failoverLdapName)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+	})
+}
+
+func TestFailoverRemote_GetVersionsOf(t *testing.T) {
+	versions := []core.Version{{Raw: "2.6.7-1"}}
+
+	t.Run("should fail over to next endpoint", func(t *testing.T) {
+		// given
+		primaryMock := newMockCesappLibRemoteRegistry(t)
+		primaryMock.EXPECT().GetVersionsOf("official/ldap").Return(nil, assert.AnError)
+		failoverMock := newMockCesappLibRemoteRegistry(t)
+		failoverMock.EXPECT().GetVersionsOf("official/ldap").Return(versions, nil)
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", Registry: primaryMock},
+			{URL: "failover", Registry: failoverMock},
+		}, time.Minute)
+
+		// when
+		actual, err := sut.GetVersionsOf("official/ldap")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, versions, actual)
+	})
+	t.Run("should not fail over on not found error", func(t *testing.T) {
+		// given
+		primaryMock := newMockCesappLibRemoteRegistry(t)
+		primaryMock.EXPECT().GetVersionsOf("official/ldap").Return(nil, errors.New("404 not found"))
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", Registry: primaryMock},
+			{URL: "failover", Registry: newMockCesappLibRemoteRegistry(t)},
+		}, time.Minute)
+
+		// when
+		_, err := sut.GetVersionsOf("official/ldap")
+
+		// then
+		assert.ErrorContains(t, err, "404 not found")
+		assert.False(t, domainservice.IsRegistryUnreachableError(err))
+	})
+	t.Run("should return authentication error if credentials are rejected", func(t *testing.T) {
+		// given
+		primaryMock := newMockCesappLibRemoteRegistry(t)
+		primaryMock.EXPECT().GetVersionsOf("official/ldap").Return(nil, errors.New("403 forbidden, not enough privileges"))
+		sut := NewFailoverRemote([]RegistryEndpoint{
+			{URL: "primary", Registry: primaryMock},
+			{URL: "failover", Registry: newMockCesappLibRemoteRegistry(t)},
+		}, time.Minute)
+
+		// when
+		_, err := sut.GetVersionsOf("official/ldap")
+
+		// then
+		assert.True(t, domainservice.IsRegistryAuthenticationError(err))
+		assert.ErrorContains(t, err, "403 forbidden")
+	})
+}
+
+func Test_isVersionsNotFoundError(t *testing.T) {
+	t.Run("should detect the not found error of the cesapp-lib", func(t *testing.T) {
+		_, err := newTestRemoteRegistry(t, http.NotFoundHandler()).GetVersionsOf("official/ldap")
+		require.Error(t, err)
+
+		assert.True(t, isVersionsNotFoundError(err))
+		assert.True(t, isVersionsNotFoundError(fmt.Errorf("failed to get versions: %w", err)))
+	})
+	t.Run("should detect typed not found error", func(t *testing.T) {
+		assert.True(t, isVersionsNotFoundError(cloudoguerrors.NewNotFoundError(assert.AnError)))
+	})
+	t.Run("should not detect other errors mentioning a 404", func(t *testing.T) {
+		handler := http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusBadGateway)
+			_, _ = writer.Write([]byte(`{"error":"upstream answered 404 not found"}`))
+		})
+		_, err := newTestRemoteRegistry(t, handler).GetVersionsOf("official/ldap")
+		require.ErrorContains(t, err, "404 not found")
+
+		assert.False(t, isVersionsNotFoundError(err))
+		assert.False(t, isVersionsNotFoundError(assert.AnError))
+	})
+}
+
+func TestFailoverRemote_setEndpoints(t *testing.T) {
+	t.Run("should use new endpoints with closed circuits", func(t *testing.T) {
+		// given
+		oldMock := newMockRemoteDoguDescriptorRepository(t)
+		oldMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(nil, assert.AnError).Times(circuitBreakerFailureThreshold)
+		sut := NewFailoverRemote([]RegistryEndpoint{{URL: "primary", DescriptorRepository: oldMock}}, time.Minute)
+		for range circuitBreakerFailureThreshold {
+			_, _ = sut.Get(context.TODO(), failoverLdapVersion)
+		}
+		newMock := newMockRemoteDoguDescriptorRepository(t)
+		newMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(failoverLdap, nil)
+
+		// when
+		sut.setEndpoints([]RegistryEndpoint{{URL: "primary", DescriptorRepository: newMock}})
+
+		// then
+		actual, err := sut.Get(context.TODO(), failoverLdapVersion)
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+	})
+}
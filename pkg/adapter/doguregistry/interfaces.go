@@ -5,13 +5,31 @@ import (
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type remoteDoguDescriptorRepository interface {
 	cescommons.RemoteDoguDescriptorRepository
 }
 
+type remoteDoguRegistry interface {
+	domainservice.RemoteDoguRegistry
+}
+
 type localDoguDescriptorRepository interface {
 	Get(ctx context.Context, doguVersion cescommons.SimpleNameVersion) (*core.Dogu, error)
 	Add(ctx context.Context, name cescommons.SimpleName, dogu *core.Dogu) error
 }
+
+// cesappLibRemoteRegistry is the part of the remote.Registry which is used by this operator.
+type cesappLibRemoteRegistry interface {
+	// GetVersionsOf return all versions of a dogu.
+	GetVersionsOf(name string) ([]core.Version, error)
+}
+
+// secretGetter is the part of the secret client which is used to read the access to the remote dogu registry.
+type secretGetter interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error)
+}
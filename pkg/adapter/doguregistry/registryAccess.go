@@ -0,0 +1,146 @@
+package doguregistry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	credentialsSecretUsernameKey = "username"
+	credentialsSecretPasswordKey = "password"
+	proxySecretURLKey            = "url"
+)
+
+// RegistryAccess contains the credentials and the proxy to access the remote dogu registry.
+type RegistryAccess struct {
+	Credentials core.Credentials
+	// ProxyURL is empty if no proxy is used.
+	ProxyURL string
+}
+
+// EndpointFactory creates the endpoints of the remote dogu registry with the given access.
+type EndpointFactory func(access RegistryAccess) ([]RegistryEndpoint, error)
+
+// AccessSecrets references the secrets which contain the access to the remote dogu registry.
+// An empty name means that the default access is used for this part.
+type AccessSecrets struct {
+	// CredentialsSecret is the name of a secret with the keys "username" and "password".
+	CredentialsSecret string
+	// ProxySecret is the name of an optional secret with the key "url". No proxy is used if the secret does not exist.
+	ProxySecret string
+}
+
+// AccessRefresher reads the access to the remote dogu registry from secrets and rebuilds the endpoints of the
+// FailoverRemote if it changed. This way, rotated credentials are used without restarting the operator.
+type AccessRefresher struct {
+	remote          *FailoverRemote
+	createEndpoints EndpointFactory
+	secretClient    secretGetter
+	secrets         AccessSecrets
+	defaultAccess   RegistryAccess
+
+	mutex         sync.Mutex
+	currentAccess *RegistryAccess
+}
+
+// NewAccessRefresher creates an AccessRefresher. The defaultAccess is used for the parts of the access without secret.
+// The endpoints of the remote get created on the first call of RefreshAccess.
+func NewAccessRefresher(
+	remote *FailoverRemote,
+	createEndpoints EndpointFactory,
+	secretClient secretGetter,
+	secrets AccessSecrets,
+	defaultAccess RegistryAccess,
+) *AccessRefresher {
+	return &AccessRefresher{
+		remote:          remote,
+		createEndpoints: createEndpoints,
+		secretClient:    secretClient,
+		secrets:         secrets,
+		defaultAccess:   defaultAccess,
+	}
+}
+
+// RefreshAccess reads the access from the secrets and rebuilds the endpoints if the access changed.
+// The current endpoints are kept if the secrets cannot be read or are invalid.
+// If there was no valid access yet, e.g. because a secret is missing at startup, the remote reports the error
+// as domainservice.RegistryUnreachableError until the access can be read.
+func (r *AccessRefresher) RefreshAccess(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("AccessRefresher.RefreshAccess")
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	access, err := r.readAccess(ctx)
+	if err != nil {
+		return r.handleAccessError(err)
+	}
+	if r.currentAccess != nil && *r.currentAccess == access {
+		return nil
+	}
+
+	endpoints, err := r.createEndpoints(access)
+	if err != nil {
+		return r.handleAccessError(fmt.Errorf("cannot create endpoints of the remote dogu registry: %w", err))
+	}
+	r.remote.setEndpoints(endpoints)
+	r.currentAccess = &access
+	logger.Info("updated the access to the remote dogu registry", "endpoints", len(endpoints))
+	return nil
+}
+
+func (r *AccessRefresher) handleAccessError(err error) error {
+	if r.currentAccess == nil {
+		r.remote.setAccessError(err)
+	}
+	return err
+}
+
+func (r *AccessRefresher) readAccess(ctx context.Context) (RegistryAccess, error) {
+	access := r.defaultAccess
+
+	if r.secrets.CredentialsSecret != "" {
+		secret, err := r.secretClient.Get(ctx, r.secrets.CredentialsSecret, metav1.GetOptions{})
+		if err != nil {
+			return RegistryAccess{}, fmt.Errorf("cannot get secret %q with the credentials of the remote dogu registry: %w", r.secrets.CredentialsSecret, err)
+		}
+		username, err := getSecretValue(secret, credentialsSecretUsernameKey)
+		if err != nil {
+			return RegistryAccess{}, err
+		}
+		password, err := getSecretValue(secret, credentialsSecretPasswordKey)
+		if err != nil {
+			return RegistryAccess{}, err
+		}
+		access.Credentials = core.Credentials{Username: username, Password: password}
+	}
+
+	if r.secrets.ProxySecret != "" {
+		secret, err := r.secretClient.Get(ctx, r.secrets.ProxySecret, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			access.ProxyURL = ""
+			return access, nil
+		}
+		if err != nil {
+			return RegistryAccess{}, fmt.Errorf("cannot get secret %q with the proxy of the remote dogu registry: %w", r.secrets.ProxySecret, err)
+		}
+		access.ProxyURL = string(secret.Data[proxySecretURLKey])
+	}
+
+	return access, nil
+}
+
+func getSecretValue(secret *corev1.Secret, key string) (string, error) {
+	value, found := secret.Data[key]
+	if !found {
+		return "", fmt.Errorf("secret %q does not contain the key %q", secret.Name, key)
+	}
+	return string(value), nil
+}
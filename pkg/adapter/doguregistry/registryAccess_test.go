@@ -0,0 +1,166 @@
+package doguregistry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testAccessSecrets = AccessSecrets{CredentialsSecret: "dogu-registry", ProxySecret: "ces-proxy"}
+
+func credentialsSecret(username, password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dogu-registry"},
+		Data:       map[string][]byte{"username": []byte(username), "password": []byte(password)},
+	}
+}
+
+func TestAccessRefresher_RefreshAccess(t *testing.T) {
+	proxySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ces-proxy"},
+		Data:       map[string][]byte{"url": []byte("https://proxy:3128")},
+	}
+	proxyNotFoundErr := k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "ces-proxy")
+
+	t.Run("should create endpoints with access from secrets", func(t *testing.T) {
+		// given
+		secretMock := newMockSecretGetter(t)
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(credentialsSecret("user", "secret"), nil)
+		secretMock.EXPECT().Get(context.TODO(), "ces-proxy", metav1.GetOptions{}).Return(proxySecret, nil)
+		endpointMock := newMockRemoteDoguDescriptorRepository(t)
+		expectedAccess := RegistryAccess{Credentials: core.Credentials{Username: "user", Password: "secret"}, ProxyURL: "https://proxy:3128"}
+		factoryMock := NewMockEndpointFactory(t)
+		factoryMock.EXPECT().Execute(expectedAccess).Return([]RegistryEndpoint{{URL: "primary", DescriptorRepository: endpointMock}}, nil)
+		remote := NewFailoverRemote(nil, time.Minute)
+		sut := NewAccessRefresher(remote, factoryMock.Execute, secretMock, testAccessSecrets, RegistryAccess{})
+
+		// when
+		err := sut.RefreshAccess(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, remote.endpoints, 1)
+		assert.Equal(t, endpointMock, remote.endpoints[0].DescriptorRepository)
+	})
+	t.Run("should not rebuild endpoints if access did not change", func(t *testing.T) {
+		// given
+		secretMock := newMockSecretGetter(t)
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(credentialsSecret("user", "secret"), nil).Twice()
+		secretMock.EXPECT().Get(context.TODO(), "ces-proxy", metav1.GetOptions{}).Return(nil, proxyNotFoundErr).Twice()
+		factoryMock := NewMockEndpointFactory(t)
+		factoryMock.EXPECT().Execute(mock.Anything).Return([]RegistryEndpoint{{URL: "primary"}}, nil).Once()
+		sut := NewAccessRefresher(NewFailoverRemote(nil, time.Minute), factoryMock.Execute, secretMock, testAccessSecrets, RegistryAccess{ProxyURL: "https://default:3128"})
+		require.NoError(t, sut.RefreshAccess(context.TODO()))
+
+		// when
+		err := sut.RefreshAccess(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "", sut.currentAccess.ProxyURL)
+	})
+	t.Run("should rebuild endpoints with rotated credentials", func(t *testing.T) {
+		// given
+		secretMock := newMockSecretGetter(t)
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(credentialsSecret("user", "old"), nil).Once()
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(credentialsSecret("user", "new"), nil).Once()
+		factoryMock := NewMockEndpointFactory(t)
+		factoryMock.EXPECT().Execute(RegistryAccess{Credentials: core.Credentials{Username: "user", Password: "old"}}).Return([]RegistryEndpoint{{URL: "old"}}, nil)
+		factoryMock.EXPECT().Execute(RegistryAccess{Credentials: core.Credentials{Username: "user", Password: "new"}}).Return([]RegistryEndpoint{{URL: "new"}}, nil)
+		remote := NewFailoverRemote(nil, time.Minute)
+		sut := NewAccessRefresher(remote, factoryMock.Execute, secretMock, AccessSecrets{CredentialsSecret: "dogu-registry"}, RegistryAccess{})
+		require.NoError(t, sut.RefreshAccess(context.TODO()))
+
+		// when
+		err := sut.RefreshAccess(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "new", remote.endpoints[0].URL)
+	})
+	t.Run("should keep endpoints if credentials secret is invalid", func(t *testing.T) {
+		// given
+		invalidSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dogu-registry"}, Data: map[string][]byte{"username": []byte("user")}}
+		secretMock := newMockSecretGetter(t)
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(invalidSecret, nil)
+		remote := NewFailoverRemote([]RegistryEndpoint{{URL: "primary"}}, time.Minute)
+		sut := NewAccessRefresher(remote, NewMockEndpointFactory(t).Execute, secretMock, testAccessSecrets, RegistryAccess{})
+
+		// when
+		err := sut.RefreshAccess(context.TODO())
+
+		// then
+		assert.ErrorContains(t, err, "secret \"dogu-registry\" does not contain the key \"password\"")
+		assert.Equal(t, "primary", remote.endpoints[0].URL)
+	})
+	t.Run("should fail to get credentials secret", func(t *testing.T) {
+		// given
+		secretMock := newMockSecretGetter(t)
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(nil, assert.AnError)
+		sut := NewAccessRefresher(NewFailoverRemote(nil, time.Minute), NewMockEndpointFactory(t).Execute, secretMock, testAccessSecrets, RegistryAccess{})
+
+		// when
+		err := sut.RefreshAccess(context.TODO())
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot get secret \"dogu-registry\" with the credentials of the remote dogu registry")
+	})
+	t.Run("should report missing credentials secret as unreachable registry until it exists", func(t *testing.T) {
+		// given
+		credentialsNotFoundErr := k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "dogu-registry")
+		secretMock := newMockSecretGetter(t)
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(nil, credentialsNotFoundErr).Once()
+		secretMock.EXPECT().Get(context.TODO(), "dogu-registry", metav1.GetOptions{}).Return(credentialsSecret("user", "secret"), nil).Once()
+		endpointMock := newMockRemoteDoguDescriptorRepository(t)
+		endpointMock.EXPECT().Get(context.TODO(), failoverLdapVersion).Return(failoverLdap, nil)
+		factoryMock := NewMockEndpointFactory(t)
+		factoryMock.EXPECT().Execute(mock.Anything).Return([]RegistryEndpoint{{URL: "primary", DescriptorRepository: endpointMock}}, nil)
+		remote := NewFailoverRemote(nil, time.Minute)
+		sut := NewAccessRefresher(remote, factoryMock.Execute, secretMock, AccessSecrets{CredentialsSecret: "dogu-registry"}, RegistryAccess{})
+
+		// when
+		err := sut.RefreshAccess(context.TODO())
+
+		// then
+		require.ErrorIs(t, err, credentialsNotFoundErr)
+		_, err = remote.Get(context.TODO(), failoverLdapVersion)
+		assert.True(t, domainservice.IsRegistryUnreachableError(err))
+		assert.ErrorContains(t, err, "no access to the remote dogu registry")
+		assert.ErrorIs(t, err, credentialsNotFoundErr)
+
+		// when
+		err = sut.RefreshAccess(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		actual, err := remote.Get(context.TODO(), failoverLdapVersion)
+		require.NoError(t, err)
+		assert.Equal(t, failoverLdap, actual)
+	})
+	t.Run("should fail to create endpoints", func(t *testing.T) {
+		// given
+		defaultAccess := RegistryAccess{Credentials: core.Credentials{Username: "user", Password: "secret"}}
+		factoryMock := NewMockEndpointFactory(t)
+		factoryMock.EXPECT().Execute(defaultAccess).Return(nil, assert.AnError)
+		sut := NewAccessRefresher(NewFailoverRemote(nil, time.Minute), factoryMock.Execute, newMockSecretGetter(t), AccessSecrets{}, defaultAccess)
+
+		// when
+		err := sut.RefreshAccess(context.TODO())
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot create endpoints of the remote dogu registry")
+		assert.Nil(t, sut.currentAccess)
+	})
+}
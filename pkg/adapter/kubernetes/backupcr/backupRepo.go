@@ -0,0 +1,61 @@
+package backupcr
+
+import (
+	"context"
+	"fmt"
+
+	backupv1 "github.com/cloudogu/k8s-backup-lib/api/v1"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type backupRepo struct {
+	backupClient BackupInterface
+}
+
+// NewBackupRepo returns a new backupRepo to interact with the backup CR.
+func NewBackupRepo(backupClient BackupInterface) domainservice.BackupRepository {
+	return &backupRepo{backupClient: backupClient}
+}
+
+func (repo *backupRepo) Get(ctx context.Context, name string) (*ecosystem.Backup, error) {
+	cr, err := repo.backupClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, domainservice.NewNotFoundError(err, "cannot find backup CR %q", name)
+		}
+		return nil, domainservice.NewInternalError(err, "error while loading backup CR %q", name)
+	}
+
+	return &ecosystem.Backup{Name: cr.Name, Status: cr.Status.Status}, nil
+}
+
+func (repo *backupRepo) Create(ctx context.Context, backup *ecosystem.Backup) error {
+	cr := &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: backup.Name,
+			Labels: map[string]string{
+				"app":                          "ces",
+				"k8s.cloudogu.com/part-of":     "backup",
+				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+			},
+		},
+		Spec: backupv1.BackupSpec{
+			Provider: backupv1.ProviderVelero,
+		},
+	}
+
+	_, err := repo.backupClient.Create(ctx, cr, metav1.CreateOptions{})
+	if err != nil {
+		if errors.IsAlreadyExists(err) {
+			return &domainservice.ConflictError{
+				WrappedError: err,
+				Message:      fmt.Sprintf("cannot create backup CR %q as it already exists", backup.Name),
+			}
+		}
+		return domainservice.NewInternalError(err, "cannot create backup CR %q", backup.Name)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package backupcr
+
+import (
+	"context"
+	"testing"
+
+	backupv1 "github.com/cloudogu/k8s-backup-lib/api/v1"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testCtx = context.Background()
+
+func TestNewBackupRepo(t *testing.T) {
+	t.Run("should create new BackupRepo", func(t *testing.T) {
+		mBackupClient := NewMockBackupInterface(t)
+
+		repo := NewBackupRepo(mBackupClient)
+
+		assert.NotNil(t, repo)
+		assert.Equal(t, mBackupClient, repo.(*backupRepo).backupClient)
+	})
+}
+
+func Test_backupRepo_Get(t *testing.T) {
+	t.Run("should return backup", func(t *testing.T) {
+		mBackupClient := NewMockBackupInterface(t)
+		mBackupClient.EXPECT().Get(testCtx, "backup-1", metav1.GetOptions{}).Return(&backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-1"},
+			Status:     backupv1.BackupStatus{Status: backupv1.BackupStatusCompleted},
+		}, nil)
+
+		repo := &backupRepo{backupClient: mBackupClient}
+
+		backup, err := repo.Get(testCtx, "backup-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, &ecosystem.Backup{Name: "backup-1", Status: ecosystem.BackupStatusCompleted}, backup)
+	})
+
+	t.Run("should return NotFoundError if backup does not exist", func(t *testing.T) {
+		mBackupClient := NewMockBackupInterface(t)
+		mBackupClient.EXPECT().Get(testCtx, "backup-1", metav1.GetOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, "backup-1"))
+
+		repo := &backupRepo{backupClient: mBackupClient}
+
+		_, err := repo.Get(testCtx, "backup-1")
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "cannot find backup CR \"backup-1\"")
+	})
+
+	t.Run("should return InternalError on other errors", func(t *testing.T) {
+		mBackupClient := NewMockBackupInterface(t)
+		mBackupClient.EXPECT().Get(testCtx, "backup-1", metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &backupRepo{backupClient: mBackupClient}
+
+		_, err := repo.Get(testCtx, "backup-1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "error while loading backup CR \"backup-1\"")
+	})
+}
+
+func Test_backupRepo_Create(t *testing.T) {
+	expectedCR := &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "backup-1",
+			Labels: map[string]string{
+				"app":                          "ces",
+				"k8s.cloudogu.com/part-of":     "backup",
+				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+			},
+		},
+		Spec: backupv1.BackupSpec{Provider: backupv1.ProviderVelero},
+	}
+
+	t.Run("should create backup CR", func(t *testing.T) {
+		mBackupClient := NewMockBackupInterface(t)
+		mBackupClient.EXPECT().Create(testCtx, expectedCR, metav1.CreateOptions{}).Return(expectedCR, nil)
+
+		repo := &backupRepo{backupClient: mBackupClient}
+
+		err := repo.Create(testCtx, ecosystem.NewBackup("backup-1"))
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return ConflictError if backup already exists", func(t *testing.T) {
+		mBackupClient := NewMockBackupInterface(t)
+		mBackupClient.EXPECT().Create(testCtx, expectedCR, metav1.CreateOptions{}).Return(nil, k8serrors.NewAlreadyExists(schema.GroupResource{}, "backup-1"))
+
+		repo := &backupRepo{backupClient: mBackupClient}
+
+		err := repo.Create(testCtx, ecosystem.NewBackup("backup-1"))
+
+		require.Error(t, err)
+		var conflictErr *domainservice.ConflictError
+		assert.ErrorAs(t, err, &conflictErr)
+	})
+
+	t.Run("should return InternalError on other errors", func(t *testing.T) {
+		mBackupClient := NewMockBackupInterface(t)
+		mBackupClient.EXPECT().Create(testCtx, expectedCR, metav1.CreateOptions{}).Return(nil, assert.AnError)
+
+		repo := &backupRepo{backupClient: mBackupClient}
+
+		err := repo.Create(testCtx, ecosystem.NewBackup("backup-1"))
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot create backup CR \"backup-1\"")
+	})
+}
@@ -0,0 +1,18 @@
+package backupcr
+
+import (
+	"context"
+
+	backupv1 "github.com/cloudogu/k8s-backup-lib/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// interface replication for generating mocks
+
+//nolint:unused
+type BackupInterface interface {
+	// Create takes the representation of a backup and creates it.  Returns the server's representation of the backup, and an error, if there is any.
+	Create(ctx context.Context, backup *backupv1.Backup, opts metav1.CreateOptions) (*backupv1.Backup, error)
+	// Get takes name of the backup, and returns the corresponding backup object, and an error if there is any.
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*backupv1.Backup, error)
+}
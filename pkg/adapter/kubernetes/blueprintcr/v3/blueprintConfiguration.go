@@ -0,0 +1,299 @@
+package v3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+
+	serializerv2 "github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/blueprintcr/v3/serializer"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+)
+
+// blueprintAnnotationPrefix is used for all blueprint options which are not part of the blueprint CRD (yet).
+const blueprintAnnotationPrefix = "blueprint.k8s.cloudogu.com/"
+
+const (
+	// allowDoguDowngradesAnnotation maps to domain.BlueprintConfiguration.AllowDoguDowngrades.
+	allowDoguDowngradesAnnotation = blueprintAnnotationPrefix + "allow-dogu-downgrades"
+	// rolloutWavesAnnotation maps to domain.BlueprintConfiguration.RolloutWaves.
+	// The value is either rolloutWavesByDependencies or a JSON list of waves with dogu names, e.g. [["postgresql"],["redmine"]].
+	rolloutWavesAnnotation = blueprintAnnotationPrefix + "rollout-waves"
+	// requirePlanApprovalAnnotation maps to domain.BlueprintConfiguration.RequirePlanApproval.
+	requirePlanApprovalAnnotation = blueprintAnnotationPrefix + "require-plan-approval"
+	// approvedPlanAnnotation maps to domain.BlueprintConfiguration.ApprovedPlanHash.
+	approvedPlanAnnotation = blueprintAnnotationPrefix + "approved-plan"
+	// rollbackToRevisionAnnotation maps to domain.BlueprintConfiguration.RollbackToRevision.
+	// The value is the number of a revision, e.g. "3".
+	rollbackToRevisionAnnotation = blueprintAnnotationPrefix + "rollback-to-revision"
+	// autoUpgradeAnnotation maps to domain.BlueprintConfiguration.AutoUpgradePolicies.
+	// The value is a comma separated list of dogu names with their policy, e.g. "ldap=patch,postgresql=minor".
+	autoUpgradeAnnotation = blueprintAnnotationPrefix + "auto-upgrade"
+	// parametersAnnotation maps to domain.BlueprintConfiguration.Parameters.
+	// The value is a JSON object with the parameter names and their values, e.g. {"mailDomain":"example.com"}.
+	parametersAnnotation = blueprintAnnotationPrefix + "parameters"
+	// enforcementAnnotation maps to domain.BlueprintConfiguration.EnforcementModes.
+	// The value is a JSON object with the modes of dogus, dogu config and global config,
+	// e.g. {"dogus":{"redmine":"report"},"doguConfig":{"cas":{"logging/root":"report"}},"globalConfig":{"fqdn":"report"}}.
+	enforcementAnnotation = blueprintAnnotationPrefix + "enforcement"
+	// pruneDogusAnnotation maps to domain.BlueprintConfiguration.PruneDogus.
+	pruneDogusAnnotation = blueprintAnnotationPrefix + "prune-dogus"
+	// completeDependenciesAnnotation maps to domain.BlueprintConfiguration.CompleteDependencies.
+	completeDependenciesAnnotation = blueprintAnnotationPrefix + "complete-dependencies"
+	// ignoreComponentHealthAnnotation maps to domain.BlueprintConfiguration.IgnoreComponentHealth.
+	ignoreComponentHealthAnnotation = blueprintAnnotationPrefix + "ignore-component-health"
+	// priorityAnnotation maps to domain.BlueprintLayer.Priority.
+	priorityAnnotation = blueprintAnnotationPrefix + "priority"
+	// maskRefsAnnotation maps to domain.BlueprintSpec.AdditionalMasks.
+	// The value is a comma separated list of BlueprintMask names, e.g. "no-premium-dogus,site-a".
+	maskRefsAnnotation = blueprintAnnotationPrefix + "mask-refs"
+	// maskSelectorAnnotation maps to domain.BlueprintSpec.AdditionalMasks.
+	// The value is a label selector for BlueprintMasks, e.g. "k8s.cloudogu.com/site in (site-a)".
+	maskSelectorAnnotation = blueprintAnnotationPrefix + "mask-selector"
+	// maskConfigAnnotation is set on BlueprintMasks and maps to domain.BlueprintMask.Config.
+	// The value is a JSON object like the config of a blueprint, e.g. {"global":[{"key":"fqdn","value":"site-a.example.com"}]}.
+	maskConfigAnnotation = blueprintAnnotationPrefix + "config"
+)
+
+const rolloutWavesByDependencies = "dependencies"
+
+// convertBlueprintConfiguration reads the blueprint options from the spec and the annotations of the blueprint CR.
+// returns a domain.InvalidBlueprintError if an annotation has an invalid value.
+func convertBlueprintConfiguration(blueprintCR *bpv3.Blueprint) (domain.BlueprintConfiguration, error) {
+	var errs []error
+	allowDoguDowngrades, err := getBoolAnnotation(blueprintCR, allowDoguDowngradesAnnotation)
+	errs = append(errs, err)
+	rolloutWaves, err := getRolloutWavesAnnotation(blueprintCR)
+	errs = append(errs, err)
+	requirePlanApproval, err := getBoolAnnotation(blueprintCR, requirePlanApprovalAnnotation)
+	errs = append(errs, err)
+	autoUpgradePolicies, err := getAutoUpgradeAnnotation(blueprintCR)
+	errs = append(errs, err)
+	rollbackToRevision, err := getRollbackToRevisionAnnotation(blueprintCR)
+	errs = append(errs, err)
+	parameters, err := getParametersAnnotation(blueprintCR)
+	errs = append(errs, err)
+	enforcementModes, err := getEnforcementAnnotation(blueprintCR)
+	errs = append(errs, err)
+	pruneDogus, err := getBoolAnnotation(blueprintCR, pruneDogusAnnotation)
+	errs = append(errs, err)
+	completeDependencies, err := getBoolAnnotation(blueprintCR, completeDependenciesAnnotation)
+	errs = append(errs, err)
+	ignoreComponentHealth, err := getBoolAnnotation(blueprintCR, ignoreComponentHealthAnnotation)
+	errs = append(errs, err)
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return domain.BlueprintConfiguration{}, &domain.InvalidBlueprintError{
+			WrappedError: err,
+			Message:      "blueprint annotations are invalid",
+		}
+	}
+
+	return domain.BlueprintConfiguration{
+		IgnoreDoguHealth:         ptr.Deref(blueprintCR.Spec.IgnoreDoguHealth, false),
+		IgnoreComponentHealth:    ignoreComponentHealth,
+		AllowDoguNamespaceSwitch: ptr.Deref(blueprintCR.Spec.AllowDoguNamespaceSwitch, false),
+		AllowDoguDowngrades:      allowDoguDowngrades,
+		RolloutWaves:             rolloutWaves,
+		RequirePlanApproval:      requirePlanApproval,
+		ApprovedPlanHash:         strings.TrimSpace(blueprintCR.Annotations[approvedPlanAnnotation]),
+		RollbackToRevision:       rollbackToRevision,
+		AutoUpgradePolicies:      autoUpgradePolicies,
+		Parameters:               parameters,
+		EnforcementModes:         enforcementModes,
+		PruneDogus:               pruneDogus,
+		CompleteDependencies:     completeDependencies,
+		Stopped:                  ptr.Deref(blueprintCR.Spec.Stopped, false),
+	}, nil
+}
+
+func getBoolAnnotation(blueprintCR *bpv3.Blueprint, key string) (bool, error) {
+	value, exists := blueprintCR.Annotations[key]
+	if !exists {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("annotation %q must be a boolean, got %q", key, value)
+	}
+	return result, nil
+}
+
+func getRolloutWavesAnnotation(blueprintCR *bpv3.Blueprint) (domain.RolloutWaves, error) {
+	value, exists := blueprintCR.Annotations[rolloutWavesAnnotation]
+	if !exists {
+		return domain.RolloutWaves{}, nil
+	}
+	if value == rolloutWavesByDependencies {
+		return domain.RolloutWaves{ByDependencyDepth: true}, nil
+	}
+
+	var waves [][]cescommons.SimpleName
+	err := json.Unmarshal([]byte(value), &waves)
+	if err != nil {
+		return domain.RolloutWaves{}, fmt.Errorf(
+			"annotation %q must be %q or a JSON list of lists with dogu names, got %q", rolloutWavesAnnotation, rolloutWavesByDependencies, value,
+		)
+	}
+	return domain.RolloutWaves{Explicit: waves}, nil
+}
+
+func getAutoUpgradeAnnotation(blueprintCR *bpv3.Blueprint) (domain.AutoUpgradePolicies, error) {
+	value, exists := blueprintCR.Annotations[autoUpgradeAnnotation]
+	if !exists {
+		return nil, nil
+	}
+
+	policies := domain.AutoUpgradePolicies{}
+	for _, entry := range strings.Split(value, ",") {
+		doguName, policy, found := strings.Cut(strings.TrimSpace(entry), "=")
+		doguName = strings.TrimSpace(doguName)
+		policy = strings.TrimSpace(policy)
+		if !found || doguName == "" || policy == "" {
+			return nil, fmt.Errorf(
+				"annotation %q must be a comma separated list of dogu names with their policy like \"ldap=patch\", got %q", autoUpgradeAnnotation, value,
+			)
+		}
+		policies[cescommons.SimpleName(doguName)] = domain.AutoUpgradePolicy(policy)
+	}
+	return policies, nil
+}
+
+func getRollbackToRevisionAnnotation(blueprintCR *bpv3.Blueprint) (*int, error) {
+	value, exists := blueprintCR.Annotations[rollbackToRevisionAnnotation]
+	if !exists {
+		return nil, nil
+	}
+
+	revision, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("annotation %q must be a revision number, got %q", rollbackToRevisionAnnotation, value)
+	}
+	return &revision, nil
+}
+
+func getParametersAnnotation(blueprintCR *bpv3.Blueprint) (map[string]string, error) {
+	value, exists := blueprintCR.Annotations[parametersAnnotation]
+	if !exists {
+		return nil, nil
+	}
+
+	var parameters map[string]string
+	err := json.Unmarshal([]byte(value), &parameters)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %q must be a JSON object with string values, got %q", parametersAnnotation, value)
+	}
+	return parameters, nil
+}
+
+// enforcementModes is the JSON representation of domain.EnforcementModes in the enforcementAnnotation.
+type enforcementModes struct {
+	Dogus        map[string]string            `json:"dogus,omitempty"`
+	DoguConfig   map[string]map[string]string `json:"doguConfig,omitempty"`
+	GlobalConfig map[string]string            `json:"globalConfig,omitempty"`
+}
+
+func getEnforcementAnnotation(blueprintCR *bpv3.Blueprint) (domain.EnforcementModes, error) {
+	value, exists := blueprintCR.Annotations[enforcementAnnotation]
+	if !exists {
+		return domain.EnforcementModes{}, nil
+	}
+
+	var modes enforcementModes
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&modes)
+	if err != nil {
+		return domain.EnforcementModes{}, fmt.Errorf(
+			"annotation %q must be a JSON object with the enforcement modes of \"dogus\", \"doguConfig\" and \"globalConfig\", got %q", enforcementAnnotation, value,
+		)
+	}
+
+	result := domain.EnforcementModes{}
+	for doguName, mode := range modes.Dogus {
+		if result.Dogus == nil {
+			result.Dogus = map[cescommons.SimpleName]domain.EnforcementMode{}
+		}
+		result.Dogus[cescommons.SimpleName(doguName)] = domain.EnforcementMode(mode)
+	}
+	for doguName, modesByKey := range modes.DoguConfig {
+		for key, mode := range modesByKey {
+			if result.DoguConfig == nil {
+				result.DoguConfig = map[common.DoguConfigKey]domain.EnforcementMode{}
+			}
+			result.DoguConfig[common.DoguConfigKey{DoguName: cescommons.SimpleName(doguName), Key: libconfig.Key(key)}] = domain.EnforcementMode(mode)
+		}
+	}
+	for key, mode := range modes.GlobalConfig {
+		if result.GlobalConfig == nil {
+			result.GlobalConfig = map[common.GlobalConfigKey]domain.EnforcementMode{}
+		}
+		result.GlobalConfig[common.GlobalConfigKey(key)] = domain.EnforcementMode(mode)
+	}
+	return result, nil
+}
+
+func getPriorityAnnotation(blueprintCR *bpv3.Blueprint) (int, error) {
+	value, exists := blueprintCR.Annotations[priorityAnnotation]
+	if !exists {
+		return 0, nil
+	}
+
+	priority, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("annotation %q must be an integer, got %q", priorityAnnotation, value)
+	}
+	return priority, nil
+}
+
+func getMaskRefsAnnotation(blueprintCR *bpv3.Blueprint) []string {
+	value := strings.TrimSpace(blueprintCR.Annotations[maskRefsAnnotation])
+	if value == "" {
+		return nil
+	}
+
+	var maskNames []string
+	for _, maskName := range strings.Split(value, ",") {
+		if maskName = strings.TrimSpace(maskName); maskName != "" {
+			maskNames = append(maskNames, maskName)
+		}
+	}
+	return maskNames
+}
+
+func getMaskSelectorAnnotation(blueprintCR *bpv3.Blueprint) (labels.Selector, error) {
+	value, exists := blueprintCR.Annotations[maskSelectorAnnotation]
+	if !exists || strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("annotation %q must be a label selector, got %q: %w", maskSelectorAnnotation, value, err)
+	}
+	return selector, nil
+}
+
+func getMaskConfigAnnotation(maskCR *bpv3.BlueprintMask) (domain.Config, error) {
+	value, exists := maskCR.Annotations[maskConfigAnnotation]
+	if !exists || strings.TrimSpace(value) == "" {
+		return domain.Config{}, nil
+	}
+
+	var config bpv3.Config
+	err := json.Unmarshal([]byte(value), &config)
+	if err != nil {
+		return domain.Config{}, fmt.Errorf("annotation %q must be a JSON object like the config of a blueprint: %w", maskConfigAnnotation, err)
+	}
+	return serializerv2.ConvertToConfigDomain(&config), nil
+}
@@ -0,0 +1,335 @@
+package v3
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+)
+
+func Test_convertBlueprintConfiguration(t *testing.T) {
+	t.Run("defaults without spec flags and annotations", func(t *testing.T) {
+		config, err := convertBlueprintConfiguration(&bpv3.Blueprint{})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.BlueprintConfiguration{}, config)
+	})
+
+	t.Run("read spec flags and annotations", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					allowDoguDowngradesAnnotation:   "true",
+					pruneDogusAnnotation:            "true",
+					completeDependenciesAnnotation:  "true",
+					ignoreComponentHealthAnnotation: "true",
+					"unrelated":                     "annotation",
+				},
+			},
+			Spec: bpv3.BlueprintSpec{IgnoreDoguHealth: &trueVar},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.BlueprintConfiguration{IgnoreDoguHealth: true, IgnoreComponentHealth: true, AllowDoguDowngrades: true, PruneDogus: true, CompleteDependencies: true}, config)
+	})
+
+	t.Run("explicitly disabled by annotation", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{allowDoguDowngradesAnnotation: "false"},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.False(t, config.AllowDoguDowngrades)
+	})
+
+	t.Run("invalid boolean annotation", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{allowDoguDowngradesAnnotation: "maybe"},
+			},
+		}
+
+		_, err := convertBlueprintConfiguration(cr)
+
+		var invalidErr *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.ErrorContains(t, err, "blueprint annotations are invalid")
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/allow-dogu-downgrades\" must be a boolean, got \"maybe\"")
+	})
+
+	t.Run("rollout waves by dependencies", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{rolloutWavesAnnotation: "dependencies"},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.RolloutWaves{ByDependencyDepth: true}, config.RolloutWaves)
+	})
+
+	t.Run("explicit rollout waves", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{rolloutWavesAnnotation: `[["postgresql","ldap"],["cas"]]`},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.RolloutWaves{Explicit: [][]cescommons.SimpleName{{"postgresql", "ldap"}, {"cas"}}}, config.RolloutWaves)
+	})
+
+	t.Run("invalid rollout waves annotation", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{rolloutWavesAnnotation: "depth"},
+			},
+		}
+
+		_, err := convertBlueprintConfiguration(cr)
+
+		var invalidErr *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/rollout-waves\" must be \"dependencies\" or a JSON list of lists with dogu names, got \"depth\"")
+	})
+
+	t.Run("plan approval", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					requirePlanApprovalAnnotation: "true",
+					approvedPlanAnnotation:        " 0123abcd ",
+				},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.True(t, config.RequirePlanApproval)
+		assert.Equal(t, "0123abcd", config.ApprovedPlanHash)
+	})
+
+	t.Run("auto upgrade policies", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{autoUpgradeAnnotation: "ldap=patch, postgresql = minor"},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.AutoUpgradePolicies{"ldap": domain.AutoUpgradePatch, "postgresql": domain.AutoUpgradeMinor}, config.AutoUpgradePolicies)
+	})
+
+	t.Run("invalid auto upgrade annotation", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{autoUpgradeAnnotation: "ldap=patch,postgresql"},
+			},
+		}
+
+		_, err := convertBlueprintConfiguration(cr)
+
+		var invalidErr *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/auto-upgrade\" must be a comma separated list of dogu names with their policy like \"ldap=patch\", got \"ldap=patch,postgresql\"")
+	})
+
+	t.Run("rollback to revision", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{rollbackToRevisionAnnotation: " 3 "},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		require.NotNil(t, config.RollbackToRevision)
+		assert.Equal(t, 3, *config.RollbackToRevision)
+	})
+
+	t.Run("invalid rollback annotation", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{rollbackToRevisionAnnotation: "latest"},
+			},
+		}
+
+		_, err := convertBlueprintConfiguration(cr)
+
+		var invalidErr *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/rollback-to-revision\" must be a revision number, got \"latest\"")
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{parametersAnnotation: `{"mailDomain":"example.com"}`},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"mailDomain": "example.com"}, config.Parameters)
+	})
+
+	t.Run("invalid parameters annotation", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{parametersAnnotation: `{"port":25}`},
+			},
+		}
+
+		_, err := convertBlueprintConfiguration(cr)
+
+		var invalidErr *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/parameters\" must be a JSON object with string values")
+	})
+
+	t.Run("enforcement modes", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{enforcementAnnotation: `{"dogus":{"redmine":"report"},"doguConfig":{"redmine":{"logging/root":"enforce"}},"globalConfig":{"fqdn":"report"}}`},
+			},
+		}
+
+		config, err := convertBlueprintConfiguration(cr)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.EnforcementModes{
+			Dogus:        map[cescommons.SimpleName]domain.EnforcementMode{"redmine": domain.EnforcementModeReport},
+			DoguConfig:   map[common.DoguConfigKey]domain.EnforcementMode{{DoguName: "redmine", Key: "logging/root"}: domain.EnforcementModeEnforce},
+			GlobalConfig: map[common.GlobalConfigKey]domain.EnforcementMode{"fqdn": domain.EnforcementModeReport},
+		}, config.EnforcementModes)
+	})
+
+	t.Run("invalid enforcement annotation", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{enforcementAnnotation: `{"redmine":"report"}`},
+			},
+		}
+
+		_, err := convertBlueprintConfiguration(cr)
+
+		var invalidErr *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/enforcement\" must be a JSON object with the enforcement modes")
+	})
+}
+
+func Test_getMaskRefsAnnotation(t *testing.T) {
+	t.Run("no annotation", func(t *testing.T) {
+		assert.Nil(t, getMaskRefsAnnotation(&bpv3.Blueprint{}))
+	})
+	t.Run("keep order and ignore empty names", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{maskRefsAnnotation: "site-a, ,no-premium-dogus"},
+			},
+		}
+
+		assert.Equal(t, []string{"site-a", "no-premium-dogus"}, getMaskRefsAnnotation(cr))
+	})
+}
+
+func Test_getMaskSelectorAnnotation(t *testing.T) {
+	t.Run("no annotation", func(t *testing.T) {
+		selector, err := getMaskSelectorAnnotation(&bpv3.Blueprint{})
+
+		require.NoError(t, err)
+		assert.Nil(t, selector)
+	})
+	t.Run("valid selector", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{maskSelectorAnnotation: "site in (site-a),tier=prod"},
+			},
+		}
+
+		selector, err := getMaskSelectorAnnotation(cr)
+
+		require.NoError(t, err)
+		assert.Equal(t, "site in (site-a),tier=prod", selector.String())
+	})
+	t.Run("invalid selector", func(t *testing.T) {
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{maskSelectorAnnotation: "site in site-a"},
+			},
+		}
+
+		_, err := getMaskSelectorAnnotation(cr)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/mask-selector\" must be a label selector, got \"site in site-a\"")
+	})
+}
+
+func Test_getMaskConfigAnnotation(t *testing.T) {
+	t.Run("no annotation", func(t *testing.T) {
+		config, err := getMaskConfigAnnotation(&bpv3.BlueprintMask{})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.Config{}, config)
+	})
+	t.Run("dogu and global config", func(t *testing.T) {
+		mask := &bpv3.BlueprintMask{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{maskConfigAnnotation: `{
+					"dogus": {"postfix": [{"key": "relayhost", "value": "mail.site-a.example.com"}, {"key": "password", "sensitive": true, "secretRef": {"name": "site-a", "key": "relay-password"}}]},
+					"global": [{"key": "admin_group", "absent": true}]
+				}`},
+			},
+		}
+
+		config, err := getMaskConfigAnnotation(mask)
+
+		require.NoError(t, err)
+		relayHost := libconfig.Value("mail.site-a.example.com")
+		assert.Equal(t, domain.Config{
+			Dogus: domain.DoguConfig{
+				"postfix": {
+					{Key: "relayhost", Value: &relayHost},
+					{Key: "password", Sensitive: true, SecretRef: &domain.SensitiveValueRef{SecretName: "site-a", SecretKey: "relay-password"}},
+				},
+			},
+			Global: domain.GlobalConfigEntries{{Key: "admin_group", Absent: true}},
+		}, config)
+	})
+	t.Run("invalid JSON", func(t *testing.T) {
+		mask := &bpv3.BlueprintMask{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{maskConfigAnnotation: "relayhost=mail"},
+			},
+		}
+
+		_, err := getMaskConfigAnnotation(mask)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/config\" must be a JSON object like the config of a blueprint")
+	})
+}
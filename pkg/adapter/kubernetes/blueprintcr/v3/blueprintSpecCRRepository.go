@@ -4,13 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
 	serializerv2 "github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/blueprintcr/v3/serializer"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	bpv3client "github.com/cloudogu/k8s-blueprint-lib/v3/client"
@@ -70,20 +71,23 @@ func (repo *blueprintSpecRepo) GetById(ctx context.Context, blueprintId string)
 		conditions = blueprintCR.Status.Conditions
 	}
 
+	blueprintConfig, err := convertBlueprintConfiguration(blueprintCR)
+	if err != nil {
+		invalidErrorEvent := domain.BlueprintSpecInvalidEvent{ValidationError: err}
+		repo.eventRecorder.Event(blueprintCR, corev1.EventTypeWarning, invalidErrorEvent.Name(), invalidErrorEvent.Message())
+		return nil, fmt.Errorf("could not deserialize blueprint CR %q: %w", blueprintId, err)
+	}
+
 	blueprintSpec := &domain.BlueprintSpec{
 		Id:                 blueprintId,
 		DisplayName:        blueprintCR.Spec.DisplayName,
 		EffectiveBlueprint: effectiveBlueprint,
 		Conditions:         conditions,
-		Config: domain.BlueprintConfiguration{
-			IgnoreDoguHealth:         ptr.Deref(blueprintCR.Spec.IgnoreDoguHealth, false),
-			AllowDoguNamespaceSwitch: ptr.Deref(blueprintCR.Spec.AllowDoguNamespaceSwitch, false),
-			Stopped:                  ptr.Deref(blueprintCR.Spec.Stopped, false),
-		},
+		Config:             blueprintConfig,
 	}
 
 	// mask could be nil, if there is non declared
-	maskManifest, err := repo.getMaskManifest(ctx, blueprintId, blueprintCR)
+	maskManifest, maskCR, err := repo.getMaskManifest(ctx, blueprintId, blueprintCR)
 	if err != nil {
 		return nil, err
 	}
@@ -94,37 +98,178 @@ func (repo *blueprintSpecRepo) GetById(ctx context.Context, blueprintId string)
 		repo.eventRecorder.Event(blueprintCR, corev1.EventTypeWarning, invalidErrorEvent.Name(), invalidErrorEvent.Message())
 		return nil, fmt.Errorf("could not deserialize blueprint CR %q: %w", blueprintId, err)
 	}
+	if maskCR != nil {
+		blueprintSpec.BlueprintMask.Config, err = getMaskConfigAnnotation(maskCR)
+		if err != nil {
+			return nil, repo.invalidMasks(blueprintCR, blueprintId, fmt.Errorf("mask %q: %w", maskCR.Name, err), "blueprint mask is invalid")
+		}
+	}
+
+	blueprintSpec.AdditionalMasks, err = repo.getAdditionalMasks(ctx, blueprintId, blueprintCR)
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintSpec.Layers, err = repo.getLayers(ctx, blueprintCR, blueprintSpec.Blueprint)
+	if err != nil {
+		invalidErrorEvent := domain.BlueprintSpecInvalidEvent{ValidationError: err}
+		repo.eventRecorder.Event(blueprintCR, corev1.EventTypeWarning, invalidErrorEvent.Name(), invalidErrorEvent.Message())
+		return nil, fmt.Errorf("could not deserialize blueprint layers of blueprint CR %q: %w", blueprintId, err)
+	}
 
 	setPersistenceContext(blueprintCR, blueprintSpec)
 	return blueprintSpec, nil
 }
 
-func (repo *blueprintSpecRepo) getMaskManifest(ctx context.Context, blueprintId string, blueprintCR *bpv3.Blueprint) (*bpv3.BlueprintMaskManifest, error) {
-	if blueprintCR.Spec.MaskSource == nil {
+// getLayers returns all blueprint CRs in the namespace as layers, if there is more than the given blueprint CR.
+// Other blueprint CRs, which cannot be deserialized, are skipped with a warning event on them,
+// so that they do not block this blueprint.
+func (repo *blueprintSpecRepo) getLayers(ctx context.Context, blueprintCR *bpv3.Blueprint, blueprint domain.Blueprint) ([]domain.BlueprintLayer, error) {
+	list, err := repo.blueprintClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, &domainservice.InternalError{
+			WrappedError: err,
+			Message:      "error while listing blueprint resources",
+		}
+	}
+	if list == nil || len(list.Items) <= 1 {
 		return nil, nil
 	}
 
+	var layers []domain.BlueprintLayer
+	for _, layerCR := range list.Items {
+		priority, priorityErr := getPriorityAnnotation(&layerCR)
+		if layerCR.Name == blueprintCR.Name {
+			if priorityErr != nil {
+				return nil, &domain.InvalidBlueprintError{WrappedError: priorityErr, Message: "blueprint layer is invalid"}
+			}
+			layers = append(layers, domain.BlueprintLayer{Id: layerCR.Name, Priority: priority, Blueprint: blueprint})
+			continue
+		}
+
+		layerBlueprint, convertErr := serializerv2.ConvertToBlueprintDomain(layerCR.Spec.Blueprint)
+		err = errors.Join(priorityErr, convertErr)
+		if err != nil {
+			invalidErr := &domain.InvalidBlueprintError{WrappedError: err, Message: "blueprint is skipped as layer of other blueprints"}
+			log.FromContext(ctx).Error(invalidErr, "skip invalid blueprint layer", "layer", layerCR.Name)
+			invalidErrorEvent := domain.BlueprintSpecInvalidEvent{ValidationError: invalidErr}
+			repo.eventRecorder.Event(&layerCR, corev1.EventTypeWarning, invalidErrorEvent.Name(), invalidErrorEvent.Message())
+			continue
+		}
+		layers = append(layers, domain.BlueprintLayer{Id: layerCR.Name, Priority: priority, Blueprint: layerBlueprint})
+	}
+	if len(layers) <= 1 {
+		// all other blueprint CRs are invalid
+		return nil, nil
+	}
+	return layers, nil
+}
+
+// getMaskManifest returns the mask manifest of the blueprint CR and the referenced BlueprintMask, if the mask is not given inline.
+func (repo *blueprintSpecRepo) getMaskManifest(ctx context.Context, blueprintId string, blueprintCR *bpv3.Blueprint) (*bpv3.BlueprintMaskManifest, *bpv3.BlueprintMask, error) {
+	if blueprintCR.Spec.MaskSource == nil {
+		return nil, nil, nil
+	}
+
 	if blueprintCR.Spec.MaskSource.Manifest != nil && blueprintCR.Spec.MaskSource.CrRef != nil {
 		err := &domain.InvalidBlueprintError{Message: "blueprint mask and mask ref cannot be set at the same time"}
 		invalidErrorEvent := domain.BlueprintSpecInvalidEvent{ValidationError: err}
 		repo.eventRecorder.Event(blueprintCR, corev1.EventTypeWarning, invalidErrorEvent.Name(), invalidErrorEvent.Message())
-		return nil, fmt.Errorf("could not deserialize blueprint CR %q: %w", blueprintId, err)
+		return nil, nil, fmt.Errorf("could not deserialize blueprint CR %q: %w", blueprintId, err)
 	}
 
 	var maskManifest = blueprintCR.Spec.MaskSource.Manifest
 	if blueprintCR.Spec.MaskSource.CrRef != nil {
 		blueprintMask, maskErr := repo.blueprintMaskClient.Get(ctx, blueprintCR.Spec.MaskSource.CrRef.Name, metav1.GetOptions{})
 		if maskErr != nil {
-			return nil, &domainservice.NotFoundError{
+			return nil, nil, &domainservice.NotFoundError{
 				WrappedError: maskErr,
 				Message:      fmt.Sprintf("could not get blueprint mask from ref %q in blueprint %q", blueprintCR.Spec.MaskSource.CrRef.Name, blueprintId),
 				DoNotRetry:   false,
 			}
 		}
 
-		maskManifest = &blueprintMask.Spec.BlueprintMaskManifest
+		return &blueprintMask.Spec.BlueprintMaskManifest, blueprintMask, nil
+	}
+	return maskManifest, nil, nil
+}
+
+// getAdditionalMasks loads the BlueprintMasks referenced by name and the ones selected by labels in the annotations of the blueprint CR.
+// The referenced masks keep their order, followed by the selected masks ordered by their name.
+// Masks which are already used by the blueprint are skipped.
+func (repo *blueprintSpecRepo) getAdditionalMasks(ctx context.Context, blueprintId string, blueprintCR *bpv3.Blueprint) ([]domain.BlueprintMask, error) {
+	usedMaskNames := map[string]bool{}
+	if blueprintCR.Spec.MaskSource != nil && blueprintCR.Spec.MaskSource.CrRef != nil {
+		usedMaskNames[blueprintCR.Spec.MaskSource.CrRef.Name] = true
+	}
+
+	var maskCRs []bpv3.BlueprintMask
+	for _, maskName := range getMaskRefsAnnotation(blueprintCR) {
+		if usedMaskNames[maskName] {
+			continue
+		}
+		usedMaskNames[maskName] = true
+		maskCR, err := repo.blueprintMaskClient.Get(ctx, maskName, metav1.GetOptions{})
+		if err != nil {
+			return nil, &domainservice.NotFoundError{
+				WrappedError: err,
+				Message:      fmt.Sprintf("could not get blueprint mask %q referenced in blueprint %q", maskName, blueprintId),
+			}
+		}
+		maskCRs = append(maskCRs, *maskCR)
+	}
+
+	selector, err := getMaskSelectorAnnotation(blueprintCR)
+	if err != nil {
+		return nil, repo.invalidMasks(blueprintCR, blueprintId, err, "additional blueprint masks are invalid")
+	}
+	if selector != nil {
+		list, err := repo.blueprintMaskClient.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, &domainservice.InternalError{
+				WrappedError: err,
+				Message:      fmt.Sprintf("error while listing blueprint masks selected in blueprint %q", blueprintId),
+			}
+		}
+		selectedMasks := slices.SortedFunc(slices.Values(list.Items), func(a, b bpv3.BlueprintMask) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, maskCR := range selectedMasks {
+			if !usedMaskNames[maskCR.Name] {
+				usedMaskNames[maskCR.Name] = true
+				maskCRs = append(maskCRs, maskCR)
+			}
+		}
+	}
+
+	var masks []domain.BlueprintMask
+	var errs []error
+	for _, maskCR := range maskCRs {
+		mask, err := serializerv2.ConvertToBlueprintMaskDomain(&maskCR.Spec.BlueprintMaskManifest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mask %q: %w", maskCR.Name, err))
+			continue
+		}
+		mask.Name = maskCR.Name
+		mask.Config, err = getMaskConfigAnnotation(&maskCR)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mask %q: %w", maskCR.Name, err))
+			continue
+		}
+		masks = append(masks, mask)
+	}
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, repo.invalidMasks(blueprintCR, blueprintId, err, "additional blueprint masks are invalid")
 	}
-	return maskManifest, nil
+	return masks, nil
+}
+
+func (repo *blueprintSpecRepo) invalidMasks(blueprintCR *bpv3.Blueprint, blueprintId string, err error, message string) error {
+	err = &domain.InvalidBlueprintError{WrappedError: err, Message: message}
+	invalidErrorEvent := domain.BlueprintSpecInvalidEvent{ValidationError: err}
+	repo.eventRecorder.Event(blueprintCR, corev1.EventTypeWarning, invalidErrorEvent.Name(), invalidErrorEvent.Message())
+	return fmt.Errorf("could not deserialize blueprint CR %q: %w", blueprintId, err)
 }
 
 func (repo *blueprintSpecRepo) Count(ctx context.Context, limit int) (int, error) {
@@ -202,6 +347,21 @@ func (repo *blueprintSpecRepo) Update(ctx context.Context, spec *domain.Blueprin
 	return nil
 }
 
+// PublishEvents publishes the events of the blueprint on the corresponding blueprint CR without changing it.
+func (repo *blueprintSpecRepo) PublishEvents(ctx context.Context, spec *domain.BlueprintSpec) error {
+	blueprintCR, err := repo.blueprintClient.Get(ctx, spec.Id, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return domainservice.NewNotFoundError(err, "cannot publish events as blueprint CR %q does not exist", spec.Id)
+		}
+		return domainservice.NewInternalError(err, "cannot load blueprint CR %q to publish events", spec.Id)
+	}
+
+	repo.publishEvents(blueprintCR, spec.Events)
+	spec.Events = []domain.Event{}
+	return nil
+}
+
 func setPersistenceContext(blueprintCR *bpv3.Blueprint, spec *domain.BlueprintSpec) {
 	if spec.PersistenceContext == nil {
 		spec.PersistenceContext = make(map[string]interface{}, 1)
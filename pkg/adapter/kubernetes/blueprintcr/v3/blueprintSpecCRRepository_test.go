@@ -6,15 +6,18 @@ import (
 	"fmt"
 	"testing"
 
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -42,8 +45,11 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
 
 		cr := &bpv3.Blueprint{
-			TypeMeta:   metav1.TypeMeta{},
-			ObjectMeta: metav1.ObjectMeta{ResourceVersion: "abc"},
+			TypeMeta: metav1.TypeMeta{},
+			ObjectMeta: metav1.ObjectMeta{
+				ResourceVersion: "abc",
+				Annotations:     map[string]string{"blueprint.k8s.cloudogu.com/allow-dogu-downgrades": "true"},
+			},
 			Spec: bpv3.BlueprintSpec{
 				DisplayName: "MyBlueprint",
 				Blueprint:   bpv3.BlueprintManifest{},
@@ -59,6 +65,7 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 			},
 		}
 		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{*cr}}, nil)
 
 		// when
 		spec, err := repo.GetById(ctx, blueprintId)
@@ -73,6 +80,7 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 			Config: domain.BlueprintConfiguration{
 				IgnoreDoguHealth:         true,
 				AllowDoguNamespaceSwitch: true,
+				AllowDoguDowngrades:      true,
 				Stopped:                  true,
 			},
 			StateDiff:          domain.StateDiff{},
@@ -102,6 +110,7 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 			},
 		}
 		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{*cr}}, nil)
 
 		// when
 		spec, err := repo.GetById(ctx, blueprintId)
@@ -162,6 +171,36 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 		assert.ErrorContains(t, err, "blueprint mask and mask ref cannot be set at the same time")
 	})
 
+	t.Run("invalid if annotations are invalid", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				ResourceVersion: "abc",
+				Annotations:     map[string]string{"blueprint.k8s.cloudogu.com/allow-dogu-downgrades": "yes"},
+			},
+			Spec: bpv3.BlueprintSpec{
+				DisplayName: "MyBlueprint",
+				Blueprint:   bpv3.BlueprintManifest{},
+			},
+		}
+		eventRecorderMock.EXPECT().Event(cr, "Warning", "BlueprintSpecInvalid", mock.Anything)
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+
+		// when
+		_, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		require.Error(t, err)
+		var expectedErrorType *domain.InvalidBlueprintError
+		assert.ErrorAs(t, err, &expectedErrorType)
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/allow-dogu-downgrades\" must be a boolean, got \"yes\"")
+	})
+
 	t.Run("internal error when not able to get blueprint mask from ref", func(t *testing.T) {
 		// given
 		blueprintClientMock := newMockBlueprintInterface(t)
@@ -221,6 +260,7 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 			},
 		}
 		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{*cr}}, nil)
 
 		// when
 		spec, err := repo.GetById(ctx, blueprintId)
@@ -271,6 +311,7 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 			},
 		}
 		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{*cr}}, nil)
 
 		// when
 		spec, err := repo.GetById(ctx, blueprintId)
@@ -293,6 +334,141 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 		}, spec)
 	})
 
+	t.Run("all ok with additional masks", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: blueprintId,
+				Annotations: map[string]string{
+					"blueprint.k8s.cloudogu.com/mask-refs":     "site-a,my-blueprint-mask",
+					"blueprint.k8s.cloudogu.com/mask-selector": "tier=prod",
+				},
+			},
+			Spec: bpv3.BlueprintSpec{
+				MaskSource: &bpv3.MaskSource{CrRef: &bpv3.BlueprintMaskCRRef{Name: "my-blueprint-mask"}},
+			},
+		}
+		ownMask := &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-blueprint-mask",
+			Annotations: map[string]string{"blueprint.k8s.cloudogu.com/config": `{"global": [{"key": "fqdn", "absent": true}]}`},
+		}}
+		siteMask := &bpv3.BlueprintMask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "site-a",
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/config": `{"dogus": {"postfix": [{"key": "relayhost", "value": "mail"}]}}`},
+			},
+			Spec: bpv3.BlueprintMaskSpec{BlueprintMaskManifest: bpv3.BlueprintMaskManifest{Dogus: []bpv3.MaskDogu{{Name: "official/ldap", Absent: &trueVar}}}},
+		}
+		prodMaskA := bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{Name: "prod-a"}}
+		prodMaskB := bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{Name: "prod-b"}}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{*cr}}, nil)
+		maskClientMock.EXPECT().Get(ctx, "my-blueprint-mask", metav1.GetOptions{}).Return(ownMask, nil)
+		maskClientMock.EXPECT().Get(ctx, "site-a", metav1.GetOptions{}).Return(siteMask, nil)
+		maskClientMock.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: "tier=prod"}).
+			Return(&bpv3.BlueprintMaskList{Items: []bpv3.BlueprintMask{prodMaskB, *siteMask, prodMaskA}}, nil)
+
+		// when
+		spec, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, domain.GlobalConfigEntries{{Key: "fqdn", Absent: true}}, spec.BlueprintMask.Config.Global)
+		require.Len(t, spec.AdditionalMasks, 3)
+		assert.Equal(t, "site-a", spec.AdditionalMasks[0].Name)
+		assert.True(t, spec.AdditionalMasks[0].Dogus[0].Absent)
+		assert.Equal(t, libconfig.Key("relayhost"), spec.AdditionalMasks[0].Config.Dogus["postfix"][0].Key)
+		assert.Equal(t, "prod-a", spec.AdditionalMasks[1].Name)
+		assert.Equal(t, "prod-b", spec.AdditionalMasks[2].Name)
+	})
+
+	t.Run("not found error on missing additional mask", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        blueprintId,
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/mask-refs": "site-a"},
+			},
+		}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		maskClientMock.EXPECT().Get(ctx, "site-a", metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		// when
+		_, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		var expectedErrorType *domainservice.NotFoundError
+		require.ErrorAs(t, err, &expectedErrorType)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, fmt.Sprintf("could not get blueprint mask \"site-a\" referenced in blueprint %q", blueprintId))
+	})
+
+	t.Run("invalid config annotation of mask", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{Name: blueprintId},
+			Spec: bpv3.BlueprintSpec{
+				MaskSource: &bpv3.MaskSource{CrRef: &bpv3.BlueprintMaskCRRef{Name: "my-blueprint-mask"}},
+			},
+		}
+		mask := &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-blueprint-mask",
+			Annotations: map[string]string{"blueprint.k8s.cloudogu.com/config": "{"},
+		}}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		maskClientMock.EXPECT().Get(ctx, "my-blueprint-mask", metav1.GetOptions{}).Return(mask, nil)
+		eventRecorderMock.EXPECT().Event(cr, "Warning", "BlueprintSpecInvalid", mock.Anything)
+
+		// when
+		_, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		var expectedErrorType *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &expectedErrorType)
+		assert.ErrorContains(t, err, "blueprint mask is invalid")
+		assert.ErrorContains(t, err, "mask \"my-blueprint-mask\": annotation \"blueprint.k8s.cloudogu.com/config\" must be a JSON object")
+	})
+
+	t.Run("invalid mask selector", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        blueprintId,
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/mask-selector": "tier in prod"},
+			},
+		}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		eventRecorderMock.EXPECT().Event(cr, "Warning", "BlueprintSpecInvalid", mock.Anything)
+
+		// when
+		_, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		var expectedErrorType *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &expectedErrorType)
+		assert.ErrorContains(t, err, "additional blueprint masks are invalid")
+	})
+
 	t.Run("invalid blueprint and mask", func(t *testing.T) {
 		// given
 		blueprintClientMock := newMockBlueprintInterface(t)
@@ -376,6 +552,156 @@ func Test_blueprintSpecRepo_GetById(t *testing.T) {
 		assert.ErrorAs(t, err, &expectedErrorType)
 		assert.ErrorContains(t, err, fmt.Sprintf("cannot load blueprint CR %q as it does not exist:", blueprintId))
 	})
+
+	t.Run("all ok with blueprint layers", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		ldapVersion := "2.6.8-1"
+		cr := &bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{Name: blueprintId, ResourceVersion: "abc"},
+			Spec: bpv3.BlueprintSpec{
+				Blueprint: bpv3.BlueprintManifest{Dogus: []bpv3.Dogu{{Name: "official/ldap", Version: &ldapVersion}}},
+			},
+		}
+		layerCR := bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "platform-base",
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/priority": "-10"},
+			},
+			Spec: bpv3.BlueprintSpec{Blueprint: bpv3.BlueprintManifest{}},
+		}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{layerCR, *cr}}, nil)
+
+		// when
+		spec, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, spec.Layers, 2)
+		assert.Equal(t, domain.BlueprintLayer{Id: "platform-base", Priority: -10}, spec.Layers[0])
+		assert.Equal(t, domain.BlueprintLayer{Id: blueprintId, Priority: 0, Blueprint: spec.Blueprint}, spec.Layers[1])
+		assert.Equal(t, "platform-base", spec.BaseBlueprintId())
+	})
+
+	t.Run("skip invalid blueprint layers", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: blueprintId}}
+		invalidVersion := "abc"
+		invalidLayerCR := bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "team-b",
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/priority": "low"},
+			},
+			Spec: bpv3.BlueprintSpec{Blueprint: bpv3.BlueprintManifest{Dogus: []bpv3.Dogu{{Name: "official/ldap", Version: &invalidVersion}}}},
+		}
+		layerCR := bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "platform-base",
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/priority": "-10"},
+			},
+		}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{invalidLayerCR, layerCR, *cr}}, nil)
+		eventRecorderMock.EXPECT().Event(mock.Anything, "Warning", "BlueprintSpecInvalid", mock.Anything).Run(func(object runtime.Object, eventtype string, reason string, message string) {
+			assert.Equal(t, "team-b", object.(*bpv3.Blueprint).Name)
+			assert.Contains(t, message, "blueprint is skipped as layer of other blueprints")
+			assert.Contains(t, message, "annotation \"blueprint.k8s.cloudogu.com/priority\" must be an integer, got \"low\"")
+			assert.Contains(t, message, "cannot deserialize blueprint")
+		})
+
+		// when
+		spec, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, spec.Layers, 2)
+		assert.Equal(t, "platform-base", spec.Layers[0].Id)
+		assert.Equal(t, blueprintId, spec.Layers[1].Id)
+	})
+
+	t.Run("ignore layers if all other blueprints are invalid", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: blueprintId}}
+		invalidLayerCR := bpv3.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "platform-base",
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/priority": "low"},
+			},
+		}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{invalidLayerCR, *cr}}, nil)
+		eventRecorderMock.EXPECT().Event(mock.Anything, "Warning", "BlueprintSpecInvalid", mock.Anything)
+
+		// when
+		spec, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, spec.Layers)
+		assert.Equal(t, blueprintId, spec.BaseBlueprintId())
+	})
+
+	t.Run("invalid priority of the own blueprint", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{
+			Name:        blueprintId,
+			Annotations: map[string]string{"blueprint.k8s.cloudogu.com/priority": "low"},
+		}}
+		layerCR := bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: "platform-base"}}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(&bpv3.BlueprintList{Items: []bpv3.Blueprint{layerCR, *cr}}, nil)
+		eventRecorderMock.EXPECT().Event(cr, "Warning", "BlueprintSpecInvalid", mock.Anything)
+
+		// when
+		_, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		var expectedErrorType *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &expectedErrorType)
+		assert.ErrorContains(t, err, fmt.Sprintf("could not deserialize blueprint layers of blueprint CR %q", blueprintId))
+		assert.ErrorContains(t, err, "annotation \"blueprint.k8s.cloudogu.com/priority\" must be an integer, got \"low\"")
+	})
+
+	t.Run("internal error while listing blueprint layers", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		maskClientMock := newMockBlueprintMaskInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, maskClientMock, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: blueprintId}}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		blueprintClientMock.EXPECT().List(ctx, metav1.ListOptions{}).Return(nil, assert.AnError)
+		eventRecorderMock.EXPECT().Event(cr, "Warning", "BlueprintSpecInvalid", mock.Anything)
+
+		// when
+		_, err := repo.GetById(ctx, blueprintId)
+
+		// then
+		var expectedErrorType *domainservice.InternalError
+		require.ErrorAs(t, err, &expectedErrorType)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
 }
 
 func Test_blueprintSpecRepo_Update(t *testing.T) {
@@ -720,3 +1046,56 @@ func Test_blueprintSpecRepo_ListIds(t *testing.T) {
 	})
 
 }
+
+func Test_blueprintSpecRepo_PublishEvents(t *testing.T) {
+	blueprintId := "MyBlueprint"
+	events := []domain.Event{domain.DriftDetectedEvent{Drift: domain.Drift{Dogus: []cescommons.SimpleName{"redmine"}}}}
+
+	t.Run("should publish events on blueprint CR", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, nil, eventRecorderMock)
+
+		cr := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: blueprintId}}
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(cr, nil)
+		eventRecorderMock.EXPECT().Event(cr, "Normal", "DriftDetected", "drift is only reported and not corrected: dogus [redmine]")
+		spec := &domain.BlueprintSpec{Id: blueprintId, Events: events}
+
+		// when
+		err := repo.PublishEvents(ctx, spec)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, spec.Events)
+	})
+
+	t.Run("should return not found error if blueprint CR does not exist", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, nil, newMockEventRecorder(t))
+
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(nil, k8sErrors.NewNotFound(schema.GroupResource{}, blueprintId))
+
+		// when
+		err := repo.PublishEvents(ctx, &domain.BlueprintSpec{Id: blueprintId, Events: events})
+
+		// then
+		assert.True(t, domainservice.IsNotFoundError(err))
+	})
+
+	t.Run("should return internal error on other errors", func(t *testing.T) {
+		// given
+		blueprintClientMock := newMockBlueprintInterface(t)
+		repo := NewBlueprintSpecRepository(blueprintClientMock, nil, newMockEventRecorder(t))
+
+		blueprintClientMock.EXPECT().Get(ctx, blueprintId, metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		// when
+		err := repo.PublishEvents(ctx, &domain.BlueprintSpec{Id: blueprintId, Events: events})
+
+		// then
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -3,6 +3,7 @@ package serializer
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
@@ -21,6 +22,8 @@ func SerializeBlueprintAndMask(blueprintSpec *domain.BlueprintSpec, manifest bpv
 	return nil
 }
 
+// ConvertBlueprintStatus converts the effective blueprint of the status.
+// The CRD has no field for domain.Dogu.AutoAdded, so it is restored from the domain.ConditionDependenciesAutoAdded.
 func ConvertBlueprintStatus(blueprintCR *bpv3.Blueprint) (domain.EffectiveBlueprint, error) {
 	var effectiveBlueprint domain.EffectiveBlueprint
 	var err error
@@ -29,6 +32,10 @@ func ConvertBlueprintStatus(blueprintCR *bpv3.Blueprint) (domain.EffectiveBluepr
 		if err != nil {
 			return domain.EffectiveBlueprint{}, err
 		}
+		autoAddedDogus := domain.GetAutoAddedDogus(blueprintCR.Status.Conditions)
+		for i, dogu := range effectiveBlueprint.Dogus {
+			effectiveBlueprint.Dogus[i].AutoAdded = slices.Contains(autoAddedDogus, dogu.Name)
+		}
 	}
 	return effectiveBlueprint, nil
 }
@@ -127,6 +127,45 @@ func TestConvertToBlueprintDTO(t *testing.T) {
 	})
 }
 
+func TestConvertBlueprintStatus(t *testing.T) {
+	t.Run("keep auto added dogus in a round trip", func(t *testing.T) {
+		// given
+		officialDogu1 := cescommons.QualifiedName{Namespace: "official", SimpleName: "dogu1"}
+		officialDogu2 := cescommons.QualifiedName{Namespace: "official", SimpleName: "dogu2"}
+		spec := &domain.BlueprintSpec{
+			EffectiveBlueprint: domain.EffectiveBlueprint{Dogus: []domain.Dogu{{Name: officialDogu1, Version: &version3211}}},
+			Config:             domain.BlueprintConfiguration{CompleteDependencies: true},
+		}
+		err := spec.AddMissingDependencies([]domain.Dogu{{Name: officialDogu2, Version: &version1233}}, nil)
+		require.NoError(t, err)
+
+		effectiveBlueprintDTO := ConvertToBlueprintDTO(spec.EffectiveBlueprint)
+		blueprintCR := &crd.Blueprint{Status: &crd.BlueprintStatus{
+			EffectiveBlueprint: &effectiveBlueprintDTO,
+			Conditions:         spec.Conditions,
+		}}
+
+		// when
+		effectiveBlueprint, err := ConvertBlueprintStatus(blueprintCR)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, effectiveBlueprint.Dogus, 2)
+		assert.Equal(t, officialDogu1, effectiveBlueprint.Dogus[0].Name)
+		assert.False(t, effectiveBlueprint.Dogus[0].AutoAdded)
+		assert.Equal(t, officialDogu2, effectiveBlueprint.Dogus[1].Name)
+		assert.Equal(t, "1.2.3-3", effectiveBlueprint.Dogus[1].Version.Raw)
+		assert.True(t, effectiveBlueprint.Dogus[1].AutoAdded)
+	})
+
+	t.Run("no status", func(t *testing.T) {
+		effectiveBlueprint, err := ConvertBlueprintStatus(&crd.Blueprint{})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.EffectiveBlueprint{}, effectiveBlueprint)
+	})
+}
+
 func TestConvertToEffectiveBlueprintDomain(t *testing.T) {
 	t.Run("all ok", func(t *testing.T) {
 		//given
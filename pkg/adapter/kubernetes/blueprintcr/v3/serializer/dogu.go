@@ -28,16 +28,21 @@ func ConvertDogus(dogus []bpv3.Dogu) ([]domain.Dogu, error) {
 		}
 		result.Name = name
 
-		var version *core.Version
-		if dogu.Version != nil && *dogu.Version != "" {
+		if dogu.Version != nil && domain.IsVersionConstraint(*dogu.Version) {
+			constraint, err := domain.ParseVersionConstraint(*dogu.Version)
+			if err != nil {
+				errorList = append(errorList, fmt.Errorf("could not parse version constraint of target dogu %q: %w", dogu.Name, err))
+				continue
+			}
+			result.VersionConstraint = &constraint
+		} else if dogu.Version != nil && *dogu.Version != "" {
 			coreVersion, err := core.ParseVersion(*dogu.Version)
-			version = &coreVersion
 			if err != nil {
 				errorList = append(errorList, fmt.Errorf("could not parse version of target dogu %q: %w", dogu.Name, err))
 				continue
 			}
+			result.Version = &coreVersion
 		}
-		result.Version = version
 		result.Absent = ptr.Deref(dogu.Absent, false)
 
 		err = convertPlatformConfigFromDTOToDomain(&dogu, &result)
@@ -83,9 +88,34 @@ func convertPlatformConfigFromDTOToDomain(dtoDogu *bpv3.Dogu, domainDogu *domain
 		domainDogu.AdditionalMounts = additionalMounts
 	}
 
+	if dtoDogu.PlatformConfig.ReverseProxyConfig != nil {
+		reverseProxyConfig, err := convertReverseProxyConfigFromDTOToDomain(dtoDogu.PlatformConfig.ReverseProxyConfig)
+		if err != nil {
+			return fmt.Errorf("could not parse reverse proxy config for dogu %q: %w", dtoDogu.Name, err)
+		}
+		domainDogu.ReverseProxyConfig = reverseProxyConfig
+	}
+
 	return nil
 }
 
+func convertReverseProxyConfigFromDTOToDomain(config *bpv3.ReverseProxyConfig) (ecosystem.ReverseProxyConfig, error) {
+	var maxBodySize *ecosystem.BodySize
+	if config.MaxBodySize != nil {
+		var err error
+		maxBodySize, err = ecosystem.GetNonNilQuantityRef(*config.MaxBodySize)
+		if err != nil {
+			return ecosystem.ReverseProxyConfig{}, fmt.Errorf("could not parse maximum body size %q: %w", *config.MaxBodySize, err)
+		}
+	}
+
+	return ecosystem.ReverseProxyConfig{
+		MaxBodySize:      maxBodySize,
+		RewriteTarget:    ecosystem.RewriteTarget(ptr.Deref(config.RewriteTarget, "")),
+		AdditionalConfig: ecosystem.AdditionalConfig(ptr.Deref(config.AdditionalConfig, "")),
+	}, nil
+}
+
 func ConvertMaskDogus(dogus []bpv3.MaskDogu) ([]domain.MaskDogu, error) {
 	var convertedDogus []domain.MaskDogu
 	var errorList []error
@@ -140,6 +170,9 @@ func ConvertToDoguDTOs(dogus []domain.Dogu) []bpv3.Dogu {
 		var version *string
 		if dogu.Version != nil {
 			version = &dogu.Version.Raw
+		} else if dogu.VersionConstraint != nil {
+			// the constraint is not resolved yet
+			version = &dogu.VersionConstraint.Raw
 		}
 		return bpv3.Dogu{
 			Name:           dogu.Name.String(),
@@ -152,17 +185,37 @@ func ConvertToDoguDTOs(dogus []domain.Dogu) []bpv3.Dogu {
 }
 
 func convertPlatformConfigDTO(dogu domain.Dogu) *bpv3.PlatformConfig {
-	if dogu.MinVolumeSize == nil && len(dogu.AdditionalMounts) == 0 {
+	if dogu.MinVolumeSize == nil && len(dogu.AdditionalMounts) == 0 && dogu.ReverseProxyConfig.IsEmpty() {
 		return nil
 	}
 
 	config := bpv3.PlatformConfig{}
 	config.ResourceConfig = convertResourceConfigDTO(dogu)
 	config.AdditionalMountsConfig = convertAdditionalMountsConfigDTO(dogu)
+	config.ReverseProxyConfig = convertReverseProxyConfigDTO(dogu.ReverseProxyConfig)
 
 	return &config
 }
 
+func convertReverseProxyConfigDTO(config ecosystem.ReverseProxyConfig) *bpv3.ReverseProxyConfig {
+	if config.IsEmpty() {
+		return nil
+	}
+
+	var rewriteTarget, additionalConfig *string
+	if config.RewriteTarget != "" {
+		rewriteTarget = ptr.To(string(config.RewriteTarget))
+	}
+	if config.AdditionalConfig != "" {
+		additionalConfig = ptr.To(string(config.AdditionalConfig))
+	}
+	return &bpv3.ReverseProxyConfig{
+		MaxBodySize:      ecosystem.GetQuantityString(config.MaxBodySize),
+		RewriteTarget:    rewriteTarget,
+		AdditionalConfig: additionalConfig,
+	}
+}
+
 func convertResourceConfigDTO(dogu domain.Dogu) *bpv3.ResourceConfig {
 	config := bpv3.ResourceConfig{}
 	config.MinVolumeSize = ecosystem.GetQuantityString(dogu.MinVolumeSize)
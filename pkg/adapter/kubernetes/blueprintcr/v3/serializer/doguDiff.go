@@ -6,6 +6,8 @@ import (
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 )
 
+// convertToDoguDiffDTO converts the diff without its reason, as the blueprint CRD has no field for it yet.
+// The reason of pruned dogus is persisted in the domain.ConditionDogusPruned instead.
 func convertToDoguDiffDTO(domainModel domain.DoguDiff) bpv3.DoguDiff {
 	neededActions := domainModel.NeededActions
 	doguActions := make([]bpv3.DoguAction, 0, len(neededActions))
@@ -34,11 +36,12 @@ func convertToDoguDiffStateDTO(domainModel domain.DoguDiffState) bpv3.DoguDiffSt
 		}
 	}
 	return bpv3.DoguDiffState{
-		Namespace:        string(domainModel.Namespace),
-		Version:          version,
-		Absent:           domainModel.Absent,
-		ResourceConfig:   resourceConfig,
-		AdditionalMounts: convertAdditionalMountsToDoguDiffDTO(domainModel.AdditionalMounts),
+		Namespace:          string(domainModel.Namespace),
+		Version:            version,
+		Absent:             domainModel.Absent,
+		ResourceConfig:     resourceConfig,
+		ReverseProxyConfig: convertReverseProxyConfigDTO(domainModel.ReverseProxyConfig),
+		AdditionalMounts:   convertAdditionalMountsToDoguDiffDTO(domainModel.AdditionalMounts),
 	}
 }
 
@@ -3,8 +3,10 @@ package serializer
 import (
 	"testing"
 
+	"github.com/cloudogu/cesapp-lib/core"
 	crd "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 )
@@ -30,4 +32,60 @@ func Test_convertToDoguDiffStateDTO(t *testing.T) {
 		assert.NotNil(t, result.ResourceConfig)
 		assert.Empty(t, cmp.Diff(want, result))
 	})
+	t.Run("should convert reverse proxy config", func(t *testing.T) {
+		// given
+		domainDiffState := domain.DoguDiffState{
+			ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+				MaxBodySize:      &proxyBodySize,
+				AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+			},
+		}
+		// when
+		result := convertToDoguDiffStateDTO(domainDiffState)
+		// then
+		want := crd.DoguDiffState{
+			ReverseProxyConfig: &crd.ReverseProxyConfig{
+				MaxBodySize:      &proxyBodySizeString,
+				AdditionalConfig: &additionalConfig,
+			},
+		}
+
+		assert.Empty(t, cmp.Diff(want, result))
+	})
+
+	t.Run("should omit empty reverse proxy config", func(t *testing.T) {
+		// when
+		result := convertToDoguDiffStateDTO(domain.DoguDiffState{})
+		// then
+		assert.Nil(t, result.ReverseProxyConfig)
+	})
+}
+
+func Test_convertToDoguDiffDTO(t *testing.T) {
+	t.Run("should convert pruned dogu", func(t *testing.T) {
+		// given
+		version := "1.2.3-4"
+		domainDiff := domain.DoguDiff{
+			DoguName: "redmine",
+			Actual: domain.DoguDiffState{
+				Namespace: "official",
+				Version:   &core.Version{Raw: version},
+			},
+			Expected: domain.DoguDiffState{
+				Namespace: "official",
+				Absent:    true,
+			},
+			NeededActions: []domain.Action{domain.ActionUninstall},
+			Reason:        domain.DoguDiffReasonPruned,
+		}
+		// when
+		result := convertToDoguDiffDTO(domainDiff)
+		// then
+		want := crd.DoguDiff{
+			Actual:        crd.DoguDiffState{Namespace: "official", Version: &version},
+			Expected:      crd.DoguDiffState{Namespace: "official", Absent: true},
+			NeededActions: []crd.DoguAction{"uninstall"},
+		}
+		assert.Empty(t, cmp.Diff(want, result))
+	})
 }
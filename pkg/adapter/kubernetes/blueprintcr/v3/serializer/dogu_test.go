@@ -16,16 +16,18 @@ import (
 )
 
 var (
-	wrongVersion        = "1."
-	rewriteTarget       = "/"
-	additionalConfig    = "additional"
-	volumeSize          = resource.MustParse("1Gi")
-	volumeSizeString    = volumeSize.String()
-	storageClassName    = "example-storage-class"
-	proxyBodySize       = resource.MustParse("1G")
-	proxyBodySizeString = proxyBodySize.String()
-	subfolder           = "subfolder"
-	subfolder2          = "secsubfolder"
+	wrongVersion           = "1."
+	versionConstraint, _   = domain.ParseVersionConstraint(">=3.2.1-1 <4")
+	wrongVersionConstraint = "~abc"
+	rewriteTarget          = "/"
+	additionalConfig       = "additional"
+	volumeSize             = resource.MustParse("1Gi")
+	volumeSizeString       = volumeSize.String()
+	storageClassName       = "example-storage-class"
+	proxyBodySize          = resource.MustParse("1G")
+	proxyBodySizeString    = proxyBodySize.String()
+	subfolder              = "subfolder"
+	subfolder2             = "secsubfolder"
 )
 
 func TestConvertDogus(t *testing.T) {
@@ -95,6 +97,26 @@ func TestConvertDogus(t *testing.T) {
 			want:    nil,
 			wantErr: assert.Error,
 		},
+		{
+			name: "dogu with reverse proxy config",
+			args: args{dogus: []bpv3.Dogu{{Name: "official/nexus", Version: &version3211.Raw, PlatformConfig: &bpv3.PlatformConfig{ReverseProxyConfig: &bpv3.ReverseProxyConfig{
+				MaxBodySize:      &proxyBodySizeString,
+				RewriteTarget:    &rewriteTarget,
+				AdditionalConfig: &additionalConfig,
+			}}}}},
+			want: []domain.Dogu{{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "nexus"}, Version: &version3211, ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+				MaxBodySize:      &proxyBodySize,
+				RewriteTarget:    ecosystem.RewriteTarget(rewriteTarget),
+				AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+			}}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "dogu with invalid max body size",
+			args:    args{dogus: []bpv3.Dogu{{Name: "official/nexus", Version: &version3211.Raw, PlatformConfig: &bpv3.PlatformConfig{ReverseProxyConfig: &bpv3.ReverseProxyConfig{MaxBodySize: &wrongVolumeSize}}}}},
+			want:    nil,
+			wantErr: assert.Error,
+		},
 		{
 			name:    "no namespace",
 			args:    args{dogus: []bpv3.Dogu{{Name: "postgres", Version: &version3211.Raw}}},
@@ -107,6 +129,20 @@ func TestConvertDogus(t *testing.T) {
 			want:    nil,
 			wantErr: assert.Error,
 		},
+		{
+			name:    "version constraint",
+			args:    args{dogus: []bpv3.Dogu{{Name: "official/postgres", Version: &versionConstraint.Raw}}},
+			want:    []domain.Dogu{{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "postgres"}, VersionConstraint: &versionConstraint}},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "unparsable version constraint",
+			args: args{dogus: []bpv3.Dogu{{Name: "official/postgres", Version: &wrongVersionConstraint}}},
+			want: nil,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorContains(t, err, "could not parse version constraint of target dogu \"official/postgres\"")
+			},
+		},
 		{
 			name: "should convert additionalMounts",
 			args: args{dogus: []bpv3.Dogu{{
@@ -266,6 +302,23 @@ func TestConvertToDoguDTOs(t *testing.T) {
 			}}},
 			want: []bpv3.Dogu{{Name: "official/postgres", Version: &version3211.Raw, Absent: &falseVar, PlatformConfig: &bpv3.PlatformConfig{ResourceConfig: &bpv3.ResourceConfig{MinVolumeSize: &volumeSizeString, StorageClassName: &storageClassName}}}},
 		},
+		{
+			name: "resolved version constraint",
+			args: args{dogus: []domain.Dogu{{
+				Name:              cescommons.QualifiedName{Namespace: "official", SimpleName: "postgres"},
+				Version:           &version3211,
+				VersionConstraint: &versionConstraint,
+			}}},
+			want: []bpv3.Dogu{{Name: "official/postgres", Version: &version3211.Raw, Absent: &falseVar}},
+		},
+		{
+			name: "unresolved version constraint",
+			args: args{dogus: []domain.Dogu{{
+				Name:              cescommons.QualifiedName{Namespace: "official", SimpleName: "postgres"},
+				VersionConstraint: &versionConstraint,
+			}}},
+			want: []bpv3.Dogu{{Name: "official/postgres", Version: &versionConstraint.Raw, Absent: &falseVar}},
+		},
 		{
 			name: "additionalMountsConfig",
 			args: args{dogus: []domain.Dogu{{
@@ -311,6 +364,28 @@ func TestConvertToDoguDTOs(t *testing.T) {
 					},
 				}}},
 		},
+		{
+			name: "reverseProxyConfig",
+			args: args{dogus: []domain.Dogu{{
+				Name:    cescommons.QualifiedName{Namespace: "official", SimpleName: "nexus"},
+				Version: &version3211,
+				ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+					MaxBodySize:   &proxyBodySize,
+					RewriteTarget: ecosystem.RewriteTarget(rewriteTarget),
+				},
+			}}},
+			want: []bpv3.Dogu{{
+				Name:    "official/nexus",
+				Version: &version3211.Raw,
+				Absent:  &falseVar,
+				PlatformConfig: &bpv3.PlatformConfig{
+					ResourceConfig: &bpv3.ResourceConfig{},
+					ReverseProxyConfig: &bpv3.ReverseProxyConfig{
+						MaxBodySize:   &proxyBodySizeString,
+						RewriteTarget: &rewriteTarget,
+					},
+				}}},
+		},
 		{
 			name: "should return nil slice if dogu contains an nil slice",
 			args: args{dogus: []domain.Dogu{{
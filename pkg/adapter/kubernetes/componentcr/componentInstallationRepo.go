@@ -0,0 +1,75 @@
+package componentcr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ComponentResource identifies the component CRs of the k8s-component-operator.
+var ComponentResource = schema.GroupVersionResource{Group: "k8s.cloudogu.com", Version: "v1", Resource: "components"}
+
+type componentInstallationRepo struct {
+	componentClient ComponentInterface
+}
+
+// NewComponentInstallationRepo returns a new componentInstallationRepo to read the health of the installed components.
+func NewComponentInstallationRepo(componentClient ComponentInterface) domainservice.ComponentInstallationRepository {
+	return &componentInstallationRepo{componentClient: componentClient}
+}
+
+// GetAll returns all installed components. If the component CRD is not installed, there are no components to check,
+// so this is not an error.
+func (repo *componentInstallationRepo) GetAll(ctx context.Context) (map[string]*ecosystem.ComponentInstallation, error) {
+	crList, err := repo.componentClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			log.FromContext(ctx).WithName("componentInstallationRepo.GetAll").
+				Info("component CRD is not installed, there are no components to check")
+			return map[string]*ecosystem.ComponentInstallation{}, nil
+		}
+		return nil, &domainservice.InternalError{
+			WrappedError: err,
+			Message:      "error while listing component CRs",
+		}
+	}
+
+	var errs []error
+	componentInstallations := make(map[string]*ecosystem.ComponentInstallation, len(crList.Items))
+	for _, cr := range crList.Items {
+		componentInstallation, err := parseComponentCR(cr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		componentInstallations[componentInstallation.Name] = componentInstallation
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, &domainservice.InternalError{
+			WrappedError: err,
+			Message:      "failed to parse some component CRs",
+		}
+	}
+	return componentInstallations, nil
+}
+
+func parseComponentCR(cr unstructured.Unstructured) (*ecosystem.ComponentInstallation, error) {
+	health, _, err := unstructured.NestedString(cr.Object, "status", "health")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read health of component CR %q: %w", cr.GetName(), err)
+	}
+	return &ecosystem.ComponentInstallation{
+		Name:   cr.GetName(),
+		Health: ecosystem.HealthStatus(health),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package componentcr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var testCtx = context.Background()
+
+func componentCR(name string, status map[string]interface{}) unstructured.Unstructured {
+	cr := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "k8s.cloudogu.com/v1",
+		"kind":       "Component",
+		"metadata":   map[string]interface{}{"name": name},
+	}}
+	if status != nil {
+		cr.Object["status"] = status
+	}
+	return cr
+}
+
+func TestNewComponentInstallationRepo(t *testing.T) {
+	componentClientMock := NewMockComponentInterface(t)
+
+	repo := NewComponentInstallationRepo(componentClientMock)
+
+	assert.Same(t, componentClientMock, repo.(*componentInstallationRepo).componentClient)
+}
+
+func Test_componentInstallationRepo_GetAll(t *testing.T) {
+	t.Run("should return health of all components", func(t *testing.T) {
+		// given
+		componentClientMock := NewMockComponentInterface(t)
+		componentList := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+			componentCR("k8s-dogu-operator", map[string]interface{}{"health": "available"}),
+			componentCR("k8s-longhorn", map[string]interface{}{"health": "unavailable"}),
+			componentCR("k8s-cert-manager", nil),
+		}}
+		componentClientMock.EXPECT().List(testCtx, metav1.ListOptions{}).Return(componentList, nil)
+		sut := NewComponentInstallationRepo(componentClientMock)
+
+		// when
+		components, err := sut.GetAll(testCtx)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]*ecosystem.ComponentInstallation{
+			"k8s-dogu-operator": {Name: "k8s-dogu-operator", Health: ecosystem.AvailableHealthStatus},
+			"k8s-longhorn":      {Name: "k8s-longhorn", Health: ecosystem.UnavailableHealthStatus},
+			"k8s-cert-manager":  {Name: "k8s-cert-manager", Health: ecosystem.PendingHealthStatus},
+		}, components)
+	})
+	t.Run("should return no components if the component CRD is not installed", func(t *testing.T) {
+		// given
+		componentClientMock := NewMockComponentInterface(t)
+		notFoundErr := k8sErrors.NewNotFound(ComponentResource.GroupResource(), "")
+		componentClientMock.EXPECT().List(testCtx, metav1.ListOptions{}).Return(nil, notFoundErr)
+		sut := NewComponentInstallationRepo(componentClientMock)
+
+		// when
+		components, err := sut.GetAll(testCtx)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, components)
+	})
+	t.Run("should fail to list components", func(t *testing.T) {
+		// given
+		componentClientMock := NewMockComponentInterface(t)
+		componentClientMock.EXPECT().List(testCtx, metav1.ListOptions{}).Return(nil, assert.AnError)
+		sut := NewComponentInstallationRepo(componentClientMock)
+
+		// when
+		_, err := sut.GetAll(testCtx)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		var internalError *domainservice.InternalError
+		assert.ErrorAs(t, err, &internalError)
+		assert.ErrorContains(t, err, "error while listing component CRs")
+	})
+	t.Run("should fail to parse component with invalid health", func(t *testing.T) {
+		// given
+		componentClientMock := NewMockComponentInterface(t)
+		componentList := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+			componentCR("k8s-longhorn", map[string]interface{}{"health": int64(1)}),
+		}}
+		componentClientMock.EXPECT().List(testCtx, metav1.ListOptions{}).Return(componentList, nil)
+		sut := NewComponentInstallationRepo(componentClientMock)
+
+		// when
+		_, err := sut.GetAll(testCtx)
+
+		// then
+		var internalError *domainservice.InternalError
+		require.ErrorAs(t, err, &internalError)
+		assert.ErrorContains(t, err, "failed to parse some component CRs")
+		assert.ErrorContains(t, err, "cannot read health of component CR \"k8s-longhorn\"")
+	})
+}
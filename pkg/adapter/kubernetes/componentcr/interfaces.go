@@ -0,0 +1,15 @@
+package componentcr
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ComponentInterface is the part of the dynamic client which is needed to read the component CRs.
+// There is no typed client for components in this operator, therefore the CRs are read as unstructured objects.
+type ComponentInterface interface {
+	// List returns the component CRs in the namespace of the client.
+	List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+}
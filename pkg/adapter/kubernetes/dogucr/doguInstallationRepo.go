@@ -44,7 +44,7 @@ func (repo *doguInstallationRepo) GetByName(ctx context.Context, doguName cescom
 			Message:      fmt.Sprintf("error while loading dogu CR %q", doguName),
 		}
 	}
-	return parseDoguCR(cr)
+	return parseDoguCR(ctx, cr)
 }
 
 func (repo *doguInstallationRepo) GetAll(ctx context.Context) (map[cescommons.SimpleName]*ecosystem.DoguInstallation, error) {
@@ -59,7 +59,7 @@ func (repo *doguInstallationRepo) GetAll(ctx context.Context) (map[cescommons.Si
 	var errs []error
 	doguInstallations := make(map[cescommons.SimpleName]*ecosystem.DoguInstallation, len(crList.Items))
 	for _, cr := range crList.Items {
-		doguInstallation, err := parseDoguCR(&cr)
+		doguInstallation, err := parseDoguCR(ctx, &cr)
 		if err != nil {
 			errs = append(errs, err)
 			continue
@@ -1,6 +1,7 @@
 package dogucr
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,7 +15,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func parseDoguCR(cr *v2.Dogu) (*ecosystem.DoguInstallation, error) {
+// installedByBlueprintAnnotation maps to ecosystem.DoguInstallation.InstalledByBlueprint.
+// It is no label, as blueprint ids may be too long for label values.
+const installedByBlueprintAnnotation = "blueprint.k8s.cloudogu.com/installed-by"
+
+func parseDoguCR(ctx context.Context, cr *v2.Dogu) (*ecosystem.DoguInstallation, error) {
 	if cr == nil {
 		return nil, &domainservice.InternalError{
 			WrappedError: nil,
@@ -35,7 +40,6 @@ func parseDoguCR(cr *v2.Dogu) (*ecosystem.DoguInstallation, error) {
 	// for the state diff, we want the 2Gi but the consequence is,
 	// that we maybe override an empty value with the default 2Gi if we update the Dogu CR for any reason.
 	minVolumeSize, volumeSizeErr := cr.GetMinDataVolumeSize()
-
 	err := errors.Join(versionErr, nameErr, volumeSizeErr, installedVersionErr)
 	if err != nil {
 		return nil, &domainservice.InternalError{
@@ -51,17 +55,23 @@ func parseDoguCR(cr *v2.Dogu) (*ecosystem.DoguInstallation, error) {
 	}
 
 	return &ecosystem.DoguInstallation{
-		Name:               doguName,
-		Version:            version,
-		Status:             cr.Status.Status,
-		Health:             ecosystem.HealthStatus(cr.Status.Health),
-		InstalledVersion:   installedVersion,
-		StartedAt:          cr.Status.StartedAt,
-		UpgradeConfig:      ecosystem.UpgradeConfig{AllowNamespaceSwitch: cr.Spec.UpgradeConfig.AllowNamespaceSwitch},
-		MinVolumeSize:      &minVolumeSize,
-		StorageClassName:   cr.Spec.Resources.StorageClassName,
-		PersistenceContext: persistenceContext,
-		AdditionalMounts:   parseAdditionalMounts(cr.Spec.AdditionalMounts),
+		Name:             doguName,
+		Version:          version,
+		Status:           cr.Status.Status,
+		Health:           ecosystem.HealthStatus(cr.Status.Health),
+		InstalledVersion: installedVersion,
+		StartedAt:        cr.Status.StartedAt,
+		UpgradeConfig: ecosystem.UpgradeConfig{
+			AllowNamespaceSwitch: cr.Spec.UpgradeConfig.AllowNamespaceSwitch,
+			// ForceUpgrade is not read back, so that only the patch of a downgrade forces the upgrade
+			// and every later patch removes it again, see ecosystem.DoguInstallation.Downgrade
+		},
+		MinVolumeSize:        &minVolumeSize,
+		StorageClassName:     cr.Spec.Resources.StorageClassName,
+		PersistenceContext:   persistenceContext,
+		AdditionalMounts:     parseAdditionalMounts(cr.Spec.AdditionalMounts),
+		ReverseProxyConfig:   parseReverseProxyConfig(ctx, cr.Spec.AdditionalIngressAnnotations),
+		InstalledByBlueprint: cr.Annotations[installedByBlueprintAnnotation],
 	}, nil
 }
 
@@ -84,6 +94,11 @@ func toDoguCR(dogu *ecosystem.DoguInstallation) *v2.Dogu {
 		minVolumeSize = *dogu.MinVolumeSize
 	}
 
+	var annotations map[string]string
+	if dogu.InstalledByBlueprint != "" {
+		annotations = map[string]string{installedByBlueprintAnnotation: dogu.InstalledByBlueprint}
+	}
+
 	return &v2.Dogu{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
@@ -98,6 +113,7 @@ func toDoguCR(dogu *ecosystem.DoguInstallation) *v2.Dogu {
 				"app.kubernetes.io/part-of":    "ces",
 				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
 			},
+			Annotations: annotations,
 		},
 		Spec: v2.DoguSpec{
 			Name:    dogu.Name.String(),
@@ -115,7 +131,8 @@ func toDoguCR(dogu *ecosystem.DoguInstallation) *v2.Dogu {
 				AllowNamespaceSwitch: dogu.UpgradeConfig.AllowNamespaceSwitch,
 				ForceUpgrade:         false,
 			},
-			AdditionalMounts: toDoguCRAdditionalMounts(dogu.AdditionalMounts),
+			AdditionalIngressAnnotations: toIngressAnnotations(dogu.ReverseProxyConfig),
+			AdditionalMounts:             toDoguCRAdditionalMounts(dogu.AdditionalMounts),
 		},
 		Status: v2.DoguStatus{},
 	}
@@ -147,6 +164,9 @@ type doguSpecPatch struct {
 	PauseReconciliation bool               `json:"pauseReconciliation"`
 	UpgradeConfig       upgradeConfigPatch `json:"upgradeConfig"`
 	AdditionalMounts    []v2.DataMount     `json:"additionalMounts"`
+	// AdditionalIngressAnnotations only contains the set annotations of the reverse proxy config.
+	// With a merge patch, other annotations stay untouched.
+	AdditionalIngressAnnotations v2.IngressAnnotations `json:"additionalIngressAnnotations,omitempty"`
 }
 
 type upgradeConfigPatch struct {
@@ -186,10 +206,11 @@ func toDoguCRPatch(dogu *ecosystem.DoguInstallation) *doguCRPatch {
 			PauseReconciliation: dogu.PauseReconciliation,
 			UpgradeConfig: upgradeConfigPatch{
 				AllowNamespaceSwitch: dogu.UpgradeConfig.AllowNamespaceSwitch,
-				// this is a useful default as long as blueprints itself have no forceUpgrade flag implemented
-				ForceUpgrade: false,
+				// only set for downgrades, see ecosystem.DoguInstallation.Downgrade
+				ForceUpgrade: dogu.UpgradeConfig.ForceUpgrade,
 			},
-			AdditionalMounts: toDoguCRAdditionalMounts(dogu.AdditionalMounts),
+			AdditionalMounts:             toDoguCRAdditionalMounts(dogu.AdditionalMounts),
+			AdditionalIngressAnnotations: toIngressAnnotations(dogu.ReverseProxyConfig),
 		},
 	}
 }
@@ -91,6 +91,84 @@ func Test_parseDoguCR(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "blueprint which installed the dogu",
+			args: args{cr: &v2.Dogu{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "postgresql",
+					ResourceVersion: crResourceVersion,
+					Annotations:     map[string]string{"blueprint.k8s.cloudogu.com/installed-by": "my-blueprint"},
+				},
+				Spec: v2.DoguSpec{
+					Name:    "official/postgresql",
+					Version: version3214.Raw,
+				},
+			}},
+			want: &ecosystem.DoguInstallation{
+				Name:                 postgresDoguName,
+				Version:              version3214,
+				MinVolumeSize:        &defaultVolSize,
+				PersistenceContext:   persistenceContext,
+				InstalledByBlueprint: "my-blueprint",
+			},
+			wantErr: false,
+		},
+		{
+			name: "reverse proxy config",
+			args: args{cr: &v2.Dogu{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "postgresql",
+					ResourceVersion: crResourceVersion,
+				},
+				Spec: v2.DoguSpec{
+					Name:    "official/postgresql",
+					Version: version3214.Raw,
+					AdditionalIngressAnnotations: v2.IngressAnnotations{
+						"nginx.ingress.kubernetes.io/rewrite-target":        rewriteTarget,
+						"nginx.ingress.kubernetes.io/configuration-snippet": additionalConfig,
+						"nginx.ingress.kubernetes.io/other":                 "not managed by the blueprint",
+					},
+				},
+			}},
+			want: &ecosystem.DoguInstallation{
+				Name:               postgresDoguName,
+				Version:            version3214,
+				MinVolumeSize:      &defaultVolSize,
+				PersistenceContext: persistenceContext,
+				ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+					RewriteTarget:    ecosystem.RewriteTarget(rewriteTarget),
+					AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ignore unparseable max body size",
+			args: args{cr: &v2.Dogu{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "postgresql",
+					ResourceVersion: crResourceVersion,
+				},
+				Spec: v2.DoguSpec{
+					Name:    "official/postgresql",
+					Version: version3214.Raw,
+					AdditionalIngressAnnotations: v2.IngressAnnotations{
+						"nginx.ingress.kubernetes.io/proxy-body-size": "1Gi",
+						"nginx.ingress.kubernetes.io/rewrite-target":  rewriteTarget,
+					},
+				},
+			}},
+			want: &ecosystem.DoguInstallation{
+				Name:               postgresDoguName,
+				Version:            version3214,
+				MinVolumeSize:      &defaultVolSize,
+				PersistenceContext: persistenceContext,
+				ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+					RewriteTarget: ecosystem.RewriteTarget(rewriteTarget),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "cannot parse version",
 			args: args{cr: &v2.Dogu{
@@ -301,7 +379,7 @@ func Test_parseDoguCR(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseDoguCR(tt.args.cr)
+			got, err := parseDoguCR(testCtx, tt.args.cr)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseDoguCR() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -361,6 +439,34 @@ func Test_toDoguCR(t *testing.T) {
 				Status: v2.DoguStatus{},
 			},
 		},
+		{
+			name: "blueprint which installed the dogu",
+			dogu: &ecosystem.DoguInstallation{
+				Name:                 postgresDoguName,
+				Version:              version3214,
+				InstalledByBlueprint: "my-blueprint",
+			},
+			want: &v2.Dogu{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "postgresql",
+					Labels: map[string]string{
+						"app":                          "ces",
+						"k8s.cloudogu.com/app":         "ces",
+						"dogu.name":                    "postgresql",
+						"k8s.cloudogu.com/dogu.name":   "postgresql",
+						"app.kubernetes.io/name":       "postgresql",
+						"app.kubernetes.io/version":    version3214.Raw,
+						"app.kubernetes.io/part-of":    "ces",
+						"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+					},
+					Annotations: map[string]string{"blueprint.k8s.cloudogu.com/installed-by": "my-blueprint"},
+				},
+				Spec: v2.DoguSpec{
+					Name:    "official/postgresql",
+					Version: version3214.Raw,
+				},
+			},
+		},
 		{
 			name: "convert additional mounts",
 			dogu: &ecosystem.DoguInstallation{
@@ -417,6 +523,42 @@ func Test_toDoguCR(t *testing.T) {
 				Status: v2.DoguStatus{},
 			},
 		},
+		{
+			name: "convert reverse proxy config",
+			dogu: &ecosystem.DoguInstallation{
+				Name:    postgresDoguName,
+				Version: version3214,
+				ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+					MaxBodySize:      resource.NewQuantity(100*1024*1024, resource.BinarySI),
+					AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+				},
+			},
+			want: &v2.Dogu{
+				TypeMeta: metav1.TypeMeta{},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "postgresql",
+					Labels: map[string]string{
+						"app":                          "ces",
+						"k8s.cloudogu.com/app":         "ces",
+						"dogu.name":                    "postgresql",
+						"k8s.cloudogu.com/dogu.name":   "postgresql",
+						"app.kubernetes.io/name":       "postgresql",
+						"app.kubernetes.io/version":    version3214.Raw,
+						"app.kubernetes.io/part-of":    "ces",
+						"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+					},
+				},
+				Spec: v2.DoguSpec{
+					Name:    "official/postgresql",
+					Version: version3214.Raw,
+					AdditionalIngressAnnotations: v2.IngressAnnotations{
+						"nginx.ingress.kubernetes.io/proxy-body-size":       "100m",
+						"nginx.ingress.kubernetes.io/configuration-snippet": "additional",
+					},
+				},
+				Status: v2.DoguStatus{},
+			},
+		},
 		{
 			name: "set MinDataVolumeSize instead of deprecated DataVolumeSize",
 			dogu: &ecosystem.DoguInstallation{
@@ -497,6 +639,46 @@ func Test_toDoguCRPatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "reverse proxy config",
+			dogu: &ecosystem.DoguInstallation{
+				Name:    postgresDoguName,
+				Version: version3214,
+				ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+					MaxBodySize:   &proxyBodySize,
+					RewriteTarget: ecosystem.RewriteTarget(rewriteTarget),
+				},
+			},
+			want: &doguCRPatch{
+				Spec: doguSpecPatch{
+					Name:    "official/postgresql",
+					Version: version3214.Raw,
+					AdditionalIngressAnnotations: v2.IngressAnnotations{
+						"nginx.ingress.kubernetes.io/proxy-body-size": "1000000000",
+						"nginx.ingress.kubernetes.io/rewrite-target":  rewriteTarget,
+					},
+				},
+			},
+		},
+		{
+			name: "downgrade",
+			dogu: &ecosystem.DoguInstallation{
+				Name:    postgresDoguName,
+				Version: version3214,
+				UpgradeConfig: ecosystem.UpgradeConfig{
+					ForceUpgrade: true,
+				},
+			},
+			want: &doguCRPatch{
+				Spec: doguSpecPatch{
+					Name:    "official/postgresql",
+					Version: version3214.Raw,
+					UpgradeConfig: upgradeConfigPatch{
+						ForceUpgrade: true,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -564,3 +746,46 @@ func Test_toDoguCRPatchBytes(t *testing.T) {
 		})
 	}
 }
+
+func Test_toDoguCRPatchBytes_forceUpgrade(t *testing.T) {
+	downgradedCR := &v2.Dogu{
+		Spec: v2.DoguSpec{
+			Name:          "official/postgresql",
+			Version:       version3213.Raw,
+			UpgradeConfig: v2.UpgradeConfig{ForceUpgrade: true},
+		},
+	}
+
+	t.Run("should force upgrade in the patch of a downgrade", func(t *testing.T) {
+		dogu, err := parseDoguCR(testCtx, &v2.Dogu{Spec: v2.DoguSpec{Name: "official/postgresql", Version: version3214.Raw}})
+		assert.NoError(t, err)
+
+		dogu.Downgrade(&version3213)
+		patch, err := toDoguCRPatchBytes(dogu)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(patch), "\"forceUpgrade\":true")
+	})
+
+	t.Run("should not force upgrade in a volume patch after a downgrade", func(t *testing.T) {
+		dogu, err := parseDoguCR(testCtx, downgradedCR)
+		assert.NoError(t, err)
+
+		dogu.UpdateMinVolumeSize(&volSize25G)
+		patch, err := toDoguCRPatchBytes(dogu)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(patch), "\"forceUpgrade\":false")
+	})
+
+	t.Run("should not force upgrade in a reverse proxy patch after a downgrade", func(t *testing.T) {
+		dogu, err := parseDoguCR(testCtx, downgradedCR)
+		assert.NoError(t, err)
+
+		dogu.UpdateReverseProxyConfig(ecosystem.ReverseProxyConfig{RewriteTarget: ecosystem.RewriteTarget(rewriteTarget)})
+		patch, err := toDoguCRPatchBytes(dogu)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(patch), "\"forceUpgrade\":false")
+	})
+}
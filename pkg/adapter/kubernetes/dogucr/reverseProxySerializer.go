@@ -0,0 +1,110 @@
+package dogucr
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	v2 "github.com/cloudogu/k8s-dogu-lib/v2/api/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	maxBodySizeAnnotation      = "nginx.ingress.kubernetes.io/proxy-body-size"
+	rewriteTargetAnnotation    = "nginx.ingress.kubernetes.io/rewrite-target"
+	additionalConfigAnnotation = "nginx.ingress.kubernetes.io/configuration-snippet"
+)
+
+const (
+	kibibyte = 1024
+	mebibyte = 1024 * kibibyte
+	gibibyte = 1024 * mebibyte
+)
+
+// nginxSizeRegex matches sizes like nginx accepts them, e.g. "512", "100k", "10m" or "1g".
+var nginxSizeRegex = regexp.MustCompile(`^(\d+)([kKmMgG]?)$`)
+
+// parseReverseProxyConfig reads the reverse proxy config from the ingress annotations.
+// A body size which cannot be parsed was not set by the blueprint, so it is treated as unmanaged and left out.
+// Thus, the dogu can still be loaded and the annotation stays untouched unless the blueprint sets a body size.
+func parseReverseProxyConfig(ctx context.Context, annotations v2.IngressAnnotations) ecosystem.ReverseProxyConfig {
+	config := ecosystem.ReverseProxyConfig{
+		RewriteTarget:    ecosystem.RewriteTarget(annotations[rewriteTargetAnnotation]),
+		AdditionalConfig: ecosystem.AdditionalConfig(annotations[additionalConfigAnnotation]),
+	}
+
+	maxBodySize, found := annotations[maxBodySizeAnnotation]
+	if found {
+		size, err := parseNginxSize(maxBodySize)
+		if err != nil {
+			log.FromContext(ctx).WithName("parseReverseProxyConfig").
+				Info(fmt.Sprintf("ignore ingress annotation %q as it is not managed by the blueprint: %s", maxBodySizeAnnotation, err.Error()))
+		} else {
+			config.MaxBodySize = size
+		}
+	}
+	return config
+}
+
+func parseNginxSize(size string) (*ecosystem.BodySize, error) {
+	matches := nginxSizeRegex.FindStringSubmatch(size)
+	if matches == nil {
+		return nil, fmt.Errorf("%q is no valid nginx size", size)
+	}
+	value, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is no valid nginx size: %w", size, err)
+	}
+
+	switch strings.ToLower(matches[2]) {
+	case "k":
+		value *= kibibyte
+	case "m":
+		value *= mebibyte
+	case "g":
+		value *= gibibyte
+	}
+	return resource.NewQuantity(value, resource.BinarySI), nil
+}
+
+// toNginxSize converts the size to the format of nginx, which does not understand kubernetes quantities.
+// Nginx interprets the units k, m and g as binary units, so 1Mi gets "1m".
+func toNginxSize(size *ecosystem.BodySize) string {
+	value := size.Value()
+	switch {
+	case value == 0:
+		return "0"
+	case value%gibibyte == 0:
+		return fmt.Sprintf("%dg", value/gibibyte)
+	case value%mebibyte == 0:
+		return fmt.Sprintf("%dm", value/mebibyte)
+	case value%kibibyte == 0:
+		return fmt.Sprintf("%dk", value/kibibyte)
+	default:
+		return strconv.FormatInt(value, 10)
+	}
+}
+
+// toIngressAnnotations only contains the annotations of the settings which are set,
+// so that a patch leaves all other annotations untouched.
+func toIngressAnnotations(config ecosystem.ReverseProxyConfig) v2.IngressAnnotations {
+	if config.IsEmpty() {
+		return nil
+	}
+
+	annotations := v2.IngressAnnotations{}
+	if config.MaxBodySize != nil {
+		annotations[maxBodySizeAnnotation] = toNginxSize(config.MaxBodySize)
+	}
+	if config.RewriteTarget != "" {
+		annotations[rewriteTargetAnnotation] = string(config.RewriteTarget)
+	}
+	if config.AdditionalConfig != "" {
+		annotations[additionalConfigAnnotation] = string(config.AdditionalConfig)
+	}
+	return annotations
+}
@@ -0,0 +1,96 @@
+package dogucr
+
+import (
+	"testing"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	v2 "github.com/cloudogu/k8s-dogu-lib/v2/api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_parseReverseProxyConfig(t *testing.T) {
+	t.Run("no annotations", func(t *testing.T) {
+		config := parseReverseProxyConfig(testCtx, nil)
+
+		assert.True(t, config.IsEmpty())
+	})
+
+	t.Run("all annotations", func(t *testing.T) {
+		config := parseReverseProxyConfig(testCtx, v2.IngressAnnotations{
+			"nginx.ingress.kubernetes.io/proxy-body-size":       "100m",
+			"nginx.ingress.kubernetes.io/rewrite-target":        rewriteTarget,
+			"nginx.ingress.kubernetes.io/configuration-snippet": additionalConfig,
+		})
+
+		require.NotNil(t, config.MaxBodySize)
+		assert.Equal(t, 0, config.MaxBodySize.Cmp(resource.MustParse("100Mi")))
+		assert.Equal(t, ecosystem.RewriteTarget(rewriteTarget), config.RewriteTarget)
+		assert.Equal(t, ecosystem.AdditionalConfig(additionalConfig), config.AdditionalConfig)
+	})
+
+	t.Run("ignore invalid body size", func(t *testing.T) {
+		config := parseReverseProxyConfig(testCtx, v2.IngressAnnotations{
+			"nginx.ingress.kubernetes.io/proxy-body-size":       "100MB",
+			"nginx.ingress.kubernetes.io/configuration-snippet": additionalConfig,
+		})
+
+		assert.Nil(t, config.MaxBodySize)
+		assert.Equal(t, ecosystem.AdditionalConfig(additionalConfig), config.AdditionalConfig)
+	})
+}
+
+func Test_parseNginxSize(t *testing.T) {
+	tests := []struct {
+		size string
+		want string
+	}{
+		{size: "0", want: "0"},
+		{size: "512", want: "512"},
+		{size: "8k", want: "8Ki"},
+		{size: "100M", want: "100Mi"},
+		{size: "1g", want: "1Gi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := parseNginxSize(tt.size)
+
+			require.NoError(t, err)
+			assert.Equal(t, 0, got.Cmp(resource.MustParse(tt.want)))
+		})
+	}
+}
+
+func Test_toNginxSize(t *testing.T) {
+	tests := []struct {
+		size string
+		want string
+	}{
+		{size: "0", want: "0"},
+		{size: "1000", want: "1000"},
+		{size: "8Ki", want: "8k"},
+		{size: "100Mi", want: "100m"},
+		{size: "1024Mi", want: "1g"},
+		{size: "1G", want: "1000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			size := resource.MustParse(tt.size)
+
+			assert.Equal(t, tt.want, toNginxSize(&size))
+		})
+	}
+}
+
+func Test_toIngressAnnotations(t *testing.T) {
+	t.Run("nil without reverse proxy config", func(t *testing.T) {
+		assert.Nil(t, toIngressAnnotations(ecosystem.ReverseProxyConfig{}))
+	})
+
+	t.Run("only set values", func(t *testing.T) {
+		annotations := toIngressAnnotations(ecosystem.ReverseProxyConfig{RewriteTarget: ecosystem.RewriteTarget(rewriteTarget)})
+
+		assert.Equal(t, v2.IngressAnnotations{"nginx.ingress.kubernetes.io/rewrite-target": rewriteTarget}, annotations)
+	})
+}
@@ -0,0 +1,169 @@
+package outdatedcm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	reportNamePrefix = "blueprint-outdated-dogus-"
+
+	// the blueprint id is no label as it may be too long for a label value and no data entry as the data keys are the dogu names
+	blueprintIdAnnotation = "blueprint.k8s.cloudogu.com/blueprint-id"
+)
+
+type outdatedRepo struct {
+	configMapClient configMapClient
+	blueprintClient blueprintInterface
+}
+
+// NewOutdatedRepo returns a new outdatedRepo which stores the outdated report of each blueprint in a config map.
+// The config map contains one entry per dogu, so that admins can look up single dogus easily.
+func NewOutdatedRepo(configMapClient configMapClient, blueprintClient blueprintInterface) domainservice.OutdatedReportRepository {
+	return &outdatedRepo{configMapClient: configMapClient, blueprintClient: blueprintClient}
+}
+
+// doguUpdatesDTO is the serializable form of domain.DoguUpdates.
+type doguUpdatesDTO struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Patch   string `json:"patch,omitempty"`
+	Minor   string `json:"minor,omitempty"`
+	Major   string `json:"major,omitempty"`
+}
+
+func (repo *outdatedRepo) Get(ctx context.Context, blueprintId string) (domain.OutdatedReport, error) {
+	name := getReportName(blueprintId)
+	configMap, err := repo.configMapClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return domain.OutdatedReport{}, domainservice.NewNotFoundError(err, "cannot find outdated report of blueprint %q", blueprintId)
+		}
+		return domain.OutdatedReport{}, domainservice.NewInternalError(err, "error while loading outdated report config map %q", name)
+	}
+
+	var report domain.OutdatedReport
+	for key, value := range configMap.Data {
+		doguUpdates, err := toDoguUpdates(value)
+		if err != nil {
+			return domain.OutdatedReport{}, domainservice.NewInternalError(err, "cannot deserialize outdated report of dogu %q of blueprint %q", key, blueprintId)
+		}
+		report.Dogus = append(report.Dogus, doguUpdates)
+	}
+	slices.SortFunc(report.Dogus, func(a, b domain.DoguUpdates) int {
+		return strings.Compare(string(a.Name.SimpleName), string(b.Name.SimpleName))
+	})
+	return report, nil
+}
+
+func (repo *outdatedRepo) Update(ctx context.Context, blueprintId string, report domain.OutdatedReport) error {
+	data := map[string]string{}
+	for _, doguUpdates := range report.Dogus {
+		serializedUpdates, err := json.Marshal(toDoguUpdatesDTO(doguUpdates))
+		if err != nil {
+			return domainservice.NewInternalError(err, "cannot serialize outdated report of dogu %q of blueprint %q", doguUpdates.Name, blueprintId)
+		}
+		data[string(doguUpdates.Name.SimpleName)] = string(serializedUpdates)
+	}
+	blueprint, err := repo.blueprintClient.Get(ctx, blueprintId, metav1.GetOptions{})
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot load blueprint %q to set it as owner of its outdated report", blueprintId)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: getReportName(blueprintId),
+			// the report is worthless without the blueprint, so it gets garbage collected with it
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: bpv3.GroupVersion.String(),
+				Kind:       "Blueprint",
+				Name:       blueprint.Name,
+				UID:        blueprint.UID,
+			}},
+			Labels: map[string]string{
+				"app":                          "ces",
+				"k8s.cloudogu.com/part-of":     "blueprint-outdated-dogus",
+				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+			},
+			Annotations: map[string]string{blueprintIdAnnotation: blueprintId},
+		},
+		Data: data,
+	}
+
+	_, err = repo.configMapClient.Update(ctx, configMap, metav1.UpdateOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = repo.configMapClient.Create(ctx, configMap, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot save outdated report config map %q", configMap.Name)
+	}
+	return nil
+}
+
+func toDoguUpdatesDTO(updates domain.DoguUpdates) doguUpdatesDTO {
+	rawVersion := func(version *core.Version) string {
+		if version == nil {
+			return ""
+		}
+		return version.Raw
+	}
+	return doguUpdatesDTO{
+		Name:    updates.Name.String(),
+		Version: updates.Version.Raw,
+		Patch:   rawVersion(updates.Patch),
+		Minor:   rawVersion(updates.Minor),
+		Major:   rawVersion(updates.Major),
+	}
+}
+
+func toDoguUpdates(serializedUpdates string) (domain.DoguUpdates, error) {
+	var dto doguUpdatesDTO
+	err := json.Unmarshal([]byte(serializedUpdates), &dto)
+	if err != nil {
+		return domain.DoguUpdates{}, err
+	}
+
+	name, err := cescommons.QualifiedNameFromString(dto.Name)
+	if err != nil {
+		return domain.DoguUpdates{}, err
+	}
+	version, err := core.ParseVersion(dto.Version)
+	if err != nil {
+		return domain.DoguUpdates{}, fmt.Errorf("cannot parse version: %w", err)
+	}
+	patch, patchErr := parseOptionalVersion(dto.Patch)
+	minor, minorErr := parseOptionalVersion(dto.Minor)
+	major, majorErr := parseOptionalVersion(dto.Major)
+	err = errors.Join(patchErr, minorErr, majorErr)
+	if err != nil {
+		return domain.DoguUpdates{}, fmt.Errorf("cannot parse newer releases: %w", err)
+	}
+	return domain.DoguUpdates{Name: name, Version: version, Patch: patch, Minor: minor, Major: major}, nil
+}
+
+func parseOptionalVersion(raw string) (*core.Version, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	version, err := core.ParseVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func getReportName(blueprintId string) string {
+	return fmt.Sprintf("%s%s", reportNamePrefix, blueprintId)
+}
@@ -0,0 +1,226 @@
+package outdatedcm
+
+import (
+	"context"
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testCtx = context.Background()
+
+const (
+	blueprintId = "my-blueprint"
+	name        = "blueprint-outdated-dogus-my-blueprint"
+
+	serializedLdap    = `{"name":"official/ldap","version":"2.6.7-1"}`
+	serializedRedmine = `{"name":"official/redmine","version":"5.1.3-1","patch":"5.1.3-2","major":"6.0.0-1"}`
+)
+
+var (
+	ldapVersion, _    = core.ParseVersion("2.6.7-1")
+	redmineVersion, _ = core.ParseVersion("5.1.3-1")
+	redminePatch, _   = core.ParseVersion("5.1.3-2")
+	redmineMajor, _   = core.ParseVersion("6.0.0-1")
+
+	testReport = domain.OutdatedReport{Dogus: []domain.DoguUpdates{
+		{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "ldap"}, Version: ldapVersion},
+		{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "redmine"}, Version: redmineVersion, Patch: &redminePatch, Major: &redmineMajor},
+	}}
+)
+
+func TestNewOutdatedRepo(t *testing.T) {
+	t.Run("should create new OutdatedRepo", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		bpClient := newMockBlueprintInterface(t)
+
+		repo := NewOutdatedRepo(mClient, bpClient)
+
+		assert.NotNil(t, repo)
+		assert.Equal(t, mClient, repo.(*outdatedRepo).configMapClient)
+		assert.Equal(t, bpClient, repo.(*outdatedRepo).blueprintClient)
+	})
+}
+
+func Test_outdatedRepo_Get(t *testing.T) {
+	t.Run("should return report", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{"blueprint.k8s.cloudogu.com/blueprint-id": blueprintId},
+			},
+			Data: map[string]string{
+				"redmine": serializedRedmine,
+				"ldap":    serializedLdap,
+			},
+		}, nil)
+
+		repo := &outdatedRepo{configMapClient: mClient}
+
+		report, err := repo.Get(testCtx, blueprintId)
+
+		require.NoError(t, err)
+		assert.Equal(t, testReport, report)
+	})
+
+	t.Run("should return dogu with the name blueprintId", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data: map[string]string{
+				"blueprintId": `{"name":"k8s/blueprintId","version":"1.0.0-1"}`,
+			},
+		}, nil)
+
+		repo := &outdatedRepo{configMapClient: mClient}
+
+		report, err := repo.Get(testCtx, blueprintId)
+
+		require.NoError(t, err)
+		require.Len(t, report.Dogus, 1)
+		assert.Equal(t, "k8s/blueprintId", report.Dogus[0].Name.String())
+		assert.Equal(t, "1.0.0-1", report.Dogus[0].Version.Raw)
+	})
+
+	t.Run("should return NotFoundError if report does not exist", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+
+		repo := &outdatedRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, blueprintId)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "cannot find outdated report of blueprint \"my-blueprint\"")
+	})
+
+	t.Run("should return InternalError on other errors", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &outdatedRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, blueprintId)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should return InternalError if report cannot be deserialized", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data: map[string]string{
+				"redmine": `{"name":"official/redmine","version":"5.1.3-1","patch":"a.b.c"}`,
+			},
+		}, nil)
+
+		repo := &outdatedRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, blueprintId)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot deserialize outdated report of dogu \"redmine\" of blueprint \"my-blueprint\"")
+	})
+}
+
+func Test_outdatedRepo_Update(t *testing.T) {
+	blueprint := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: blueprintId, UID: "c0ffee"}}
+	expectedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "k8s.cloudogu.com/v3",
+				Kind:       "Blueprint",
+				Name:       blueprintId,
+				UID:        "c0ffee",
+			}},
+			Labels: map[string]string{
+				"app":                          "ces",
+				"k8s.cloudogu.com/part-of":     "blueprint-outdated-dogus",
+				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+			},
+			Annotations: map[string]string{"blueprint.k8s.cloudogu.com/blueprint-id": blueprintId},
+		},
+		Data: map[string]string{
+			"ldap":    serializedLdap,
+			"redmine": serializedRedmine,
+		},
+	}
+
+	t.Run("should update report", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Update(testCtx, expectedConfigMap, metav1.UpdateOptions{}).Return(expectedConfigMap, nil)
+
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &outdatedRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, testReport)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should create report if it does not exist", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Update(testCtx, expectedConfigMap, metav1.UpdateOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+		mClient.EXPECT().Create(testCtx, expectedConfigMap, metav1.CreateOptions{}).Return(expectedConfigMap, nil)
+
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &outdatedRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, testReport)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return InternalError on error", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Update(testCtx, mock.Anything, metav1.UpdateOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+		mClient.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, assert.AnError)
+
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &outdatedRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, domain.OutdatedReport{})
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot save outdated report config map \"blueprint-outdated-dogus-my-blueprint\"")
+	})
+
+	t.Run("should return InternalError if the blueprint cannot be loaded", func(t *testing.T) {
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &outdatedRepo{configMapClient: newMockConfigMapClient(t), blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, testReport)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load blueprint \"my-blueprint\" to set it as owner of its outdated report")
+	})
+}
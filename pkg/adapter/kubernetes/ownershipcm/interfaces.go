@@ -0,0 +1,14 @@
+package ownershipcm
+
+import (
+	bpv3client "github.com/cloudogu/k8s-blueprint-lib/v3/client"
+	k8sv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type configMapClient interface {
+	k8sv1.ConfigMapInterface
+}
+
+type blueprintInterface interface {
+	bpv3client.BlueprintInterface
+}
@@ -0,0 +1,149 @@
+package ownershipcm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ownershipNamePrefix = "blueprint-config-ownership-"
+
+	blueprintIdKey = "blueprintId"
+	ownershipKey   = "ownership"
+)
+
+type ownershipRepo struct {
+	configMapClient configMapClient
+	blueprintClient blueprintInterface
+}
+
+// NewOwnershipRepo returns a new ownershipRepo which stores the config ownership of each blueprint in a config map.
+// The config map is owned by the blueprint, so that it gets deleted together with the blueprint.
+func NewOwnershipRepo(configMapClient configMapClient, blueprintClient blueprintInterface) domainservice.ConfigOwnershipRepository {
+	return &ownershipRepo{configMapClient: configMapClient, blueprintClient: blueprintClient}
+}
+
+// ownershipDTO is the serializable form of domain.ConfigOwnership.
+type ownershipDTO struct {
+	Dogus          map[string][]string `json:"dogus,omitempty"`
+	SensitiveDogus map[string][]string `json:"sensitiveDogus,omitempty"`
+	Global         []string            `json:"global,omitempty"`
+}
+
+func (repo *ownershipRepo) Get(ctx context.Context, blueprintId string) (domain.ConfigOwnership, error) {
+	name := getOwnershipName(blueprintId)
+	configMap, err := repo.configMapClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return domain.ConfigOwnership{}, domainservice.NewNotFoundError(err, "cannot find config ownership of blueprint %q", blueprintId)
+		}
+		return domain.ConfigOwnership{}, domainservice.NewInternalError(err, "error while loading config ownership config map %q", name)
+	}
+
+	var dto ownershipDTO
+	err = json.Unmarshal([]byte(configMap.Data[ownershipKey]), &dto)
+	if err != nil {
+		return domain.ConfigOwnership{}, domainservice.NewInternalError(err, "cannot deserialize config ownership of blueprint %q", blueprintId)
+	}
+	// NewConfigOwnership sorts the keys like they were before serialization
+	return domain.NewConfigOwnership(toConfig(dto)), nil
+}
+
+func (repo *ownershipRepo) Update(ctx context.Context, blueprintId string, ownership domain.ConfigOwnership) error {
+	serializedOwnership, err := json.Marshal(toOwnershipDTO(ownership))
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot serialize config ownership of blueprint %q", blueprintId)
+	}
+	blueprint, err := repo.blueprintClient.Get(ctx, blueprintId, metav1.GetOptions{})
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot load blueprint %q to set it as owner of its config ownership", blueprintId)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: getOwnershipName(blueprintId),
+			// the ownership is worthless without the blueprint, so it gets garbage collected with it
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: bpv3.GroupVersion.String(),
+				Kind:       "Blueprint",
+				Name:       blueprint.Name,
+				UID:        blueprint.UID,
+			}},
+			Labels: map[string]string{
+				"app":                          "ces",
+				"k8s.cloudogu.com/part-of":     "blueprint-config-ownership",
+				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+			},
+		},
+		Data: map[string]string{
+			blueprintIdKey: blueprintId,
+			ownershipKey:   string(serializedOwnership),
+		},
+	}
+
+	_, err = repo.configMapClient.Update(ctx, configMap, metav1.UpdateOptions{})
+	if errors.IsNotFound(err) {
+		_, err = repo.configMapClient.Create(ctx, configMap, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot save config ownership config map %q", configMap.Name)
+	}
+	return nil
+}
+
+func toOwnershipDTO(ownership domain.ConfigOwnership) ownershipDTO {
+	dto := ownershipDTO{
+		Dogus:          toDoguKeysDTO(ownership.DoguConfig),
+		SensitiveDogus: toDoguKeysDTO(ownership.SensitiveDoguConfig),
+	}
+	for _, key := range ownership.GlobalConfig {
+		dto.Global = append(dto.Global, string(key))
+	}
+	return dto
+}
+
+func toDoguKeysDTO(keys []common.DoguConfigKey) map[string][]string {
+	if len(keys) == 0 {
+		return nil
+	}
+	dto := map[string][]string{}
+	for _, key := range keys {
+		dto[string(key.DoguName)] = append(dto[string(key.DoguName)], string(key.Key))
+	}
+	return dto
+}
+
+// toConfig converts the dto to a config which sets all owned keys, so that it can be used with domain.NewConfigOwnership.
+func toConfig(dto ownershipDTO) domain.Config {
+	blueprintConfig := domain.Config{Dogus: map[cescommons.SimpleName]domain.DoguConfigEntries{}}
+	addDoguKeys := func(keysByDogu map[string][]string, sensitive bool) {
+		for doguName, keys := range keysByDogu {
+			for _, key := range keys {
+				blueprintConfig.Dogus[cescommons.SimpleName(doguName)] = append(blueprintConfig.Dogus[cescommons.SimpleName(doguName)], domain.ConfigEntry{
+					Key:       config.Key(key),
+					Sensitive: sensitive,
+				})
+			}
+		}
+	}
+	addDoguKeys(dto.Dogus, false)
+	addDoguKeys(dto.SensitiveDogus, true)
+	for _, key := range dto.Global {
+		blueprintConfig.Global = append(blueprintConfig.Global, domain.ConfigEntry{Key: config.Key(key)})
+	}
+	return blueprintConfig
+}
+
+func getOwnershipName(blueprintId string) string {
+	return fmt.Sprintf("%s%s", ownershipNamePrefix, blueprintId)
+}
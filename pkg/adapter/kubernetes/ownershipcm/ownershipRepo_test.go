@@ -0,0 +1,197 @@
+package ownershipcm
+
+import (
+	"context"
+	"testing"
+
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testCtx = context.Background()
+
+const (
+	blueprintId = "my-blueprint"
+	name        = "blueprint-config-ownership-my-blueprint"
+
+	serializedOwnership = `{"dogus":{"ldap":["container_config/memory_limit","password_change/notification_enabled"],"redmine":["logging/root"]},"sensitiveDogus":{"redmine":["password"]},"global":["admin_group","fqdn"]}`
+)
+
+var testOwnership = domain.ConfigOwnership{
+	DoguConfig: []common.DoguConfigKey{
+		{DoguName: "ldap", Key: "container_config/memory_limit"},
+		{DoguName: "ldap", Key: "password_change/notification_enabled"},
+		{DoguName: "redmine", Key: "logging/root"},
+	},
+	SensitiveDoguConfig: []common.DoguConfigKey{{DoguName: "redmine", Key: "password"}},
+	GlobalConfig:        []common.GlobalConfigKey{"admin_group", "fqdn"},
+}
+
+func TestNewOwnershipRepo(t *testing.T) {
+	t.Run("should create new OwnershipRepo", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		bpClient := newMockBlueprintInterface(t)
+
+		repo := NewOwnershipRepo(mClient, bpClient)
+
+		assert.NotNil(t, repo)
+		assert.Equal(t, mClient, repo.(*ownershipRepo).configMapClient)
+		assert.Equal(t, bpClient, repo.(*ownershipRepo).blueprintClient)
+	})
+}
+
+func Test_ownershipRepo_Get(t *testing.T) {
+	t.Run("should return ownership", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data: map[string]string{
+				"blueprintId": blueprintId,
+				"ownership":   serializedOwnership,
+			},
+		}, nil)
+
+		repo := &ownershipRepo{configMapClient: mClient}
+
+		ownership, err := repo.Get(testCtx, blueprintId)
+
+		require.NoError(t, err)
+		assert.Equal(t, testOwnership, ownership)
+	})
+
+	t.Run("should return NotFoundError if ownership does not exist", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+
+		repo := &ownershipRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, blueprintId)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "cannot find config ownership of blueprint \"my-blueprint\"")
+	})
+
+	t.Run("should return InternalError on other errors", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &ownershipRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, blueprintId)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should return InternalError if ownership cannot be deserialized", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data:       map[string]string{"ownership": "{"},
+		}, nil)
+
+		repo := &ownershipRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, blueprintId)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot deserialize config ownership of blueprint \"my-blueprint\"")
+	})
+}
+
+func Test_ownershipRepo_Update(t *testing.T) {
+	blueprint := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: blueprintId, UID: "c0ffee"}}
+	expectedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "k8s.cloudogu.com/v3",
+				Kind:       "Blueprint",
+				Name:       blueprintId,
+				UID:        "c0ffee",
+			}},
+			Labels: map[string]string{
+				"app":                          "ces",
+				"k8s.cloudogu.com/part-of":     "blueprint-config-ownership",
+				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+			},
+		},
+		Data: map[string]string{
+			"blueprintId": blueprintId,
+			"ownership":   serializedOwnership,
+		},
+	}
+
+	t.Run("should update ownership", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Update(testCtx, expectedConfigMap, metav1.UpdateOptions{}).Return(expectedConfigMap, nil)
+
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &ownershipRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, testOwnership)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should create ownership if it does not exist", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Update(testCtx, expectedConfigMap, metav1.UpdateOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+		mClient.EXPECT().Create(testCtx, expectedConfigMap, metav1.CreateOptions{}).Return(expectedConfigMap, nil)
+
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &ownershipRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, testOwnership)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return InternalError on error", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Update(testCtx, mock.Anything, metav1.UpdateOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+		mClient.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, assert.AnError)
+
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &ownershipRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, domain.ConfigOwnership{})
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot save config ownership config map \"blueprint-config-ownership-my-blueprint\"")
+	})
+
+	t.Run("should return InternalError if the blueprint cannot be loaded", func(t *testing.T) {
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, blueprintId, metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &ownershipRepo{configMapClient: newMockConfigMapClient(t), blueprintClient: bpClient}
+
+		err := repo.Update(testCtx, blueprintId, testOwnership)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load blueprint \"my-blueprint\" to set it as owner of its config ownership")
+	})
+}
@@ -0,0 +1,74 @@
+package plancm
+
+import (
+	"context"
+	"crypto/rand"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	planKeySecretName = "blueprint-plan-key"
+	planKeyDataKey    = "key"
+	planKeyLength     = 32
+)
+
+type planKeyRepo struct {
+	secretClient secretClient
+}
+
+// NewPlanKeyRepo returns a new planKeyRepo which stores the key to hash sensitive plan values in an immutable secret.
+func NewPlanKeyRepo(secretClient secretClient) domainservice.PlanKeyRepository {
+	return &planKeyRepo{secretClient: secretClient}
+}
+
+func (repo *planKeyRepo) GetOrCreate(ctx context.Context) ([]byte, error) {
+	key, err := repo.get(ctx)
+	if err == nil || !errors.IsNotFound(err) {
+		return key, wrapGetKeyError(err)
+	}
+
+	key = make([]byte, planKeyLength)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, domainservice.NewInternalError(err, "cannot generate plan key")
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: planKeySecretName, Labels: getLabels()},
+		Immutable:  ptr.To(true),
+		Data:       map[string][]byte{planKeyDataKey: key},
+	}
+	_, err = repo.secretClient.Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		if errors.IsAlreadyExists(err) {
+			// another reconciliation created the key concurrently
+			key, err = repo.get(ctx)
+			return key, wrapGetKeyError(err)
+		}
+		return nil, domainservice.NewInternalError(err, "cannot create plan key secret %q", planKeySecretName)
+	}
+	return key, nil
+}
+
+func (repo *planKeyRepo) get(ctx context.Context) ([]byte, error) {
+	secret, err := repo.secretClient.Get(ctx, planKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	key := secret.Data[planKeyDataKey]
+	if len(key) == 0 {
+		return nil, domainservice.NewInternalError(nil, "plan key secret %q does not contain a key", planKeySecretName)
+	}
+	return key, nil
+}
+
+func wrapGetKeyError(err error) error {
+	if err == nil || domainservice.IsInternalError(err) {
+		return err
+	}
+	return domainservice.NewInternalError(err, "cannot load plan key secret %q", planKeySecretName)
+}
@@ -0,0 +1,116 @@
+package plancm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewPlanKeyRepo(t *testing.T) {
+	t.Run("should create new PlanKeyRepo", func(t *testing.T) {
+		mClient := newMockSecretClient(t)
+
+		repo := NewPlanKeyRepo(mClient)
+
+		assert.NotNil(t, repo)
+		assert.Equal(t, mClient, repo.(*planKeyRepo).secretClient)
+	})
+}
+
+func Test_planKeyRepo_GetOrCreate(t *testing.T) {
+	notFoundErr := k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "blueprint-plan-key")
+	alreadyExistsErr := k8serrors.NewAlreadyExists(schema.GroupResource{Resource: "secrets"}, "blueprint-plan-key")
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "blueprint-plan-key"},
+		Data:       map[string][]byte{"key": []byte("my-key")},
+	}
+
+	t.Run("should return existing key", func(t *testing.T) {
+		mClient := newMockSecretClient(t)
+		mClient.EXPECT().Get(testCtx, "blueprint-plan-key", metav1.GetOptions{}).Return(keySecret, nil)
+		repo := &planKeyRepo{secretClient: mClient}
+
+		key, err := repo.GetOrCreate(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte("my-key"), key)
+	})
+
+	t.Run("should create immutable secret with random key if it does not exist", func(t *testing.T) {
+		mClient := newMockSecretClient(t)
+		mClient.EXPECT().Get(testCtx, "blueprint-plan-key", metav1.GetOptions{}).Return(nil, notFoundErr)
+		var createdSecret *corev1.Secret
+		mClient.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).
+			RunAndReturn(func(_ context.Context, secret *corev1.Secret, _ metav1.CreateOptions) (*corev1.Secret, error) {
+				createdSecret = secret
+				return secret, nil
+			})
+		repo := &planKeyRepo{secretClient: mClient}
+
+		key, err := repo.GetOrCreate(testCtx)
+
+		require.NoError(t, err)
+		assert.Len(t, key, 32)
+		assert.Equal(t, key, createdSecret.Data["key"])
+		assert.Equal(t, ptr.To(true), createdSecret.Immutable)
+		assert.Equal(t, "blueprint-plan", createdSecret.Labels["k8s.cloudogu.com/part-of"])
+	})
+
+	t.Run("should load key created concurrently", func(t *testing.T) {
+		mClient := newMockSecretClient(t)
+		mClient.EXPECT().Get(testCtx, "blueprint-plan-key", metav1.GetOptions{}).Return(nil, notFoundErr).Once()
+		mClient.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, alreadyExistsErr)
+		mClient.EXPECT().Get(testCtx, "blueprint-plan-key", metav1.GetOptions{}).Return(keySecret, nil).Once()
+		repo := &planKeyRepo{secretClient: mClient}
+
+		key, err := repo.GetOrCreate(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte("my-key"), key)
+	})
+
+	t.Run("should return InternalError on get error", func(t *testing.T) {
+		mClient := newMockSecretClient(t)
+		mClient.EXPECT().Get(testCtx, "blueprint-plan-key", metav1.GetOptions{}).Return(nil, assert.AnError)
+		repo := &planKeyRepo{secretClient: mClient}
+
+		_, err := repo.GetOrCreate(testCtx)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot load plan key secret \"blueprint-plan-key\"")
+	})
+
+	t.Run("should return InternalError if secret contains no key", func(t *testing.T) {
+		mClient := newMockSecretClient(t)
+		mClient.EXPECT().Get(testCtx, "blueprint-plan-key", metav1.GetOptions{}).Return(&corev1.Secret{}, nil)
+		repo := &planKeyRepo{secretClient: mClient}
+
+		_, err := repo.GetOrCreate(testCtx)
+
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "plan key secret \"blueprint-plan-key\" does not contain a key")
+	})
+
+	t.Run("should return InternalError on create error", func(t *testing.T) {
+		mClient := newMockSecretClient(t)
+		mClient.EXPECT().Get(testCtx, "blueprint-plan-key", metav1.GetOptions{}).Return(nil, notFoundErr)
+		mClient.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, assert.AnError)
+		repo := &planKeyRepo{secretClient: mClient}
+
+		_, err := repo.GetOrCreate(testCtx)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot create plan key secret \"blueprint-plan-key\"")
+	})
+}
@@ -0,0 +1,137 @@
+package plancm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	planNamePrefix = "blueprint-plan-"
+	// planNameHashLength keeps the name short. The complete hash is checked after loading the plan.
+	planNameHashLength = 16
+
+	blueprintIdKey = "blueprintId"
+	hashKey        = "hash"
+	changesKey     = "changes"
+
+	partOfLabel     = "k8s.cloudogu.com/part-of"
+	planPartOfValue = "blueprint-plan"
+)
+
+type planRepo struct {
+	configMapClient configMapClient
+	blueprintClient blueprintInterface
+}
+
+// NewPlanRepo returns a new planRepo which stores plans in immutable config maps owned by their blueprint.
+func NewPlanRepo(configMapClient configMapClient, blueprintClient blueprintInterface) domainservice.PlanRepository {
+	return &planRepo{configMapClient: configMapClient, blueprintClient: blueprintClient}
+}
+
+func (repo *planRepo) Get(ctx context.Context, hash string) (domain.Plan, error) {
+	name := getPlanName(hash)
+	configMap, err := repo.configMapClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return domain.Plan{}, domainservice.NewNotFoundError(err, "cannot find plan %q", hash)
+		}
+		return domain.Plan{}, domainservice.NewInternalError(err, "error while loading plan config map %q", name)
+	}
+
+	plan := domain.Plan{BlueprintId: configMap.Data[blueprintIdKey]}
+	if configMap.Data[changesKey] != "" {
+		plan.Changes = strings.Split(configMap.Data[changesKey], "\n")
+	}
+	if plan.Hash() != hash {
+		return domain.Plan{}, domainservice.NewNotFoundError(nil, "plan config map %q does not contain the plan %q", name, hash)
+	}
+	return plan, nil
+}
+
+func (repo *planRepo) Create(ctx context.Context, plan domain.Plan) error {
+	// the blueprint owns its plans, so that they get garbage collected together with it
+	blueprint, err := repo.blueprintClient.Get(ctx, plan.BlueprintId, metav1.GetOptions{})
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot load blueprint %q to set it as owner of its plan", plan.BlueprintId)
+	}
+
+	hash := plan.Hash()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   getPlanName(hash),
+			Labels: getLabels(),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: bpv3.GroupVersion.String(),
+				Kind:       "Blueprint",
+				Name:       blueprint.Name,
+				UID:        blueprint.UID,
+			}},
+		},
+		Immutable: ptr.To(true),
+		Data: map[string]string{
+			blueprintIdKey: plan.BlueprintId,
+			hashKey:        hash,
+			changesKey:     strings.Join(plan.Changes, "\n"),
+		},
+	}
+
+	_, err = repo.configMapClient.Create(ctx, configMap, metav1.CreateOptions{})
+	if err != nil {
+		if errors.IsAlreadyExists(err) {
+			return &domainservice.ConflictError{
+				WrappedError: err,
+				Message:      fmt.Sprintf("cannot create plan %q as it already exists", hash),
+			}
+		}
+		return domainservice.NewInternalError(err, "cannot create plan config map %q", configMap.Name)
+	}
+
+	repo.deleteSupersededPlans(ctx, plan.BlueprintId, configMap.Name)
+	return nil
+}
+
+// deleteSupersededPlans deletes all other plans of the blueprint, as only the newest plan can be approved.
+// Errors are only logged, because the new plan is already stored and the old plans get deleted with the next plan
+// or the blueprint at the latest.
+func (repo *planRepo) deleteSupersededPlans(ctx context.Context, blueprintId string, currentPlanName string) {
+	logger := log.FromContext(ctx).WithName("planRepo.deleteSupersededPlans")
+	list, err := repo.configMapClient.List(ctx, metav1.ListOptions{LabelSelector: partOfLabel + "=" + planPartOfValue})
+	if err != nil {
+		logger.Error(err, "cannot list plans to delete superseded plans", "blueprint", blueprintId)
+		return
+	}
+	for _, configMap := range list.Items {
+		if configMap.Name == currentPlanName || configMap.Data[blueprintIdKey] != blueprintId {
+			continue
+		}
+		err = repo.configMapClient.Delete(ctx, configMap.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "cannot delete superseded plan", "plan", configMap.Data[hashKey])
+		}
+	}
+}
+
+func getLabels() map[string]string {
+	return map[string]string{
+		"app":                          "ces",
+		partOfLabel:                    planPartOfValue,
+		"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+	}
+}
+
+func getPlanName(hash string) string {
+	if len(hash) > planNameHashLength {
+		hash = hash[:planNameHashLength]
+	}
+	return planNamePrefix + hash
+}
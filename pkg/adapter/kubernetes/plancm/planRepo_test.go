@@ -0,0 +1,230 @@
+package plancm
+
+import (
+	"context"
+	"testing"
+
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+)
+
+var testCtx = context.Background()
+
+var testPlan = domain.Plan{
+	BlueprintId: "my-blueprint",
+	Changes:     []string{"dogu {\"DoguName\":\"ldap\"}", "globalConfig {\"Key\":\"fqdn\"}"},
+}
+
+func TestNewPlanRepo(t *testing.T) {
+	t.Run("should create new PlanRepo", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		bpClient := newMockBlueprintInterface(t)
+
+		repo := NewPlanRepo(mClient, bpClient)
+
+		assert.NotNil(t, repo)
+		assert.Equal(t, mClient, repo.(*planRepo).configMapClient)
+		assert.Equal(t, bpClient, repo.(*planRepo).blueprintClient)
+	})
+}
+
+func Test_planRepo_Get(t *testing.T) {
+	hash := testPlan.Hash()
+	name := "blueprint-plan-" + hash[:16]
+
+	t.Run("should return plan", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data: map[string]string{
+				"blueprintId": "my-blueprint",
+				"hash":        hash,
+				"changes":     "dogu {\"DoguName\":\"ldap\"}\nglobalConfig {\"Key\":\"fqdn\"}",
+			},
+		}, nil)
+
+		repo := &planRepo{configMapClient: mClient}
+
+		plan, err := repo.Get(testCtx, hash)
+
+		require.NoError(t, err)
+		assert.Equal(t, testPlan, plan)
+	})
+
+	t.Run("should return NotFoundError if plan does not exist", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+
+		repo := &planRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, hash)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "cannot find plan \""+hash+"\"")
+	})
+
+	t.Run("should return NotFoundError if config map contains another plan", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data:       map[string]string{"blueprintId": "other-blueprint"},
+		}, nil)
+
+		repo := &planRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, hash)
+
+		require.Error(t, err)
+		assert.True(t, domainservice.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "does not contain the plan")
+	})
+
+	t.Run("should return InternalError on other errors", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &planRepo{configMapClient: mClient}
+
+		_, err := repo.Get(testCtx, hash)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "error while loading plan config map \""+name+"\"")
+	})
+}
+
+func Test_planRepo_Create(t *testing.T) {
+	hash := testPlan.Hash()
+	name := "blueprint-plan-" + hash[:16]
+	blueprint := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: "my-blueprint", UID: "c0ffee"}}
+	expectedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app":                          "ces",
+				"k8s.cloudogu.com/part-of":     "blueprint-plan",
+				"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "k8s.cloudogu.com/v3",
+				Kind:       "Blueprint",
+				Name:       "my-blueprint",
+				UID:        "c0ffee",
+			}},
+		},
+		Immutable: ptr.To(true),
+		Data: map[string]string{
+			"blueprintId": "my-blueprint",
+			"hash":        hash,
+			"changes":     "dogu {\"DoguName\":\"ldap\"}\nglobalConfig {\"Key\":\"fqdn\"}",
+		},
+	}
+	listOptions := metav1.ListOptions{LabelSelector: "k8s.cloudogu.com/part-of=blueprint-plan"}
+
+	t.Run("should create plan config map and delete superseded plans of the blueprint", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Create(testCtx, expectedConfigMap, metav1.CreateOptions{}).Return(expectedConfigMap, nil)
+		mClient.EXPECT().List(testCtx, listOptions).Return(&corev1.ConfigMapList{Items: []corev1.ConfigMap{
+			*expectedConfigMap,
+			{ObjectMeta: metav1.ObjectMeta{Name: "blueprint-plan-old"}, Data: map[string]string{"blueprintId": "my-blueprint"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "blueprint-plan-other"}, Data: map[string]string{"blueprintId": "other-blueprint"}},
+		}}, nil)
+		mClient.EXPECT().Delete(testCtx, "blueprint-plan-old", metav1.DeleteOptions{}).Return(nil)
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &planRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Create(testCtx, testPlan)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not fail if superseded plans cannot be listed", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Create(testCtx, expectedConfigMap, metav1.CreateOptions{}).Return(expectedConfigMap, nil)
+		mClient.EXPECT().List(testCtx, listOptions).Return(nil, assert.AnError)
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &planRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Create(testCtx, testPlan)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not fail if a superseded plan cannot be deleted", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Create(testCtx, expectedConfigMap, metav1.CreateOptions{}).Return(expectedConfigMap, nil)
+		mClient.EXPECT().List(testCtx, listOptions).Return(&corev1.ConfigMapList{Items: []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{Name: "blueprint-plan-old1"}, Data: map[string]string{"blueprintId": "my-blueprint"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "blueprint-plan-old2"}, Data: map[string]string{"blueprintId": "my-blueprint"}},
+		}}, nil)
+		mClient.EXPECT().Delete(testCtx, "blueprint-plan-old1", metav1.DeleteOptions{}).Return(assert.AnError)
+		mClient.EXPECT().Delete(testCtx, "blueprint-plan-old2", metav1.DeleteOptions{}).Return(nil)
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &planRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Create(testCtx, testPlan)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return ConflictError if plan already exists", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Create(testCtx, expectedConfigMap, metav1.CreateOptions{}).Return(nil, k8serrors.NewAlreadyExists(schema.GroupResource{}, name))
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &planRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Create(testCtx, testPlan)
+
+		var conflictErr *domainservice.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("should return InternalError on other errors", func(t *testing.T) {
+		mClient := newMockConfigMapClient(t)
+		mClient.EXPECT().Create(testCtx, expectedConfigMap, metav1.CreateOptions{}).Return(nil, assert.AnError)
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(blueprint, nil)
+
+		repo := &planRepo{configMapClient: mClient, blueprintClient: bpClient}
+
+		err := repo.Create(testCtx, testPlan)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot create plan config map \""+name+"\"")
+	})
+
+	t.Run("should return InternalError if the blueprint cannot be loaded", func(t *testing.T) {
+		bpClient := newMockBlueprintInterface(t)
+		bpClient.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &planRepo{configMapClient: newMockConfigMapClient(t), blueprintClient: bpClient}
+
+		err := repo.Create(testCtx, testPlan)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot load blueprint \"my-blueprint\" to set it as owner of its plan")
+	})
+}
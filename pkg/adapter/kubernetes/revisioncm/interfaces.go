@@ -0,0 +1,18 @@
+package revisioncm
+
+import (
+	bpv3client "github.com/cloudogu/k8s-blueprint-lib/v3/client"
+	k8sv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type configMapClient interface {
+	k8sv1.ConfigMapInterface
+}
+
+type secretClient interface {
+	k8sv1.SecretInterface
+}
+
+type blueprintInterface interface {
+	bpv3client.BlueprintInterface
+}
@@ -0,0 +1,289 @@
+package revisioncm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/blueprintcr/v3/serializer"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+)
+
+const (
+	revisionNamePrefix = "blueprint-revision-"
+	partOfLabel        = "k8s.cloudogu.com/part-of"
+	partOfLabelValue   = "blueprint-revision"
+
+	blueprintIdKey         = "blueprintId"
+	revisionKey            = "revision"
+	rolledBackToKey        = "rolledBackTo"
+	effectiveBlueprintKey  = "effectiveBlueprint"
+	stateDiffKey           = "stateDiff"
+	previousConfigKey      = "previousConfig"
+	sensitiveConfigRefKey  = "previousSensitiveConfigSecret"
+	sensitiveConfigDataKey = "previousSensitiveConfig"
+)
+
+type revisionRepo struct {
+	configMapClient configMapClient
+	secretClient    secretClient
+	blueprintClient blueprintInterface
+}
+
+// NewRevisionRepo returns a new revisionRepo which stores revisions in immutable config maps.
+// Previous values of sensitive config are stored in immutable secrets. Both are owned by the blueprint.
+func NewRevisionRepo(configMapClient configMapClient, secretClient secretClient, blueprintClient blueprintInterface) domainservice.BlueprintRevisionRepository {
+	return &revisionRepo{configMapClient: configMapClient, secretClient: secretClient, blueprintClient: blueprintClient}
+}
+
+// configValueStateDTO is the serializable form of domain.ConfigValueState.
+type configValueStateDTO struct {
+	Value  *string `json:"value,omitempty"`
+	Exists bool    `json:"exists"`
+}
+
+// doguConfigStatesDTO contains config value states by dogu and key.
+type doguConfigStatesDTO map[string]map[string]configValueStateDTO
+
+// revisionConfigDTO is the serializable form of the non-sensitive part of domain.RevisionConfig.
+type revisionConfigDTO struct {
+	Dogus  doguConfigStatesDTO            `json:"dogus,omitempty"`
+	Global map[string]configValueStateDTO `json:"global,omitempty"`
+}
+
+func (repo *revisionRepo) GetAll(ctx context.Context, blueprintId string) ([]domain.BlueprintRevision, error) {
+	list, err := repo.configMapClient.List(ctx, metav1.ListOptions{LabelSelector: partOfLabel + "=" + partOfLabelValue})
+	if err != nil {
+		return nil, domainservice.NewInternalError(err, "error while listing revisions of blueprint %q", blueprintId)
+	}
+
+	var revisions []domain.BlueprintRevision
+	for _, configMap := range list.Items {
+		// the blueprint id is no label as it may be too long for a label value
+		if configMap.Data[blueprintIdKey] != blueprintId {
+			continue
+		}
+		revision, err := repo.toRevision(ctx, configMap)
+		if errors.IsNotFound(err) {
+			// the secret is deleted first, so the deletion of this revision was interrupted
+			log.FromContext(ctx).Info(fmt.Sprintf("skip revision config map %q as its secret with previous sensitive config is missing", configMap.Name))
+			continue
+		}
+		if err != nil {
+			return nil, domainservice.NewInternalError(err, "cannot read revision config map %q", configMap.Name)
+		}
+		revisions = append(revisions, revision)
+	}
+	slices.SortFunc(revisions, func(a, b domain.BlueprintRevision) int { return a.Number - b.Number })
+	return revisions, nil
+}
+
+func (repo *revisionRepo) toRevision(ctx context.Context, configMap corev1.ConfigMap) (domain.BlueprintRevision, error) {
+	number, err := strconv.Atoi(configMap.Data[revisionKey])
+	if err != nil {
+		return domain.BlueprintRevision{}, fmt.Errorf("revision number is invalid: %w", err)
+	}
+	revision := domain.BlueprintRevision{
+		BlueprintId: configMap.Data[blueprintIdKey],
+		Number:      number,
+	}
+	if rawRolledBackTo, exists := configMap.Data[rolledBackToKey]; exists {
+		rolledBackTo, err := strconv.Atoi(rawRolledBackTo)
+		if err != nil {
+			return domain.BlueprintRevision{}, fmt.Errorf("rolled back revision number is invalid: %w", err)
+		}
+		revision.RolledBackTo = &rolledBackTo
+	}
+
+	var previousConfig revisionConfigDTO
+	err = json.Unmarshal([]byte(configMap.Data[previousConfigKey]), &previousConfig)
+	if err != nil {
+		return domain.BlueprintRevision{}, fmt.Errorf("cannot deserialize previous config: %w", err)
+	}
+	revision.PreviousConfig = domain.RevisionConfig{
+		DoguConfig:   toDoguConfigStates(previousConfig.Dogus),
+		GlobalConfig: map[common.GlobalConfigKey]domain.ConfigValueState{},
+	}
+	for key, state := range previousConfig.Global {
+		revision.PreviousConfig.GlobalConfig[common.GlobalConfigKey(key)] = domain.ConfigValueState(state)
+	}
+
+	revision.PreviousConfig.SensitiveDoguConfig = map[common.DoguConfigKey]domain.ConfigValueState{}
+	secretName, hasSensitiveConfig := configMap.Data[sensitiveConfigRefKey]
+	if hasSensitiveConfig {
+		secret, err := repo.secretClient.Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			return domain.BlueprintRevision{}, fmt.Errorf("cannot load secret %q with previous sensitive config: %w", secretName, err)
+		}
+		var previousSensitiveConfig doguConfigStatesDTO
+		err = json.Unmarshal(secret.Data[sensitiveConfigDataKey], &previousSensitiveConfig)
+		if err != nil {
+			return domain.BlueprintRevision{}, fmt.Errorf("cannot deserialize previous sensitive config: %w", err)
+		}
+		revision.PreviousConfig.SensitiveDoguConfig = toDoguConfigStates(previousSensitiveConfig)
+	}
+	return revision, nil
+}
+
+func toDoguConfigStates(dto doguConfigStatesDTO) map[common.DoguConfigKey]domain.ConfigValueState {
+	states := map[common.DoguConfigKey]domain.ConfigValueState{}
+	for doguName, statesByKey := range dto {
+		for key, state := range statesByKey {
+			states[common.DoguConfigKey{DoguName: cescommons.SimpleName(doguName), Key: config.Key(key)}] = domain.ConfigValueState(state)
+		}
+	}
+	return states
+}
+
+func toDoguConfigStatesDTO(states map[common.DoguConfigKey]domain.ConfigValueState) doguConfigStatesDTO {
+	if len(states) == 0 {
+		return nil
+	}
+	dto := doguConfigStatesDTO{}
+	for key, state := range states {
+		doguName := string(key.DoguName)
+		if dto[doguName] == nil {
+			dto[doguName] = map[string]configValueStateDTO{}
+		}
+		dto[doguName][string(key.Key)] = configValueStateDTO(state)
+	}
+	return dto
+}
+
+func (repo *revisionRepo) Create(ctx context.Context, revision domain.BlueprintRevision) error {
+	name := getRevisionName(revision.BlueprintId, revision.Number)
+
+	// the blueprint owns its revisions, so that the previous sensitive config does not outlive the blueprint
+	blueprint, err := repo.blueprintClient.Get(ctx, revision.BlueprintId, metav1.GetOptions{})
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot load blueprint %q to set it as owner of its revision", revision.BlueprintId)
+	}
+	ownerReferences := getOwnerReferences(blueprint)
+
+	previousConfig := revisionConfigDTO{Dogus: toDoguConfigStatesDTO(revision.PreviousConfig.DoguConfig)}
+	for key, state := range revision.PreviousConfig.GlobalConfig {
+		if previousConfig.Global == nil {
+			previousConfig.Global = map[string]configValueStateDTO{}
+		}
+		previousConfig.Global[string(key)] = configValueStateDTO(state)
+	}
+	serializedPreviousConfig, err := json.Marshal(previousConfig)
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot serialize previous config of revision %q", name)
+	}
+	// the serializer omits sensitive values of the state diff
+	serializedStateDiff, err := json.Marshal(serializer.ConvertToStateDiffDTO(revision.StateDiff))
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot serialize state diff of revision %q", name)
+	}
+	serializedBlueprint, err := json.Marshal(serializer.ConvertToBlueprintDTO(revision.EffectiveBlueprint))
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot serialize effective blueprint of revision %q", name)
+	}
+
+	data := map[string]string{
+		blueprintIdKey:        revision.BlueprintId,
+		revisionKey:           strconv.Itoa(revision.Number),
+		effectiveBlueprintKey: string(serializedBlueprint),
+		stateDiffKey:          string(serializedStateDiff),
+		previousConfigKey:     string(serializedPreviousConfig),
+	}
+	if revision.RolledBackTo != nil {
+		data[rolledBackToKey] = strconv.Itoa(*revision.RolledBackTo)
+	}
+
+	// create the secret first, so that the config map never references a missing secret
+	if len(revision.PreviousConfig.SensitiveDoguConfig) != 0 {
+		err = repo.createSensitiveConfigSecret(ctx, name, ownerReferences, revision.PreviousConfig.SensitiveDoguConfig)
+		if err != nil {
+			return err
+		}
+		data[sensitiveConfigRefKey] = name
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: getLabels(), OwnerReferences: ownerReferences},
+		Immutable:  ptr.To(true),
+		Data:       data,
+	}
+	_, err = repo.configMapClient.Create(ctx, configMap, metav1.CreateOptions{})
+	if err != nil {
+		return wrapCreateError(err, "revision config map", name)
+	}
+	return nil
+}
+
+func (repo *revisionRepo) Delete(ctx context.Context, blueprintId string, number int) error {
+	name := getRevisionName(blueprintId, number)
+	// delete the secret first, so that the revision is still listed and its deletion is retried if the secret cannot be deleted
+	err := repo.secretClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return domainservice.NewInternalError(err, "cannot delete revision secret %q", name)
+	}
+	err = repo.configMapClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return domainservice.NewInternalError(err, "cannot delete revision config map %q", name)
+	}
+	return nil
+}
+
+func (repo *revisionRepo) createSensitiveConfigSecret(ctx context.Context, name string, ownerReferences []metav1.OwnerReference, states map[common.DoguConfigKey]domain.ConfigValueState) error {
+	serializedConfig, err := json.Marshal(toDoguConfigStatesDTO(states))
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot serialize previous sensitive config of revision %q", name)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: getLabels(), OwnerReferences: ownerReferences},
+		Immutable:  ptr.To(true),
+		Data:       map[string][]byte{sensitiveConfigDataKey: serializedConfig},
+	}
+	_, err = repo.secretClient.Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return wrapCreateError(err, "revision secret", name)
+	}
+	return nil
+}
+
+func wrapCreateError(err error, kind string, name string) error {
+	if errors.IsAlreadyExists(err) {
+		return &domainservice.ConflictError{
+			WrappedError: err,
+			Message:      fmt.Sprintf("cannot create %s %q as it already exists", kind, name),
+		}
+	}
+	return domainservice.NewInternalError(err, "cannot create %s %q", kind, name)
+}
+
+func getLabels() map[string]string {
+	return map[string]string{
+		"app":                          "ces",
+		partOfLabel:                    partOfLabelValue,
+		"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+	}
+}
+
+func getOwnerReferences(blueprint *bpv3.Blueprint) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{
+		APIVersion: bpv3.GroupVersion.String(),
+		Kind:       "Blueprint",
+		Name:       blueprint.Name,
+		UID:        blueprint.UID,
+	}}
+}
+
+func getRevisionName(blueprintId string, number int) string {
+	return fmt.Sprintf("%s%s-%d", revisionNamePrefix, blueprintId, number)
+}
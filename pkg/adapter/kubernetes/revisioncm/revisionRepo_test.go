@@ -0,0 +1,340 @@
+package revisioncm
+
+import (
+	"context"
+	"testing"
+
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+)
+
+var testCtx = context.Background()
+
+var (
+	oldValue    = "old"
+	secretValue = "secret"
+	testLabels  = map[string]string{
+		"app":                          "ces",
+		"k8s.cloudogu.com/part-of":     "blueprint-revision",
+		"app.kubernetes.io/managed-by": "k8s-blueprint-operator",
+	}
+	listOptions         = metav1.ListOptions{LabelSelector: "k8s.cloudogu.com/part-of=blueprint-revision"}
+	testBlueprint       = &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: "my-blueprint", UID: "c0ffee"}}
+	testOwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "k8s.cloudogu.com/v3",
+		Kind:       "Blueprint",
+		Name:       "my-blueprint",
+		UID:        "c0ffee",
+	}}
+)
+
+func newTestBlueprintClient(t *testing.T) *mockBlueprintInterface {
+	blueprintClientMock := newMockBlueprintInterface(t)
+	blueprintClientMock.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(testBlueprint, nil)
+	return blueprintClientMock
+}
+
+func newTestRevision() domain.BlueprintRevision {
+	return domain.BlueprintRevision{
+		BlueprintId:  "my-blueprint",
+		Number:       2,
+		RolledBackTo: ptr.To(0),
+		PreviousConfig: domain.RevisionConfig{
+			DoguConfig: map[common.DoguConfigKey]domain.ConfigValueState{
+				{DoguName: "redmine", Key: "key"}: {Value: &oldValue, Exists: true},
+			},
+			SensitiveDoguConfig: map[common.DoguConfigKey]domain.ConfigValueState{
+				{DoguName: "cas", Key: "password"}: {Value: &secretValue, Exists: true},
+			},
+			GlobalConfig: map[common.GlobalConfigKey]domain.ConfigValueState{
+				"fqdn": {},
+			},
+		},
+	}
+}
+
+func TestNewRevisionRepo(t *testing.T) {
+	t.Run("should create new RevisionRepo", func(t *testing.T) {
+		configMapClientMock := newMockConfigMapClient(t)
+		secretClientMock := newMockSecretClient(t)
+		blueprintClientMock := newMockBlueprintInterface(t)
+
+		repo := NewRevisionRepo(configMapClientMock, secretClientMock, blueprintClientMock)
+
+		assert.NotNil(t, repo)
+		assert.Equal(t, configMapClientMock, repo.(*revisionRepo).configMapClient)
+		assert.Equal(t, secretClientMock, repo.(*revisionRepo).secretClient)
+		assert.Equal(t, blueprintClientMock, repo.(*revisionRepo).blueprintClient)
+	})
+}
+
+func Test_revisionRepo_Create(t *testing.T) {
+	name := "blueprint-revision-my-blueprint-2"
+
+	t.Run("should create secret and config map", func(t *testing.T) {
+		secretClientMock := newMockSecretClient(t)
+		var createdSecret *corev1.Secret
+		secretClientMock.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).
+			Run(func(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) {
+				createdSecret = secret
+			}).Return(nil, nil)
+		configMapClientMock := newMockConfigMapClient(t)
+		var createdConfigMap *corev1.ConfigMap
+		configMapClientMock.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).
+			Run(func(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) {
+				createdConfigMap = configMap
+			}).Return(nil, nil)
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock, blueprintClient: newTestBlueprintClient(t)}
+
+		err := repo.Create(testCtx, newTestRevision())
+
+		require.NoError(t, err)
+		require.NotNil(t, createdSecret)
+		assert.Equal(t, name, createdSecret.Name)
+		assert.Equal(t, testLabels, createdSecret.Labels)
+		assert.Equal(t, testOwnerReferences, createdSecret.OwnerReferences)
+		assert.Equal(t, ptr.To(true), createdSecret.Immutable)
+		assert.JSONEq(t, `{"cas":{"password":{"value":"secret","exists":true}}}`, string(createdSecret.Data["previousSensitiveConfig"]))
+
+		require.NotNil(t, createdConfigMap)
+		assert.Equal(t, name, createdConfigMap.Name)
+		assert.Equal(t, testLabels, createdConfigMap.Labels)
+		assert.Equal(t, testOwnerReferences, createdConfigMap.OwnerReferences)
+		assert.Equal(t, ptr.To(true), createdConfigMap.Immutable)
+		assert.Equal(t, "my-blueprint", createdConfigMap.Data["blueprintId"])
+		assert.Equal(t, "2", createdConfigMap.Data["revision"])
+		assert.Equal(t, "0", createdConfigMap.Data["rolledBackTo"])
+		assert.Equal(t, name, createdConfigMap.Data["previousSensitiveConfigSecret"])
+		assert.JSONEq(t, `{"dogus":{"redmine":{"key":{"value":"old","exists":true}}},"global":{"fqdn":{"exists":false}}}`, createdConfigMap.Data["previousConfig"])
+		assert.NotContains(t, createdConfigMap.Data["previousConfig"], secretValue)
+		assert.Contains(t, createdConfigMap.Data, "effectiveBlueprint")
+		assert.Contains(t, createdConfigMap.Data, "stateDiff")
+	})
+
+	t.Run("should not create secret without sensitive config", func(t *testing.T) {
+		revision := newTestRevision()
+		revision.PreviousConfig.SensitiveDoguConfig = nil
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).
+			Run(func(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) {
+				assert.NotContains(t, configMap.Data, "previousSensitiveConfigSecret")
+			}).Return(nil, nil)
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: newMockSecretClient(t), blueprintClient: newTestBlueprintClient(t)}
+
+		err := repo.Create(testCtx, revision)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return ConflictError if revision already exists", func(t *testing.T) {
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, k8serrors.NewAlreadyExists(schema.GroupResource{}, name))
+
+		repo := &revisionRepo{configMapClient: newMockConfigMapClient(t), secretClient: secretClientMock, blueprintClient: newTestBlueprintClient(t)}
+
+		err := repo.Create(testCtx, newTestRevision())
+
+		var conflictErr *domainservice.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.ErrorContains(t, err, "cannot create revision secret \""+name+"\" as it already exists")
+	})
+
+	t.Run("should return InternalError on other errors", func(t *testing.T) {
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, nil)
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, assert.AnError)
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock, blueprintClient: newTestBlueprintClient(t)}
+
+		err := repo.Create(testCtx, newTestRevision())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot create revision config map \""+name+"\"")
+	})
+
+	t.Run("should return InternalError if the blueprint cannot be loaded", func(t *testing.T) {
+		blueprintClientMock := newMockBlueprintInterface(t)
+		blueprintClientMock.EXPECT().Get(testCtx, "my-blueprint", metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &revisionRepo{configMapClient: newMockConfigMapClient(t), secretClient: newMockSecretClient(t), blueprintClient: blueprintClientMock}
+
+		err := repo.Create(testCtx, newTestRevision())
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot load blueprint \"my-blueprint\" to set it as owner of its revision")
+	})
+}
+
+func Test_revisionRepo_Delete(t *testing.T) {
+	const name = "blueprint-revision-my-blueprint-2"
+	notFoundErr := k8serrors.NewNotFound(schema.GroupResource{}, name)
+
+	t.Run("should delete secret and config map", func(t *testing.T) {
+		secretClientMock := newMockSecretClient(t)
+		secretDeletion := secretClientMock.EXPECT().Delete(testCtx, name, metav1.DeleteOptions{}).Return(nil)
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Delete(testCtx, name, metav1.DeleteOptions{}).Return(nil).NotBefore(secretDeletion.Call)
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock}
+
+		err := repo.Delete(testCtx, "my-blueprint", 2)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should ignore missing config map and secret", func(t *testing.T) {
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Delete(testCtx, name, metav1.DeleteOptions{}).Return(notFoundErr)
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Delete(testCtx, name, metav1.DeleteOptions{}).Return(notFoundErr)
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock}
+
+		err := repo.Delete(testCtx, "my-blueprint", 2)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return InternalError if config map cannot be deleted", func(t *testing.T) {
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Delete(testCtx, name, metav1.DeleteOptions{}).Return(nil)
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Delete(testCtx, name, metav1.DeleteOptions{}).Return(assert.AnError)
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock}
+
+		err := repo.Delete(testCtx, "my-blueprint", 2)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot delete revision config map \""+name+"\"")
+	})
+
+	t.Run("should keep config map if secret cannot be deleted", func(t *testing.T) {
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Delete(testCtx, name, metav1.DeleteOptions{}).Return(assert.AnError)
+		repo := &revisionRepo{configMapClient: newMockConfigMapClient(t), secretClient: secretClientMock}
+
+		err := repo.Delete(testCtx, "my-blueprint", 2)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot delete revision secret \""+name+"\"")
+	})
+}
+
+func Test_revisionRepo_GetAll(t *testing.T) {
+	name := "blueprint-revision-my-blueprint-2"
+	revisionConfigMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Data: map[string]string{
+			"blueprintId":                   "my-blueprint",
+			"revision":                      "2",
+			"rolledBackTo":                  "0",
+			"previousConfig":                `{"dogus":{"redmine":{"key":{"value":"old","exists":true}}},"global":{"fqdn":{"exists":false}}}`,
+			"previousSensitiveConfigSecret": name,
+		},
+	}
+
+	t.Run("should return sorted revisions of blueprint", func(t *testing.T) {
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, listOptions).Return(&corev1.ConfigMapList{Items: []corev1.ConfigMap{
+			revisionConfigMap,
+			{Data: map[string]string{"blueprintId": "other-blueprint", "revision": "1"}},
+			{Data: map[string]string{"blueprintId": "my-blueprint", "revision": "1", "previousConfig": "{}"}},
+		}}, nil)
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"previousSensitiveConfig": []byte(`{"cas":{"password":{"value":"secret","exists":true}}}`)},
+		}, nil)
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock}
+
+		revisions, err := repo.GetAll(testCtx, "my-blueprint")
+
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, 1, revisions[0].Number)
+		assert.Nil(t, revisions[0].RolledBackTo)
+		assert.Equal(t, 0, revisions[0].PreviousConfig.Len())
+		expected := newTestRevision()
+		assert.Equal(t, expected.BlueprintId, revisions[1].BlueprintId)
+		assert.Equal(t, expected.Number, revisions[1].Number)
+		assert.Equal(t, expected.RolledBackTo, revisions[1].RolledBackTo)
+		assert.Equal(t, expected.PreviousConfig, revisions[1].PreviousConfig)
+	})
+
+	t.Run("should return InternalError on list error", func(t *testing.T) {
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, listOptions).Return(nil, assert.AnError)
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: newMockSecretClient(t)}
+
+		_, err := repo.GetAll(testCtx, "my-blueprint")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "error while listing revisions of blueprint \"my-blueprint\"")
+	})
+
+	t.Run("should return InternalError on invalid revision", func(t *testing.T) {
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, listOptions).Return(&corev1.ConfigMapList{Items: []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{Name: name}, Data: map[string]string{"blueprintId": "my-blueprint", "revision": "two"}},
+		}}, nil)
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: newMockSecretClient(t)}
+
+		_, err := repo.GetAll(testCtx, "my-blueprint")
+
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot read revision config map \""+name+"\": revision number is invalid")
+	})
+
+	t.Run("should skip revision with missing secret", func(t *testing.T) {
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, listOptions).Return(&corev1.ConfigMapList{Items: []corev1.ConfigMap{
+			revisionConfigMap,
+			{Data: map[string]string{"blueprintId": "my-blueprint", "revision": "3", "previousConfig": "{}"}},
+		}}, nil)
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, name))
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock}
+
+		revisions, err := repo.GetAll(testCtx, "my-blueprint")
+
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, 3, revisions[0].Number)
+	})
+
+	t.Run("should return InternalError if secret cannot be loaded", func(t *testing.T) {
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, listOptions).Return(&corev1.ConfigMapList{Items: []corev1.ConfigMap{revisionConfigMap}}, nil)
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Get(testCtx, name, metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		repo := &revisionRepo{configMapClient: configMapClientMock, secretClient: secretClientMock}
+
+		_, err := repo.GetAll(testCtx, "my-blueprint")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, domainservice.IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot load secret \""+name+"\" with previous sensitive config")
+	})
+}
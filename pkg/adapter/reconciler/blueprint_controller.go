@@ -3,6 +3,7 @@ package reconciler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,8 @@ type BlueprintReconciler struct {
 	debounce               SingletonDebounce
 	window                 time.Duration
 	errorHandler           *ErrorHandler
+	registryPoller         *RegistryPoller
+	outdatedReporter       *OutdatedReporter
 }
 
 func NewBlueprintReconciler(
@@ -35,6 +38,8 @@ func NewBlueprintReconciler(
 	repo domainservice.BlueprintSpecRepository,
 	namespace string,
 	window time.Duration,
+	registryPoller *RegistryPoller,
+	outdatedReporter *OutdatedReporter,
 ) *BlueprintReconciler {
 	return &BlueprintReconciler{
 		blueprintChangeHandler: blueprintChangeHandler,
@@ -43,6 +48,8 @@ func NewBlueprintReconciler(
 		debounce:               SingletonDebounce{},
 		window:                 window,
 		errorHandler:           NewErrorHandler(),
+		registryPoller:         registryPoller,
+		outdatedReporter:       outdatedReporter,
 	}
 }
 
@@ -59,13 +66,7 @@ func (r *BlueprintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		WithName("BlueprintReconciler.Reconcile").
 		WithValues("resourceName", req.Name)
 
-	err := r.blueprintChangeHandler.CheckForMultipleBlueprintResources(ctx)
-
-	if err != nil {
-		return r.errorHandler.handleError(logger, err)
-	}
-
-	err = r.blueprintChangeHandler.HandleUntilApplied(ctx, req.Name)
+	err := r.blueprintChangeHandler.HandleUntilApplied(ctx, req.Name)
 
 	if err != nil {
 		return r.errorHandler.handleError(logger, err)
@@ -91,15 +92,32 @@ func (r *BlueprintReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		NeedLeaderElection: controllerOptions.NeedLeaderElection,
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+	builder := ctrl.NewControllerManagedBy(mgr).
+		// annotation changes are relevant, as some blueprint options are set via annotations
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
 		WithOptions(options).
 		For(&bpv3.Blueprint{}).
 		WatchesRawSource(r.getConfigMapKind(mgr)).
 		WatchesRawSource(r.getSecretKind(mgr)).
 		WatchesRawSource(r.getDoguKind(mgr)).
-		WatchesRawSource(r.getBlueprintMaskKind(mgr)).
-		Complete(r)
+		WatchesRawSource(r.getBlueprintMaskKind(mgr))
+
+	if r.registryPoller != nil {
+		err := mgr.Add(r.registryPoller)
+		if err != nil {
+			return fmt.Errorf("cannot add registry poller to manager: %w", err)
+		}
+		// new releases in the remote dogu registry are no kubernetes resources, so they have to be polled
+		builder = builder.WatchesRawSource(r.registryPoller.source())
+	}
+	if r.outdatedReporter != nil {
+		err := mgr.Add(r.outdatedReporter)
+		if err != nil {
+			return fmt.Errorf("cannot add outdated reporter to manager: %w", err)
+		}
+	}
+
+	return builder.Complete(r)
 }
 
 func (r *BlueprintReconciler) getConfigMapKind(mgr ctrl.Manager) source.TypedSyncingSource[reconcile.Request] {
@@ -155,14 +173,14 @@ func (r *BlueprintReconciler) getBlueprintMaskKind(mgr ctrl.Manager) source.Type
 		}),
 		predicate.And(
 			makeResourcePredicate[*bpv3.BlueprintMask](r.hasOperatorNamespace),
-			makeContentPredicate(&r.debounce, r.window, blueprintMaskSpecChanged),
+			makeContentPredicate(&r.debounce, r.window, blueprintMaskChanged),
 		),
 	)
 }
 
 func (r *BlueprintReconciler) getBlueprintRequest(ctx context.Context) []reconcile.Request {
 	idList, err := r.blueprintRepo.ListIds(ctx)
-	if err != nil || len(idList) != 1 {
+	if err != nil || len(idList) == 0 {
 		return nil
 	}
 
@@ -26,7 +26,7 @@ var testCtx = context.Background()
 const testBlueprint = "test-blueprint"
 
 func TestNewBlueprintReconciler(t *testing.T) {
-	reconciler := NewBlueprintReconciler(nil, nil, "", time.Duration(0))
+	reconciler := NewBlueprintReconciler(nil, nil, "", time.Duration(0), nil, nil)
 	assert.NotNil(t, reconciler)
 	assert.NotNil(t, reconciler.errorHandler)
 }
@@ -61,6 +61,41 @@ func TestBlueprintReconciler_SetupWithManager(t *testing.T) {
 		// then
 		require.NoError(t, err)
 	})
+	t.Run("should add registry poller", func(t *testing.T) {
+		// given
+		ctrlManMock := newMockControllerManager(t)
+		skipNameValidation := true
+		ctrlManMock.EXPECT().GetControllerOptions().Return(config.Controller{SkipNameValidation: &skipNameValidation})
+		ctrlManMock.EXPECT().GetScheme().Return(createScheme(t))
+		logger := log.FromContext(testCtx)
+		ctrlManMock.EXPECT().GetLogger().Return(logger)
+		ctrlManMock.EXPECT().Add(mock.Anything).Return(nil).Twice()
+		ctrlManMock.EXPECT().GetCache().Return(nil)
+
+		sut := &BlueprintReconciler{registryPoller: NewRegistryPoller(nil, nil, "", time.Hour)}
+
+		// when
+		err := sut.SetupWithManager(ctrlManMock)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail to add registry poller", func(t *testing.T) {
+		// given
+		ctrlManMock := newMockControllerManager(t)
+		ctrlManMock.EXPECT().GetControllerOptions().Return(config.Controller{})
+		ctrlManMock.EXPECT().GetCache().Return(nil)
+		ctrlManMock.EXPECT().Add(mock.Anything).Return(assert.AnError)
+
+		sut := &BlueprintReconciler{registryPoller: NewRegistryPoller(nil, nil, "", time.Hour)}
+
+		// when
+		err := sut.SetupWithManager(ctrlManMock)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot add registry poller to manager")
+	})
 }
 
 func createScheme(t *testing.T) *runtime.Scheme {
@@ -78,7 +113,6 @@ func TestBlueprintReconciler_Reconcile(t *testing.T) {
 		changeHandlerMock := NewMockBlueprintChangeHandler(t)
 		sut := &BlueprintReconciler{blueprintChangeHandler: changeHandlerMock}
 
-		changeHandlerMock.EXPECT().CheckForMultipleBlueprintResources(testCtx).Return(nil)
 		changeHandlerMock.EXPECT().HandleUntilApplied(testCtx, testBlueprint).Return(nil)
 		// when
 		actual, err := sut.Reconcile(testCtx, request)
@@ -88,28 +122,12 @@ func TestBlueprintReconciler_Reconcile(t *testing.T) {
 		assert.Equal(t, ctrl.Result{}, actual)
 	})
 
-	t.Run("should fail on multiple blueprint resource error", func(t *testing.T) {
-		// given
-		request := ctrl.Request{NamespacedName: types.NamespacedName{Name: testBlueprint}}
-		changeHandlerMock := NewMockBlueprintChangeHandler(t)
-		sut := &BlueprintReconciler{blueprintChangeHandler: changeHandlerMock}
-
-		changeHandlerMock.EXPECT().CheckForMultipleBlueprintResources(testCtx).Return(assert.AnError)
-		// when
-		_, err := sut.Reconcile(testCtx, request)
-
-		// then
-		require.Error(t, err)
-		assert.ErrorIs(t, err, assert.AnError)
-	})
-
 	t.Run("should fail on HandleUntilApplied error", func(t *testing.T) {
 		// given
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Name: testBlueprint}}
 		changeHandlerMock := NewMockBlueprintChangeHandler(t)
 		sut := &BlueprintReconciler{blueprintChangeHandler: changeHandlerMock}
 
-		changeHandlerMock.EXPECT().CheckForMultipleBlueprintResources(testCtx).Return(nil)
 		changeHandlerMock.EXPECT().HandleUntilApplied(testCtx, testBlueprint).Return(errors.New("test"))
 		// when
 		_, err := sut.Reconcile(testCtx, request)
@@ -123,7 +141,7 @@ func TestBlueprintReconciler_Reconcile(t *testing.T) {
 		mockHandler := NewMockBlueprintChangeHandler(t)
 		mockRepo := NewMockBlueprintSpecRepository(t)
 
-		reconciler := NewBlueprintReconciler(mockHandler, mockRepo, "test-namespace", 5*time.Second)
+		reconciler := NewBlueprintReconciler(mockHandler, mockRepo, "test-namespace", 5*time.Second, nil, nil)
 
 		req := ctrl.Request{
 			NamespacedName: types.NamespacedName{
@@ -135,7 +153,6 @@ func TestBlueprintReconciler_Reconcile(t *testing.T) {
 		ctx := context.TODO()
 		testErr := &domainservice.ConflictError{Message: "conflict error"}
 
-		mockHandler.EXPECT().CheckForMultipleBlueprintResources(ctx).Return(nil)
 		mockHandler.EXPECT().HandleUntilApplied(ctx, "test-blueprint").Return(testErr)
 
 		result, err := reconciler.Reconcile(ctx, req)
@@ -148,7 +165,7 @@ func TestBlueprintReconciler_Reconcile(t *testing.T) {
 		mockHandler := NewMockBlueprintChangeHandler(t)
 		mockRepo := NewMockBlueprintSpecRepository(t)
 
-		reconciler := NewBlueprintReconciler(mockHandler, mockRepo, "test-namespace", 5*time.Second)
+		reconciler := NewBlueprintReconciler(mockHandler, mockRepo, "test-namespace", 5*time.Second, nil, nil)
 
 		// Set up debounce to have pending request
 		reconciler.debounce.AllowOrMark(1 * time.Second)
@@ -164,7 +181,6 @@ func TestBlueprintReconciler_Reconcile(t *testing.T) {
 
 		ctx := context.TODO()
 
-		mockHandler.EXPECT().CheckForMultipleBlueprintResources(ctx).Return(nil)
 		mockHandler.EXPECT().HandleUntilApplied(ctx, "test-blueprint").Return(nil)
 
 		result, err := reconciler.Reconcile(ctx, req)
@@ -216,15 +232,22 @@ func TestBlueprintReconciler_getBlueprintRequest(t *testing.T) {
 		assert.Nil(t, result)
 	})
 
-	t.Run("no reconcile request when multiple blueprints", func(t *testing.T) {
+	t.Run("one reconcile request when multiple blueprints", func(t *testing.T) {
 		idList := []string{"bp1", "bp2"}
 
 		mockRepo := NewMockBlueprintSpecRepository(t)
 		mockRepo.EXPECT().ListIds(ctx).Return(idList, nil)
 
-		reconciler := &BlueprintReconciler{blueprintRepo: mockRepo}
+		reconciler := &BlueprintReconciler{blueprintRepo: mockRepo, namespace: "test-namespace"}
 		result := reconciler.getBlueprintRequest(ctx)
 
-		assert.Nil(t, result)
+		expected := []reconcile.Request{{
+			NamespacedName: types.NamespacedName{
+				Name:      "bp1",
+				Namespace: "test-namespace",
+			},
+		}}
+
+		assert.Equal(t, expected, result)
 	})
 }
@@ -23,16 +23,24 @@ func NewErrorHandler() *ErrorHandler {
 func (h *ErrorHandler) handleError(logger logr.Logger, err error) (ctrl.Result, error) {
 	errLogger := logger.WithValues("error", err)
 
+	var registryUnreachableError *domainservice.RegistryUnreachableError
+	var registryAuthenticationError *domainservice.RegistryAuthenticationError
 	var internalError *domainservice.InternalError
 	var conflictError *domainservice.ConflictError
 	var notFoundError *domainservice.NotFoundError
 	var invalidBlueprintError *domain.InvalidBlueprintError
 	var healthError *domain.UnhealthyEcosystemError
 	var stateDiffNotEmptyError *domain.StateDiffNotEmptyError
-	var multipleBlueprintsError *domain.MultipleBlueprintsError
 	var dogusNotUpToDateError *domain.DogusNotUpToDateError
 	var restoreInProgressError *domain.RestoreInProgressError
+	var backupInProgressError *domain.BackupInProgressError
+	var planNotApprovedError *domain.PlanNotApprovedError
 	switch {
+	// check before the InternalError, as the registry adapters wrap the RegistryUnreachableError in an InternalError
+	case errors.As(err, &registryUnreachableError):
+		return h.handleRegistryUnreachableError(errLogger, err)
+	case errors.As(err, &registryAuthenticationError):
+		return h.handleRegistryAuthenticationError(errLogger, err)
 	case errors.As(err, &internalError):
 		return h.handleInternalError(errLogger, err)
 	case errors.As(err, &conflictError):
@@ -45,12 +53,14 @@ func (h *ErrorHandler) handleError(logger logr.Logger, err error) (ctrl.Result,
 		return h.handleHealthError(errLogger)
 	case errors.As(err, &stateDiffNotEmptyError):
 		return h.handleStateDiffNotEmptyError(errLogger)
-	case errors.As(err, &multipleBlueprintsError):
-		return h.handleMultipleBlueprintsError(errLogger, err)
 	case errors.As(err, &dogusNotUpToDateError):
 		return h.handleDogusNotUpToDateError(errLogger, err)
 	case errors.As(err, &restoreInProgressError):
 		return h.handleRestoreInProgressError(errLogger, err)
+	case errors.As(err, &backupInProgressError):
+		return h.handleBackupInProgressError(errLogger, err)
+	case errors.As(err, &planNotApprovedError):
+		return h.handlePlanNotApprovedError(errLogger, err)
 	default:
 		return h.handleUnknownError(errLogger, err)
 	}
@@ -61,6 +71,18 @@ func (h *ErrorHandler) handleInternalError(logger logr.Logger, err error) (ctrl.
 	return ctrl.Result{}, err // automatic requeue because of non-nil err
 }
 
+func (h *ErrorHandler) handleRegistryUnreachableError(logger logr.Logger, err error) (ctrl.Result, error) {
+	// no error as the exponential backoff would retry too often at first, while the registry endpoints are skipped anyway
+	logger.Info(fmt.Sprintf("The remote dogu registry is unreachable. Retry later: %s", err.Error()))
+	return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+}
+
+func (h *ErrorHandler) handleRegistryAuthenticationError(logger logr.Logger, err error) (ctrl.Result, error) {
+	// no error as the exponential backoff would not help, only changed credentials do
+	logger.Error(err, "The remote dogu registry rejected the credentials. Check the credentials of the dogu registry. Retry later")
+	return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+}
+
 func (h *ErrorHandler) handleConflictError(logger logr.Logger) (ctrl.Result, error) {
 	logger.Info("A concurrent update happened in conflict to the processing of the blueprint spec. A retry could fix this issue")
 	return ctrl.Result{RequeueAfter: 1 * time.Second}, nil // no error as this would lead to the ignorance of our own retry params
@@ -94,11 +116,6 @@ func (h *ErrorHandler) handleStateDiffNotEmptyError(logger logr.Logger) (ctrl.Re
 	return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 }
 
-func (h *ErrorHandler) handleMultipleBlueprintsError(logger logr.Logger, err error) (ctrl.Result, error) {
-	logger.Error(err, "Ecosystem contains multiple blueprints - delete all but one. Retry later")
-	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-}
-
 func (h *ErrorHandler) handleDogusNotUpToDateError(logger logr.Logger, err error) (ctrl.Result, error) {
 	// really normal case
 	logger.Info(fmt.Sprintf("Dogus are not up to date yet. Retry later: %s", err.Error()))
@@ -111,6 +128,18 @@ func (h *ErrorHandler) handleRestoreInProgressError(logger logr.Logger, err erro
 	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 }
 
+func (h *ErrorHandler) handleBackupInProgressError(logger logr.Logger, err error) (ctrl.Result, error) {
+	// really normal case
+	logger.Info(fmt.Sprintf("Waiting for a backup to complete. Retry later: %s", err.Error()))
+	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+func (h *ErrorHandler) handlePlanNotApprovedError(logger logr.Logger, err error) (ctrl.Result, error) {
+	// no requeue as the approval changes the blueprint, which triggers the reconciler by itself
+	logger.Info(fmt.Sprintf("Waiting for the approval of the blueprint plan: %s", err.Error()))
+	return ctrl.Result{}, nil
+}
+
 func (h *ErrorHandler) handleUnknownError(logger logr.Logger, err error) (ctrl.Result, error) {
 	logger.Error(err, "An unknown error type occurred. Retry with default backoff")
 	return ctrl.Result{}, err // automatic requeue because of non-nil err
@@ -31,15 +31,12 @@ func Test_decideRequeueForError(t *testing.T) {
 		assert.Equal(t, ctrl.Result{}, actual)
 		assert.Contains(t, logSinkMock.output, "0: An internal error occurred and can maybe be fixed by retrying it later")
 	})
-	t.Run("should catch wrapped ConflictError, issue a log line and requeue timely", func(t *testing.T) {
+	t.Run("should catch RegistryUnreachableError wrapped in InternalError, issue a log line and requeue later", func(t *testing.T) {
 		// given
 		logSinkMock := newTrivialTestLogSink()
 		testLogger := logr.New(logSinkMock)
 
-		intermediateErr := &domainservice.ConflictError{
-			WrappedError: assert.AnError,
-			Message:      "a generic oh-noez",
-		}
+		intermediateErr := domainservice.NewInternalError(domainservice.NewRegistryUnreachableError(assert.AnError, "no endpoint reachable"), "cannot load dogu")
 		errorChain := fmt.Errorf("could not do the thing: %w", intermediateErr)
 
 		// when
@@ -48,15 +45,31 @@ func Test_decideRequeueForError(t *testing.T) {
 
 		// then
 		require.NoError(t, err)
-		assert.Equal(t, ctrl.Result{RequeueAfter: 1 * time.Second}, actual)
-		assert.Contains(t, logSinkMock.output, "0: A concurrent update happened in conflict to the processing of the blueprint spec. A retry could fix this issue")
+		assert.Equal(t, ctrl.Result{RequeueAfter: 1 * time.Minute}, actual)
+		assert.Contains(t, logSinkMock.output, "0: The remote dogu registry is unreachable. Retry later: could not do the thing: cannot load dogu: no endpoint reachable: "+assert.AnError.Error())
 	})
-	t.Run("should catch wrapped NotFoundError, issue a log line and requeue", func(t *testing.T) {
+	t.Run("should catch wrapped RegistryAuthenticationError, issue a log line and requeue later", func(t *testing.T) {
 		// given
 		logSinkMock := newTrivialTestLogSink()
 		testLogger := logr.New(logSinkMock)
 
-		intermediateErr := &domainservice.NotFoundError{
+		errorChain := fmt.Errorf("could not do the thing: %w", domainservice.NewRegistryAuthenticationError(assert.AnError, "credentials rejected"))
+
+		// when
+		sut := NewErrorHandler()
+		actual, err := sut.handleError(testLogger, errorChain)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: 1 * time.Minute}, actual)
+		assert.Contains(t, logSinkMock.output, "0: The remote dogu registry rejected the credentials. Check the credentials of the dogu registry. Retry later")
+	})
+	t.Run("should catch wrapped ConflictError, issue a log line and requeue timely", func(t *testing.T) {
+		// given
+		logSinkMock := newTrivialTestLogSink()
+		testLogger := logr.New(logSinkMock)
+
+		intermediateErr := &domainservice.ConflictError{
 			WrappedError: assert.AnError,
 			Message:      "a generic oh-noez",
 		}
@@ -68,16 +81,17 @@ func Test_decideRequeueForError(t *testing.T) {
 
 		// then
 		require.NoError(t, err)
-		assert.Equal(t, ctrl.Result{RequeueAfter: 10 * time.Second}, actual)
-		assert.Contains(t, logSinkMock.output, "0: Resource was not found, so maybe it was deleted in the meantime. Retry later")
+		assert.Equal(t, ctrl.Result{RequeueAfter: 1 * time.Second}, actual)
+		assert.Contains(t, logSinkMock.output, "0: A concurrent update happened in conflict to the processing of the blueprint spec. A retry could fix this issue")
 	})
-	t.Run("should catch wrapped MultipleBlueprintsError, issue a error log line and requeue", func(t *testing.T) {
+	t.Run("should catch wrapped NotFoundError, issue a log line and requeue", func(t *testing.T) {
 		// given
 		logSinkMock := newTrivialTestLogSink()
 		testLogger := logr.New(logSinkMock)
 
-		intermediateErr := &domain.MultipleBlueprintsError{
-			Message: "multiple blueprints found",
+		intermediateErr := &domainservice.NotFoundError{
+			WrappedError: assert.AnError,
+			Message:      "a generic oh-noez",
 		}
 		errorChain := fmt.Errorf("could not do the thing: %w", intermediateErr)
 
@@ -88,7 +102,7 @@ func Test_decideRequeueForError(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		assert.Equal(t, ctrl.Result{RequeueAfter: 10 * time.Second}, actual)
-		assert.Contains(t, logSinkMock.output, "0: Ecosystem contains multiple blueprints - delete all but one. Retry later")
+		assert.Contains(t, logSinkMock.output, "0: Resource was not found, so maybe it was deleted in the meantime. Retry later")
 	})
 	t.Run("NotFoundError, should not retry if DoNotRetry-Flag is set", func(t *testing.T) {
 		// given
@@ -169,6 +183,44 @@ func Test_decideRequeueForError(t *testing.T) {
 		assert.Equal(t, ctrl.Result{RequeueAfter: 10 * time.Second}, actual)
 		assert.Contains(t, logSinkMock.output, "0: A restore is currently in progress. Retry later: could not do the thing: a generic oh-noez")
 	})
+	t.Run("should catch wrapped BackupInProgressError, issue a log line and requeue timely", func(t *testing.T) {
+		// given
+		logSinkMock := newTrivialTestLogSink()
+		testLogger := logr.New(logSinkMock)
+
+		intermediateErr := &domain.BackupInProgressError{
+			Message: "a generic oh-noez",
+		}
+		errorChain := fmt.Errorf("could not do the thing: %w", intermediateErr)
+
+		// when
+		sut := NewErrorHandler()
+		actual, err := sut.handleError(testLogger, errorChain)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: 10 * time.Second}, actual)
+		assert.Contains(t, logSinkMock.output, "0: Waiting for a backup to complete. Retry later: could not do the thing: a generic oh-noez")
+	})
+	t.Run("should catch wrapped PlanNotApprovedError, issue a log line and do not requeue", func(t *testing.T) {
+		// given
+		logSinkMock := newTrivialTestLogSink()
+		testLogger := logr.New(logSinkMock)
+
+		intermediateErr := &domain.PlanNotApprovedError{
+			Message: "a generic oh-noez",
+		}
+		errorChain := fmt.Errorf("could not do the thing: %w", intermediateErr)
+
+		// when
+		sut := NewErrorHandler()
+		actual, err := sut.handleError(testLogger, errorChain)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, actual)
+		assert.Contains(t, logSinkMock.output, "0: Waiting for the approval of the blueprint plan: could not do the thing: a generic oh-noez")
+	})
 	t.Run("should catch general errors, issue a log line and return requeue with error", func(t *testing.T) {
 		// given
 		logSinkMock := newTrivialTestLogSink()
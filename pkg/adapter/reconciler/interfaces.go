@@ -17,7 +17,18 @@ type controllerManager interface {
 
 type BlueprintChangeHandler interface {
 	HandleUntilApplied(ctx context.Context, blueprintId string) error
-	CheckForMultipleBlueprintResources(ctx context.Context) error
+}
+
+type AutoUpgradeChecker interface {
+	CheckForAutoUpgrades(ctx context.Context, blueprintId string) (bool, error)
+}
+
+type OutdatedDoguReporter interface {
+	ReportOutdatedDogus(ctx context.Context, blueprintId string) error
+}
+
+type RegistryAccessRefresher interface {
+	RefreshAccess(ctx context.Context) error
 }
 
 type BlueprintSpecRepository interface {
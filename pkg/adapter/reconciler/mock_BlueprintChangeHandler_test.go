@@ -21,52 +21,6 @@ func (_m *MockBlueprintChangeHandler) EXPECT() *MockBlueprintChangeHandler_Expec
 	return &MockBlueprintChangeHandler_Expecter{mock: &_m.Mock}
 }
 
-// CheckForMultipleBlueprintResources provides a mock function with given fields: ctx
-func (_m *MockBlueprintChangeHandler) CheckForMultipleBlueprintResources(ctx context.Context) error {
-	ret := _m.Called(ctx)
-
-	if len(ret) == 0 {
-		panic("no return value specified for CheckForMultipleBlueprintResources")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(ctx)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckForMultipleBlueprintResources'
-type MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call struct {
-	*mock.Call
-}
-
-// CheckForMultipleBlueprintResources is a helper method to define mock.On call
-//   - ctx context.Context
-func (_e *MockBlueprintChangeHandler_Expecter) CheckForMultipleBlueprintResources(ctx interface{}) *MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call {
-	return &MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call{Call: _e.mock.On("CheckForMultipleBlueprintResources", ctx)}
-}
-
-func (_c *MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call) Run(run func(ctx context.Context)) *MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call {
-	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
-	})
-	return _c
-}
-
-func (_c *MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call) Return(_a0 error) *MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call {
-	_c.Call.Return(_a0)
-	return _c
-}
-
-func (_c *MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call) RunAndReturn(run func(context.Context) error) *MockBlueprintChangeHandler_CheckForMultipleBlueprintResources_Call {
-	_c.Call.Return(run)
-	return _c
-}
-
 // HandleUntilApplied provides a mock function with given fields: ctx, blueprintId
 func (_m *MockBlueprintChangeHandler) HandleUntilApplied(ctx context.Context, blueprintId string) error {
 	ret := _m.Called(ctx, blueprintId)
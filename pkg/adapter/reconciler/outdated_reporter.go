@@ -0,0 +1,73 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+)
+
+// OutdatedReporter periodically reports which dogus of the blueprint have newer releases in the remote dogu registry.
+// Unlike the RegistryPoller, it never triggers a reconciliation, as the report does not change the ecosystem.
+type OutdatedReporter struct {
+	outdatedDoguReporter OutdatedDoguReporter
+	blueprintRepo        BlueprintSpecRepository
+	interval             time.Duration
+}
+
+func NewOutdatedReporter(
+	outdatedDoguReporter OutdatedDoguReporter,
+	repo domainservice.BlueprintSpecRepository,
+	interval time.Duration,
+) *OutdatedReporter {
+	return &OutdatedReporter{
+		outdatedDoguReporter: outdatedDoguReporter,
+		blueprintRepo:        repo,
+		interval:             interval,
+	}
+}
+
+// Start reports the outdated dogus once and then in the interval until the context is done.
+// Reporting is disabled if the interval is not positive.
+func (r *OutdatedReporter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("OutdatedReporter.Start")
+	if r.interval <= 0 {
+		logger.Info("reporting outdated dogus is disabled")
+		return nil
+	}
+
+	// report immediately, so that the report does not get lost with every restart of the operator
+	r.report(ctx)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.report(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection lets only the leading operator instance report outdated dogus.
+func (r *OutdatedReporter) NeedLeaderElection() bool {
+	return true
+}
+
+func (r *OutdatedReporter) report(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("OutdatedReporter.report")
+
+	idList, err := r.blueprintRepo.ListIds(ctx)
+	if err != nil || len(idList) == 0 {
+		return
+	}
+
+	blueprintId := idList[0]
+	err = r.outdatedDoguReporter.ReportOutdatedDogus(ctx, blueprintId)
+	if err != nil {
+		logger.Error(err, "cannot report outdated dogus, try again with the next interval", "blueprint", blueprintId)
+	}
+}
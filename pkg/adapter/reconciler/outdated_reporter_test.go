@@ -0,0 +1,86 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutdatedReporter_Start(t *testing.T) {
+	t.Run("should return immediately if reporting is disabled", func(t *testing.T) {
+		// given
+		sut := NewOutdatedReporter(nil, nil, 0)
+
+		// when
+		err := sut.Start(testCtx)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should report once on start", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(ctx).Return([]string{testBlueprint}, nil)
+		reporterMock := NewMockOutdatedDoguReporter(t)
+		reporterMock.EXPECT().ReportOutdatedDogus(ctx, testBlueprint).Run(func(context.Context, string) { cancel() }).Return(nil)
+		sut := NewOutdatedReporter(reporterMock, repoMock, time.Hour)
+
+		// when
+		err := sut.Start(ctx)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestOutdatedReporter_NeedLeaderElection(t *testing.T) {
+	sut := NewOutdatedReporter(nil, nil, time.Hour)
+
+	assert.True(t, sut.NeedLeaderElection())
+}
+
+func TestOutdatedReporter_report(t *testing.T) {
+	t.Run("should report outdated dogus of blueprint", func(t *testing.T) {
+		// given
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(testCtx).Return([]string{testBlueprint}, nil)
+		reporterMock := NewMockOutdatedDoguReporter(t)
+		reporterMock.EXPECT().ReportOutdatedDogus(testCtx, testBlueprint).Return(nil)
+		sut := NewOutdatedReporter(reporterMock, repoMock, time.Hour)
+
+		// when
+		sut.report(testCtx)
+	})
+
+	t.Run("should only log errors", func(t *testing.T) {
+		// given
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(testCtx).Return([]string{testBlueprint}, nil)
+		reporterMock := NewMockOutdatedDoguReporter(t)
+		reporterMock.EXPECT().ReportOutdatedDogus(testCtx, testBlueprint).Return(assert.AnError)
+		sut := NewOutdatedReporter(reporterMock, repoMock, time.Hour)
+
+		// when
+		sut.report(testCtx)
+	})
+
+	t.Run("should not report without blueprint", func(t *testing.T) {
+		// given
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(testCtx).Return(nil, assert.AnError)
+		reporterMock := NewMockOutdatedDoguReporter(t)
+		sut := NewOutdatedReporter(reporterMock, repoMock, time.Hour)
+
+		// when
+		sut.report(testCtx)
+
+		// then
+		reporterMock.AssertNotCalled(t, "ReportOutdatedDogus", mock.Anything, mock.Anything)
+	})
+}
@@ -53,8 +53,12 @@ func doguSpecChanged(oldObj, newObj *v2.Dogu) bool {
 	return !equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec)
 }
 
-func blueprintMaskSpecChanged(oldObj, newObj *bpv3.BlueprintMask) bool {
-	return !equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec)
+// blueprintMaskChanged also considers labels because blueprints can select masks by their labels
+// and annotations because masks can contain config in their annotations.
+func blueprintMaskChanged(oldObj, newObj *bpv3.BlueprintMask) bool {
+	return !equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec) ||
+		!equality.Semantic.DeepEqual(oldObj.Labels, newObj.Labels) ||
+		!equality.Semantic.DeepEqual(oldObj.Annotations, newObj.Annotations)
 }
 
 func hasCesLabel(o client.Object) bool {
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
 	v2 "github.com/cloudogu/k8s-dogu-lib/v2/api/v2"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -314,6 +315,51 @@ func TestDoguSpecChanged(t *testing.T) {
 	}
 }
 
+func TestBlueprintMaskChanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldMask  *bpv3.BlueprintMask
+		newMask  *bpv3.BlueprintMask
+		expected bool
+	}{
+		{
+			name: "spec changed",
+			oldMask: &bpv3.BlueprintMask{
+				Spec: bpv3.BlueprintMaskSpec{BlueprintMaskManifest: bpv3.BlueprintMaskManifest{Dogus: []bpv3.MaskDogu{{Name: "official/dogu1"}}}},
+			},
+			newMask: &bpv3.BlueprintMask{
+				Spec: bpv3.BlueprintMaskSpec{BlueprintMaskManifest: bpv3.BlueprintMaskManifest{Dogus: []bpv3.MaskDogu{{Name: "official/dogu2"}}}},
+			},
+			expected: true,
+		},
+		{
+			name:     "labels changed",
+			oldMask:  &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"site": "a"}}},
+			newMask:  &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"site": "b"}}},
+			expected: true,
+		},
+		{
+			name:     "annotations changed",
+			oldMask:  &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"blueprint.k8s.cloudogu.com/config": "{}"}}},
+			newMask:  &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{}},
+			expected: true,
+		},
+		{
+			name:     "no change",
+			oldMask:  &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"site": "a"}, ResourceVersion: "1"}},
+			newMask:  &bpv3.BlueprintMask{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"site": "a"}, ResourceVersion: "2"}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := blueprintMaskChanged(tt.oldMask, tt.newMask)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestHasCesLabel(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,106 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// RegistryAccessReconciler watches the secrets with the credentials and the proxy of the remote dogu registry
+// and refreshes the access to the registry if they change, so that rotated credentials are used without a restart.
+type RegistryAccessReconciler struct {
+	accessRefresher RegistryAccessRefresher
+	namespace       string
+	secretNames     []string
+}
+
+// NewRegistryAccessReconciler creates a RegistryAccessReconciler which watches the secrets with the given names
+// in the given namespace. Empty names are ignored.
+func NewRegistryAccessReconciler(accessRefresher RegistryAccessRefresher, namespace string, secretNames ...string) *RegistryAccessReconciler {
+	var nonEmptyNames []string
+	for _, name := range secretNames {
+		if name != "" {
+			nonEmptyNames = append(nonEmptyNames, name)
+		}
+	}
+	return &RegistryAccessReconciler{
+		accessRefresher: accessRefresher,
+		namespace:       namespace,
+		secretNames:     nonEmptyNames,
+	}
+}
+
+// Reconcile refreshes the access to the remote dogu registry. The access is read from all secrets,
+// so it does not matter which of them changed.
+func (r *RegistryAccessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName("RegistryAccessReconciler.Reconcile").WithValues("secret", req.Name)
+
+	err := r.accessRefresher.RefreshAccess(ctx)
+	if err != nil {
+		logger.Error(err, "cannot refresh the access to the remote dogu registry, keep using the previous access")
+		return ctrl.Result{}, err // automatic requeue because of non-nil err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Every watched secret gets its own cache restricted to its name, so that the controller does not cache
+// all secrets of the namespace. Field selectors cannot select several names at once.
+func (r *RegistryAccessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if mgr == nil {
+		return errors.New("must provide a non-nil Manager")
+	}
+
+	controllerOptions := mgr.GetControllerOptions()
+	options := controller.TypedOptions[reconcile.Request]{
+		SkipNameValidation: controllerOptions.SkipNameValidation,
+		RecoverPanic:       controllerOptions.RecoverPanic,
+		// every operator instance needs the current access, not only the leader
+		NeedLeaderElection: ptr.To(false),
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		Named("registry-access").
+		WithOptions(options)
+	for _, name := range r.secretNames {
+		secretCache, err := r.newSecretCache(mgr, name)
+		if err != nil {
+			return fmt.Errorf("cannot create cache for registry access secret %q: %w", name, err)
+		}
+		err = mgr.Add(secretCache)
+		if err != nil {
+			return fmt.Errorf("cannot add cache for registry access secret %q to manager: %w", name, err)
+		}
+		builder = builder.WatchesRawSource(source.Kind(secretCache, &corev1.Secret{}, &handler.TypedEnqueueRequestForObject[*corev1.Secret]{}))
+	}
+
+	err := builder.Complete(r)
+	if err != nil {
+		return fmt.Errorf("cannot create registry access controller: %w", err)
+	}
+	return nil
+}
+
+func (r *RegistryAccessReconciler) newSecretCache(mgr ctrl.Manager, name string) (cache.Cache, error) {
+	return cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient:        mgr.GetHTTPClient(),
+		Scheme:            mgr.GetScheme(),
+		Mapper:            mgr.GetRESTMapper(),
+		DefaultNamespaces: map[string]cache.Config{r.namespace: {}},
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {Field: fields.OneTermEqualSelector("metadata.name", name)},
+		},
+	})
+}
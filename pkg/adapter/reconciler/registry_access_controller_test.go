@@ -0,0 +1,57 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestRegistryAccessReconciler_Reconcile(t *testing.T) {
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "dogu-registry"}}
+
+	t.Run("should refresh access", func(t *testing.T) {
+		// given
+		refresherMock := NewMockRegistryAccessRefresher(t)
+		refresherMock.EXPECT().RefreshAccess(testCtx).Return(nil)
+		sut := NewRegistryAccessReconciler(refresherMock, "ecosystem", "dogu-registry")
+
+		// when
+		actual, err := sut.Reconcile(testCtx, request)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, actual)
+	})
+	t.Run("should return error to retry refresh", func(t *testing.T) {
+		// given
+		refresherMock := NewMockRegistryAccessRefresher(t)
+		refresherMock.EXPECT().RefreshAccess(testCtx).Return(assert.AnError)
+		sut := NewRegistryAccessReconciler(refresherMock, "ecosystem", "dogu-registry")
+
+		// when
+		_, err := sut.Reconcile(testCtx, request)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestRegistryAccessReconciler_SetupWithManager(t *testing.T) {
+	t.Run("should fail without manager", func(t *testing.T) {
+		sut := NewRegistryAccessReconciler(nil, "ecosystem", "dogu-registry")
+
+		err := sut.SetupWithManager(nil)
+
+		assert.ErrorContains(t, err, "must provide a non-nil Manager")
+	})
+}
+
+func TestNewRegistryAccessReconciler(t *testing.T) {
+	sut := NewRegistryAccessReconciler(nil, "ecosystem", "dogu-registry", "")
+
+	assert.Equal(t, "ecosystem", sut.namespace)
+	assert.Equal(t, []string{"dogu-registry"}, sut.secretNames)
+}
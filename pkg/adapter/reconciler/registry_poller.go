@@ -0,0 +1,98 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	bpv3 "github.com/cloudogu/k8s-blueprint-lib/v3/api/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+)
+
+// RegistryPoller periodically checks the remote dogu registry for new releases which match the auto upgrade policies
+// of the blueprint and triggers a reconciliation of the blueprint if there are any.
+// Without polling, new releases would only be picked up after a change to the blueprint or the ecosystem.
+type RegistryPoller struct {
+	autoUpgradeChecker AutoUpgradeChecker
+	blueprintRepo      BlueprintSpecRepository
+	namespace          string
+	interval           time.Duration
+	events             chan event.TypedGenericEvent[*bpv3.Blueprint]
+}
+
+func NewRegistryPoller(
+	autoUpgradeChecker AutoUpgradeChecker,
+	repo domainservice.BlueprintSpecRepository,
+	namespace string,
+	interval time.Duration,
+) *RegistryPoller {
+	return &RegistryPoller{
+		autoUpgradeChecker: autoUpgradeChecker,
+		blueprintRepo:      repo,
+		namespace:          namespace,
+		interval:           interval,
+		// one pending event is enough, as it triggers a reconciliation of the whole blueprint
+		events: make(chan event.TypedGenericEvent[*bpv3.Blueprint], 1),
+	}
+}
+
+// Start polls the remote dogu registry until the context is done. Polling is disabled if the interval is not positive.
+func (p *RegistryPoller) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("RegistryPoller.Start")
+	if p.interval <= 0 {
+		logger.Info("polling the remote dogu registry for auto upgrades is disabled")
+		return nil
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.poll(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection lets only the leading operator instance poll the remote dogu registry.
+func (p *RegistryPoller) NeedLeaderElection() bool {
+	return true
+}
+
+func (p *RegistryPoller) poll(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("RegistryPoller.poll")
+
+	idList, err := p.blueprintRepo.ListIds(ctx)
+	if err != nil || len(idList) == 0 {
+		return
+	}
+
+	blueprintId := idList[0]
+	upgradeAvailable, err := p.autoUpgradeChecker.CheckForAutoUpgrades(ctx, blueprintId)
+	if err != nil {
+		logger.Error(err, "cannot check remote dogu registry for auto upgrades, try again with the next poll")
+		return
+	}
+	if !upgradeAvailable {
+		return
+	}
+
+	logger.Info("trigger reconciliation for auto upgrades", "blueprint", blueprintId)
+	blueprint := &bpv3.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: blueprintId, Namespace: p.namespace}}
+	select {
+	case p.events <- event.TypedGenericEvent[*bpv3.Blueprint]{Object: blueprint}:
+	default:
+		// a reconciliation is already pending
+	}
+}
+
+func (p *RegistryPoller) source() source.Source {
+	return source.Channel(p.events, &handler.TypedEnqueueRequestForObject[*bpv3.Blueprint]{})
+}
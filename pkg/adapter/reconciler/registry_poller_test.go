@@ -0,0 +1,92 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryPoller_Start(t *testing.T) {
+	t.Run("should return immediately if polling is disabled", func(t *testing.T) {
+		// given
+		sut := NewRegistryPoller(nil, nil, "ecosystem", 0)
+
+		// when
+		err := sut.Start(testCtx)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestRegistryPoller_NeedLeaderElection(t *testing.T) {
+	sut := NewRegistryPoller(nil, nil, "ecosystem", time.Hour)
+
+	assert.True(t, sut.NeedLeaderElection())
+}
+
+func TestRegistryPoller_poll(t *testing.T) {
+	t.Run("should trigger reconciliation if upgrade is available", func(t *testing.T) {
+		// given
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(testCtx).Return([]string{testBlueprint}, nil)
+		checkerMock := NewMockAutoUpgradeChecker(t)
+		checkerMock.EXPECT().CheckForAutoUpgrades(testCtx, testBlueprint).Return(true, nil).Twice()
+		sut := NewRegistryPoller(checkerMock, repoMock, "ecosystem", time.Hour)
+
+		// when
+		sut.poll(testCtx)
+		sut.poll(testCtx)
+
+		// then
+		require.Len(t, sut.events, 1)
+		triggered := <-sut.events
+		assert.Equal(t, testBlueprint, triggered.Object.Name)
+		assert.Equal(t, "ecosystem", triggered.Object.Namespace)
+	})
+
+	t.Run("should not trigger reconciliation without upgrade", func(t *testing.T) {
+		// given
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(testCtx).Return([]string{testBlueprint}, nil)
+		checkerMock := NewMockAutoUpgradeChecker(t)
+		checkerMock.EXPECT().CheckForAutoUpgrades(testCtx, testBlueprint).Return(false, nil)
+		sut := NewRegistryPoller(checkerMock, repoMock, "ecosystem", time.Hour)
+
+		// when
+		sut.poll(testCtx)
+
+		// then
+		assert.Empty(t, sut.events)
+	})
+
+	t.Run("should not trigger reconciliation on error", func(t *testing.T) {
+		// given
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(testCtx).Return([]string{testBlueprint}, nil)
+		checkerMock := NewMockAutoUpgradeChecker(t)
+		checkerMock.EXPECT().CheckForAutoUpgrades(testCtx, testBlueprint).Return(false, assert.AnError)
+		sut := NewRegistryPoller(checkerMock, repoMock, "ecosystem", time.Hour)
+
+		// when
+		sut.poll(testCtx)
+
+		// then
+		assert.Empty(t, sut.events)
+	})
+
+	t.Run("should not check for upgrades without blueprint", func(t *testing.T) {
+		// given
+		repoMock := NewMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().ListIds(testCtx).Return([]string{}, nil)
+		sut := NewRegistryPoller(NewMockAutoUpgradeChecker(t), repoMock, "ecosystem", time.Hour)
+
+		// when
+		sut.poll(testCtx)
+
+		// then
+		assert.Empty(t, sut.events)
+	})
+}
@@ -27,9 +27,14 @@ func NewApplyDogusUseCase(
 // ApplyDogus applies dogus if necessary.
 // The conditions in the blueprint will be set accordingly.
 // returns true if the dogus were applied, false if not.
+// If the blueprint defines rollout waves, only the next wave gets applied.
+// returns a domain.DogusNotUpToDateError if the previous rollout wave is not completed yet or
 // returns domainservice.ConflictError if there was a concurrent update to the blueprint or
 // returns a domainservice.InternalError if there was an unspecified error while collecting or modifying the ecosystem state.
 func (useCase *ApplyDogusUseCase) ApplyDogus(ctx context.Context, blueprint *domain.BlueprintSpec) (bool, error) {
+	if blueprint.Config.RolloutWaves.IsEnabled() {
+		return useCase.applyNextRolloutWave(ctx, blueprint)
+	}
 	err := useCase.doguInstallUseCase.ApplyDoguStates(ctx, blueprint)
 	isDogusApplied := blueprint.StateDiff.DoguDiffs.HasChanges() && err == nil
 	conditionChanged := blueprint.MarkDogusApplied(isDogusApplied, err)
@@ -42,3 +47,28 @@ func (useCase *ApplyDogusUseCase) ApplyDogus(ctx context.Context, blueprint *dom
 	}
 	return isDogusApplied, err
 }
+
+func (useCase *ApplyDogusUseCase) applyNextRolloutWave(ctx context.Context, blueprint *domain.BlueprintSpec) (bool, error) {
+	wave, pendingWaves, err := useCase.doguInstallUseCase.ApplyNextRolloutWave(ctx, blueprint)
+	var dogusNotUpToDateErr *domain.DogusNotUpToDateError
+	if errors.As(err, &dogusNotUpToDateErr) {
+		// the previous wave is still in progress, this is no failure
+		return false, err
+	}
+
+	isDogusApplied := wave.HasChanges() && err == nil
+	conditionChanged := blueprint.MarkDogusApplied(false, err)
+	if isDogusApplied {
+		conditionChanged = blueprint.MarkRolloutWaveApplied(wave, pendingWaves) || conditionChanged
+	} else if err == nil {
+		conditionChanged = blueprint.MarkAllRolloutWavesApplied() || conditionChanged
+	}
+
+	if isDogusApplied || conditionChanged {
+		updateErr := useCase.repo.Update(ctx, blueprint)
+		if updateErr != nil {
+			return isDogusApplied, fmt.Errorf("cannot update status while applying rollout wave: %w", errors.Join(updateErr, err))
+		}
+	}
+	return isDogusApplied, err
+}
@@ -111,4 +111,110 @@ func TestApplyDogusUseCase_ApplyDogus(t *testing.T) {
 		assert.Equal(t, domain.DogusAppliedEvent{Diffs: blueprint.StateDiff.DoguDiffs}, blueprint.Events[0])
 		assert.True(t, changed)
 	})
+
+	t.Run("apply next rollout wave", func(t *testing.T) {
+		wave := domain.DoguDiffs{{DoguName: "postgresql", NeededActions: []domain.Action{domain.ActionUpgrade}}}
+		blueprint := &domain.BlueprintSpec{
+			Conditions: []domain.Condition{},
+			Config:     domain.BlueprintConfiguration{RolloutWaves: domain.RolloutWaves{ByDependencyDepth: true}},
+		}
+
+		repoMock := newMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		doguInstallUseCaseMock := newMockDoguInstallationUseCase(t)
+		doguInstallUseCaseMock.EXPECT().ApplyNextRolloutWave(testCtx, blueprint).Return(wave, 2, nil)
+		useCase := NewApplyDogusUseCase(repoMock, doguInstallUseCaseMock)
+
+		changed, err := useCase.ApplyDogus(testCtx, blueprint)
+
+		require.NoError(t, err)
+		assert.True(t, changed)
+		require.Equal(t, 1, len(blueprint.Events))
+		assert.Equal(t, domain.RolloutWaveAppliedEvent{Diffs: wave, PendingWaves: 2}, blueprint.Events[0])
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionRolloutWave))
+	})
+
+	t.Run("all rollout waves applied", func(t *testing.T) {
+		blueprint := &domain.BlueprintSpec{
+			Conditions: []domain.Condition{},
+			Config:     domain.BlueprintConfiguration{RolloutWaves: domain.RolloutWaves{ByDependencyDepth: true}},
+		}
+
+		repoMock := newMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().Update(testCtx, blueprint).Return(nil).Once()
+		doguInstallUseCaseMock := newMockDoguInstallationUseCase(t)
+		doguInstallUseCaseMock.EXPECT().ApplyNextRolloutWave(testCtx, blueprint).Return(nil, 0, nil)
+		useCase := NewApplyDogusUseCase(repoMock, doguInstallUseCaseMock)
+
+		changed, err := useCase.ApplyDogus(testCtx, blueprint)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.True(t, meta.IsStatusConditionTrue(blueprint.Conditions, domain.ConditionRolloutWave))
+		assert.Empty(t, blueprint.Events)
+		// no update if the condition does not change
+		changed, err = useCase.ApplyDogus(testCtx, blueprint)
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("wait for previous rollout wave", func(t *testing.T) {
+		blueprint := &domain.BlueprintSpec{
+			Conditions: []domain.Condition{},
+			Config:     domain.BlueprintConfiguration{RolloutWaves: domain.RolloutWaves{ByDependencyDepth: true}},
+		}
+
+		repoMock := newMockBlueprintSpecRepository(t)
+		doguInstallUseCaseMock := newMockDoguInstallationUseCase(t)
+		notUpToDateErr := &domain.DogusNotUpToDateError{Message: "not up to date"}
+		doguInstallUseCaseMock.EXPECT().ApplyNextRolloutWave(testCtx, blueprint).Return(nil, 0, notUpToDateErr)
+		useCase := NewApplyDogusUseCase(repoMock, doguInstallUseCaseMock)
+
+		changed, err := useCase.ApplyDogus(testCtx, blueprint)
+
+		require.ErrorIs(t, err, notUpToDateErr)
+		assert.False(t, changed)
+		assert.Empty(t, blueprint.Conditions)
+		assert.Empty(t, blueprint.Events)
+	})
+
+	t.Run("fail to apply rollout wave", func(t *testing.T) {
+		wave := domain.DoguDiffs{{DoguName: "postgresql", NeededActions: []domain.Action{domain.ActionUpgrade}}}
+		blueprint := &domain.BlueprintSpec{
+			Conditions: []domain.Condition{},
+			Config:     domain.BlueprintConfiguration{RolloutWaves: domain.RolloutWaves{ByDependencyDepth: true}},
+		}
+
+		repoMock := newMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		doguInstallUseCaseMock := newMockDoguInstallationUseCase(t)
+		doguInstallUseCaseMock.EXPECT().ApplyNextRolloutWave(testCtx, blueprint).Return(wave, 1, assert.AnError)
+		useCase := NewApplyDogusUseCase(repoMock, doguInstallUseCaseMock)
+
+		changed, err := useCase.ApplyDogus(testCtx, blueprint)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.False(t, changed)
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionLastApplySucceeded))
+		assert.Nil(t, meta.FindStatusCondition(blueprint.Conditions, domain.ConditionRolloutWave))
+	})
+
+	t.Run("fail to update blueprint after rollout wave", func(t *testing.T) {
+		wave := domain.DoguDiffs{{DoguName: "postgresql", NeededActions: []domain.Action{domain.ActionUpgrade}}}
+		blueprint := &domain.BlueprintSpec{
+			Conditions: []domain.Condition{},
+			Config:     domain.BlueprintConfiguration{RolloutWaves: domain.RolloutWaves{ByDependencyDepth: true}},
+		}
+
+		repoMock := newMockBlueprintSpecRepository(t)
+		repoMock.EXPECT().Update(testCtx, blueprint).Return(assert.AnError)
+		doguInstallUseCaseMock := newMockDoguInstallationUseCase(t)
+		doguInstallUseCaseMock.EXPECT().ApplyNextRolloutWave(testCtx, blueprint).Return(wave, 0, nil)
+		useCase := NewApplyDogusUseCase(repoMock, doguInstallUseCaseMock)
+
+		changed, err := useCase.ApplyDogus(testCtx, blueprint)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot update status while applying rollout wave")
+		assert.True(t, changed)
+	})
 }
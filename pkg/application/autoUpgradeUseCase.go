@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type AutoUpgradeUseCase struct {
+	blueprintSpecRepo  blueprintSpecRepository
+	remoteDoguRegistry remoteDoguRegistry
+}
+
+func NewAutoUpgradeUseCase(
+	blueprintSpecRepo domainservice.BlueprintSpecRepository,
+	remoteDoguRegistry domainservice.RemoteDoguRegistry,
+) *AutoUpgradeUseCase {
+	return &AutoUpgradeUseCase{
+		blueprintSpecRepo:  blueprintSpecRepo,
+		remoteDoguRegistry: remoteDoguRegistry,
+	}
+}
+
+// CheckForAutoUpgrades checks the remote dogu registry for new releases which match the auto upgrade policies
+// of the blueprint and are newer than the versions in its effective blueprint.
+// returns true if at least one dogu can be upgraded or
+// a domainservice.NotFoundError if the blueprintId does not correspond to a blueprintSpec or
+// a domainservice.InternalError if there is any error while loading the blueprintSpec or the dogu versions.
+func (useCase *AutoUpgradeUseCase) CheckForAutoUpgrades(ctx context.Context, blueprintId string) (bool, error) {
+	logger := log.FromContext(ctx).WithName("AutoUpgradeUseCase.CheckForAutoUpgrades")
+
+	blueprint, err := getBaseBlueprint(ctx, useCase.blueprintSpecRepo, blueprintId)
+	if err != nil {
+		return false, fmt.Errorf("cannot load blueprint spec %q to check for auto upgrades: %w", blueprintId, err)
+	}
+
+	autoUpgradeDogus, err := blueprint.GetAutoUpgradeDogus()
+	if err != nil {
+		// the blueprint is invalid, which gets reported by the reconciliation anyway
+		logger.V(1).Info("skip auto upgrade check for invalid blueprint", "error", err.Error())
+		return false, nil
+	}
+
+	upgradeAvailable := false
+	for _, dogu := range autoUpgradeDogus {
+		availableVersions, err := useCase.remoteDoguRegistry.GetVersionsOf(ctx, dogu.Name)
+		if err != nil {
+			return false, fmt.Errorf("cannot load versions of dogu %q to check for auto upgrades: %w", dogu.Name, err)
+		}
+		newestVersion, err := dogu.VersionConstraint.Resolve(availableVersions, nil)
+		if err != nil {
+			logger.V(1).Info("no release matches the auto upgrade policy", "dogu", dogu.Name, "error", err.Error())
+			continue
+		}
+
+		currentDogu, found := domain.FindDoguByName(blueprint.EffectiveBlueprint.Dogus, dogu.Name.SimpleName)
+		if !found || currentDogu.Version == nil || newestVersion.IsNewerThan(*currentDogu.Version) {
+			logger.Info(fmt.Sprintf("new release %s of dogu %q matches auto upgrade policy %q", newestVersion.Raw, dogu.Name, dogu.AutoUpgradePolicy))
+			upgradeAvailable = true
+		}
+	}
+	return upgradeAvailable, nil
+}
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoUpgradeUseCase_CheckForAutoUpgrades(t *testing.T) {
+	postgresqlName := cescommons.QualifiedName{Namespace: "official", SimpleName: "postgresql"}
+	newBlueprint := func(effectiveVersion core.Version) *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id: "blueprint",
+			Blueprint: domain.Blueprint{Dogus: []domain.Dogu{
+				{Name: postgresqlName, Version: &version3211},
+				{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "ldap"}, Version: &version3211},
+			}},
+			Config: domain.BlueprintConfiguration{AutoUpgradePolicies: domain.AutoUpgradePolicies{"postgresql": domain.AutoUpgradePatch}},
+			EffectiveBlueprint: domain.EffectiveBlueprint{Dogus: []domain.Dogu{
+				{Name: postgresqlName, Version: &effectiveVersion},
+			}},
+		}
+	}
+
+	t.Run("new release matches auto upgrade policy", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(version3211), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return([]core.Version{version3211, version3212}, nil)
+		sut := NewAutoUpgradeUseCase(blueprintRepoMock, registryMock)
+
+		// when
+		upgradeAvailable, err := sut.CheckForAutoUpgrades(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, upgradeAvailable)
+	})
+
+	t.Run("effective blueprint already contains newest release", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(version3212), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return([]core.Version{version3211, version3212}, nil)
+		sut := NewAutoUpgradeUseCase(blueprintRepoMock, registryMock)
+
+		// when
+		upgradeAvailable, err := sut.CheckForAutoUpgrades(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, upgradeAvailable)
+	})
+
+	t.Run("no release matches auto upgrade policy", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(version3211), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return([]core.Version{}, nil)
+		sut := NewAutoUpgradeUseCase(blueprintRepoMock, registryMock)
+
+		// when
+		upgradeAvailable, err := sut.CheckForAutoUpgrades(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, upgradeAvailable)
+	})
+
+	t.Run("skip invalid blueprint", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint(version3211)
+		blueprint.BlueprintMask = domain.BlueprintMask{Dogus: []domain.MaskDogu{
+			{Name: cescommons.QualifiedName{Namespace: "premium", SimpleName: "postgresql"}},
+		}}
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(blueprint, nil)
+		sut := NewAutoUpgradeUseCase(blueprintRepoMock, newMockRemoteDoguRegistry(t))
+
+		// when
+		upgradeAvailable, err := sut.CheckForAutoUpgrades(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, upgradeAvailable)
+	})
+
+	t.Run("fail to load blueprint", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(nil, assert.AnError)
+		sut := NewAutoUpgradeUseCase(blueprintRepoMock, newMockRemoteDoguRegistry(t))
+
+		// when
+		_, err := sut.CheckForAutoUpgrades(testCtx, "blueprint")
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load blueprint spec \"blueprint\" to check for auto upgrades")
+	})
+
+	t.Run("fail to load versions", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(version3211), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return(nil, assert.AnError)
+		sut := NewAutoUpgradeUseCase(blueprintRepoMock, registryMock)
+
+		// when
+		_, err := sut.CheckForAutoUpgrades(testCtx, "blueprint")
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load versions of dogu \"official/postgresql\" to check for auto upgrades")
+	})
+}
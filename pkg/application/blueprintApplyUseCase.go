@@ -2,19 +2,24 @@ package application
 
 import (
 	"context"
+	"errors"
 
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
 )
 
 type BlueprintApplyUseCase struct {
-	completeUseCase        completeBlueprintUseCase
-	ecosystemConfigUseCase ecosystemConfigUseCase
-	applyDogusUseCase      applyDogusUseCase
-	healthUseCase          ecosystemHealthUseCase
-	dogusUpToDateUseCase   dogusUpToDateUseCase
+	planApprovalUseCase       planApprovalUseCase
+	preDowngradeBackupUseCase preDowngradeBackupUseCase
+	completeUseCase           completeBlueprintUseCase
+	ecosystemConfigUseCase    ecosystemConfigUseCase
+	applyDogusUseCase         applyDogusUseCase
+	healthUseCase             ecosystemHealthUseCase
+	dogusUpToDateUseCase      dogusUpToDateUseCase
 }
 
 func NewBlueprintApplyUseCase(
+	planApprovalUseCase planApprovalUseCase,
+	preDowngradeBackupUseCase preDowngradeBackupUseCase,
 	completeUseCase completeBlueprintUseCase,
 	ecosystemConfigUseCase ecosystemConfigUseCase,
 	applyDogusUseCase applyDogusUseCase,
@@ -22,20 +27,39 @@ func NewBlueprintApplyUseCase(
 	dogusUpToDateUseCase dogusUpToDateUseCase,
 ) BlueprintApplyUseCase {
 	return BlueprintApplyUseCase{
-		completeUseCase:        completeUseCase,
-		ecosystemConfigUseCase: ecosystemConfigUseCase,
-		applyDogusUseCase:      applyDogusUseCase,
-		healthUseCase:          healthUseCase,
-		dogusUpToDateUseCase:   dogusUpToDateUseCase,
+		planApprovalUseCase:       planApprovalUseCase,
+		preDowngradeBackupUseCase: preDowngradeBackupUseCase,
+		completeUseCase:           completeUseCase,
+		ecosystemConfigUseCase:    ecosystemConfigUseCase,
+		applyDogusUseCase:         applyDogusUseCase,
+		healthUseCase:             healthUseCase,
+		dogusUpToDateUseCase:      dogusUpToDateUseCase,
 	}
 }
 
 func (useCase *BlueprintApplyUseCase) applyBlueprint(ctx context.Context, blueprint *domain.BlueprintSpec) error {
-	err := useCase.ecosystemConfigUseCase.ApplyConfig(ctx, blueprint)
+	// nothing gets changed before the plan is approved
+	err := useCase.planApprovalUseCase.CheckPlanApproval(ctx, blueprint)
 	if err != nil {
 		return err
 	}
-	changedDogus, err := useCase.applyDogusUseCase.ApplyDogus(ctx, blueprint)
+	// the backup before downgrades must not contain any config of the blueprint yet,
+	// so nothing gets changed until it is completed
+	err = useCase.preDowngradeBackupUseCase.EnsurePreDowngradeBackup(ctx, blueprint)
+	if err != nil {
+		return err
+	}
+	changedDogus := false
+	previousConfig, err := useCase.ecosystemConfigUseCase.ApplyConfig(ctx, blueprint)
+	if err == nil {
+		changedDogus, err = useCase.applyDogusUseCase.ApplyDogus(ctx, blueprint)
+	}
+	// Record every apply which changed something, even if it failed afterward, so that the config can be rolled back.
+	// The following checks only wait for the results of the changes, and the state diff of the next reconciliation
+	// does not contain these changes anymore.
+	if changedDogus || previousConfig.Len() > 0 {
+		err = errors.Join(err, useCase.ecosystemConfigUseCase.CreateRevision(ctx, blueprint, previousConfig))
+	}
 	if err != nil {
 		return err
 	}
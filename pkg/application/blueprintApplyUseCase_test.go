@@ -0,0 +1,84 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprintApplyUseCase_applyBlueprint(t *testing.T) {
+	downgradeBlueprint := func() *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id: testBlueprintId,
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{{DoguName: "postgresql", NeededActions: []domain.Action{domain.ActionDowngrade}}},
+			},
+		}
+	}
+
+	t.Run("should ensure the backup before downgrades before applying config", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		mocks := createAllMocks(t)
+		approvalCall := mocks.planApproval.EXPECT().CheckPlanApproval(testCtx, blueprint).Return(nil)
+		backupCall := mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(testCtx, blueprint).Return(nil).NotBefore(approvalCall.Call)
+		configCall := mocks.ecosystemConfig.EXPECT().ApplyConfig(testCtx, blueprint).Return(testPreviousConfig, nil).NotBefore(backupCall)
+		dogusCall := mocks.applyDogus.EXPECT().ApplyDogus(testCtx, blueprint).Return(false, assert.AnError).NotBefore(configCall)
+		mocks.ecosystemConfig.EXPECT().CreateRevision(testCtx, blueprint, testPreviousConfig).Return(nil).NotBefore(dogusCall)
+		useCase := createUseCase(mocks).applyUseCase
+
+		// when
+		err := useCase.applyBlueprint(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should not apply config while the backup before downgrades is in progress", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupErr := &domain.BackupInProgressError{Message: "backup in progress"}
+		mocks := createAllMocks(t)
+		mocks.planApproval.EXPECT().CheckPlanApproval(testCtx, blueprint).Return(nil)
+		mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(testCtx, blueprint).Return(backupErr)
+		useCase := createUseCase(mocks).applyUseCase
+
+		// when
+		err := useCase.applyBlueprint(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, backupErr)
+	})
+
+	t.Run("should not apply anything if the backup before downgrades fails", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		mocks := createAllMocks(t)
+		mocks.planApproval.EXPECT().CheckPlanApproval(testCtx, blueprint).Return(nil)
+		mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(testCtx, blueprint).Return(assert.AnError)
+		useCase := createUseCase(mocks).applyUseCase
+
+		// when
+		err := useCase.applyBlueprint(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should not ensure the backup before the plan is approved", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		mocks := createAllMocks(t)
+		mocks.planApproval.EXPECT().CheckPlanApproval(testCtx, blueprint).Return(&domain.PlanNotApprovedError{})
+		useCase := createUseCase(mocks).applyUseCase
+
+		// when
+		err := useCase.applyBlueprint(testCtx, blueprint)
+
+		// then
+		var notApprovedErr *domain.PlanNotApprovedError
+		require.ErrorAs(t, err, &notApprovedErr)
+	})
+}
@@ -2,10 +2,13 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -13,27 +16,32 @@ type BlueprintSpecChangeUseCase struct {
 	repo               blueprintSpecRepository
 	preparationUseCase BlueprintPreparationUseCase
 	applyUseCase       BlueprintApplyUseCase
+	rollbackUseCase    configRollbackUseCase
 }
 
 func NewBlueprintSpecChangeUseCase(
 	repo blueprintSpecRepository,
 	preparationUseCase BlueprintPreparationUseCase,
 	applyUseCase BlueprintApplyUseCase,
+	rollbackUseCase configRollbackUseCase,
 ) *BlueprintSpecChangeUseCase {
 	return &BlueprintSpecChangeUseCase{
 		repo:               repo,
 		preparationUseCase: preparationUseCase,
 		applyUseCase:       applyUseCase,
+		rollbackUseCase:    rollbackUseCase,
 	}
 }
 
 // HandleUntilApplied further executes a blueprint given by the blueprintId until it is as far applied as possible or an error occurred.
 // If the process needs to wait for something, this function will return.
 // Another call of this function is necessary to proceed.
+// If the blueprint is one of multiple layers, the base layer gets handled instead.
 // Returns a domainservice.NotFoundError if the blueprintId does not correspond to a blueprintSpec or
 // a domainservice.InternalError if there is any error while loading or persisting the blueprintSpec or
 // a domainservice.ConflictError if there was a concurrent write or
-// a domain.InvalidBlueprintError if the blueprint is invalid.
+// a domain.InvalidBlueprintError if the blueprint is invalid or
+// a domainservice.RegistryUnreachableError if no endpoint of the remote dogu registry is reachable.
 func (useCase *BlueprintSpecChangeUseCase) HandleUntilApplied(givenCtx context.Context, blueprintId string) error {
 	logger := log.FromContext(givenCtx).
 		WithValues("blueprintId", blueprintId)
@@ -43,7 +51,7 @@ func (useCase *BlueprintSpecChangeUseCase) HandleUntilApplied(givenCtx context.C
 	logger = logger.WithName("BlueprintSpecChangeUseCase.HandleUntilApplied")
 
 	logger.V(2).Info("getting changed blueprint") // log with id
-	blueprint, err := useCase.repo.GetById(ctx, blueprintId)
+	blueprint, err := getBaseBlueprint(ctx, useCase.repo, blueprintId)
 	if err != nil {
 		errMsg := "cannot load blueprint spec"
 		logger.Error(err, errMsg)
@@ -52,7 +60,21 @@ func (useCase *BlueprintSpecChangeUseCase) HandleUntilApplied(givenCtx context.C
 
 	logger.V(1).Info("handle blueprint")
 
-	err = useCase.preparationUseCase.prepareBlueprint(ctx, blueprint)
+	err = useCase.handleUntilApplied(ctx, logger, blueprint)
+	return useCase.updateRegistryReachability(ctx, blueprint, err)
+}
+
+func (useCase *BlueprintSpecChangeUseCase) handleUntilApplied(ctx context.Context, logger logr.Logger, blueprint *domain.BlueprintSpec) error {
+	// roll back before the preparation, as the blueprint may not be applicable anymore because of the broken config
+	// an executed rollback is also handled after its request was removed, so that its record gets removed as well
+	if blueprint.Config.RollbackToRevision != nil || meta.FindStatusCondition(blueprint.Conditions, domain.ConditionConfigRolledBack) != nil {
+		err := useCase.rollbackUseCase.RollbackConfig(ctx, blueprint)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := useCase.preparationUseCase.prepareBlueprint(ctx, blueprint)
 	if err != nil {
 		return err
 	}
@@ -72,6 +94,28 @@ func (useCase *BlueprintSpecChangeUseCase) HandleUntilApplied(givenCtx context.C
 	return nil
 }
 
+// updateRegistryReachability marks the remote dogu registry as unreachable if the handling failed because of it,
+// or as reachable again if the handling succeeded. The given handling error is returned in any case.
+func (useCase *BlueprintSpecChangeUseCase) updateRegistryReachability(ctx context.Context, blueprint *domain.BlueprintSpec, handlingErr error) error {
+	var registryUnreachableError *domainservice.RegistryUnreachableError
+	var conditionChanged bool
+	switch {
+	case errors.As(handlingErr, &registryUnreachableError):
+		conditionChanged = blueprint.MarkRegistryUnreachable(registryUnreachableError)
+	case handlingErr == nil:
+		conditionChanged = blueprint.MarkRegistryReachable()
+	}
+	if !conditionChanged {
+		return handlingErr
+	}
+
+	err := useCase.repo.Update(ctx, blueprint)
+	if err != nil {
+		return errors.Join(handlingErr, fmt.Errorf("cannot update registry reachability of blueprint: %w", err))
+	}
+	return handlingErr
+}
+
 func (useCase *BlueprintSpecChangeUseCase) handleShouldNotBeApplied(ctx context.Context, logger logr.Logger, blueprint *domain.BlueprintSpec) error {
 	// post event and log only if blueprint is stopped, all other cases are just NoOps
 	if blueprint.Config.Stopped {
@@ -87,24 +131,18 @@ func (useCase *BlueprintSpecChangeUseCase) handleShouldNotBeApplied(ctx context.
 	return nil
 }
 
-// CheckForMultipleBlueprintResources checks if there is indeed only a single Blueprint-resource
-func (useCase *BlueprintSpecChangeUseCase) CheckForMultipleBlueprintResources(ctx context.Context) error {
-	logger := log.FromContext(ctx).WithName("BlueprintSpecChangeUseCase.CheckForMultipleBlueprintResources")
-
-	logger.V(2).Info("check for multiple blueprints")
-	// Ask for just 2 items: enough to detect "more than one"
-	count, err := useCase.repo.Count(ctx, 2)
-	if err == nil {
-		switch count {
-		case 0, 1:
-		default:
-			err = &domain.MultipleBlueprintsError{Message: "more than one blueprint CR found"}
-		}
-	}
-
+// getBaseBlueprint loads the blueprintSpec with the given id. If the blueprint is one of multiple layers,
+// the blueprintSpec of the base layer gets loaded instead, as only the base layer applies the merged blueprint.
+func getBaseBlueprint(ctx context.Context, repo blueprintSpecRepository, blueprintId string) (*domain.BlueprintSpec, error) {
+	blueprint, err := repo.GetById(ctx, blueprintId)
 	if err != nil {
-		return fmt.Errorf("%s: %w", "check for multiple blueprints not successful", err)
+		return nil, err
 	}
 
-	return nil
+	baseBlueprintId := blueprint.BaseBlueprintId()
+	if baseBlueprintId == blueprintId {
+		return blueprint, nil
+	}
+	log.FromContext(ctx).V(1).Info(fmt.Sprintf("blueprint %q is a layer of base blueprint %q, continue with the base blueprint", blueprintId, baseBlueprintId))
+	return repo.GetById(ctx, baseBlueprintId)
 }
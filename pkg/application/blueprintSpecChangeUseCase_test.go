@@ -5,12 +5,16 @@ import (
 	"testing"
 
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 var (
@@ -23,6 +27,9 @@ var (
 	testBlueprintSpecEmptyDiff = &domain.BlueprintSpec{
 		Id: testBlueprintId,
 	}
+	testPreviousConfig = domain.RevisionConfig{
+		GlobalConfig: map[common.GlobalConfigKey]domain.ConfigValueState{"fqdn": {}},
+	}
 	testStoppedBlueprintSpec = &domain.BlueprintSpec{
 		Id:     testBlueprintId,
 		Config: domain.BlueprintConfiguration{Stopped: true},
@@ -48,6 +55,8 @@ func TestNewBlueprintSpecChangeUseCase(t *testing.T) {
 		mocks.restoreInProgress,
 	)
 	applyUseCases := NewBlueprintApplyUseCase(
+		mocks.planApproval,
+		mocks.preDowngradeBackup,
 		mocks.completeBlueprint,
 		mocks.ecosystemConfig,
 		mocks.applyDogus,
@@ -56,11 +65,12 @@ func TestNewBlueprintSpecChangeUseCase(t *testing.T) {
 	)
 
 	// when
-	result := NewBlueprintSpecChangeUseCase(mocks.repo, preparationUseCases, applyUseCases)
+	result := NewBlueprintSpecChangeUseCase(mocks.repo, preparationUseCases, applyUseCases, mocks.configRollback)
 
 	// then
 	require.NotNil(t, result)
 	assert.Equal(t, mocks.repo, result.repo)
+	assert.Equal(t, mocks.configRollback, result.rollbackUseCase)
 	assertPreparationUseCases(t, result.preparationUseCase, mocks)
 	assertApplyUseCases(t, result.applyUseCase, mocks)
 }
@@ -77,6 +87,9 @@ type allMocks struct {
 	ecosystemHealth    *mockEcosystemHealthUseCase
 	dogusUpToDate      *mockDogusUpToDateUseCase
 	restoreInProgress  *mockRestoreInProgressUseCase
+	planApproval       *mockPlanApprovalUseCase
+	preDowngradeBackup *mockPreDowngradeBackupUseCase
+	configRollback     *mockConfigRollbackUseCase
 }
 
 func createAllMocks(t *testing.T) *allMocks {
@@ -92,6 +105,9 @@ func createAllMocks(t *testing.T) *allMocks {
 		ecosystemHealth:    newMockEcosystemHealthUseCase(t),
 		dogusUpToDate:      newMockDogusUpToDateUseCase(t),
 		restoreInProgress:  newMockRestoreInProgressUseCase(t),
+		planApproval:       newMockPlanApprovalUseCase(t),
+		preDowngradeBackup: newMockPreDowngradeBackupUseCase(t),
+		configRollback:     newMockConfigRollbackUseCase(t),
 	}
 }
 
@@ -104,6 +120,8 @@ func assertPreparationUseCases(t *testing.T, useCases BlueprintPreparationUseCas
 }
 
 func assertApplyUseCases(t *testing.T, useCases BlueprintApplyUseCase, mocks *allMocks) {
+	assert.Equal(t, mocks.planApproval, useCases.planApprovalUseCase)
+	assert.Equal(t, mocks.preDowngradeBackup, useCases.preDowngradeBackupUseCase)
 	assert.Equal(t, mocks.ecosystemConfig, useCases.ecosystemConfigUseCase)
 	assert.Equal(t, mocks.applyDogus, useCases.applyDogusUseCase)
 	assert.Equal(t, mocks.completeBlueprint, useCases.completeUseCase)
@@ -129,6 +147,25 @@ func TestBlueprintSpecChangeUseCase_HandleUntilApplied_RepositoryErrors(t *testi
 		// then
 		assert.ErrorContains(t, err, "cannot load blueprint spec")
 	})
+	t.Run("should return error on error getting base blueprint of layer", func(t *testing.T) {
+		// given
+		mocks := createAllMocks(t)
+		layer := &domain.BlueprintSpec{Id: testBlueprintId, Layers: []domain.BlueprintLayer{
+			{Id: testBlueprintId, Priority: 10},
+			{Id: "platform-base"},
+		}}
+		mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(layer, nil)
+		mocks.repo.EXPECT().GetById(mock.Anything, "platform-base").Return(nil, assert.AnError)
+
+		useCase := createUseCase(mocks)
+
+		// when
+		err := useCase.HandleUntilApplied(testCtx, testBlueprintId)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load blueprint spec")
+	})
 }
 
 func TestBlueprintSpecChangeUseCase_HandleUntilApplied_PreparationPhaseErrors(t *testing.T) {
@@ -262,6 +299,132 @@ func TestBlueprintSpecChangeUseCase_HandleUntilApplied_SpecialBlueprintStates(t
 		assert.NoError(t, err)
 	})
 
+	t.Run("should roll back config of stopped blueprint before preparation", func(t *testing.T) {
+		// given
+		mocks := createAllMocks(t)
+		rollbackBlueprint := &domain.BlueprintSpec{
+			Id:     testBlueprintId,
+			Config: domain.BlueprintConfiguration{Stopped: true, RollbackToRevision: ptr.To(1)},
+		}
+		mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(rollbackBlueprint, nil)
+		rollbackCall := mocks.configRollback.EXPECT().RollbackConfig(mock.Anything, rollbackBlueprint).Return(nil).Call
+		mocks.initialStatus.EXPECT().InitateConditions(mock.Anything, mock.Anything).Return(nil).NotBefore(rollbackCall)
+		mocks.validation.EXPECT().ValidateBlueprintSpecStatically(mock.Anything, mock.Anything).Return(assert.AnError)
+
+		useCase := createUseCase(mocks)
+
+		// when
+		err := useCase.HandleUntilApplied(testCtx, testBlueprintId)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should handle executed config rollback after its request was removed", func(t *testing.T) {
+		// given
+		mocks := createAllMocks(t)
+		rolledBackBlueprint := &domain.BlueprintSpec{
+			Id:         testBlueprintId,
+			Conditions: []domain.Condition{{Type: domain.ConditionConfigRolledBack, Status: metav1.ConditionTrue}},
+		}
+		mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(rolledBackBlueprint, nil)
+		mocks.configRollback.EXPECT().RollbackConfig(mock.Anything, rolledBackBlueprint).Return(assert.AnError)
+
+		useCase := createUseCase(mocks)
+
+		// when
+		err := useCase.HandleUntilApplied(testCtx, testBlueprintId)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should return error on config rollback error", func(t *testing.T) {
+		// given
+		mocks := createAllMocks(t)
+		rollbackBlueprint := &domain.BlueprintSpec{
+			Id:     testBlueprintId,
+			Config: domain.BlueprintConfiguration{Stopped: true, RollbackToRevision: ptr.To(1)},
+		}
+		mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(rollbackBlueprint, nil)
+		mocks.configRollback.EXPECT().RollbackConfig(mock.Anything, rollbackBlueprint).Return(assert.AnError)
+
+		useCase := createUseCase(mocks)
+
+		// when
+		err := useCase.HandleUntilApplied(testCtx, testBlueprintId)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should mark registry as unreachable", func(t *testing.T) {
+		// given
+		mocks := createAllMocks(t)
+		blueprint := &domain.BlueprintSpec{Id: testBlueprintId}
+		registryErr := domainservice.NewRegistryUnreachableError(assert.AnError, "no endpoint reachable")
+		mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(blueprint, nil)
+		mocks.initialStatus.EXPECT().InitateConditions(mock.Anything, blueprint).Return(nil)
+		mocks.validation.EXPECT().ValidateBlueprintSpecStatically(mock.Anything, blueprint).Return(nil)
+		mocks.effectiveBlueprint.EXPECT().CalculateEffectiveBlueprint(mock.Anything, blueprint).
+			Return(domainservice.NewInternalError(registryErr, "cannot load versions"))
+		mocks.repo.EXPECT().Update(mock.Anything, blueprint).Run(func(ctx context.Context, blueprint *domain.BlueprintSpec) {
+			assert.Equal(t, []domain.Event{domain.RegistryUnreachableEvent{Error: registryErr}}, blueprint.Events)
+		}).Return(nil)
+
+		useCase := createUseCase(mocks)
+
+		// when
+		err := useCase.HandleUntilApplied(testCtx, testBlueprintId)
+
+		// then
+		assert.True(t, domainservice.IsRegistryUnreachableError(err))
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionRegistryReachable))
+	})
+
+	t.Run("should mark registry as reachable again", func(t *testing.T) {
+		// given
+		mocks := createAllMocks(t)
+		blueprint := &domain.BlueprintSpec{
+			Id:         testBlueprintId,
+			Conditions: []domain.Condition{{Type: domain.ConditionCompleted, Status: metav1.ConditionTrue}},
+		}
+		blueprint.MarkRegistryUnreachable(assert.AnError)
+		blueprint.Events = nil
+		mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(blueprint, nil)
+		setupSuccessfulPreparationPhase(mocks, blueprint)
+		mocks.repo.EXPECT().Update(mock.Anything, blueprint).Return(nil)
+
+		useCase := createUseCase(mocks)
+
+		// when
+		err := useCase.HandleUntilApplied(testCtx, testBlueprintId)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionTrue(blueprint.Conditions, domain.ConditionRegistryReachable))
+	})
+
+	t.Run("should return error on error updating registry reachability", func(t *testing.T) {
+		// given
+		mocks := createAllMocks(t)
+		blueprint := &domain.BlueprintSpec{Id: testBlueprintId}
+		registryErr := domainservice.NewRegistryUnreachableError(nil, "no endpoint reachable")
+		mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(blueprint, nil)
+		mocks.initialStatus.EXPECT().InitateConditions(mock.Anything, blueprint).Return(registryErr)
+		mocks.repo.EXPECT().Update(mock.Anything, blueprint).Return(assert.AnError)
+
+		useCase := createUseCase(mocks)
+
+		// when
+		err := useCase.HandleUntilApplied(testCtx, testBlueprintId)
+
+		// then
+		assert.ErrorIs(t, err, registryErr)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot update registry reachability of blueprint")
+	})
+
 	t.Run("should not apply completed blueprint with no diff", func(t *testing.T) {
 		// given
 		mocks := createAllMocks(t)
@@ -284,12 +447,26 @@ func TestBlueprintSpecChangeUseCase_HandleUntilApplied_ApplyPhaseErrors(t *testi
 		setupMocks  func(*allMocks)
 		wantErrTest func(*testing.T, error)
 	}{
+		{
+			name: "should return error if plan is not approved",
+			setupMocks: func(mocks *allMocks) {
+				mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(testBlueprintSpec, nil)
+				setupSuccessfulPreparationPhase(mocks, testBlueprintSpec)
+				mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, testBlueprintSpec).Return(&domain.PlanNotApprovedError{})
+			},
+			wantErrTest: func(t *testing.T, err error) {
+				var targetError *domain.PlanNotApprovedError
+				assert.ErrorAs(t, err, &targetError)
+			},
+		},
 		{
 			name: "should return error on error apply config",
 			setupMocks: func(mocks *allMocks) {
 				mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(testBlueprintSpec, nil)
 				setupSuccessfulPreparationPhase(mocks, testBlueprintSpec)
-				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(assert.AnError)
+				mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(domain.RevisionConfig{}, assert.AnError)
 			},
 			wantErrTest: func(t *testing.T, err error) {
 				assert.Error(t, err)
@@ -300,7 +477,9 @@ func TestBlueprintSpecChangeUseCase_HandleUntilApplied_ApplyPhaseErrors(t *testi
 			setupMocks: func(mocks *allMocks) {
 				mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(testBlueprintSpec, nil)
 				setupSuccessfulPreparationPhase(mocks, testBlueprintSpec)
-				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(domain.RevisionConfig{}, nil)
 				mocks.applyDogus.EXPECT().ApplyDogus(mock.Anything, testBlueprintSpec).Return(false, assert.AnError)
 			},
 			wantErrTest: func(t *testing.T, err error) {
@@ -312,20 +491,55 @@ func TestBlueprintSpecChangeUseCase_HandleUntilApplied_ApplyPhaseErrors(t *testi
 			setupMocks: func(mocks *allMocks) {
 				mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(testBlueprintSpec, nil)
 				setupSuccessfulPreparationPhase(mocks, testBlueprintSpec)
-				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(domain.RevisionConfig{}, nil)
 				mocks.applyDogus.EXPECT().ApplyDogus(mock.Anything, testBlueprintSpec).Return(true, nil)
+				mocks.ecosystemConfig.EXPECT().CreateRevision(mock.Anything, testBlueprintSpec, domain.RevisionConfig{}).Return(nil)
 				mocks.ecosystemHealth.EXPECT().CheckEcosystemHealth(mock.Anything, testBlueprintSpec).Return(ecosystem.HealthResult{}, assert.AnError)
 			},
 			wantErrTest: func(t *testing.T, err error) {
 				assert.Error(t, err)
 			},
 		},
+		{
+			name: "should store revision for changed config even if the dogu apply fails",
+			setupMocks: func(mocks *allMocks) {
+				mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(testBlueprintSpec, nil)
+				setupSuccessfulPreparationPhase(mocks, testBlueprintSpec)
+				mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(testPreviousConfig, nil)
+				mocks.applyDogus.EXPECT().ApplyDogus(mock.Anything, testBlueprintSpec).Return(false, assert.AnError)
+				mocks.ecosystemConfig.EXPECT().CreateRevision(mock.Anything, testBlueprintSpec, testPreviousConfig).Return(nil)
+			},
+			wantErrTest: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+			},
+		},
+		{
+			name: "should return error on error storing revision",
+			setupMocks: func(mocks *allMocks) {
+				mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(testBlueprintSpec, nil)
+				setupSuccessfulPreparationPhase(mocks, testBlueprintSpec)
+				mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(testPreviousConfig, nil)
+				mocks.applyDogus.EXPECT().ApplyDogus(mock.Anything, testBlueprintSpec).Return(false, nil)
+				mocks.ecosystemConfig.EXPECT().CreateRevision(mock.Anything, testBlueprintSpec, testPreviousConfig).Return(assert.AnError)
+			},
+			wantErrTest: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+			},
+		},
 		{
 			name: "should return error on error checking if dogus are up to date",
 			setupMocks: func(mocks *allMocks) {
 				mocks.repo.EXPECT().GetById(mock.Anything, testBlueprintId).Return(testBlueprintSpec, nil)
 				setupSuccessfulPreparationPhase(mocks, testBlueprintSpec)
-				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(mock.Anything, testBlueprintSpec).Return(nil)
+				mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, testBlueprintSpec).Return(domain.RevisionConfig{}, nil)
 				mocks.applyDogus.EXPECT().ApplyDogus(mock.Anything, testBlueprintSpec).Return(false, nil)
 				mocks.dogusUpToDate.EXPECT().CheckDogus(mock.Anything, testBlueprintSpec).Return(assert.AnError)
 			},
@@ -414,17 +628,20 @@ func createUseCase(mocks *allMocks) *BlueprintSpecChangeUseCase {
 		restoreInProgressUseCase: mocks.restoreInProgress,
 	}
 	applyUseCases := BlueprintApplyUseCase{
-		completeUseCase:        mocks.completeBlueprint,
-		ecosystemConfigUseCase: mocks.ecosystemConfig,
-		applyDogusUseCase:      mocks.applyDogus,
-		healthUseCase:          mocks.ecosystemHealth,
-		dogusUpToDateUseCase:   mocks.dogusUpToDate,
+		planApprovalUseCase:       mocks.planApproval,
+		preDowngradeBackupUseCase: mocks.preDowngradeBackup,
+		completeUseCase:           mocks.completeBlueprint,
+		ecosystemConfigUseCase:    mocks.ecosystemConfig,
+		applyDogusUseCase:         mocks.applyDogus,
+		healthUseCase:             mocks.ecosystemHealth,
+		dogusUpToDateUseCase:      mocks.dogusUpToDate,
 	}
 
 	return &BlueprintSpecChangeUseCase{
 		repo:               mocks.repo,
 		preparationUseCase: preparationUseCases,
 		applyUseCase:       applyUseCases,
+		rollbackUseCase:    mocks.configRollback,
 	}
 }
 
@@ -439,58 +656,10 @@ func setupSuccessfulPreparationPhase(mocks *allMocks, spec *domain.BlueprintSpec
 }
 
 func setupSuccessfulApplyPhaseExceptComplete(mocks *allMocks, spec *domain.BlueprintSpec) {
-	mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, spec).Return(nil)
+	mocks.planApproval.EXPECT().CheckPlanApproval(mock.Anything, spec).Return(nil)
+	mocks.preDowngradeBackup.EXPECT().EnsurePreDowngradeBackup(mock.Anything, spec).Return(nil)
+	mocks.ecosystemConfig.EXPECT().ApplyConfig(mock.Anything, spec).Return(domain.RevisionConfig{}, nil)
 	mocks.applyDogus.EXPECT().ApplyDogus(mock.Anything, spec).Return(false, nil)
 	mocks.dogusUpToDate.EXPECT().CheckDogus(mock.Anything, spec).Return(nil)
 	// Note: no completeBlueprint expectation - this allows the completion steps to be tested
 }
-
-func TestBlueprintSpecChangeUseCase_CheckForMultipleBlueprintResources(t *testing.T) {
-	t.Run("should succeed without error", func(t *testing.T) {
-		// given
-		mockRepo := newMockBlueprintSpecRepository(t)
-		mockRepo.EXPECT().Count(t.Context(), 2).Return(1, nil)
-		useCase := &BlueprintSpecChangeUseCase{
-			repo: mockRepo,
-		}
-
-		//when
-		err := useCase.CheckForMultipleBlueprintResources(t.Context())
-
-		// then
-		require.NoError(t, err)
-	})
-
-	t.Run("should return error on repo error", func(t *testing.T) {
-		// given
-		mockRepo := newMockBlueprintSpecRepository(t)
-		mockRepo.EXPECT().Count(t.Context(), 2).Return(0, assert.AnError)
-		useCase := &BlueprintSpecChangeUseCase{
-			repo: mockRepo,
-		}
-
-		//when
-		err := useCase.CheckForMultipleBlueprintResources(t.Context())
-
-		// then
-		require.Error(t, err)
-		assert.ErrorIs(t, err, assert.AnError)
-		assert.ErrorContains(t, err, "check for multiple blueprints not successful")
-	})
-
-	t.Run("should return error on multiple blueprints", func(t *testing.T) {
-		// given
-		mockRepo := newMockBlueprintSpecRepository(t)
-		mockRepo.EXPECT().Count(t.Context(), 2).Return(2, nil)
-		useCase := &BlueprintSpecChangeUseCase{
-			repo: mockRepo,
-		}
-
-		//when
-		err := useCase.CheckForMultipleBlueprintResources(t.Context())
-
-		// then
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "check for multiple blueprints not successful: more than one blueprint CR found")
-	})
-}
@@ -15,6 +15,7 @@ type BlueprintSpecValidationUseCase struct {
 	validateDependenciesUseCase validateDependenciesDomainUseCase
 	validateMountsUseCase       validateAdditionalMountsDomainUseCase
 	validateStorageClassUseCase validateDoguStorageClassDomainUseCase
+	validateDoguConfigUseCase   validateDoguConfigDomainUseCase
 }
 
 func NewBlueprintSpecValidationUseCase(
@@ -22,16 +23,18 @@ func NewBlueprintSpecValidationUseCase(
 	validateDependenciesUseCase validateDependenciesDomainUseCase,
 	validateMountsUseCase validateAdditionalMountsDomainUseCase,
 	validateStorageClassUseCase validateDoguStorageClassDomainUseCase,
+	validateDoguConfigUseCase validateDoguConfigDomainUseCase,
 ) *BlueprintSpecValidationUseCase {
 	return &BlueprintSpecValidationUseCase{
 		repo:                        repo,
 		validateDependenciesUseCase: validateDependenciesUseCase,
 		validateMountsUseCase:       validateMountsUseCase,
 		validateStorageClassUseCase: validateStorageClassUseCase,
+		validateDoguConfigUseCase:   validateDoguConfigUseCase,
 	}
 }
 
-// ValidateBlueprintSpecStatically checks the blueprintSpec for semantic errors and persists it.
+// ValidateBlueprintSpecStatically merges the blueprint layers, checks the blueprintSpec for semantic errors and persists it.
 // returns a domain.InvalidBlueprintError if blueprint is invalid or
 // a domainservice.InternalError if there is any error while loading or persisting the blueprintSpec or
 // a domainservice.ConflictError if there was a concurrent write.
@@ -41,7 +44,11 @@ func (useCase *BlueprintSpecValidationUseCase) ValidateBlueprintSpecStatically(c
 
 	logger.V(1).Info("statically validate blueprint spec")
 
-	invalidBlueprintError := blueprint.ValidateStatically()
+	// merge before the validation, so that the merged blueprint gets validated
+	invalidBlueprintError := blueprint.MergeLayers()
+	if invalidBlueprintError == nil {
+		invalidBlueprintError = blueprint.ValidateStatically()
+	}
 	err := useCase.repo.Update(ctx, blueprint)
 	if err != nil {
 		// InternalError or ConflictError, both should be handled by the caller
@@ -65,6 +72,7 @@ func (useCase *BlueprintSpecValidationUseCase) ValidateBlueprintSpecDynamically(
 		useCase.validateDependenciesUseCase.ValidateDependenciesForAllDogus(ctx, blueprint.EffectiveBlueprint),
 		useCase.validateMountsUseCase.ValidateAdditionalMounts(ctx, blueprint.EffectiveBlueprint),
 		useCase.validateStorageClassUseCase.ValidateDoguStorageClass(ctx, blueprint.EffectiveBlueprint),
+		useCase.validateDoguConfigUseCase.ValidateDoguConfig(ctx, blueprint.EffectiveBlueprint, blueprint.Config.ConfigTemplatesEnabled()),
 	)
 
 	if validationError != nil {
@@ -31,7 +31,8 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecStatically_ok(t *testing.T) {
 	dependencyUseCase := newMockValidateDependenciesDomainUseCase(t)
 	mountsUseCase := newMockValidateAdditionalMountsDomainUseCase(t)
 	storageClassUseCase := newMockValidateDoguStorageClassDomainUseCase(t)
-	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase)
+	doguConfigUseCase := newMockValidateDoguConfigDomainUseCase(t)
+	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase, doguConfigUseCase)
 
 	repoMock.EXPECT().Update(ctx, &domain.BlueprintSpec{
 		Id: "testBlueprint1",
@@ -56,7 +57,8 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecStatically_invalid(t *testing
 	dependencyUseCase := newMockValidateDependenciesDomainUseCase(t)
 	mountsUseCase := newMockValidateAdditionalMountsDomainUseCase(t)
 	storageClassUseCase := newMockValidateDoguStorageClassDomainUseCase(t)
-	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase)
+	doguConfigUseCase := newMockValidateDoguConfigDomainUseCase(t)
+	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase, doguConfigUseCase)
 
 	repoMock.EXPECT().
 		Update(ctx, blueprint).
@@ -72,6 +74,59 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecStatically_invalid(t *testing
 	assert.ErrorContains(t, err, "blueprint spec is invalid: blueprint spec doesn't have an ID")
 }
 
+func TestBlueprintSpecUseCase_ValidateBlueprintSpecStatically_layers(t *testing.T) {
+	t.Run("validate merged blueprint", func(t *testing.T) {
+		//given
+		version := core.Version{Raw: "1.2.3-1"}
+		blueprint := &domain.BlueprintSpec{
+			Id: "team-a",
+			Layers: []domain.BlueprintLayer{
+				{Id: "team-a", Priority: 10},
+				{Id: "platform-base", Blueprint: domain.Blueprint{Dogus: []domain.Dogu{{Name: redmineQualifiedDoguName, Version: &version}}}},
+			},
+		}
+		repoMock := newMockBlueprintSpecRepository(t)
+		ctx := context.Background()
+		useCase := NewBlueprintSpecValidationUseCase(repoMock, nil, nil, nil, nil)
+
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		//when
+		err := useCase.ValidateBlueprintSpecStatically(ctx, blueprint)
+
+		//then
+		require.NoError(t, err)
+		assert.Len(t, blueprint.Blueprint.Dogus, 1)
+		assert.True(t, meta.IsStatusConditionTrue(blueprint.Conditions, domain.ConditionLayersMerged))
+	})
+	t.Run("conflicting layers", func(t *testing.T) {
+		//given
+		version := core.Version{Raw: "1.2.3-1"}
+		otherVersion := core.Version{Raw: "1.2.3-2"}
+		blueprint := &domain.BlueprintSpec{
+			Id: "team-a",
+			Layers: []domain.BlueprintLayer{
+				{Id: "team-a", Blueprint: domain.Blueprint{Dogus: []domain.Dogu{{Name: redmineQualifiedDoguName, Version: &version}}}},
+				{Id: "team-b", Blueprint: domain.Blueprint{Dogus: []domain.Dogu{{Name: redmineQualifiedDoguName, Version: &otherVersion}}}},
+			},
+		}
+		repoMock := newMockBlueprintSpecRepository(t)
+		ctx := context.Background()
+		useCase := NewBlueprintSpecValidationUseCase(repoMock, nil, nil, nil, nil)
+
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		//when
+		err := useCase.ValidateBlueprintSpecStatically(ctx, blueprint)
+
+		//then
+		var invalidError *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "blueprint layers are conflicting")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionValid))
+	})
+}
+
 func TestBlueprintSpecUseCase_ValidateBlueprintSpecStatically_repoError(t *testing.T) {
 	t.Run("error while saving blueprint spec", func(t *testing.T) {
 		//given
@@ -83,7 +138,8 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecStatically_repoError(t *testi
 		dependencyUseCase := newMockValidateDependenciesDomainUseCase(t)
 		mountsUseCase := newMockValidateAdditionalMountsDomainUseCase(t)
 		storageClassUseCase := newMockValidateDoguStorageClassDomainUseCase(t)
-		useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase)
+		doguConfigUseCase := newMockValidateDoguConfigDomainUseCase(t)
+		useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase, doguConfigUseCase)
 
 		repoMock.EXPECT().Update(ctx, mock.Anything).Return(&domainservice.InternalError{Message: "test-error"})
 
@@ -110,11 +166,13 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecDynamically_ok(t *testing.T)
 	dependencyUseCase := newMockValidateDependenciesDomainUseCase(t)
 	mountsUseCase := newMockValidateAdditionalMountsDomainUseCase(t)
 	storageClassUseCase := newMockValidateDoguStorageClassDomainUseCase(t)
-	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase)
+	doguConfigUseCase := newMockValidateDoguConfigDomainUseCase(t)
+	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase, doguConfigUseCase)
 
 	dependencyUseCase.EXPECT().ValidateDependenciesForAllDogus(ctx, mock.Anything).Return(nil)
 	mountsUseCase.EXPECT().ValidateAdditionalMounts(ctx, mock.Anything).Return(nil)
 	storageClassUseCase.EXPECT().ValidateDoguStorageClass(ctx, mock.Anything).Return(nil)
+	doguConfigUseCase.EXPECT().ValidateDoguConfig(ctx, mock.Anything, false).Return(nil)
 
 	repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
 
@@ -133,7 +191,8 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecDynamically_invalid(t *testin
 	dependencyUseCase := newMockValidateDependenciesDomainUseCase(t)
 	mountsUseCase := newMockValidateAdditionalMountsDomainUseCase(t)
 	storageClassUseCase := newMockValidateDoguStorageClassDomainUseCase(t)
-	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase)
+	doguConfigUseCase := newMockValidateDoguConfigDomainUseCase(t)
+	useCase := NewBlueprintSpecValidationUseCase(repoMock, dependencyUseCase, mountsUseCase, storageClassUseCase, doguConfigUseCase)
 
 	version, _ := core.ParseVersion("1.0.0-1")
 	blueprint := &domain.BlueprintSpec{
@@ -148,9 +207,11 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecDynamically_invalid(t *testin
 	invalidDependencyError := errors.New("invalid dependencies")
 	invalidMountsError := errors.New("invalid mounts")
 	invalidStorageClassError := errors.New("invalid storage class")
+	invalidDoguConfigError := errors.New("invalid dogu config")
 	dependencyUseCase.EXPECT().ValidateDependenciesForAllDogus(ctx, mock.Anything).Return(invalidDependencyError)
 	mountsUseCase.EXPECT().ValidateAdditionalMounts(ctx, mock.Anything).Return(invalidMountsError)
 	storageClassUseCase.EXPECT().ValidateDoguStorageClass(ctx, mock.Anything).Return(invalidStorageClassError)
+	doguConfigUseCase.EXPECT().ValidateDoguConfig(ctx, mock.Anything, false).Return(invalidDoguConfigError)
 	repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
 
 	// when
@@ -164,6 +225,7 @@ func TestBlueprintSpecUseCase_ValidateBlueprintSpecDynamically_invalid(t *testin
 	assert.ErrorAs(t, err, &invalidError)
 	assert.ErrorIs(t, err, invalidDependencyError)
 	assert.ErrorIs(t, err, invalidMountsError)
+	assert.ErrorIs(t, err, invalidDoguConfigError)
 	assert.ErrorContains(t, err, "blueprint spec is invalid")
 
 	assert.Equal(t, "testBlueprint1", blueprint.Id)
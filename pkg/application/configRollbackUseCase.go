@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ConfigRollbackUseCase restores the dogu, sensitive dogu and global config of an earlier domain.BlueprintRevision.
+type ConfigRollbackUseCase struct {
+	blueprintRepository           blueprintSpecRepository
+	revisionRepository            blueprintRevisionRepository
+	doguConfigRepository          doguConfigRepository
+	sensitiveDoguConfigRepository sensitiveDoguConfigRepository
+	globalConfigRepository        globalConfigRepository
+}
+
+func NewConfigRollbackUseCase(
+	blueprintRepository blueprintSpecRepository,
+	revisionRepository blueprintRevisionRepository,
+	doguConfigRepository doguConfigRepository,
+	sensitiveDoguConfigRepository sensitiveDoguConfigRepository,
+	globalConfigRepository globalConfigRepository,
+) *ConfigRollbackUseCase {
+	return &ConfigRollbackUseCase{
+		blueprintRepository:           blueprintRepository,
+		revisionRepository:            revisionRepository,
+		doguConfigRepository:          doguConfigRepository,
+		sensitiveDoguConfigRepository: sensitiveDoguConfigRepository,
+		globalConfigRepository:        globalConfigRepository,
+	}
+}
+
+// RollbackConfig restores the config of the revision given in domain.BlueprintConfiguration.RollbackToRevision.
+// The rollback is only executed while the blueprint is stopped and only once per request, which is recorded in the
+// blueprint until the request is removed. The rollback is stored as a new revision itself.
+// returns a domain.InvalidBlueprintError if the revision does not exist or
+// a domainservice.InternalError or domainservice.ConflictError if the config could not be loaded or persisted.
+func (useCase *ConfigRollbackUseCase) RollbackConfig(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	logger := log.FromContext(ctx).WithName("ConfigRollbackUseCase.RollbackConfig")
+	targetRevision := blueprint.Config.RollbackToRevision
+	if targetRevision == nil {
+		return useCase.resetConfigRollback(ctx, blueprint)
+	}
+	if !blueprint.Config.Stopped {
+		// the static validation rejects a rollback of a blueprint which is not stopped
+		return nil
+	}
+	if blueprint.IsConfigRollbackExecuted() {
+		logger.V(1).Info(fmt.Sprintf("config is already rolled back to revision %d", *targetRevision))
+		return nil
+	}
+
+	number, revisions, err := getNextRevisionNumber(ctx, useCase.revisionRepository, blueprint.Id)
+	if err != nil {
+		return useCase.handleFailedRollback(ctx, blueprint, err)
+	}
+
+	configToRestore, err := domain.GetConfigToRestore(revisions, *targetRevision)
+	if err != nil {
+		return useCase.handleFailedRollback(ctx, blueprint, &domain.InvalidBlueprintError{WrappedError: err, Message: "cannot roll back config"})
+	}
+	restoreDiff, err := determineRestoreStateDiff(ctx, useCase.doguConfigRepository, useCase.sensitiveDoguConfigRepository, useCase.globalConfigRepository, configToRestore)
+	if err != nil {
+		return useCase.handleFailedRollback(ctx, blueprint, err)
+	}
+
+	// store the revision first, so that the current values are known even if the rollback fails halfway
+	revision := blueprint.RollbackConfig(number, *targetRevision, restoreDiff)
+	err = useCase.revisionRepository.Create(ctx, revision)
+	if err != nil {
+		return useCase.handleFailedRollback(ctx, blueprint, fmt.Errorf("could not store revision before rolling back config: %w", err))
+	}
+	deleteExpiredRevisions(ctx, useCase.revisionRepository, append(revisions, revision))
+
+	err = applyRestoreStateDiff(ctx, useCase.doguConfigRepository, useCase.sensitiveDoguConfigRepository, useCase.globalConfigRepository, restoreDiff)
+	if err != nil {
+		return useCase.handleFailedRollback(ctx, blueprint, fmt.Errorf("could not roll back config to revision %d: %w", *targetRevision, err))
+	}
+
+	blueprint.MarkConfigRolledBack()
+	err = useCase.blueprintRepository.Update(ctx, blueprint)
+	if err != nil {
+		return fmt.Errorf("cannot update blueprint after rolling back config: %w", err)
+	}
+	logger.Info(fmt.Sprintf("rolled back config to revision %d", *targetRevision))
+	return nil
+}
+
+// resetConfigRollback removes the record of an executed rollback after its request was removed from the blueprint.
+func (useCase *ConfigRollbackUseCase) resetConfigRollback(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	if !blueprint.ResetConfigRollback() {
+		return nil
+	}
+	err := useCase.blueprintRepository.Update(ctx, blueprint)
+	if err != nil {
+		return fmt.Errorf("cannot update blueprint after the config rollback request was removed: %w", err)
+	}
+	return nil
+}
+
+func (useCase *ConfigRollbackUseCase) handleFailedRollback(ctx context.Context, blueprint *domain.BlueprintSpec, err error) error {
+	changed := blueprint.SetLastApplySucceededConditionOnError(domain.ReasonLastApplyErrorAtConfig, err)
+	if changed {
+		repoErr := useCase.blueprintRepository.Update(ctx, blueprint)
+		if repoErr != nil {
+			repoErr = errors.Join(repoErr, err)
+			log.FromContext(ctx).Error(repoErr, "cannot mark config rollback as failed")
+			return fmt.Errorf("cannot mark config rollback as failed: %w", repoErr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,333 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+)
+
+func TestNewConfigRollbackUseCase(t *testing.T) {
+	// given
+	blueprintRepoMock := newMockBlueprintSpecRepository(t)
+	revisionRepoMock := newMockBlueprintRevisionRepository(t)
+	doguConfigMock := newMockDoguConfigRepository(t)
+	sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+	globalConfigMock := newMockGlobalConfigRepository(t)
+
+	// when
+	useCase := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock)
+
+	// then
+	assert.Equal(t, blueprintRepoMock, useCase.blueprintRepository)
+	assert.Equal(t, revisionRepoMock, useCase.revisionRepository)
+	assert.Equal(t, doguConfigMock, useCase.doguConfigRepository)
+	assert.Equal(t, sensitiveDoguConfigMock, useCase.sensitiveDoguConfigRepository)
+	assert.Equal(t, globalConfigMock, useCase.globalConfigRepository)
+}
+
+func TestConfigRollbackUseCase_RollbackConfig(t *testing.T) {
+	oldValue := "old"
+	firstRevision := domain.BlueprintRevision{
+		BlueprintId: testBlueprintId,
+		Number:      1,
+		PreviousConfig: domain.RevisionConfig{
+			DoguConfig: map[common.DoguConfigKey]domain.ConfigValueState{
+				{DoguName: redmine, Key: "key"}: {Value: &oldValue, Exists: true},
+			},
+			SensitiveDoguConfig: map[common.DoguConfigKey]domain.ConfigValueState{
+				{DoguName: cas, Key: "secret"}: {},
+			},
+			GlobalConfig: map[common.GlobalConfigKey]domain.ConfigValueState{
+				"key": {},
+			},
+		},
+	}
+	newStoppedBlueprint := func(targetRevision int) *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id:     testBlueprintId,
+			Config: domain.BlueprintConfiguration{Stopped: true, RollbackToRevision: &targetRevision},
+		}
+	}
+
+	t.Run("do nothing without revision to roll back to", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Id: testBlueprintId, Config: domain.BlueprintConfiguration{Stopped: true}}
+		sut := NewConfigRollbackUseCase(nil, nil, nil, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, blueprint.Events)
+	})
+	t.Run("do nothing if blueprint is not stopped", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Id: testBlueprintId, Config: domain.BlueprintConfiguration{RollbackToRevision: ptr.To(1)}}
+		sut := NewConfigRollbackUseCase(nil, nil, nil, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, blueprint.Events)
+	})
+	t.Run("do nothing if config is already rolled back", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(0)
+		blueprint.MarkConfigRolledBack()
+		// later revisions do not matter, as the request was already executed
+		sut := NewConfigRollbackUseCase(nil, nil, nil, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, blueprint.Events)
+	})
+	t.Run("remove record of executed rollback after the request was removed", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(0)
+		blueprint.MarkConfigRolledBack()
+		blueprint.Config.RollbackToRevision = nil
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, nil, nil, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, meta.FindStatusCondition(blueprint.Conditions, domain.ConditionConfigRolledBack))
+	})
+	t.Run("error removing record of executed rollback", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(0)
+		blueprint.MarkConfigRolledBack()
+		blueprint.Config.RollbackToRevision = nil
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(assert.AnError)
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, nil, nil, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot update blueprint after the config rollback request was removed")
+	})
+	t.Run("restore previous config", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(0)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, testBlueprintId).Return([]domain.BlueprintRevision{firstRevision}, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Run(func(ctx context.Context, revision domain.BlueprintRevision) {
+			newValue := "new"
+			secretValue := "pw"
+			globalValue := "value"
+			assert.Equal(t, 2, revision.Number)
+			assert.Equal(t, ptr.To(0), revision.RolledBackTo)
+			assert.Equal(t, domain.RevisionConfig{
+				DoguConfig: map[common.DoguConfigKey]domain.ConfigValueState{
+					{DoguName: redmine, Key: "key"}: {Value: &newValue, Exists: true},
+				},
+				SensitiveDoguConfig: map[common.DoguConfigKey]domain.ConfigValueState{
+					{DoguName: cas, Key: "secret"}: {Value: &secretValue, Exists: true},
+				},
+				GlobalConfig: map[common.GlobalConfigKey]domain.ConfigValueState{
+					"key": {Value: &globalValue, Exists: true},
+				},
+			}, revision.PreviousConfig)
+		}).Return(nil)
+
+		redmineConfig := config.CreateDoguConfig(redmine, map[config.Key]config.Value{"key": "new"})
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{redmine: redmineConfig}, nil)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{redmine}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{redmine: redmineConfig}, nil)
+		doguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Run(func(ctx context.Context, doguConfig config.DoguConfig) {
+			value, _ := doguConfig.Get("key")
+			assert.Equal(t, config.Value("old"), value)
+		}).Return(config.DoguConfig{}, nil)
+
+		casConfig := config.CreateDoguConfig(cas, map[config.Key]config.Value{"secret": "pw"})
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{cas: casConfig}, nil)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{cas}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{cas: casConfig}, nil)
+		sensitiveDoguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Run(func(ctx context.Context, doguConfig config.DoguConfig) {
+			_, exists := doguConfig.Get("secret")
+			assert.False(t, exists)
+		}).Return(config.DoguConfig{}, nil)
+
+		entries, _ := config.MapToEntries(map[string]any{"key": "value"})
+		globalConfig := config.CreateGlobalConfig(entries)
+		globalConfigMock := newMockGlobalConfigRepository(t)
+		globalConfigMock.EXPECT().Get(testCtx).Return(globalConfig, nil).Times(2)
+		globalConfigMock.EXPECT().Update(testCtx, mock.Anything).Run(func(ctx context.Context, globalConfig config.GlobalConfig) {
+			_, exists := globalConfig.Get("key")
+			assert.False(t, exists)
+		}).Return(globalConfig, nil)
+
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, blueprint.Events, 1)
+		assert.Equal(t, domain.ConfigRolledBackEvent{Revision: 2, TargetRevision: 0, ChangedEntries: 3}, blueprint.Events[0])
+		assert.True(t, blueprint.IsConfigRollbackExecuted())
+	})
+	t.Run("error on unknown revision", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(5)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, testBlueprintId).Return([]domain.BlueprintRevision{firstRevision}, nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, nil, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		var invalidError *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "cannot restore revision 5 as the latest revision is 1")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionLastApplySucceeded))
+	})
+	t.Run("error loading revisions", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(0)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, testBlueprintId).Return(nil, assert.AnError)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, nil, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load revisions of blueprint")
+	})
+	t.Run("error loading actual config", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(0)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, testBlueprintId).Return([]domain.BlueprintRevision{firstRevision}, nil)
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).Return(nil, assert.AnError)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, doguConfigMock, nil, nil)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not load dogu config to restore")
+	})
+	t.Run("error storing revision", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(1)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, testBlueprintId).Return([]domain.BlueprintRevision{firstRevision}, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(assert.AnError)
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(nil, nil)
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(nil, nil)
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigMock := newMockGlobalConfigRepository(t)
+		globalConfigMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not store revision before rolling back config")
+	})
+	t.Run("error applying config", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(1)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, testBlueprintId).Return([]domain.BlueprintRevision{firstRevision}, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(nil)
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(nil, nil).Once()
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(nil, assert.AnError).Once()
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(nil, nil).Times(2)
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigMock := newMockGlobalConfigRepository(t)
+		globalConfigMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil).Times(2)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not roll back config to revision 1")
+		assert.False(t, blueprint.IsConfigRollbackExecuted(), "the rollback should be retried")
+	})
+	t.Run("error updating blueprint", func(t *testing.T) {
+		// given
+		blueprint := newStoppedBlueprint(1)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, testBlueprintId).Return([]domain.BlueprintRevision{firstRevision}, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(nil)
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(nil, nil).Times(2)
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(nil, nil).Times(2)
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigMock := newMockGlobalConfigRepository(t)
+		globalConfigMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil).Times(2)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(assert.AnError)
+
+		sut := NewConfigRollbackUseCase(blueprintRepoMock, revisionRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock)
+
+		// when
+		err := sut.RollbackConfig(testCtx, blueprint)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot update blueprint after rolling back config")
+	})
+}
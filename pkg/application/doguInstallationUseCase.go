@@ -15,8 +15,8 @@ import (
 
 const noDowngradesExplanationTextFmt = "downgrades are not allowed as the data model of the %s could have changed and " +
 	"doing rollbacks to older models is not supported. " +
-	"You can downgrade %s by restoring a backup. " +
-	"If you want an 'allow-downgrades' flag, issue a feature request"
+	"You can downgrade %s by restoring a backup or by allowing dogu downgrades in the blueprint, " +
+	"which creates a backup before the downgrade"
 
 type DoguInstallationUseCase struct {
 	blueprintSpecRepo       blueprintSpecRepository
@@ -24,6 +24,7 @@ type DoguInstallationUseCase struct {
 	globalConfigRepo        globalConfigRepository
 	doguConfigRepo          doguConfigRepository
 	sensitiveDoguConfigRepo sensitiveDoguConfigRepository
+	sortDoguDiffsUseCase    sortDoguDiffsDomainUseCase
 }
 
 func NewDoguInstallationUseCase(
@@ -32,6 +33,7 @@ func NewDoguInstallationUseCase(
 	globalConfigRepo globalConfigRepository,
 	doguConfigRepo doguConfigRepository,
 	sensitiveDoguConfigRepo sensitiveDoguConfigRepository,
+	sortDoguDiffsUseCase sortDoguDiffsDomainUseCase,
 ) *DoguInstallationUseCase {
 	return &DoguInstallationUseCase{
 		blueprintSpecRepo:       blueprintSpecRepo,
@@ -39,6 +41,7 @@ func NewDoguInstallationUseCase(
 		globalConfigRepo:        globalConfigRepo,
 		doguConfigRepo:          doguConfigRepo,
 		sensitiveDoguConfigRepo: sensitiveDoguConfigRepo,
+		sortDoguDiffsUseCase:    sortDoguDiffsUseCase,
 	}
 }
 
@@ -107,6 +110,7 @@ func (useCase *DoguInstallationUseCase) getDoguConfigUpdateTimes(ctx context.Con
 }
 
 // ApplyDoguStates applies the expected dogu state from the Blueprint to the ecosystem.
+// The dogus are applied in the order of their dependencies, see domain.DoguDiffs.SortByDependencies.
 // Fail-fast here, so that the possible damage is as small as possible.
 func (useCase *DoguInstallationUseCase) ApplyDoguStates(ctx context.Context, blueprint *domain.BlueprintSpec) error {
 	logger := log.FromContext(ctx).WithName("DoguInstallationUseCase.ApplyDoguChanges")
@@ -117,8 +121,65 @@ func (useCase *DoguInstallationUseCase) ApplyDoguStates(ctx context.Context, blu
 		return fmt.Errorf("cannot load dogu installations to apply dogu state: %w", err)
 	}
 
-	for _, doguDiff := range blueprint.StateDiff.DoguDiffs {
-		err = useCase.applyDoguState(ctx, doguDiff, dogus[doguDiff.DoguName], blueprint.Config)
+	if blueprint.StateDiff.DoguDiffs.HasChanges() {
+		// keep the sorted diffs in the blueprint, so that the status shows the order in which the dogus were applied
+		blueprint.StateDiff.DoguDiffs, err = useCase.sortDoguDiffsUseCase.SortDoguDiffsByDependencies(ctx, blueprint.StateDiff.DoguDiffs)
+		if err != nil {
+			return fmt.Errorf("cannot determine the order to apply dogu states: %w", err)
+		}
+	}
+
+	return useCase.applyDoguDiffs(ctx, blueprint.StateDiff.DoguDiffs, dogus, blueprint.Id, blueprint.Config)
+}
+
+// ApplyNextRolloutWave applies the dogu changes of the next rollout wave of the blueprint, see domain.RolloutWaves.
+// The wave only gets applied if all dogus are up to date, so that a wave does not start before the previous one is completed.
+// returns the applied wave and the number of waves which are still pending afterward. The wave is empty if there are no dogu changes left.
+// returns a domain.DogusNotUpToDateError if there are dogus which are not up to date yet or
+// returns a domainservice.InternalError if there was an unspecified error while collecting or modifying the ecosystem state.
+func (useCase *DoguInstallationUseCase) ApplyNextRolloutWave(ctx context.Context, blueprint *domain.BlueprintSpec) (domain.DoguDiffs, int, error) {
+	logger := log.FromContext(ctx).WithName("DoguInstallationUseCase.ApplyNextRolloutWave")
+	if !blueprint.StateDiff.DoguDiffs.HasChanges() {
+		return nil, 0, nil
+	}
+
+	waves, err := useCase.sortDoguDiffsUseCase.GroupDoguDiffsIntoWaves(ctx, blueprint.StateDiff.DoguDiffs, blueprint.Config.RolloutWaves)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot determine the rollout waves to apply dogu states: %w", err)
+	}
+	if len(waves) == 0 {
+		return nil, 0, nil
+	}
+
+	dogusNotUpToDate, err := useCase.CheckDogusUpToDate(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot check if the previous rollout wave is completed: %w", err)
+	}
+	if len(dogusNotUpToDate) > 0 {
+		return nil, 0, &domain.DogusNotUpToDateError{
+			Message: fmt.Sprintf("waiting for dogus to be up to date before the next rollout wave: %v", dogusNotUpToDate),
+		}
+	}
+
+	dogus, err := useCase.doguRepo.GetAll(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot load dogu installations to apply dogu state: %w", err)
+	}
+
+	nextWave, pendingWaves := waves[0], len(waves)-1
+	logger.Info("apply rollout wave", "dogus", nextWave.GetDoguNames(), "pendingWaves", pendingWaves)
+	return nextWave, pendingWaves, useCase.applyDoguDiffs(ctx, nextWave, dogus, blueprint.Id, blueprint.Config)
+}
+
+func (useCase *DoguInstallationUseCase) applyDoguDiffs(
+	ctx context.Context,
+	doguDiffs domain.DoguDiffs,
+	dogus map[cescommons.SimpleName]*ecosystem.DoguInstallation,
+	blueprintId string,
+	blueprintConfig domain.BlueprintConfiguration,
+) error {
+	for _, doguDiff := range doguDiffs {
+		err := useCase.applyDoguState(ctx, doguDiff, dogus[doguDiff.DoguName], blueprintId, blueprintConfig)
 		if err != nil {
 			return fmt.Errorf("an error occurred while applying dogu state to the ecosystem: %w", err)
 		}
@@ -130,6 +191,7 @@ func (useCase *DoguInstallationUseCase) applyDoguState(
 	ctx context.Context,
 	doguDiff domain.DoguDiff,
 	doguInstallation *ecosystem.DoguInstallation,
+	blueprintId string,
 	blueprintConfig domain.BlueprintConfiguration,
 ) error {
 	logger := log.FromContext(ctx).
@@ -145,7 +207,10 @@ func (useCase *DoguInstallationUseCase) applyDoguState(
 				doguDiff.Expected.MinVolumeSize,
 				doguDiff.Expected.StorageClassName,
 				doguDiff.Expected.AdditionalMounts,
+				doguDiff.Expected.ReverseProxyConfig,
 			)
+			// remember the blueprint, so that the dogu can be pruned when it is removed from the blueprint
+			newDogu.InstalledByBlueprint = blueprintId
 			return useCase.doguRepo.Create(ctx, newDogu)
 		case domain.ActionUninstall:
 			if doguInstallation == nil {
@@ -157,8 +222,12 @@ func (useCase *DoguInstallationUseCase) applyDoguState(
 			doguInstallation.Upgrade(doguDiff.Expected.Version)
 			continue
 		case domain.ActionDowngrade:
+			if !blueprintConfig.AllowDoguDowngrades {
+				return fmt.Errorf(noDowngradesExplanationTextFmt, "dogu", "dogus")
+			}
 			logger.Info("downgrade dogu")
-			return fmt.Errorf(noDowngradesExplanationTextFmt, "dogu", "dogus")
+			doguInstallation.Downgrade(doguDiff.Expected.Version)
+			continue
 		case domain.ActionSwitchDoguNamespace:
 			logger.Info("do namespace switch for dogu")
 			err := doguInstallation.SwitchNamespace(
@@ -177,6 +246,10 @@ func (useCase *DoguInstallationUseCase) applyDoguState(
 			logger.Info("update additional mounts")
 			doguInstallation.UpdateAdditionalMounts(doguDiff.Expected.AdditionalMounts)
 			continue
+		case domain.ActionUpdateReverseProxyConfig:
+			logger.Info("update reverse proxy config")
+			doguInstallation.UpdateReverseProxyConfig(doguDiff.Expected.ReverseProxyConfig)
+			continue
 		default:
 			return fmt.Errorf("cannot perform unknown action %q for dogu %q", action, doguDiff.DoguName)
 		}
@@ -12,6 +12,7 @@ import (
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	"github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,7 +44,7 @@ var (
 func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 	t.Run("action none", func(t *testing.T) {
 		// given
-		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(testCtx, domain.DoguDiff{
@@ -62,7 +63,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 		}, &ecosystem.DoguInstallation{
 			Name:    postgresqlQualifiedName,
 			Version: version3211,
-		}, domain.BlueprintConfiguration{})
+		}, "", domain.BlueprintConfiguration{})
 
 		// then
 		require.NoError(t, err)
@@ -80,13 +81,12 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 			},
 		}
 
+		expectedDogu := ecosystem.InstallDogu(postgresqlQualifiedName, &version3211, &volumeSize, &storageClassName, additionalMounts, ecosystem.ReverseProxyConfig{})
+		expectedDogu.InstalledByBlueprint = "my-blueprint"
 		doguRepoMock := newMockDoguInstallationRepository(t)
-		doguRepoMock.EXPECT().
-			Create(testCtx,
-				ecosystem.InstallDogu(postgresqlQualifiedName, &version3211, &volumeSize, &storageClassName, additionalMounts)).
-			Return(nil)
+		doguRepoMock.EXPECT().Create(testCtx, expectedDogu).Return(nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -116,6 +116,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{domain.ActionInstall},
 			},
 			nil,
+			"my-blueprint",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -129,7 +130,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 			Delete(testCtx, cescommons.SimpleName("postgresql")).
 			Return(nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -142,6 +143,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				Name:    postgresqlQualifiedName,
 				Version: version3211,
 			},
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -152,7 +154,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 	t.Run("action uninstall throws NotFoundError when dogu not found", func(t *testing.T) {
 		doguRepoMock := newMockDoguInstallationRepository(t)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -162,6 +164,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{domain.ActionUninstall},
 			},
 			nil,
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -181,7 +184,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 			Update(testCtx, dogu).
 			Return(nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		dogu.PauseReconciliation = true // test if it gets reset on update (the dogu in the EXPECT Update call has this to false)
 
@@ -196,6 +199,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{domain.ActionUpgrade},
 			},
 			dogu,
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -211,7 +215,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 			Version: version3212,
 		}
 
-		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -224,6 +228,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{domain.ActionDowngrade},
 			},
 			dogu,
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -232,6 +237,39 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 		assert.Equal(t, version3212, dogu.Version)
 	})
 
+	t.Run("action downgrade allowed", func(t *testing.T) {
+		dogu := &ecosystem.DoguInstallation{
+			Name:    postgresqlQualifiedName,
+			Version: version3212,
+		}
+		doguRepoMock := newMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().
+			Update(testCtx, dogu).
+			Return(nil)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
+
+		// when
+		err := sut.applyDoguState(
+			testCtx,
+			domain.DoguDiff{
+				DoguName: "postgresql",
+				Expected: domain.DoguDiffState{
+					Version: &version3211,
+				},
+				NeededActions: []domain.Action{domain.ActionDowngrade},
+			},
+			dogu,
+			"",
+			domain.BlueprintConfiguration{AllowDoguDowngrades: true},
+		)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, version3211, dogu.Version)
+		assert.True(t, dogu.UpgradeConfig.ForceUpgrade)
+	})
+
 	t.Run("action update volume size", func(t *testing.T) {
 		volumeSize := resource.MustParse("2Gi")
 		expectedVolumeSize := resource.MustParse("3Gi")
@@ -248,7 +286,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 		doguRepoMock := newMockDoguInstallationRepository(t)
 		doguRepoMock.EXPECT().Update(testCtx, expectedDogu).Return(nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -261,6 +299,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{domain.ActionUpdateDoguResourceMinVolumeSize},
 			},
 			dogu,
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -329,10 +368,51 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 		doguRepoMock := newMockDoguInstallationRepository(t)
 		doguRepoMock.EXPECT().Update(testCtx, expectedDogu).Return(nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
+
+		// when
+		err := sut.applyDoguState(testCtx, diff, dogu, "", domain.BlueprintConfiguration{})
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should update reverse proxy config", func(t *testing.T) {
+		maxBodySize := resource.MustParse("100Mi")
+		reverseProxyConfig := ecosystem.ReverseProxyConfig{
+			MaxBodySize:   &maxBodySize,
+			RewriteTarget: ecosystem.RewriteTarget(rewriteTarget),
+		}
+		// the additional config of an admin is kept, as the blueprint does not set it
+		expectedDogu := &ecosystem.DoguInstallation{
+			Name: postgresqlQualifiedName,
+			ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+				MaxBodySize:      &maxBodySize,
+				RewriteTarget:    ecosystem.RewriteTarget(rewriteTarget),
+				AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+			},
+		}
+
+		dogu := &ecosystem.DoguInstallation{
+			Name: postgresqlQualifiedName,
+			ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+				AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+			},
+		}
+
+		diff := domain.DoguDiff{
+			DoguName:      "postgresql",
+			Expected:      domain.DoguDiffState{ReverseProxyConfig: reverseProxyConfig},
+			NeededActions: []domain.Action{domain.ActionUpdateReverseProxyConfig},
+		}
+
+		doguRepoMock := newMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().Update(testCtx, expectedDogu).Return(nil)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
-		err := sut.applyDoguState(testCtx, diff, dogu, domain.BlueprintConfiguration{})
+		err := sut.applyDoguState(testCtx, diff, dogu, "", domain.BlueprintConfiguration{})
 
 		// then
 		require.NoError(t, err)
@@ -356,7 +436,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 		doguRepoMock := newMockDoguInstallationRepository(t)
 		doguRepoMock.EXPECT().Update(testCtx, expectedDogu).Return(nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -373,6 +453,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				},
 			},
 			dogu,
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -386,7 +467,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 			Version: version3212,
 		}
 
-		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -399,6 +480,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{domain.ActionSwitchDoguNamespace},
 			},
 			dogu,
+			"",
 			domain.BlueprintConfiguration{
 				AllowDoguNamespaceSwitch: false,
 			},
@@ -416,7 +498,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 		doguRepoMock := newMockDoguInstallationRepository(t)
 		doguRepoMock.EXPECT().Update(testCtx, dogu).Return(nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -429,6 +511,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{domain.ActionSwitchDoguNamespace},
 			},
 			dogu,
+			"",
 			domain.BlueprintConfiguration{
 				AllowDoguNamespaceSwitch: true,
 			},
@@ -441,7 +524,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 
 	t.Run("unknown action", func(t *testing.T) {
 		// given
-		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -454,6 +537,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{"unknown"},
 			},
 			nil,
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -463,7 +547,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 
 	t.Run("should no fail with no actions", func(t *testing.T) {
 		// given
-		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil, nil)
 
 		// when
 		err := sut.applyDoguState(
@@ -476,6 +560,7 @@ func TestDoguInstallationUseCase_applyDoguState(t *testing.T) {
 				NeededActions: []domain.Action{},
 			},
 			nil,
+			"",
 			domain.BlueprintConfiguration{},
 		)
 
@@ -490,7 +575,7 @@ func TestDoguInstallationUseCase_ApplyDoguStates(t *testing.T) {
 		doguRepoMock := newMockDoguInstallationRepository(t)
 		doguRepoMock.EXPECT().GetAll(testCtx).Return(nil, assert.AnError)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.ApplyDoguStates(testCtx, &domain.BlueprintSpec{})
@@ -518,7 +603,7 @@ func TestDoguInstallationUseCase_ApplyDoguStates(t *testing.T) {
 		doguRepoMock := newMockDoguInstallationRepository(t)
 		doguRepoMock.EXPECT().GetAll(testCtx).Return(map[cescommons.SimpleName]*ecosystem.DoguInstallation{}, nil)
 
-		sut := NewDoguInstallationUseCase(blueprintSpecRepoMock, doguRepoMock, nil, nil, nil)
+		sut := NewDoguInstallationUseCase(blueprintSpecRepoMock, doguRepoMock, nil, nil, nil, nil)
 
 		// when
 		err := sut.ApplyDoguStates(testCtx, blueprint)
@@ -550,7 +635,10 @@ func TestDoguInstallationUseCase_ApplyDoguStates(t *testing.T) {
 			},
 		}, nil)
 
-		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil)
+		sortMock := newMockSortDoguDiffsDomainUseCase(t)
+		sortMock.EXPECT().SortDoguDiffsByDependencies(testCtx, blueprint.StateDiff.DoguDiffs).Return(blueprint.StateDiff.DoguDiffs, nil)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, sortMock)
 
 		// when
 		err := sut.ApplyDoguStates(testCtx, blueprint)
@@ -559,6 +647,197 @@ func TestDoguInstallationUseCase_ApplyDoguStates(t *testing.T) {
 		require.ErrorContains(t, err, fmt.Sprintf(noDowngradesExplanationTextFmt, "dogu", "dogus"))
 		require.ErrorContains(t, err, "an error occurred while applying dogu state to the ecosystem")
 	})
+
+	t.Run("apply dogus in sorted order and stop on first error", func(t *testing.T) {
+		// given
+		redmineInstall := domain.DoguDiff{
+			DoguName:      "redmine",
+			Expected:      domain.DoguDiffState{Namespace: "official", Version: &version3212},
+			NeededActions: []domain.Action{domain.ActionInstall},
+		}
+		postgresqlInstall := domain.DoguDiff{
+			DoguName:      "postgresql",
+			Expected:      domain.DoguDiffState{Namespace: "official", Version: &version3212},
+			NeededActions: []domain.Action{domain.ActionInstall},
+		}
+		blueprint := &domain.BlueprintSpec{
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{redmineInstall, postgresqlInstall},
+			},
+		}
+		sortedDiffs := domain.DoguDiffs{postgresqlInstall, redmineInstall}
+
+		doguRepoMock := newMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetAll(testCtx).Return(map[cescommons.SimpleName]*ecosystem.DoguInstallation{}, nil)
+		// only postgresql is tried, redmine must not be created
+		doguRepoMock.EXPECT().Create(testCtx, mock.MatchedBy(func(dogu *ecosystem.DoguInstallation) bool {
+			return dogu.Name.SimpleName == "postgresql"
+		})).Return(assert.AnError)
+		sortMock := newMockSortDoguDiffsDomainUseCase(t)
+		sortMock.EXPECT().SortDoguDiffsByDependencies(testCtx, blueprint.StateDiff.DoguDiffs).Return(sortedDiffs, nil)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, sortMock)
+
+		// when
+		err := sut.ApplyDoguStates(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, sortedDiffs, blueprint.StateDiff.DoguDiffs)
+	})
+
+	t.Run("cannot sort dogu diffs", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{
+					{DoguName: "postgresql", NeededActions: []domain.Action{domain.ActionInstall}},
+				},
+			},
+		}
+
+		doguRepoMock := newMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetAll(testCtx).Return(map[cescommons.SimpleName]*ecosystem.DoguInstallation{}, nil)
+		sortMock := newMockSortDoguDiffsDomainUseCase(t)
+		sortMock.EXPECT().SortDoguDiffsByDependencies(testCtx, blueprint.StateDiff.DoguDiffs).Return(nil, assert.AnError)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, sortMock)
+
+		// when
+		err := sut.ApplyDoguStates(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot determine the order to apply dogu states")
+	})
+}
+
+func TestDoguInstallationUseCase_ApplyNextRolloutWave(t *testing.T) {
+	postgresqlInstall := domain.DoguDiff{
+		DoguName:      "postgresql",
+		Expected:      domain.DoguDiffState{Namespace: "official", Version: &version3212},
+		NeededActions: []domain.Action{domain.ActionInstall},
+	}
+	redmineInstall := domain.DoguDiff{
+		DoguName:      "redmine",
+		Expected:      domain.DoguDiffState{Namespace: "official", Version: &version3212},
+		NeededActions: []domain.Action{domain.ActionInstall},
+	}
+	rolloutWaves := domain.RolloutWaves{ByDependencyDepth: true}
+	newBlueprint := func() *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Config: domain.BlueprintConfiguration{RolloutWaves: rolloutWaves},
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{redmineInstall, postgresqlInstall},
+			},
+		}
+	}
+
+	t.Run("apply only the first wave", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		doguRepoMock := newMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetAll(testCtx).Return(map[cescommons.SimpleName]*ecosystem.DoguInstallation{}, nil)
+		doguRepoMock.EXPECT().Create(testCtx, mock.MatchedBy(func(dogu *ecosystem.DoguInstallation) bool {
+			return dogu.Name.SimpleName == "postgresql"
+		})).Return(nil)
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(config.GlobalConfig{}, nil)
+		sortMock := newMockSortDoguDiffsDomainUseCase(t)
+		sortMock.EXPECT().GroupDoguDiffsIntoWaves(testCtx, blueprint.StateDiff.DoguDiffs, rolloutWaves).
+			Return([]domain.DoguDiffs{{postgresqlInstall}, {redmineInstall}}, nil)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, globalConfigRepoMock, nil, nil, sortMock)
+
+		// when
+		wave, pendingWaves, err := sut.ApplyNextRolloutWave(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, domain.DoguDiffs{postgresqlInstall}, wave)
+		assert.Equal(t, 1, pendingWaves)
+	})
+
+	t.Run("nothing to apply without dogu changes", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			Config:    domain.BlueprintConfiguration{RolloutWaves: rolloutWaves},
+			StateDiff: domain.StateDiff{DoguDiffs: domain.DoguDiffs{{DoguName: "postgresql"}}},
+		}
+		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil, nil)
+
+		// when
+		wave, pendingWaves, err := sut.ApplyNextRolloutWave(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, wave)
+		assert.Equal(t, 0, pendingWaves)
+	})
+
+	t.Run("wait for dogus of the previous wave", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		doguRepoMock := newMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetAll(testCtx).Return(map[cescommons.SimpleName]*ecosystem.DoguInstallation{
+			"postgresql": {
+				Name:             postgresqlQualifiedName,
+				Version:          version3212,
+				InstalledVersion: version3211,
+			},
+		}, nil).Once()
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(config.GlobalConfig{}, nil)
+		sortMock := newMockSortDoguDiffsDomainUseCase(t)
+		sortMock.EXPECT().GroupDoguDiffsIntoWaves(testCtx, blueprint.StateDiff.DoguDiffs, rolloutWaves).
+			Return([]domain.DoguDiffs{{redmineInstall}}, nil)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, globalConfigRepoMock, nil, nil, sortMock)
+
+		// when
+		wave, _, err := sut.ApplyNextRolloutWave(testCtx, blueprint)
+
+		// then
+		var notUpToDateErr *domain.DogusNotUpToDateError
+		require.ErrorAs(t, err, &notUpToDateErr)
+		assert.ErrorContains(t, err, "waiting for dogus to be up to date before the next rollout wave: [postgresql]")
+		assert.Empty(t, wave)
+	})
+
+	t.Run("cannot check if dogus are up to date", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		doguRepoMock := newMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetAll(testCtx).Return(nil, assert.AnError)
+		sortMock := newMockSortDoguDiffsDomainUseCase(t)
+		sortMock.EXPECT().GroupDoguDiffsIntoWaves(testCtx, blueprint.StateDiff.DoguDiffs, rolloutWaves).
+			Return([]domain.DoguDiffs{{redmineInstall}}, nil)
+
+		sut := NewDoguInstallationUseCase(nil, doguRepoMock, nil, nil, nil, sortMock)
+
+		// when
+		_, _, err := sut.ApplyNextRolloutWave(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot check if the previous rollout wave is completed")
+	})
+
+	t.Run("cannot group dogu diffs into waves", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		sortMock := newMockSortDoguDiffsDomainUseCase(t)
+		sortMock.EXPECT().GroupDoguDiffsIntoWaves(testCtx, blueprint.StateDiff.DoguDiffs, rolloutWaves).Return(nil, assert.AnError)
+
+		sut := NewDoguInstallationUseCase(nil, nil, nil, nil, nil, sortMock)
+
+		// when
+		_, _, err := sut.ApplyNextRolloutWave(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot determine the rollout waves to apply dogu states")
+	})
 }
 
 func TestDoguInstallationUseCase_CheckDogusUpToDate(t *testing.T) {
@@ -13,46 +13,63 @@ import (
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	"github.com/cloudogu/k8s-registry-lib/config"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// storeRevisionBackoff is used to retry storing a revision after the blueprint was applied.
+var storeRevisionBackoff = retry.DefaultBackoff
+
 type EcosystemConfigUseCase struct {
 	blueprintRepository           blueprintSpecRepository
 	doguConfigRepository          doguConfigRepository
 	sensitiveDoguConfigRepository sensitiveDoguConfigRepository
 	globalConfigRepository        globalConfigRepository
 	doguInstallationRepository    doguInstallationRepository
+	revisionRepository            blueprintRevisionRepository
+	configOwnershipRepository     configOwnershipRepository
 }
 
-func NewEcosystemConfigUseCase(blueprintRepository blueprintSpecRepository, doguConfigRepository doguConfigRepository, sensitiveDoguConfigRepository sensitiveDoguConfigRepository, globalConfigRepository globalConfigRepository, doguInstallationRepository domainservice.DoguInstallationRepository) *EcosystemConfigUseCase {
+func NewEcosystemConfigUseCase(blueprintRepository blueprintSpecRepository, doguConfigRepository doguConfigRepository, sensitiveDoguConfigRepository sensitiveDoguConfigRepository, globalConfigRepository globalConfigRepository, doguInstallationRepository domainservice.DoguInstallationRepository, revisionRepository blueprintRevisionRepository, configOwnershipRepository configOwnershipRepository) *EcosystemConfigUseCase {
 	return &EcosystemConfigUseCase{
 		blueprintRepository:           blueprintRepository,
 		doguConfigRepository:          doguConfigRepository,
 		sensitiveDoguConfigRepository: sensitiveDoguConfigRepository,
 		globalConfigRepository:        globalConfigRepository,
 		doguInstallationRepository:    doguInstallationRepository,
+		revisionRepository:            revisionRepository,
+		configOwnershipRepository:     configOwnershipRepository,
 	}
 }
 
 // ApplyConfig fetches the dogu and global config stateDiff of the blueprint and applies these keys to the repositories.
-func (useCase *EcosystemConfigUseCase) ApplyConfig(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+// It returns a snapshot of the changed config entries before the apply, which becomes the previous config of the
+// domain.BlueprintRevision created by CreateRevision after the whole blueprint got applied.
+// The config is applied all-or-nothing: If a write fails, the already written config entries are restored from the snapshot.
+// Afterwards, all config keys created or updated by the blueprint are owned by it, so that they get removed when they disappear from the blueprint.
+func (useCase *EcosystemConfigUseCase) ApplyConfig(ctx context.Context, blueprint *domain.BlueprintSpec) (domain.RevisionConfig, error) {
 	logger := log.FromContext(ctx).WithName("EcosystemConfigUseCase.ApplyConfig")
 
-	err := useCase.pauseReconciliationForDogus(ctx, blueprint.StateDiff)
-	if err != nil {
-		return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, fmt.Errorf("could not pause reconciliation for some dogus: %w", err))
+	var snapshot domain.RevisionConfig
+	if blueprint.StateDiff.HasConfigChanges() {
+		var err error
+		snapshot, err = useCase.snapshotConfig(ctx, blueprint.StateDiff)
+		if err != nil {
+			return domain.RevisionConfig{}, useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, domain.ReasonLastApplyErrorAtConfig, fmt.Errorf("could not snapshot config before applying it: %w", err))
+		}
 	}
-	err = applyDoguConfigDiffs(ctx, useCase.doguConfigRepository, blueprint.StateDiff.DoguConfigDiffs)
+
+	err := useCase.pauseReconciliationForDogus(ctx, blueprint.StateDiff)
 	if err != nil {
-		return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, fmt.Errorf("could not apply normal dogu config: %w", err))
+		return domain.RevisionConfig{}, useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, domain.ReasonLastApplyErrorAtConfig, fmt.Errorf("could not pause reconciliation for some dogus: %w", err))
 	}
-	err = applyDoguConfigDiffs(ctx, useCase.sensitiveDoguConfigRepository, blueprint.StateDiff.SensitiveDoguConfigDiffs)
+	err = useCase.applyConfigDiffs(ctx, blueprint.StateDiff)
 	if err != nil {
-		return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, fmt.Errorf("could not apply sensitive dogu config: %w", err))
+		return domain.RevisionConfig{}, useCase.compensateFailedApply(ctx, blueprint, snapshot, err)
 	}
-	err = useCase.applyGlobalConfigDiffs(ctx, blueprint.StateDiff.GlobalConfigDiffs.GetGlobalConfigDiffsByAction())
+	err = useCase.updateConfigOwnership(ctx, blueprint)
 	if err != nil {
-		return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, fmt.Errorf("could not apply global config: %w", err))
+		return domain.RevisionConfig{}, useCase.compensateFailedApply(ctx, blueprint, snapshot, fmt.Errorf("could not save config ownership: %w", err))
 	}
 
 	if blueprint.StateDiff.HasConfigChanges() {
@@ -60,14 +77,184 @@ func (useCase *EcosystemConfigUseCase) ApplyConfig(ctx context.Context, blueprin
 		repoErr := useCase.blueprintRepository.Update(ctx, blueprint)
 
 		if repoErr != nil {
-			repoErr = errors.Join(repoErr, err)
 			logger.Error(repoErr, "cannot update blueprint events")
-			return fmt.Errorf("cannot update blueprint events: %w", repoErr)
+			return snapshot, fmt.Errorf("cannot update blueprint events: %w", repoErr)
 		}
 	}
+	return snapshot, nil
+}
+
+// CreateRevision stores a new domain.BlueprintRevision for the applied state diff of the blueprint.
+// The previous config is the snapshot taken by ApplyConfig. Storing the revision is retried, as the state diff of the
+// next reconciliation does not contain the applied changes anymore. The applied config is never rolled back here,
+// because the dogus were already applied and may run with the new config.
+func (useCase *EcosystemConfigUseCase) CreateRevision(ctx context.Context, blueprint *domain.BlueprintSpec, previousConfig domain.RevisionConfig) error {
+	err := retry.OnError(storeRevisionBackoff, func(error) bool { return true }, func() error {
+		return useCase.createRevision(ctx, blueprint, previousConfig)
+	})
+	if err != nil {
+		return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, domain.ReasonLastApplyErrorAtRevision, fmt.Errorf("could not store revision after applying blueprint: %w", err))
+	}
+	err = useCase.blueprintRepository.Update(ctx, blueprint)
+	if err != nil {
+		return fmt.Errorf("cannot update blueprint events: %w", err)
+	}
+	return nil
+}
+
+func (useCase *EcosystemConfigUseCase) updateConfigOwnership(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	ownership := blueprint.GetAppliedConfigOwnership()
+	if ownership.Equal(blueprint.ConfigOwnership) {
+		return nil
+	}
+	err := useCase.configOwnershipRepository.Update(ctx, blueprint.Id, ownership)
+	if err != nil {
+		return err
+	}
+	blueprint.ConfigOwnership = ownership
 	return nil
 }
 
+func (useCase *EcosystemConfigUseCase) applyConfigDiffs(ctx context.Context, diff domain.StateDiff) error {
+	err := applyDoguConfigDiffs(ctx, useCase.doguConfigRepository, diff.DoguConfigDiffs)
+	if err != nil {
+		return fmt.Errorf("could not apply normal dogu config: %w", err)
+	}
+	err = applyDoguConfigDiffs(ctx, useCase.sensitiveDoguConfigRepository, diff.SensitiveDoguConfigDiffs)
+	if err != nil {
+		return fmt.Errorf("could not apply sensitive dogu config: %w", err)
+	}
+	err = applyGlobalConfigDiffs(ctx, useCase.globalConfigRepository, diff.GlobalConfigDiffs.GetGlobalConfigDiffsByAction())
+	if err != nil {
+		return fmt.Errorf("could not apply global config: %w", err)
+	}
+	return nil
+}
+
+// snapshotConfig loads the actual states of all config entries which get changed by the state diff.
+func (useCase *EcosystemConfigUseCase) snapshotConfig(ctx context.Context, diff domain.StateDiff) (domain.RevisionConfig, error) {
+	doguConfig, err := useCase.doguConfigRepository.GetAllExisting(ctx, getDogusWithConfigChanges(diff.DoguConfigDiffs))
+	if err != nil {
+		return domain.RevisionConfig{}, fmt.Errorf("could not load dogu config: %w", err)
+	}
+	sensitiveDoguConfig, err := useCase.sensitiveDoguConfigRepository.GetAllExisting(ctx, getDogusWithConfigChanges(diff.SensitiveDoguConfigDiffs))
+	if err != nil {
+		return domain.RevisionConfig{}, fmt.Errorf("could not load sensitive dogu config: %w", err)
+	}
+	globalConfig, err := useCase.globalConfigRepository.Get(ctx)
+	if err != nil {
+		return domain.RevisionConfig{}, fmt.Errorf("could not load global config: %w", err)
+	}
+	return domain.NewConfigSnapshot(diff, doguConfig, sensitiveDoguConfig, globalConfig), nil
+}
+
+func getDogusWithConfigChanges(diffsByDogu map[cescommons.SimpleName]domain.DoguConfigDiffs) []cescommons.SimpleName {
+	var dogus []cescommons.SimpleName
+	for dogu, entryDiffs := range diffsByDogu {
+		if entryDiffs.HasChanges() {
+			dogus = append(dogus, dogu)
+		}
+	}
+	// sort to simplify tests
+	slices.Sort(dogus)
+	return dogus
+}
+
+// compensateFailedApply restores the snapshot of the config, so that a failed apply does not leave the config half-changed.
+func (useCase *EcosystemConfigUseCase) compensateFailedApply(ctx context.Context, blueprint *domain.BlueprintSpec, snapshot domain.RevisionConfig, applyErr error) error {
+	if snapshot.Len() == 0 {
+		// nothing was changed, as there are no config changes at all
+		return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, domain.ReasonLastApplyErrorAtConfig, applyErr)
+	}
+
+	restoreDiff, err := determineRestoreStateDiff(ctx, useCase.doguConfigRepository, useCase.sensitiveDoguConfigRepository, useCase.globalConfigRepository, snapshot)
+	if err == nil {
+		err = applyRestoreStateDiff(ctx, useCase.doguConfigRepository, useCase.sensitiveDoguConfigRepository, useCase.globalConfigRepository, restoreDiff)
+	}
+	if err != nil {
+		return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, domain.ReasonLastApplyErrorAtConfig,
+			errors.Join(applyErr, fmt.Errorf("could not roll back the config changes of this apply: %w", err)))
+	}
+	return useCase.handleFailedApplyEcosystemConfig(ctx, blueprint, domain.ReasonLastApplyErrorAtConfigRolledBack,
+		fmt.Errorf("%w; rolled back all config changes of this apply", applyErr))
+}
+
+// determineRestoreStateDiff loads the actual config of all entries in the config to restore and
+// determines the config diffs to restore it.
+func determineRestoreStateDiff(
+	ctx context.Context,
+	doguConfigRepository doguConfigRepository,
+	sensitiveDoguConfigRepository doguConfigRepository,
+	globalConfigRepository globalConfigRepository,
+	configToRestore domain.RevisionConfig,
+) (domain.StateDiff, error) {
+	dogus := configToRestore.GetDogus()
+	doguConfig, err := doguConfigRepository.GetAllExisting(ctx, dogus)
+	if err != nil {
+		return domain.StateDiff{}, fmt.Errorf("could not load dogu config to restore: %w", err)
+	}
+	sensitiveDoguConfig, err := sensitiveDoguConfigRepository.GetAllExisting(ctx, dogus)
+	if err != nil {
+		return domain.StateDiff{}, fmt.Errorf("could not load sensitive dogu config to restore: %w", err)
+	}
+	globalConfig, err := globalConfigRepository.Get(ctx)
+	if err != nil {
+		return domain.StateDiff{}, fmt.Errorf("could not load global config to restore: %w", err)
+	}
+	return domain.NewRestoreStateDiff(configToRestore, doguConfig, sensitiveDoguConfig, globalConfig), nil
+}
+
+// applyRestoreStateDiff applies all config diffs, even if some of them fail, to restore as much config as possible.
+func applyRestoreStateDiff(
+	ctx context.Context,
+	doguConfigRepository doguConfigRepository,
+	sensitiveDoguConfigRepository doguConfigRepository,
+	globalConfigRepository globalConfigRepository,
+	restoreDiff domain.StateDiff,
+) error {
+	return errors.Join(
+		applyDoguConfigDiffs(ctx, doguConfigRepository, restoreDiff.DoguConfigDiffs),
+		applyDoguConfigDiffs(ctx, sensitiveDoguConfigRepository, restoreDiff.SensitiveDoguConfigDiffs),
+		applyGlobalConfigDiffs(ctx, globalConfigRepository, restoreDiff.GlobalConfigDiffs.GetGlobalConfigDiffsByAction()),
+	)
+}
+
+func (useCase *EcosystemConfigUseCase) createRevision(ctx context.Context, blueprint *domain.BlueprintSpec, previousConfig domain.RevisionConfig) error {
+	number, revisions, err := getNextRevisionNumber(ctx, useCase.revisionRepository, blueprint.Id)
+	if err != nil {
+		return err
+	}
+	revision := blueprint.CreateRevision(number, previousConfig)
+	err = useCase.revisionRepository.Create(ctx, revision)
+	if err != nil {
+		return err
+	}
+	deleteExpiredRevisions(ctx, useCase.revisionRepository, append(revisions, revision))
+	return nil
+}
+
+// deleteExpiredRevisions deletes the revisions which exceed domain.MaxBlueprintRevisions.
+// Errors are only logged, as the expired revisions are deleted with the next revision again.
+func deleteExpiredRevisions(ctx context.Context, repo blueprintRevisionRepository, revisions []domain.BlueprintRevision) {
+	logger := log.FromContext(ctx).WithName("deleteExpiredRevisions")
+	for _, revision := range domain.GetExpiredRevisions(revisions) {
+		err := repo.Delete(ctx, revision.BlueprintId, revision.Number)
+		if err != nil {
+			logger.Error(err, "cannot delete expired revision", "revision", revision.Number)
+		}
+	}
+}
+
+// getNextRevisionNumber returns the number for the next revision of the blueprint together with all existing revisions.
+func getNextRevisionNumber(ctx context.Context, repo blueprintRevisionRepository, blueprintId string) (int, []domain.BlueprintRevision, error) {
+	revisions, err := repo.GetAll(ctx, blueprintId)
+	if err != nil {
+		return 0, nil, fmt.Errorf("cannot load revisions of blueprint %q: %w", blueprintId, err)
+	}
+	latest, _ := domain.GetLatestRevision(revisions)
+	return latest.Number + 1, revisions, nil
+}
+
 func (useCase *EcosystemConfigUseCase) pauseReconciliationForDogus(ctx context.Context, diff domain.StateDiff) error {
 	allDogus, err := useCase.doguInstallationRepository.GetAll(ctx)
 	if err != nil {
@@ -99,10 +286,10 @@ func findDoguDiff(diff domain.StateDiff, dogu *ecosystem.DoguInstallation) domai
 	return domain.DoguDiff{}
 }
 
-func (useCase *EcosystemConfigUseCase) applyGlobalConfigDiffs(ctx context.Context, globalConfigDiffsByAction map[domain.ConfigAction][]domain.GlobalConfigEntryDiff) error {
+func applyGlobalConfigDiffs(ctx context.Context, repo globalConfigRepository, globalConfigDiffsByAction map[domain.ConfigAction][]domain.GlobalConfigEntryDiff) error {
 	var errs []error
 
-	globalConfig, err := useCase.globalConfigRepository.Get(ctx)
+	globalConfig, err := repo.Get(ctx)
 	if err != nil {
 		return err
 	}
@@ -125,7 +312,7 @@ func (useCase *EcosystemConfigUseCase) applyGlobalConfigDiffs(ctx context.Contex
 	}
 
 	if len(entryDiffsToSet) != 0 || len(entryDiffsToRemove) != 0 {
-		_, err = useCase.globalConfigRepository.Update(ctx, config.GlobalConfig{Config: updatedEntries})
+		_, err = repo.Update(ctx, config.GlobalConfig{Config: updatedEntries})
 		errs = append(errs, err)
 	}
 
@@ -180,13 +367,13 @@ func saveDoguConfigs(
 	return nil
 }
 
-func (useCase *EcosystemConfigUseCase) handleFailedApplyEcosystemConfig(ctx context.Context, blueprint *domain.BlueprintSpec, err error) error {
+func (useCase *EcosystemConfigUseCase) handleFailedApplyEcosystemConfig(ctx context.Context, blueprint *domain.BlueprintSpec, reason string, err error) error {
 	logger := log.FromContext(ctx).
 		WithName("EcosystemConfigUseCase.handleFailedApplyEcosystemConfig").
 		WithValues("blueprintId", blueprint.Id)
 
 	// sets condition
-	changed := blueprint.SetLastApplySucceededConditionOnError(domain.ReasonLastApplyErrorAtConfig, err)
+	changed := blueprint.SetLastApplySucceededConditionOnError(reason, err)
 	if changed {
 		repoErr := useCase.blueprintRepository.Update(ctx, blueprint)
 
@@ -15,6 +15,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -76,18 +78,24 @@ func TestEcosystemConfigUseCase_ApplyConfig(t *testing.T) {
 		// Just check if the routine hits the repos. Check values in concrete test of methods.
 		doguConfigMock.EXPECT().
 			GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).
-			Return(map[cescommons.SimpleName]config.DoguConfig{
-				redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
-				cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
-			}, nil)
+			RunAndReturn(func(ctx context.Context, dogus []cescommons.SimpleName) (map[cescommons.SimpleName]config.DoguConfig, error) {
+				// return new configs on each call as applying the diff changes them
+				return map[cescommons.SimpleName]config.DoguConfig{
+					redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
+					cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
+				}, nil
+			})
 		doguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Return(config.DoguConfig{}, nil).Times(2)
 
 		sensitiveDoguConfigMock.EXPECT().
 			GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).
-			Return(map[cescommons.SimpleName]config.DoguConfig{
-				redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
-				cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
-			}, nil)
+			RunAndReturn(func(ctx context.Context, dogus []cescommons.SimpleName) (map[cescommons.SimpleName]config.DoguConfig, error) {
+				// return new configs on each call as applying the diff changes them
+				return map[cescommons.SimpleName]config.DoguConfig{
+					redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
+					cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
+				}, nil
+			})
 		sensitiveDoguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Return(config.DoguConfig{}, nil).Times(2)
 
 		entries, _ := config.MapToEntries(map[string]any{})
@@ -99,15 +107,191 @@ func TestEcosystemConfigUseCase_ApplyConfig(t *testing.T) {
 		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
 		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
 
-		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock, newMockBlueprintRevisionRepository(t), newMockConfigOwnershipRepository(t))
 
 		// when
-		err := sut.ApplyConfig(testCtx, blueprint)
+		snapshot, err := sut.ApplyConfig(testCtx, blueprint)
 
 		// then
 		require.NoError(t, err)
-		require.Len(t, blueprint.Events, 1)
-		assert.Equal(t, domain.EcosystemConfigAppliedEvent{}, blueprint.Events[0])
+		assert.Equal(t, 5, snapshot.Len())
+		assert.Equal(t, []domain.Event{domain.EcosystemConfigAppliedEvent{}}, blueprint.Events)
+	})
+
+	t.Run("return snapshot of the changed config before the apply", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			Id: "test-blueprint",
+			StateDiff: domain.StateDiff{
+				GlobalConfigDiffs: domain.GlobalConfigDiffs{
+					getSetGlobalConfigEntryDiff("key", "value"),
+				},
+			},
+		}
+
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfig := config.CreateGlobalConfig(entries)
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		globalConfigRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(globalConfig, nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(nil)
+		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+
+		ownershipRepoMock := newMockConfigOwnershipRepository(t)
+		ownershipRepoMock.EXPECT().Update(testCtx, "test-blueprint", mock.Anything).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock, newMockBlueprintRevisionRepository(t), ownershipRepoMock)
+
+		// when
+		snapshot, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[common.GlobalConfigKey]domain.ConfigValueState{"key": {}}, snapshot.GlobalConfig)
+	})
+
+	t.Run("apply nothing without config changes", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{}
+		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil)
+
+		sut := NewEcosystemConfigUseCase(newMockBlueprintSpecRepository(t), doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock, newMockBlueprintRevisionRepository(t), newMockConfigOwnershipRepository(t))
+
+		// when
+		_, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, blueprint.Events)
+	})
+
+	t.Run("save ownership without config which vanished from the blueprint and the ecosystem", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			Id:              "test-blueprint",
+			ConfigOwnership: domain.ConfigOwnership{GlobalConfig: []common.GlobalConfigKey{"removed"}},
+		}
+		require.NoError(t, blueprint.DetermineStateDiff(ecosystem.EcosystemState{}, nil, nil, nil, nil, false))
+		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil)
+		ownershipRepoMock := newMockConfigOwnershipRepository(t)
+		ownershipRepoMock.EXPECT().Update(testCtx, "test-blueprint", mock.Anything).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(newMockBlueprintSpecRepository(t), doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock, newMockBlueprintRevisionRepository(t), ownershipRepoMock)
+
+		// when
+		_, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, blueprint.ConfigOwnership.GlobalConfig)
+	})
+
+	t.Run("own only config set by the blueprint", func(t *testing.T) {
+		// given
+		value := "value"
+		blueprint := &domain.BlueprintSpec{
+			Id: "test-blueprint",
+			StateDiff: domain.StateDiff{
+				GlobalConfigDiffs: domain.GlobalConfigDiffs{
+					getSetGlobalConfigEntryDiff("key", "value"),
+					{Key: "existing", Actual: domain.GlobalConfigValueState{Value: &value, Exists: true}, Expected: domain.GlobalConfigValueState{Value: &value, Exists: true}, NeededAction: domain.ConfigActionNone},
+				},
+			},
+		}
+		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+
+		entries, _ := config.MapToEntries(map[string]any{"existing": "value"})
+		globalConfig := config.CreateGlobalConfig(entries)
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		globalConfigRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(globalConfig, nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(nil)
+		ownershipRepoMock := newMockConfigOwnershipRepository(t)
+		ownershipRepoMock.EXPECT().Update(testCtx, "test-blueprint", domain.ConfigOwnership{
+			GlobalConfig: []common.GlobalConfigKey{"key"},
+		}).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock, newMockBlueprintRevisionRepository(t), ownershipRepoMock)
+
+		// when
+		_, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []common.GlobalConfigKey{"key"}, blueprint.ConfigOwnership.GlobalConfig)
+	})
+
+	t.Run("error saving ownership", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			Id: "test-blueprint",
+			StateDiff: domain.StateDiff{
+				GlobalConfigDiffs: domain.GlobalConfigDiffs{getSetGlobalConfigEntryDiff("key", "value")},
+			},
+		}
+		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfig := config.CreateGlobalConfig(entries)
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		// the apply and its compensation
+		globalConfigRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(globalConfig, nil).Times(2)
+		ownershipRepoMock := newMockConfigOwnershipRepository(t)
+		ownershipRepoMock.EXPECT().Update(testCtx, "test-blueprint", mock.Anything).Return(assert.AnError)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock, newMockBlueprintRevisionRepository(t), ownershipRepoMock)
+
+		// when
+		_, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not save config ownership")
+		assert.ErrorContains(t, err, "rolled back all config changes of this apply")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionLastApplySucceeded))
 	})
 
 	t.Run("pause reconciliation for dogus with config and version changes", func(t *testing.T) {
@@ -170,15 +354,17 @@ func TestEcosystemConfigUseCase_ApplyConfig(t *testing.T) {
 		doguInstallaltionRepoMock.EXPECT().Update(testCtx, mock.Anything).Run(func(ctx context.Context, dogu *ecosystem.DoguInstallation) {
 			assert.True(t, dogu.PauseReconciliation)
 		}).Return(nil).Times(2)
-		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock)
+		ownershipRepoMock := newMockConfigOwnershipRepository(t)
+		ownershipRepoMock.EXPECT().Update(testCtx, "", mock.Anything).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigRepoMock, doguInstallaltionRepoMock, newMockBlueprintRevisionRepository(t), ownershipRepoMock)
 
 		// when
-		err := sut.ApplyConfig(testCtx, blueprint)
+		_, err := sut.ApplyConfig(testCtx, blueprint)
 
 		// then
 		require.NoError(t, err)
-		require.Len(t, blueprint.Events, 1)
-		assert.Equal(t, domain.EcosystemConfigAppliedEvent{}, blueprint.Events[0])
+		assert.Equal(t, []domain.Event{domain.EcosystemConfigAppliedEvent{}}, blueprint.Events)
 	})
 
 	t.Run("error applying dogu config", func(t *testing.T) {
@@ -204,19 +390,30 @@ func TestEcosystemConfigUseCase_ApplyConfig(t *testing.T) {
 		// Just check if the routine hits the repos. Check values in concrete test of methods.
 		doguConfigMock.EXPECT().
 			GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).
-			Return(map[cescommons.SimpleName]config.DoguConfig{
-				redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
-				cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
-			}, nil)
+			RunAndReturn(func(ctx context.Context, dogus []cescommons.SimpleName) (map[cescommons.SimpleName]config.DoguConfig, error) {
+				// return new configs on each call as applying the diff changes them
+				return map[cescommons.SimpleName]config.DoguConfig{
+					redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
+					cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
+				}, nil
+			})
 		doguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Return(config.DoguConfig{}, assert.AnError).Times(1)
+		// snapshot and compensation
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil)
 		blueprintRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(nil)
 		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
 		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+		// no revision is stored for a failed apply
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
 
-		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock, revisionRepoMock, newMockConfigOwnershipRepository(t))
 
 		// when
-		err := sut.ApplyConfig(testCtx, blueprint)
+		_, err := sut.ApplyConfig(testCtx, blueprint)
 
 		// then
 		require.Error(t, err)
@@ -252,20 +449,32 @@ func TestEcosystemConfigUseCase_ApplyConfig(t *testing.T) {
 		doguConfigMock.EXPECT().
 			GetAllExisting(testCtx, emptyDoguList).
 			Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		doguConfigMock.EXPECT().
+			GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
 		sensitiveDoguConfigMock.EXPECT().
 			GetAllExisting(testCtx, []cescommons.SimpleName{cas, redmine}).
-			Return(map[cescommons.SimpleName]config.DoguConfig{
-				redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
-				cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
-			}, nil)
+			RunAndReturn(func(ctx context.Context, dogus []cescommons.SimpleName) (map[cescommons.SimpleName]config.DoguConfig, error) {
+				// return new configs on each call as applying the diff changes them
+				return map[cescommons.SimpleName]config.DoguConfig{
+					redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{}),
+					cas:     config.CreateDoguConfig(cas, map[config.Key]config.Value{}),
+				}, nil
+			})
 		sensitiveDoguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Return(config.DoguConfig{}, assert.AnError).Times(1)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil)
 		blueprintRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(nil)
 		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
 		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
-		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock)
+		// no revision is stored for a failed apply
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock, revisionRepoMock, newMockConfigOwnershipRepository(t))
 
 		// when
-		err := sut.ApplyConfig(testCtx, blueprint)
+		_, err := sut.ApplyConfig(testCtx, blueprint)
 
 		// then
 		require.Error(t, err)
@@ -316,10 +525,13 @@ func TestEcosystemConfigUseCase_ApplyConfig(t *testing.T) {
 		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
 		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
 		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
-		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock)
+		// no revision is stored for a failed apply
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock, revisionRepoMock, newMockConfigOwnershipRepository(t))
 
 		// when
-		err := sut.ApplyConfig(testCtx, blueprint)
+		_, err := sut.ApplyConfig(testCtx, blueprint)
 
 		// then
 		require.Error(t, err)
@@ -333,6 +545,307 @@ func TestEcosystemConfigUseCase_ApplyConfig(t *testing.T) {
 		assert.Contains(t, blueprint.Events[0].Message(), "could not apply global config")
 		assert.Contains(t, blueprint.Events[0].Message(), "assert.AnError general error for testing")
 	})
+	t.Run("roll back applied dogu config on error applying global config", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			StateDiff: domain.StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]domain.DoguConfigDiffs{
+					redmine: {getSetDoguConfigEntryDiff("key", "new", redmine)},
+				},
+				GlobalConfigDiffs: domain.GlobalConfigDiffs{
+					getSetGlobalConfigEntryDiff("fqdn", "new.example.com"),
+				},
+			},
+		}
+
+		doguConfigMock := newMockDoguConfigRepository(t)
+		// snapshot and apply
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{redmine}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{
+				redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{"key": "old"}),
+			}, nil).Times(2)
+		doguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Run(func(ctx context.Context, doguConfig config.DoguConfig) {
+			value, _ := doguConfig.Get("key")
+			assert.Equal(t, config.Value("new"), value)
+		}).Return(config.DoguConfig{}, nil).Once()
+		// compensation
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{redmine}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{
+				redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{"key": "new"}),
+			}, nil).Times(2)
+		doguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Run(func(ctx context.Context, doguConfig config.DoguConfig) {
+			value, _ := doguConfig.Get("key")
+			assert.Equal(t, config.Value("old"), value)
+		}).Return(config.DoguConfig{}, nil).Once()
+
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{redmine}).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+
+		globalConfigMock := newMockGlobalConfigRepository(t)
+		globalConfigMock.EXPECT().Get(testCtx).RunAndReturn(func(ctx context.Context) (config.GlobalConfig, error) {
+			entries, _ := config.MapToEntries(map[string]any{"fqdn": "old.example.com"})
+			return config.CreateGlobalConfig(entries), nil
+		})
+		globalConfigMock.EXPECT().Update(testCtx, mock.Anything).Return(config.GlobalConfig{}, assert.AnError).Once()
+
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+		// no revision is stored for a failed apply
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock, revisionRepoMock, newMockConfigOwnershipRepository(t))
+
+		// when
+		_, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not apply global config")
+		assert.ErrorContains(t, err, "rolled back all config changes of this apply")
+		condition := meta.FindStatusCondition(blueprint.Conditions, domain.ConditionLastApplySucceeded)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, domain.ReasonLastApplyErrorAtConfigRolledBack, condition.Reason)
+		assert.Equal(t, err.Error(), condition.Message)
+	})
+	t.Run("report failed roll back", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			StateDiff: domain.StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]domain.DoguConfigDiffs{
+					redmine: {getSetDoguConfigEntryDiff("key", "new", redmine)},
+				},
+			},
+		}
+
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{redmine}).
+			Return(map[cescommons.SimpleName]config.DoguConfig{
+				redmine: config.CreateDoguConfig(redmine, map[config.Key]config.Value{"key": "old"}),
+			}, nil).Times(2)
+		doguConfigMock.EXPECT().UpdateOrCreate(testCtx, mock.Anything).Return(config.DoguConfig{}, assert.AnError).Once()
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{redmine}).Return(nil, assert.AnError).Once()
+
+		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigMock.EXPECT().GetAllExisting(testCtx, emptyDoguList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		entries, _ := config.MapToEntries(map[string]any{})
+		globalConfigMock := newMockGlobalConfigRepository(t)
+		globalConfigMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(entries), nil)
+
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		doguInstallaltionRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, nil)
+		// no revision is stored for a failed apply
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, doguInstallaltionRepoMock, revisionRepoMock, newMockConfigOwnershipRepository(t))
+
+		// when
+		_, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "could not apply normal dogu config")
+		assert.ErrorContains(t, err, "could not roll back the config changes of this apply: could not load dogu config to restore")
+		condition := meta.FindStatusCondition(blueprint.Conditions, domain.ConditionLastApplySucceeded)
+		require.NotNil(t, condition)
+		assert.Equal(t, domain.ReasonLastApplyErrorAtConfig, condition.Reason)
+	})
+	t.Run("error on snapshot", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			StateDiff: domain.StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]domain.DoguConfigDiffs{
+					redmine: {getSetDoguConfigEntryDiff("key", "new", redmine)},
+				},
+			},
+		}
+		doguConfigMock := newMockDoguConfigRepository(t)
+		doguConfigMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{redmine}).Return(nil, assert.AnError)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		// no revision is stored for a failed apply
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, nil, nil, nil, revisionRepoMock, newMockConfigOwnershipRepository(t))
+
+		// when
+		_, err := sut.ApplyConfig(testCtx, blueprint)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not snapshot config before applying it: could not load dogu config")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionLastApplySucceeded))
+	})
+}
+
+func TestEcosystemConfigUseCase_CreateRevision(t *testing.T) {
+	previousConfig := domain.RevisionConfig{
+		DoguConfig: map[common.DoguConfigKey]domain.ConfigValueState{
+			{DoguName: redmine, Key: "key"}: {Value: ptr.To("old"), Exists: true},
+		},
+	}
+
+	t.Run("store revision with the next number", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			Id: "test-blueprint",
+			StateDiff: domain.StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]domain.DoguConfigDiffs{
+					redmine: {getSetDoguConfigEntryDiff("key", "new", redmine)},
+				},
+			},
+		}
+
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, "test-blueprint").Return([]domain.BlueprintRevision{{Number: 1}, {Number: 2}}, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Run(func(ctx context.Context, revision domain.BlueprintRevision) {
+			assert.Equal(t, "test-blueprint", revision.BlueprintId)
+			assert.Equal(t, 3, revision.Number)
+			assert.Equal(t, previousConfig, revision.PreviousConfig)
+		}).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, nil, nil, nil, nil, revisionRepoMock, nil)
+
+		// when
+		err := sut.CreateRevision(testCtx, blueprint, previousConfig)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Event{domain.BlueprintRevisionCreatedEvent{Revision: 3, ChangedEntries: 1}}, blueprint.Events)
+	})
+
+	t.Run("store revision of apply without config changes", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			Id: "test-blueprint",
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{{DoguName: redmine, NeededActions: []domain.Action{domain.ActionUpgrade}}},
+			},
+		}
+
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, "test-blueprint").Return(nil, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Run(func(ctx context.Context, revision domain.BlueprintRevision) {
+			assert.Equal(t, 1, revision.Number)
+			assert.Equal(t, blueprint.StateDiff, revision.StateDiff)
+			assert.Equal(t, 0, revision.PreviousConfig.Len())
+		}).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, nil, nil, nil, nil, revisionRepoMock, nil)
+
+		// when
+		err := sut.CreateRevision(testCtx, blueprint, domain.RevisionConfig{})
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("delete expired revisions", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Id: "test-blueprint"}
+		var revisions []domain.BlueprintRevision
+		for number := 1; number <= domain.MaxBlueprintRevisions; number++ {
+			revisions = append(revisions, domain.BlueprintRevision{BlueprintId: "test-blueprint", Number: number})
+		}
+
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, "test-blueprint").Return(revisions, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(nil)
+		// deletion errors do not fail the apply
+		revisionRepoMock.EXPECT().Delete(testCtx, "test-blueprint", 1).Return(assert.AnError)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, nil, nil, nil, nil, revisionRepoMock, nil)
+
+		// when
+		err := sut.CreateRevision(testCtx, blueprint, previousConfig)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("error updating blueprint events", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Id: "test-blueprint"}
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(assert.AnError)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, "test-blueprint").Return(nil, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(nil)
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, nil, nil, nil, nil, revisionRepoMock, nil)
+
+		// when
+		err := sut.CreateRevision(testCtx, blueprint, previousConfig)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot update blueprint events")
+	})
+
+	t.Run("retry storing the revision", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Id: "test-blueprint"}
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, "test-blueprint").Return(nil, assert.AnError).Once()
+		revisionRepoMock.EXPECT().GetAll(testCtx, "test-blueprint").Return(nil, nil).Twice()
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(assert.AnError).Once()
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(nil).Once()
+
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, nil, nil, nil, nil, revisionRepoMock, nil)
+
+		// when
+		err := sut.CreateRevision(testCtx, blueprint, previousConfig)
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, meta.FindStatusCondition(blueprint.Conditions, domain.ConditionLastApplySucceeded))
+	})
+
+	t.Run("keep applied config if revision cannot be stored", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			Id: "test-blueprint",
+			StateDiff: domain.StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]domain.DoguConfigDiffs{
+					redmine: {getSetDoguConfigEntryDiff("key", "new", redmine)},
+				},
+			},
+		}
+
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
+		revisionRepoMock.EXPECT().GetAll(testCtx, "test-blueprint").Return(nil, nil)
+		revisionRepoMock.EXPECT().Create(testCtx, mock.Anything).Return(assert.AnError).Times(storeRevisionBackoff.Steps)
+
+		// the config repositories are not used, as the applied config must not be rolled back
+		sut := NewEcosystemConfigUseCase(blueprintRepoMock, nil, nil, nil, nil, revisionRepoMock, nil)
+
+		// when
+		err := sut.CreateRevision(testCtx, blueprint, previousConfig)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not store revision after applying blueprint")
+		assert.NotContains(t, err.Error(), "rolled back")
+		condition := meta.FindStatusCondition(blueprint.Conditions, domain.ConditionLastApplySucceeded)
+		require.NotNil(t, condition)
+		assert.Equal(t, domain.ReasonLastApplyErrorAtRevision, condition.Reason)
+	})
 }
 
 func TestEcosystemConfigUseCase_pauseReconciliationForDogus(t *testing.T) {
@@ -378,7 +891,7 @@ func TestEcosystemConfigUseCase_pauseReconciliationForDogus(t *testing.T) {
 		}).Return(nil).Times(2)
 
 		// when
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock, nil, newMockConfigOwnershipRepository(t))
 		err := sut.pauseReconciliationForDogus(testCtx, stateDiff)
 
 		// then
@@ -427,7 +940,7 @@ func TestEcosystemConfigUseCase_pauseReconciliationForDogus(t *testing.T) {
 		}).Return(nil).Times(2)
 
 		// when
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock, nil, newMockConfigOwnershipRepository(t))
 		err := sut.pauseReconciliationForDogus(testCtx, stateDiff)
 
 		// then
@@ -468,7 +981,7 @@ func TestEcosystemConfigUseCase_pauseReconciliationForDogus(t *testing.T) {
 		}).Return(nil).Times(2)
 
 		// when
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock, nil, newMockConfigOwnershipRepository(t))
 		err := sut.pauseReconciliationForDogus(testCtx, stateDiff)
 
 		// then
@@ -504,7 +1017,7 @@ func TestEcosystemConfigUseCase_pauseReconciliationForDogus(t *testing.T) {
 		// No Update calls
 
 		// when
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock, nil, newMockConfigOwnershipRepository(t))
 		err := sut.pauseReconciliationForDogus(testCtx, stateDiff)
 
 		// then
@@ -517,7 +1030,7 @@ func TestEcosystemConfigUseCase_pauseReconciliationForDogus(t *testing.T) {
 		doguInstallaltionRepoMock.EXPECT().GetAll(testCtx).Return(nil, assert.AnError)
 
 		// when
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock, nil, newMockConfigOwnershipRepository(t))
 		err := sut.pauseReconciliationForDogus(testCtx, domain.StateDiff{})
 
 		// then
@@ -546,7 +1059,7 @@ func TestEcosystemConfigUseCase_pauseReconciliationForDogus(t *testing.T) {
 		doguInstallaltionRepoMock.EXPECT().Update(testCtx, mock.Anything).Return(assert.AnError)
 
 		// when
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock)
+		sut := NewEcosystemConfigUseCase(nil, nil, nil, nil, doguInstallaltionRepoMock, nil, newMockConfigOwnershipRepository(t))
 		err := sut.pauseReconciliationForDogus(testCtx, stateDiff)
 
 		// then
@@ -706,7 +1219,6 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 	t.Run("should save diffs with action set", func(t *testing.T) {
 		// given
 		globalConfigMock := newMockGlobalConfigRepository(t)
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, globalConfigMock, nil)
 		diff1 := getSetGlobalConfigEntryDiff("key1", "value1")
 		diff2 := getSetGlobalConfigEntryDiff("key2", "value2")
 		byAction := map[domain.ConfigAction][]domain.GlobalConfigEntryDiff{domain.ConfigActionSet: {diff1, diff2}}
@@ -723,7 +1235,7 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 		globalConfigMock.EXPECT().Update(testCtx, config.GlobalConfig{Config: updatedEntries}).Return(globalConfig, nil)
 
 		// when
-		err = sut.applyGlobalConfigDiffs(testCtx, byAction)
+		err = applyGlobalConfigDiffs(testCtx, globalConfigMock, byAction)
 
 		// then
 		require.NoError(t, err)
@@ -732,7 +1244,6 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 	t.Run("should delete diffs with action remove", func(t *testing.T) {
 		// given
 		globalConfigMock := newMockGlobalConfigRepository(t)
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, globalConfigMock, nil)
 		diff1 := getRemoveGlobalConfigEntryDiff("key")
 		diff2 := getRemoveGlobalConfigEntryDiff("key1")
 		byAction := map[domain.ConfigAction][]domain.GlobalConfigEntryDiff{domain.ConfigActionRemove: {diff1, diff2}}
@@ -747,7 +1258,7 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 		globalConfigMock.EXPECT().Update(testCtx, config.GlobalConfig{Config: updatedEntries}).Return(globalConfig, nil)
 
 		// when
-		err := sut.applyGlobalConfigDiffs(testCtx, byAction)
+		err := applyGlobalConfigDiffs(testCtx, globalConfigMock, byAction)
 
 		// then
 		require.NoError(t, err)
@@ -756,7 +1267,6 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 	t.Run("should return nil on action none", func(t *testing.T) {
 		// given
 		globalConfigMock := newMockGlobalConfigRepository(t)
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, globalConfigMock, nil)
 		diff1 := domain.GlobalConfigEntryDiff{
 			NeededAction: domain.ConfigActionNone,
 		}
@@ -768,7 +1278,7 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 		globalConfigMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
 
 		// when
-		err := sut.applyGlobalConfigDiffs(testCtx, byAction)
+		err := applyGlobalConfigDiffs(testCtx, globalConfigMock, byAction)
 
 		// then
 		require.NoError(t, err)
@@ -777,7 +1287,6 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 	t.Run("err when get fails", func(t *testing.T) {
 		// given
 		globalConfigMock := newMockGlobalConfigRepository(t)
-		sut := NewEcosystemConfigUseCase(nil, nil, nil, globalConfigMock, nil)
 		diff1 := domain.GlobalConfigEntryDiff{
 			NeededAction: domain.ConfigActionSet,
 		}
@@ -790,7 +1299,7 @@ func TestEcosystemConfigUseCase_applyGlobalConfigDiffs(t *testing.T) {
 		globalConfigMock.EXPECT().Get(testCtx).Return(globalConfig, expectedError)
 
 		// when
-		err := sut.applyGlobalConfigDiffs(testCtx, byAction)
+		err := applyGlobalConfigDiffs(testCtx, globalConfigMock, byAction)
 
 		// then
 		require.Error(t, err)
@@ -811,7 +1320,7 @@ func TestEcosystemConfigUseCase_handleFailedApplyEcosystemConfig(t *testing.T) {
 		sut := EcosystemConfigUseCase{blueprintRepository: blueprintRepoMock}
 
 		// when
-		err := sut.handleFailedApplyEcosystemConfig(testCtx, blueprint, assert.AnError)
+		err := sut.handleFailedApplyEcosystemConfig(testCtx, blueprint, domain.ReasonLastApplyErrorAtConfig, assert.AnError)
 
 		// then
 		require.Error(t, err)
@@ -834,7 +1343,7 @@ func TestEcosystemConfigUseCase_handleFailedApplyEcosystemConfig(t *testing.T) {
 		sut := EcosystemConfigUseCase{blueprintRepository: blueprintRepoMock}
 
 		// when
-		err := sut.handleFailedApplyEcosystemConfig(testCtx, spec, assert.AnError)
+		err := sut.handleFailedApplyEcosystemConfig(testCtx, spec, domain.ReasonLastApplyErrorAtConfig, assert.AnError)
 
 		// then
 		require.Error(t, err)
@@ -851,15 +1360,17 @@ func TestNewEcosystemConfigUseCase(t *testing.T) {
 		doguConfigMock := newMockDoguConfigRepository(t)
 		sensitiveDoguConfigMock := newMockSensitiveDoguConfigRepository(t)
 		globalConfigMock := newMockGlobalConfigRepository(t)
+		revisionRepoMock := newMockBlueprintRevisionRepository(t)
 
 		// when
-		useCase := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, nil)
+		useCase := NewEcosystemConfigUseCase(blueprintRepoMock, doguConfigMock, sensitiveDoguConfigMock, globalConfigMock, nil, revisionRepoMock, newMockConfigOwnershipRepository(t))
 
 		// then
 		assert.Equal(t, blueprintRepoMock, useCase.blueprintRepository)
 		assert.Equal(t, doguConfigMock, useCase.doguConfigRepository)
 		assert.Equal(t, sensitiveDoguConfigMock, useCase.sensitiveDoguConfigRepository)
 		assert.Equal(t, globalConfigMock, useCase.globalConfigRepository)
+		assert.Equal(t, revisionRepoMock, useCase.revisionRepository)
 	})
 }
 
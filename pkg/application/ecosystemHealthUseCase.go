@@ -7,17 +7,24 @@ import (
 
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"golang.org/x/exp/maps"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type EcosystemHealthUseCase struct {
 	doguUseCase   doguInstallationUseCase
+	componentRepo componentInstallationRepository
 	blueprintRepo blueprintSpecRepository
 }
 
-func NewEcosystemHealthUseCase(doguUseCase doguInstallationUseCase, blueprintRepo blueprintSpecRepository) *EcosystemHealthUseCase {
+func NewEcosystemHealthUseCase(
+	doguUseCase doguInstallationUseCase,
+	componentRepo componentInstallationRepository,
+	blueprintRepo blueprintSpecRepository,
+) *EcosystemHealthUseCase {
 	return &EcosystemHealthUseCase{
 		doguUseCase:   doguUseCase,
+		componentRepo: componentRepo,
 		blueprintRepo: blueprintRepo,
 	}
 }
@@ -34,6 +41,7 @@ func (useCase *EcosystemHealthUseCase) CheckEcosystemHealth(
 	health, determineHealthError := useCase.getEcosystemHealth(
 		ctx,
 		blueprint.Config.IgnoreDoguHealth,
+		blueprint.Config.IgnoreComponentHealth,
 	)
 	healthChanged := blueprint.HandleHealthResult(health, determineHealthError)
 	if healthChanged {
@@ -58,6 +66,7 @@ func (useCase *EcosystemHealthUseCase) CheckEcosystemHealth(
 func (useCase *EcosystemHealthUseCase) getEcosystemHealth(
 	ctx context.Context,
 	ignoreDoguHealth bool,
+	ignoreComponentHealth bool,
 ) (ecosystem.HealthResult, error) {
 	logger := log.FromContext(ctx).WithName("EcosystemHealthUseCase.getEcosystemHealth")
 	logger.V(1).Info("check ecosystem health...")
@@ -66,8 +75,22 @@ func (useCase *EcosystemHealthUseCase) getEcosystemHealth(
 	if !ignoreDoguHealth {
 		doguHealth, doguHealthErr = useCase.doguUseCase.CheckDoguHealth(ctx)
 	}
+	var componentHealth ecosystem.ComponentHealthResult
+	var componentHealthErr error
+	if !ignoreComponentHealth {
+		componentHealth, componentHealthErr = useCase.checkComponentHealth(ctx)
+	}
 
 	return ecosystem.HealthResult{
-		DoguHealth: doguHealth,
-	}, doguHealthErr
+		DoguHealth:      doguHealth,
+		ComponentHealth: componentHealth,
+	}, errors.Join(doguHealthErr, componentHealthErr)
+}
+
+func (useCase *EcosystemHealthUseCase) checkComponentHealth(ctx context.Context) (ecosystem.ComponentHealthResult, error) {
+	installedComponents, err := useCase.componentRepo.GetAll(ctx)
+	if err != nil {
+		return ecosystem.ComponentHealthResult{}, fmt.Errorf("cannot evaluate component health states: %w", err)
+	}
+	return ecosystem.CalculateComponentHealthResult(maps.Values(installedComponents)), nil
 }
@@ -23,14 +23,24 @@ var (
 		ecosystem.UnavailableHealthStatus: {"postfix"},
 		ecosystem.PendingHealthStatus:     {"scm"},
 	}
+	healthyComponents = map[string]*ecosystem.ComponentInstallation{
+		"k8s-dogu-operator": {Name: "k8s-dogu-operator", Health: ecosystem.AvailableHealthStatus},
+	}
+	healthyComponentHealth = ecosystem.ComponentHealthResult{
+		ComponentsByStatus: map[ecosystem.HealthStatus][]string{
+			ecosystem.AvailableHealthStatus: {"k8s-dogu-operator"},
+		},
+	}
 )
 
 func TestNewEcosystemHealthUseCase(t *testing.T) {
 	doguUseCase := newMockDoguInstallationUseCase(t)
+	componentRepo := newMockComponentInstallationRepository(t)
 	blueprintRepo := newMockBlueprintSpecRepository(t)
-	useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+	useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
 
 	assert.Same(t, doguUseCase, useCase.doguUseCase)
+	assert.Same(t, componentRepo, useCase.componentRepo)
 }
 
 func TestEcosystemHealthUseCase_CheckEcosystemHealth(t *testing.T) {
@@ -47,14 +57,16 @@ func TestEcosystemHealthUseCase_CheckEcosystemHealth(t *testing.T) {
 		}
 		doguUseCase := newMockDoguInstallationUseCase(t)
 		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, nil)
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(healthyComponents, nil)
 		blueprintRepo := newMockBlueprintSpecRepository(t)
 		blueprintRepo.EXPECT().Update(testCtx, blueprint).Return(nil)
-		useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
 
 		health, err := useCase.CheckEcosystemHealth(testCtx, blueprint)
 
 		require.NoError(t, err)
-		assert.Equal(t, ecosystem.HealthResult{DoguHealth: doguHealth}, health)
+		assert.Equal(t, ecosystem.HealthResult{DoguHealth: doguHealth, ComponentHealth: healthyComponentHealth}, health)
 		assert.True(t, meta.IsStatusConditionTrue(blueprint.Conditions, domain.ConditionEcosystemHealthy))
 	})
 
@@ -71,19 +83,49 @@ func TestEcosystemHealthUseCase_CheckEcosystemHealth(t *testing.T) {
 		}
 		doguUseCase := newMockDoguInstallationUseCase(t)
 		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, nil)
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(healthyComponents, nil)
 		blueprintRepo := newMockBlueprintSpecRepository(t)
 		blueprintRepo.EXPECT().Update(testCtx, blueprint).Return(nil)
-		useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
 
 		health, err := useCase.CheckEcosystemHealth(testCtx, blueprint)
 
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "ecosystem is unhealthy")
 		assert.ErrorContains(t, err, "2 dogu(s) are unhealthy: postfix, scm")
-		assert.Equal(t, ecosystem.HealthResult{DoguHealth: doguHealth}, health)
+		assert.Equal(t, ecosystem.HealthResult{DoguHealth: doguHealth, ComponentHealth: healthyComponentHealth}, health)
 		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionEcosystemHealthy))
 	})
 
+	t.Run("unhealthy component", func(t *testing.T) {
+		blueprint := &domain.BlueprintSpec{
+			Conditions: []domain.Condition{},
+		}
+
+		doguHealth := ecosystem.DoguHealthResult{
+			DogusByStatus: healthyDogu,
+		}
+		doguUseCase := newMockDoguInstallationUseCase(t)
+		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, nil)
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(map[string]*ecosystem.ComponentInstallation{
+			"k8s-longhorn": {Name: "k8s-longhorn", Health: ecosystem.UnavailableHealthStatus},
+		}, nil)
+		blueprintRepo := newMockBlueprintSpecRepository(t)
+		blueprintRepo.EXPECT().Update(testCtx, blueprint).Return(nil)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
+
+		_, err := useCase.CheckEcosystemHealth(testCtx, blueprint)
+
+		assert.ErrorContains(t, err, "ecosystem is unhealthy")
+		assert.ErrorContains(t, err, "1 component(s) are unhealthy: k8s-longhorn")
+		condition := meta.FindStatusCondition(blueprint.Conditions, domain.ConditionEcosystemHealthy)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "1 component(s) are unhealthy: k8s-longhorn")
+	})
+
 	t.Run("error updating blueprint", func(t *testing.T) {
 		blueprint := &domain.BlueprintSpec{
 			Conditions: []domain.Condition{},
@@ -97,9 +139,11 @@ func TestEcosystemHealthUseCase_CheckEcosystemHealth(t *testing.T) {
 		}
 		doguUseCase := newMockDoguInstallationUseCase(t)
 		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, nil)
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(healthyComponents, nil)
 		blueprintRepo := newMockBlueprintSpecRepository(t)
 		blueprintRepo.EXPECT().Update(testCtx, blueprint).Return(assert.AnError)
-		useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
 
 		health, err := useCase.CheckEcosystemHealth(testCtx, blueprint)
 
@@ -120,9 +164,11 @@ func TestEcosystemHealthUseCase_CheckEcosystemHealth(t *testing.T) {
 
 		doguUseCase := newMockDoguInstallationUseCase(t)
 		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, assert.AnError)
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(healthyComponents, nil)
 		blueprintRepo := newMockBlueprintSpecRepository(t)
 		blueprintRepo.EXPECT().Update(testCtx, blueprint).Return(nil)
-		useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
 
 		_, err := useCase.CheckEcosystemHealth(testCtx, blueprint)
 
@@ -145,9 +191,11 @@ func TestEcosystemHealthUseCase_CheckEcosystemHealth(t *testing.T) {
 		}
 		doguUseCase := newMockDoguInstallationUseCase(t)
 		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, nil).Twice()
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(healthyComponents, nil).Twice()
 		blueprintRepo := newMockBlueprintSpecRepository(t)
 		blueprintRepo.EXPECT().Update(testCtx, blueprint).Return(nil).Once()
-		useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
 
 		_, err := useCase.CheckEcosystemHealth(testCtx, blueprint)
 		assert.ErrorContains(t, err, "ecosystem is unhealthy")
@@ -165,20 +213,49 @@ func TestEcosystemHealthUseCase_getEcosystemHealth(t *testing.T) {
 		}
 		doguUseCase := newMockDoguInstallationUseCase(t)
 		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, nil)
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(healthyComponents, nil)
 		blueprintRepo := newMockBlueprintSpecRepository(t)
-		useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, componentRepo, blueprintRepo)
 
-		health, err := useCase.getEcosystemHealth(testCtx, false)
+		health, err := useCase.getEcosystemHealth(testCtx, false, false)
 
 		require.NoError(t, err)
-		assert.Equal(t, ecosystem.HealthResult{DoguHealth: doguHealth}, health)
+		assert.Equal(t, ecosystem.HealthResult{DoguHealth: doguHealth, ComponentHealth: healthyComponentHealth}, health)
 	})
 
 	t.Run("ok, ignore dogu health", func(t *testing.T) {
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(healthyComponents, nil)
+		blueprintRepo := newMockBlueprintSpecRepository(t)
+		useCase := NewEcosystemHealthUseCase(nil, componentRepo, blueprintRepo)
+
+		health, err := useCase.getEcosystemHealth(testCtx, true, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, ecosystem.HealthResult{ComponentHealth: healthyComponentHealth}, health)
+	})
+
+	t.Run("ok, ignore component health", func(t *testing.T) {
+		doguHealth := ecosystem.DoguHealthResult{
+			DogusByStatus: healthyDogu,
+		}
+		doguUseCase := newMockDoguInstallationUseCase(t)
+		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(doguHealth, nil)
 		blueprintRepo := newMockBlueprintSpecRepository(t)
-		useCase := NewEcosystemHealthUseCase(nil, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, nil, blueprintRepo)
 
-		health, err := useCase.getEcosystemHealth(testCtx, true)
+		health, err := useCase.getEcosystemHealth(testCtx, false, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, ecosystem.HealthResult{DoguHealth: doguHealth}, health)
+	})
+
+	t.Run("ok, ignore all health", func(t *testing.T) {
+		blueprintRepo := newMockBlueprintSpecRepository(t)
+		useCase := NewEcosystemHealthUseCase(nil, nil, blueprintRepo)
+
+		health, err := useCase.getEcosystemHealth(testCtx, true, true)
 
 		require.NoError(t, err)
 		assert.Equal(t, ecosystem.HealthResult{}, health)
@@ -188,10 +265,22 @@ func TestEcosystemHealthUseCase_getEcosystemHealth(t *testing.T) {
 		doguUseCase := newMockDoguInstallationUseCase(t)
 		doguUseCase.EXPECT().CheckDoguHealth(mock.Anything).Return(ecosystem.DoguHealthResult{}, assert.AnError)
 		blueprintRepo := newMockBlueprintSpecRepository(t)
-		useCase := NewEcosystemHealthUseCase(doguUseCase, blueprintRepo)
+		useCase := NewEcosystemHealthUseCase(doguUseCase, nil, blueprintRepo)
+
+		_, err := useCase.getEcosystemHealth(testCtx, false, true)
+
+		require.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("error checking component health", func(t *testing.T) {
+		componentRepo := newMockComponentInstallationRepository(t)
+		componentRepo.EXPECT().GetAll(mock.Anything).Return(nil, assert.AnError)
+		blueprintRepo := newMockBlueprintSpecRepository(t)
+		useCase := NewEcosystemHealthUseCase(nil, componentRepo, blueprintRepo)
 
-		_, err := useCase.getEcosystemHealth(testCtx, false)
+		_, err := useCase.getEcosystemHealth(testCtx, true, false)
 
 		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot evaluate component health states")
 	})
 }
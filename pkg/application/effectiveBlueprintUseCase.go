@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
@@ -9,23 +10,38 @@ import (
 )
 
 type EffectiveBlueprintUseCase struct {
-	blueprintSpecRepo blueprintSpecRepository
+	blueprintSpecRepo           blueprintSpecRepository
+	resolveDoguVersionsUseCase  resolveDoguVersionsDomainUseCase
+	completeDependenciesUseCase completeDependenciesDomainUseCase
 }
 
 func NewEffectiveBlueprintUseCase(
 	blueprintSpecRepo domainservice.BlueprintSpecRepository,
+	resolveDoguVersionsUseCase resolveDoguVersionsDomainUseCase,
+	completeDependenciesUseCase completeDependenciesDomainUseCase,
 ) *EffectiveBlueprintUseCase {
 	return &EffectiveBlueprintUseCase{
-		blueprintSpecRepo: blueprintSpecRepo,
+		blueprintSpecRepo:           blueprintSpecRepo,
+		resolveDoguVersionsUseCase:  resolveDoguVersionsUseCase,
+		completeDependenciesUseCase: completeDependenciesUseCase,
 	}
 }
 
 // CalculateEffectiveBlueprint loads the blueprintSpec, lets it calculate the effective blueprint and persists it again.
+// Version constraints of dogus get resolved to concrete versions with the remote dogu registry.
+// Missing dependencies of the dogus get added if the blueprint completes dependencies.
 // returns a domainservice.NotFoundError if the blueprintId does not correspond to a blueprintSpec or
+// a domain.InvalidBlueprintError if a version constraint or a missing dependency cannot be resolved or
 // a domainservice.InternalError if there is any error while loading or persisting the blueprintSpec or
 // a domainservice.ConflictError if there was a concurrent write.
 func (useCase *EffectiveBlueprintUseCase) CalculateEffectiveBlueprint(ctx context.Context, blueprint *domain.BlueprintSpec) error {
 	calcError := blueprint.CalculateEffectiveBlueprint()
+	if calcError == nil {
+		calcError = useCase.resolveDoguVersions(ctx, blueprint)
+	}
+	if calcError == nil {
+		calcError = useCase.completeDependencies(ctx, blueprint)
+	}
 	err := useCase.blueprintSpecRepo.Update(ctx, blueprint)
 	if err != nil {
 		return fmt.Errorf("cannot save blueprint spec after calculating the effective blueprint: %w", err)
@@ -33,3 +49,26 @@ func (useCase *EffectiveBlueprintUseCase) CalculateEffectiveBlueprint(ctx contex
 
 	return calcError
 }
+
+func (useCase *EffectiveBlueprintUseCase) resolveDoguVersions(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	resolvedVersions, err := useCase.resolveDoguVersionsUseCase.ResolveDoguVersions(ctx, blueprint.EffectiveBlueprint)
+	var invalidBlueprintError *domain.InvalidBlueprintError
+	if err != nil && !errors.As(err, &invalidBlueprintError) {
+		// technical errors do not make the blueprint invalid, so just try again later
+		return fmt.Errorf("cannot resolve dogu versions: %w", err)
+	}
+	return blueprint.ResolveDoguVersions(resolvedVersions, err)
+}
+
+func (useCase *EffectiveBlueprintUseCase) completeDependencies(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	if !blueprint.Config.CompleteDependencies {
+		return blueprint.AddMissingDependencies(nil, nil)
+	}
+	missingDependencies, err := useCase.completeDependenciesUseCase.CompleteMissingDependencies(ctx, blueprint.EffectiveBlueprint)
+	var invalidBlueprintError *domain.InvalidBlueprintError
+	if err != nil && !errors.As(err, &invalidBlueprintError) {
+		// technical errors do not make the blueprint invalid, so just try again later
+		return fmt.Errorf("cannot complete missing dogu dependencies: %w", err)
+	}
+	return blueprint.AddMissingDependencies(missingDependencies, err)
+}
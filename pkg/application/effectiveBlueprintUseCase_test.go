@@ -4,9 +4,12 @@ import (
 	"context"
 	"testing"
 
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
 
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
 )
@@ -19,9 +22,11 @@ func TestBlueprintSpecUseCase_CalculateEffectiveBlueprint_ok(t *testing.T) {
 		}
 
 		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
 		ctx := context.Background()
-		useCase := NewEffectiveBlueprintUseCase(repoMock)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, newMockCompleteDependenciesDomainUseCase(t))
 
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, domain.EffectiveBlueprint{}).Return(map[cescommons.SimpleName]core.Version{}, nil)
 		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
 
 		// when
@@ -40,9 +45,11 @@ func TestBlueprintSpecUseCase_CalculateEffectiveBlueprint_ok(t *testing.T) {
 		}
 
 		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
 		ctx := context.Background()
-		useCase := NewEffectiveBlueprintUseCase(repoMock)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, newMockCompleteDependenciesDomainUseCase(t))
 
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, domain.EffectiveBlueprint{}).Return(map[cescommons.SimpleName]core.Version{}, nil)
 		repoMock.EXPECT().Update(ctx, blueprint).Return(&domainservice.InternalError{Message: "test-error"})
 
 		//when
@@ -55,3 +62,190 @@ func TestBlueprintSpecUseCase_CalculateEffectiveBlueprint_ok(t *testing.T) {
 		assert.ErrorContains(t, err, "cannot save blueprint spec after calculating the effective blueprint: test-error")
 	})
 }
+
+func TestBlueprintSpecUseCase_CalculateEffectiveBlueprint_versionConstraints(t *testing.T) {
+	ctx := context.Background()
+	constraint, err := domain.ParseVersionConstraint("~2.4")
+	require.NoError(t, err)
+	resolvedVersion, err := core.ParseVersion("2.4.48-3")
+	require.NoError(t, err)
+	ldap := cescommons.QualifiedName{Namespace: "official", SimpleName: "ldap"}
+	newBlueprint := func() *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id: "testBlueprint1",
+			Blueprint: domain.Blueprint{
+				Dogus: []domain.Dogu{{Name: ldap, VersionConstraint: &constraint}},
+			},
+		}
+	}
+	unresolvedEffectiveBlueprint := domain.EffectiveBlueprint{
+		Dogus: []domain.Dogu{{Name: ldap, VersionConstraint: &constraint}},
+	}
+
+	t.Run("should set resolved versions in effective blueprint", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, newMockCompleteDependenciesDomainUseCase(t))
+
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, unresolvedEffectiveBlueprint).
+			Return(map[cescommons.SimpleName]core.Version{"ldap": resolvedVersion}, nil)
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		// when
+		err := useCase.CalculateEffectiveBlueprint(ctx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, blueprint.EffectiveBlueprint.Dogus, 1)
+		assert.Equal(t, &resolvedVersion, blueprint.EffectiveBlueprint.Dogus[0].Version)
+	})
+
+	t.Run("should mark blueprint invalid if constraint cannot be resolved", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, newMockCompleteDependenciesDomainUseCase(t))
+
+		invalidError := &domain.InvalidBlueprintError{Message: "cannot resolve dogu version constraints"}
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, unresolvedEffectiveBlueprint).Return(nil, invalidError)
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		// when
+		err := useCase.CalculateEffectiveBlueprint(ctx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, invalidError)
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionValid))
+		require.Len(t, blueprint.Events, 1)
+	})
+
+	t.Run("should not mark blueprint invalid on internal error", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, newMockCompleteDependenciesDomainUseCase(t))
+
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, unresolvedEffectiveBlueprint).Return(nil, assert.AnError)
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		// when
+		err := useCase.CalculateEffectiveBlueprint(ctx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot resolve dogu versions")
+		assert.Nil(t, meta.FindStatusCondition(blueprint.Conditions, domain.ConditionValid))
+		assert.Empty(t, blueprint.Events)
+	})
+}
+
+func TestBlueprintSpecUseCase_CalculateEffectiveBlueprint_completeDependencies(t *testing.T) {
+	ctx := context.Background()
+	redmineVersion, err := core.ParseVersion("5.1.3-1")
+	require.NoError(t, err)
+	postgresqlVersion, err := core.ParseVersion("14.15-2")
+	require.NoError(t, err)
+	redmine := domain.Dogu{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "redmine"}, Version: &redmineVersion}
+	postgresql := domain.Dogu{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "postgresql"}, Version: &postgresqlVersion}
+	newBlueprint := func() *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id:        "testBlueprint1",
+			Blueprint: domain.Blueprint{Dogus: []domain.Dogu{redmine}},
+			Config:    domain.BlueprintConfiguration{CompleteDependencies: true},
+		}
+	}
+	incompleteEffectiveBlueprint := domain.EffectiveBlueprint{Dogus: []domain.Dogu{redmine}}
+
+	t.Run("should add missing dependencies to effective blueprint", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
+		completeMock := newMockCompleteDependenciesDomainUseCase(t)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, completeMock)
+
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, incompleteEffectiveBlueprint).Return(map[cescommons.SimpleName]core.Version{}, nil)
+		completeMock.EXPECT().CompleteMissingDependencies(ctx, incompleteEffectiveBlueprint).Return([]domain.Dogu{postgresql}, nil)
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		// when
+		err := useCase.CalculateEffectiveBlueprint(ctx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		autoAddedPostgresql := postgresql
+		autoAddedPostgresql.AutoAdded = true
+		assert.Equal(t, []domain.Dogu{redmine, autoAddedPostgresql}, blueprint.EffectiveBlueprint.Dogus)
+		assert.True(t, meta.IsStatusConditionTrue(blueprint.Conditions, domain.ConditionDependenciesAutoAdded))
+		require.Len(t, blueprint.Events, 1)
+		assert.IsType(t, domain.DependenciesAutoAddedEvent{}, blueprint.Events[0])
+	})
+
+	t.Run("should not complete dependencies if not enabled", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		blueprint.Config.CompleteDependencies = false
+		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, newMockCompleteDependenciesDomainUseCase(t))
+
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, incompleteEffectiveBlueprint).Return(map[cescommons.SimpleName]core.Version{}, nil)
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		// when
+		err := useCase.CalculateEffectiveBlueprint(ctx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, incompleteEffectiveBlueprint, blueprint.EffectiveBlueprint)
+		assert.Nil(t, meta.FindStatusCondition(blueprint.Conditions, domain.ConditionDependenciesAutoAdded))
+	})
+
+	t.Run("should mark blueprint invalid if dependencies cannot be resolved", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
+		completeMock := newMockCompleteDependenciesDomainUseCase(t)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, completeMock)
+
+		invalidError := &domain.InvalidBlueprintError{Message: "cannot complete missing dogu dependencies"}
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, incompleteEffectiveBlueprint).Return(map[cescommons.SimpleName]core.Version{}, nil)
+		completeMock.EXPECT().CompleteMissingDependencies(ctx, incompleteEffectiveBlueprint).Return(nil, invalidError)
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		// when
+		err := useCase.CalculateEffectiveBlueprint(ctx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, invalidError)
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionValid))
+		require.Len(t, blueprint.Events, 1)
+	})
+
+	t.Run("should not mark blueprint invalid on internal error", func(t *testing.T) {
+		// given
+		blueprint := newBlueprint()
+		repoMock := newMockBlueprintSpecRepository(t)
+		resolveMock := newMockResolveDoguVersionsDomainUseCase(t)
+		completeMock := newMockCompleteDependenciesDomainUseCase(t)
+		useCase := NewEffectiveBlueprintUseCase(repoMock, resolveMock, completeMock)
+
+		resolveMock.EXPECT().ResolveDoguVersions(ctx, incompleteEffectiveBlueprint).Return(map[cescommons.SimpleName]core.Version{}, nil)
+		completeMock.EXPECT().CompleteMissingDependencies(ctx, incompleteEffectiveBlueprint).Return(nil, assert.AnError)
+		repoMock.EXPECT().Update(ctx, blueprint).Return(nil)
+
+		// when
+		err := useCase.CalculateEffectiveBlueprint(ctx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot complete missing dogu dependencies")
+		assert.Nil(t, meta.FindStatusCondition(blueprint.Conditions, domain.ConditionValid))
+		assert.Empty(t, blueprint.Events)
+	})
+}
@@ -26,17 +26,20 @@ func NewInitiateBlueprintStatusUseCase(
 // InitateConditions handles the initial setting of the conditions to unknown if they are not set yet.
 // returns a domainservice.InternalError on any error.
 func (useCase *InitiateBlueprintStatusUseCase) InitateConditions(ctx context.Context, blueprint *domain.BlueprintSpec) error {
-	if len(blueprint.Conditions) != len(domain.BlueprintConditions) {
-		for _, condition := range domain.BlueprintConditions {
-			if meta.FindStatusCondition(blueprint.Conditions, condition) == nil {
-				meta.SetStatusCondition(&blueprint.Conditions, metav1.Condition{
-					Type:    condition,
-					Status:  metav1.ConditionUnknown,
-					Reason:  "InitialSyncPending",
-					Message: "controller has not determined this condition yet",
-				})
-			}
+	conditionsChanged := false
+	// optional conditions like domain.ConditionRolloutWave are not initialized, so only look for missing conditions
+	for _, condition := range domain.BlueprintConditions {
+		if meta.FindStatusCondition(blueprint.Conditions, condition) == nil {
+			meta.SetStatusCondition(&blueprint.Conditions, metav1.Condition{
+				Type:    condition,
+				Status:  metav1.ConditionUnknown,
+				Reason:  "InitialSyncPending",
+				Message: "controller has not determined this condition yet",
+			})
+			conditionsChanged = true
 		}
+	}
+	if conditionsChanged {
 		err := useCase.repo.Update(ctx, blueprint)
 		if err != nil {
 			return fmt.Errorf("cannot save blueprint spec %q after initially setting the conditions to unknown: %w", blueprint.Id, err)
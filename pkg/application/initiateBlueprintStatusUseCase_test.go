@@ -85,6 +85,35 @@ func TestInitiateBlueprintStatusUseCase_InitateConditions(t *testing.T) {
 			wantUnknownConditions: nil,
 			wantErr:               nil,
 		},
+		{
+			name: "all conditions and optional rollout wave condition",
+			args: args{
+				blueprint: &domain.BlueprintSpec{
+					Conditions: []domain.Condition{
+						{
+							Type: domain.ConditionValid,
+						},
+						{
+							Type: domain.ConditionExecutable,
+						},
+						{
+							Type: domain.ConditionEcosystemHealthy,
+						},
+						{
+							Type: domain.ConditionCompleted,
+						},
+						{
+							Type: domain.ConditionLastApplySucceeded,
+						},
+						{
+							Type: domain.ConditionRolloutWave,
+						},
+					},
+				},
+			},
+			wantUnknownConditions: nil,
+			wantErr:               nil,
+		},
 		{
 			name: "update error",
 			args: args{
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
@@ -30,6 +31,7 @@ type doguInstallationUseCase interface {
 	CheckDoguHealth(ctx context.Context) (ecosystem.DoguHealthResult, error)
 	CheckDogusUpToDate(ctx context.Context) ([]cescommons.SimpleName, error)
 	ApplyDoguStates(ctx context.Context, blueprint *domain.BlueprintSpec) error
+	ApplyNextRolloutWave(ctx context.Context, blueprint *domain.BlueprintSpec) (domain.DoguDiffs, int, error)
 }
 
 type applyDogusUseCase interface {
@@ -49,17 +51,34 @@ type dogusUpToDateUseCase interface {
 }
 
 type ecosystemConfigUseCase interface {
-	ApplyConfig(ctx context.Context, blueprint *domain.BlueprintSpec) error
+	ApplyConfig(ctx context.Context, blueprint *domain.BlueprintSpec) (domain.RevisionConfig, error)
+	CreateRevision(ctx context.Context, blueprint *domain.BlueprintSpec, previousConfig domain.RevisionConfig) error
+}
+
+type configRollbackUseCase interface {
+	RollbackConfig(ctx context.Context, blueprint *domain.BlueprintSpec) error
 }
 
 type restoreInProgressUseCase interface {
 	CheckRestoreInProgress(context.Context) error
 }
 
+type planApprovalUseCase interface {
+	CheckPlanApproval(ctx context.Context, blueprint *domain.BlueprintSpec) error
+}
+
+type preDowngradeBackupUseCase interface {
+	EnsurePreDowngradeBackup(ctx context.Context, blueprint *domain.BlueprintSpec) error
+}
+
 type doguInstallationRepository interface {
 	domainservice.DoguInstallationRepository
 }
 
+type componentInstallationRepository interface {
+	domainservice.ComponentInstallationRepository
+}
+
 //nolint:unused
 //goland:noinspection GoUnusedType
 type blueprintSpecRepository interface {
@@ -78,6 +97,40 @@ type restoreRepository interface {
 	domainservice.RestoreRepository
 }
 
+//nolint:unused
+//goland:noinspection GoUnusedType
+type backupRepository interface {
+	domainservice.BackupRepository
+}
+
+//nolint:unused
+//goland:noinspection GoUnusedType
+type planRepository interface {
+	domainservice.PlanRepository
+}
+
+type planKeyRepository interface {
+	domainservice.PlanKeyRepository
+}
+
+//nolint:unused
+//goland:noinspection GoUnusedType
+type blueprintRevisionRepository interface {
+	domainservice.BlueprintRevisionRepository
+}
+
+//nolint:unused
+//goland:noinspection GoUnusedType
+type configOwnershipRepository interface {
+	domainservice.ConfigOwnershipRepository
+}
+
+//nolint:unused
+//goland:noinspection GoUnusedType
+type outdatedReportRepository interface {
+	domainservice.OutdatedReportRepository
+}
+
 // interface duplication for mocks
 
 //nolint:unused
@@ -116,6 +169,27 @@ type validateAdditionalMountsDomainUseCase interface {
 	ValidateAdditionalMounts(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) error
 }
 
+// resolveDoguVersionsDomainUseCase is an interface for the domain service for better testability
+type resolveDoguVersionsDomainUseCase interface {
+	ResolveDoguVersions(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) (map[cescommons.SimpleName]core.Version, error)
+}
+
+// completeDependenciesDomainUseCase is an interface for the domain service for better testability
+type completeDependenciesDomainUseCase interface {
+	CompleteMissingDependencies(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) ([]domain.Dogu, error)
+}
+
+// sortDoguDiffsDomainUseCase is an interface for the domain service for better testability
+type sortDoguDiffsDomainUseCase interface {
+	SortDoguDiffsByDependencies(ctx context.Context, diffs domain.DoguDiffs) (domain.DoguDiffs, error)
+	GroupDoguDiffsIntoWaves(ctx context.Context, diffs domain.DoguDiffs, waves domain.RolloutWaves) ([]domain.DoguDiffs, error)
+}
+
 type validateDoguStorageClassDomainUseCase interface {
 	ValidateDoguStorageClass(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) error
 }
+
+// validateDoguConfigDomainUseCase is an interface for the domain service for better testability
+type validateDoguConfigDomainUseCase interface {
+	ValidateDoguConfig(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint, templatesEnabled bool) error
+}
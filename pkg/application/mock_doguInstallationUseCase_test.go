@@ -73,6 +73,72 @@ func (_c *mockDoguInstallationUseCase_ApplyDoguStates_Call) RunAndReturn(run fun
 	return _c
 }
 
+// ApplyNextRolloutWave provides a mock function with given fields: ctx, blueprint
+func (_m *mockDoguInstallationUseCase) ApplyNextRolloutWave(ctx context.Context, blueprint *domain.BlueprintSpec) (domain.DoguDiffs, int, error) {
+	ret := _m.Called(ctx, blueprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyNextRolloutWave")
+	}
+
+	var r0 domain.DoguDiffs
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.BlueprintSpec) (domain.DoguDiffs, int, error)); ok {
+		return rf(ctx, blueprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.BlueprintSpec) domain.DoguDiffs); ok {
+		r0 = rf(ctx, blueprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.DoguDiffs)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.BlueprintSpec) int); ok {
+		r1 = rf(ctx, blueprint)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.BlueprintSpec) error); ok {
+		r2 = rf(ctx, blueprint)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// mockDoguInstallationUseCase_ApplyNextRolloutWave_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyNextRolloutWave'
+type mockDoguInstallationUseCase_ApplyNextRolloutWave_Call struct {
+	*mock.Call
+}
+
+// ApplyNextRolloutWave is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blueprint *domain.BlueprintSpec
+func (_e *mockDoguInstallationUseCase_Expecter) ApplyNextRolloutWave(ctx interface{}, blueprint interface{}) *mockDoguInstallationUseCase_ApplyNextRolloutWave_Call {
+	return &mockDoguInstallationUseCase_ApplyNextRolloutWave_Call{Call: _e.mock.On("ApplyNextRolloutWave", ctx, blueprint)}
+}
+
+func (_c *mockDoguInstallationUseCase_ApplyNextRolloutWave_Call) Run(run func(ctx context.Context, blueprint *domain.BlueprintSpec)) *mockDoguInstallationUseCase_ApplyNextRolloutWave_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.BlueprintSpec))
+	})
+	return _c
+}
+
+func (_c *mockDoguInstallationUseCase_ApplyNextRolloutWave_Call) Return(_a0 domain.DoguDiffs, _a1 int, _a2 error) *mockDoguInstallationUseCase_ApplyNextRolloutWave_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *mockDoguInstallationUseCase_ApplyNextRolloutWave_Call) RunAndReturn(run func(context.Context, *domain.BlueprintSpec) (domain.DoguDiffs, int, error)) *mockDoguInstallationUseCase_ApplyNextRolloutWave_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CheckDoguHealth provides a mock function with given fields: ctx
 func (_m *mockDoguInstallationUseCase) CheckDoguHealth(ctx context.Context) (ecosystem.DoguHealthResult, error) {
 	ret := _m.Called(ctx)
@@ -23,21 +23,31 @@ func (_m *mockEcosystemConfigUseCase) EXPECT() *mockEcosystemConfigUseCase_Expec
 }
 
 // ApplyConfig provides a mock function with given fields: ctx, blueprint
-func (_m *mockEcosystemConfigUseCase) ApplyConfig(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+func (_m *mockEcosystemConfigUseCase) ApplyConfig(ctx context.Context, blueprint *domain.BlueprintSpec) (domain.RevisionConfig, error) {
 	ret := _m.Called(ctx, blueprint)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ApplyConfig")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.BlueprintSpec) error); ok {
+	var r0 domain.RevisionConfig
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.BlueprintSpec) (domain.RevisionConfig, error)); ok {
+		return rf(ctx, blueprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.BlueprintSpec) domain.RevisionConfig); ok {
 		r0 = rf(ctx, blueprint)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(domain.RevisionConfig)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.BlueprintSpec) error); ok {
+		r1 = rf(ctx, blueprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // mockEcosystemConfigUseCase_ApplyConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyConfig'
@@ -59,12 +69,60 @@ func (_c *mockEcosystemConfigUseCase_ApplyConfig_Call) Run(run func(ctx context.
 	return _c
 }
 
-func (_c *mockEcosystemConfigUseCase_ApplyConfig_Call) Return(_a0 error) *mockEcosystemConfigUseCase_ApplyConfig_Call {
+func (_c *mockEcosystemConfigUseCase_ApplyConfig_Call) Return(_a0 domain.RevisionConfig, _a1 error) *mockEcosystemConfigUseCase_ApplyConfig_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockEcosystemConfigUseCase_ApplyConfig_Call) RunAndReturn(run func(context.Context, *domain.BlueprintSpec) (domain.RevisionConfig, error)) *mockEcosystemConfigUseCase_ApplyConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRevision provides a mock function with given fields: ctx, blueprint, previousConfig
+func (_m *mockEcosystemConfigUseCase) CreateRevision(ctx context.Context, blueprint *domain.BlueprintSpec, previousConfig domain.RevisionConfig) error {
+	ret := _m.Called(ctx, blueprint, previousConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRevision")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.BlueprintSpec, domain.RevisionConfig) error); ok {
+		r0 = rf(ctx, blueprint, previousConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockEcosystemConfigUseCase_CreateRevision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRevision'
+type mockEcosystemConfigUseCase_CreateRevision_Call struct {
+	*mock.Call
+}
+
+// CreateRevision is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blueprint *domain.BlueprintSpec
+//   - previousConfig domain.RevisionConfig
+func (_e *mockEcosystemConfigUseCase_Expecter) CreateRevision(ctx interface{}, blueprint interface{}, previousConfig interface{}) *mockEcosystemConfigUseCase_CreateRevision_Call {
+	return &mockEcosystemConfigUseCase_CreateRevision_Call{Call: _e.mock.On("CreateRevision", ctx, blueprint, previousConfig)}
+}
+
+func (_c *mockEcosystemConfigUseCase_CreateRevision_Call) Run(run func(ctx context.Context, blueprint *domain.BlueprintSpec, previousConfig domain.RevisionConfig)) *mockEcosystemConfigUseCase_CreateRevision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.BlueprintSpec), args[2].(domain.RevisionConfig))
+	})
+	return _c
+}
+
+func (_c *mockEcosystemConfigUseCase_CreateRevision_Call) Return(_a0 error) *mockEcosystemConfigUseCase_CreateRevision_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *mockEcosystemConfigUseCase_ApplyConfig_Call) RunAndReturn(run func(context.Context, *domain.BlueprintSpec) error) *mockEcosystemConfigUseCase_ApplyConfig_Call {
+func (_c *mockEcosystemConfigUseCase_CreateRevision_Call) RunAndReturn(run func(context.Context, *domain.BlueprintSpec, domain.RevisionConfig) error) *mockEcosystemConfigUseCase_CreateRevision_Call {
 	_c.Call.Return(run)
 	return _c
 }
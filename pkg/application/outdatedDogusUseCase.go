@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type OutdatedDogusUseCase struct {
+	blueprintSpecRepo  blueprintSpecRepository
+	remoteDoguRegistry remoteDoguRegistry
+	outdatedReportRepo outdatedReportRepository
+}
+
+func NewOutdatedDogusUseCase(
+	blueprintSpecRepo domainservice.BlueprintSpecRepository,
+	remoteDoguRegistry domainservice.RemoteDoguRegistry,
+	outdatedReportRepo domainservice.OutdatedReportRepository,
+) *OutdatedDogusUseCase {
+	return &OutdatedDogusUseCase{
+		blueprintSpecRepo:  blueprintSpecRepo,
+		remoteDoguRegistry: remoteDoguRegistry,
+		outdatedReportRepo: outdatedReportRepo,
+	}
+}
+
+// ReportOutdatedDogus compares the versions of all dogus in the effective blueprint with the newest releases
+// in the remote dogu registry and saves the result as domain.OutdatedReport.
+// An event gets published on the blueprint if there are new releases since the last report.
+// returns a domainservice.NotFoundError if the blueprintId does not correspond to a blueprintSpec or
+// a domainservice.InternalError if there is any error while loading or persisting the report or the dogu versions.
+func (useCase *OutdatedDogusUseCase) ReportOutdatedDogus(ctx context.Context, blueprintId string) error {
+	logger := log.FromContext(ctx).WithName("OutdatedDogusUseCase.ReportOutdatedDogus")
+
+	blueprint, err := getBaseBlueprint(ctx, useCase.blueprintSpecRepo, blueprintId)
+	if err != nil {
+		return fmt.Errorf("cannot load blueprint spec %q to report outdated dogus: %w", blueprintId, err)
+	}
+
+	var report domain.OutdatedReport
+	for _, dogu := range blueprint.EffectiveBlueprint.GetWantedDogus() {
+		if dogu.Version == nil {
+			continue
+		}
+		availableVersions, err := useCase.remoteDoguRegistry.GetVersionsOf(ctx, dogu.Name)
+		if err != nil {
+			if domainservice.IsNotFoundError(err) {
+				logger.V(1).Info("skip dogu without releases in the remote dogu registry", "dogu", dogu.Name)
+				continue
+			}
+			return fmt.Errorf("cannot load versions of dogu %q to report outdated dogus: %w", dogu.Name, err)
+		}
+		report.Dogus = append(report.Dogus, domain.NewDoguUpdates(dogu.Name, *dogu.Version, availableVersions))
+	}
+
+	previousReport, err := useCase.outdatedReportRepo.Get(ctx, blueprint.Id)
+	if err != nil && !domainservice.IsNotFoundError(err) {
+		return fmt.Errorf("cannot load previous outdated report of blueprint %q: %w", blueprint.Id, err)
+	}
+	err = useCase.outdatedReportRepo.Update(ctx, blueprint.Id, report)
+	if err != nil {
+		return fmt.Errorf("cannot save outdated report of blueprint %q: %w", blueprint.Id, err)
+	}
+
+	blueprint.ReportOutdatedDogus(previousReport, report)
+	if len(blueprint.Events) == 0 {
+		return nil
+	}
+	logger.Info("blueprint falls behind new dogu releases", "outdatedDogus", len(report.GetOutdatedDogus()))
+	err = useCase.blueprintSpecRepo.PublishEvents(ctx, blueprint)
+	if err != nil {
+		return fmt.Errorf("cannot publish outdated dogus of blueprint %q: %w", blueprint.Id, err)
+	}
+	return nil
+}
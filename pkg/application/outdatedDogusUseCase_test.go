@@ -0,0 +1,201 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutdatedDogusUseCase_ReportOutdatedDogus(t *testing.T) {
+	postgresqlName := cescommons.QualifiedName{Namespace: "official", SimpleName: "postgresql"}
+	ldapName := cescommons.QualifiedName{Namespace: "official", SimpleName: "ldap"}
+	newBlueprint := func() *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id: "blueprint",
+			EffectiveBlueprint: domain.EffectiveBlueprint{Dogus: []domain.Dogu{
+				{Name: postgresqlName, Version: &version3211},
+				{Name: ldapName, Version: &version3212},
+				{Name: cescommons.QualifiedName{Namespace: "official", SimpleName: "redmine"}, Absent: true},
+			}},
+		}
+	}
+	expectedReport := domain.OutdatedReport{Dogus: []domain.DoguUpdates{
+		{Name: postgresqlName, Version: version3211, Patch: &version3212},
+		{Name: ldapName, Version: version3212},
+	}}
+
+	t.Run("should save report and publish event for new releases", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		blueprintRepoMock.EXPECT().PublishEvents(testCtx, mock.Anything).RunAndReturn(func(_ context.Context, spec *domain.BlueprintSpec) error {
+			assert.Equal(t, []domain.Event{domain.BlueprintOutdatedEvent{OutdatedDogus: expectedReport.Dogus[:1]}}, spec.Events)
+			return nil
+		})
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return([]core.Version{version3211, version3212}, nil)
+		registryMock.EXPECT().GetVersionsOf(testCtx, ldapName).Return([]core.Version{version3211, version3212}, nil)
+		reportRepoMock := newMockOutdatedReportRepository(t)
+		reportRepoMock.EXPECT().Get(testCtx, "blueprint").Return(domain.OutdatedReport{}, &domainservice.NotFoundError{})
+		reportRepoMock.EXPECT().Update(testCtx, "blueprint", expectedReport).Return(nil)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, reportRepoMock)
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should not publish event for known releases", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return([]core.Version{version3211, version3212}, nil)
+		registryMock.EXPECT().GetVersionsOf(testCtx, ldapName).Return([]core.Version{version3211, version3212}, nil)
+		reportRepoMock := newMockOutdatedReportRepository(t)
+		reportRepoMock.EXPECT().Get(testCtx, "blueprint").Return(expectedReport, nil)
+		reportRepoMock.EXPECT().Update(testCtx, "blueprint", expectedReport).Return(nil)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, reportRepoMock)
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should skip dogus without releases", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return(nil, &domainservice.NotFoundError{})
+		registryMock.EXPECT().GetVersionsOf(testCtx, ldapName).Return([]core.Version{version3211, version3212}, nil)
+		reportRepoMock := newMockOutdatedReportRepository(t)
+		reportRepoMock.EXPECT().Get(testCtx, "blueprint").Return(domain.OutdatedReport{}, nil)
+		reportRepoMock.EXPECT().Update(testCtx, "blueprint", domain.OutdatedReport{Dogus: expectedReport.Dogus[1:]}).Return(nil)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, reportRepoMock)
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should skip dogus missing in the remote dogu registry", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).
+			Return(nil, domainservice.NewNotFoundError(assert.AnError, "dogu %q could not be found", postgresqlName))
+		registryMock.EXPECT().GetVersionsOf(testCtx, ldapName).Return([]core.Version{version3211, version3212}, nil)
+		reportRepoMock := newMockOutdatedReportRepository(t)
+		reportRepoMock.EXPECT().Get(testCtx, "blueprint").Return(domain.OutdatedReport{}, nil)
+		reportRepoMock.EXPECT().Update(testCtx, "blueprint", domain.OutdatedReport{Dogus: expectedReport.Dogus[1:]}).Return(nil)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, reportRepoMock)
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail to load blueprint", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(nil, assert.AnError)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, newMockRemoteDoguRegistry(t), newMockOutdatedReportRepository(t))
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load blueprint spec \"blueprint\" to report outdated dogus")
+	})
+
+	t.Run("should fail to load versions", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, postgresqlName).Return(nil, assert.AnError)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, newMockOutdatedReportRepository(t))
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load versions of dogu \"official/postgresql\" to report outdated dogus")
+	})
+
+	t.Run("should fail to load previous report", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, mock.Anything).Return([]core.Version{version3211, version3212}, nil)
+		reportRepoMock := newMockOutdatedReportRepository(t)
+		reportRepoMock.EXPECT().Get(testCtx, "blueprint").Return(domain.OutdatedReport{}, assert.AnError)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, reportRepoMock)
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load previous outdated report of blueprint \"blueprint\"")
+	})
+
+	t.Run("should fail to save report", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, mock.Anything).Return([]core.Version{version3211, version3212}, nil)
+		reportRepoMock := newMockOutdatedReportRepository(t)
+		reportRepoMock.EXPECT().Get(testCtx, "blueprint").Return(domain.OutdatedReport{}, nil)
+		reportRepoMock.EXPECT().Update(testCtx, "blueprint", mock.Anything).Return(assert.AnError)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, reportRepoMock)
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot save outdated report of blueprint \"blueprint\"")
+	})
+
+	t.Run("should fail to publish event", func(t *testing.T) {
+		// given
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().GetById(testCtx, "blueprint").Return(newBlueprint(), nil)
+		blueprintRepoMock.EXPECT().PublishEvents(testCtx, mock.Anything).Return(assert.AnError)
+		registryMock := newMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(testCtx, mock.Anything).Return([]core.Version{version3211, version3212}, nil)
+		reportRepoMock := newMockOutdatedReportRepository(t)
+		reportRepoMock.EXPECT().Get(testCtx, "blueprint").Return(domain.OutdatedReport{}, nil)
+		reportRepoMock.EXPECT().Update(testCtx, "blueprint", mock.Anything).Return(nil)
+		sut := NewOutdatedDogusUseCase(blueprintRepoMock, registryMock, reportRepoMock)
+
+		// when
+		err := sut.ReportOutdatedDogus(testCtx, "blueprint")
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot publish outdated dogus of blueprint \"blueprint\"")
+	})
+}
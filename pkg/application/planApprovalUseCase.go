@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PlanApprovalUseCase makes sure that only approved plans get applied if the blueprint requires a plan approval.
+type PlanApprovalUseCase struct {
+	blueprintSpecRepo blueprintSpecRepository
+	planRepo          planRepository
+	planKeyRepo       planKeyRepository
+}
+
+func NewPlanApprovalUseCase(
+	blueprintSpecRepo blueprintSpecRepository,
+	planRepo planRepository,
+	planKeyRepo planKeyRepository,
+) *PlanApprovalUseCase {
+	return &PlanApprovalUseCase{
+		blueprintSpecRepo: blueprintSpecRepo,
+		planRepo:          planRepo,
+		planKeyRepo:       planKeyRepo,
+	}
+}
+
+// CheckPlanApproval checks if the changes of the blueprint are part of the approved plan.
+// If not, the current plan gets stored, so that it can be reviewed and approved by its hash.
+// returns nil if no approval is required, there are no changes or the changes are approved.
+// returns a domain.PlanNotApprovedError if the current plan is not approved or
+// returns a domainservice.ConflictError if there was a concurrent update to the blueprint or
+// returns a domainservice.InternalError if there was an error while loading or storing the plan.
+func (useCase *PlanApprovalUseCase) CheckPlanApproval(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	if !blueprint.Config.RequirePlanApproval || !blueprint.StateDiff.HasChanges() {
+		return nil
+	}
+
+	sensitiveValueKey, err := useCase.planKeyRepo.GetOrCreate(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot check plan approval: %w", err)
+	}
+
+	plan, err := blueprint.GetPlan(sensitiveValueKey)
+	if err != nil {
+		return domainservice.NewInternalError(err, "cannot check plan approval")
+	}
+
+	approvedHash := blueprint.Config.ApprovedPlanHash
+	if approvedHash != "" {
+		isApproved, err := useCase.isPartOfApprovedPlan(ctx, plan, approvedHash)
+		if err != nil {
+			return err
+		}
+		if isApproved {
+			return useCase.markPlanApproved(ctx, blueprint)
+		}
+	}
+
+	return useCase.requestApproval(ctx, blueprint, plan)
+}
+
+func (useCase *PlanApprovalUseCase) isPartOfApprovedPlan(ctx context.Context, plan domain.Plan, approvedHash string) (bool, error) {
+	approvedPlan, err := useCase.planRepo.Get(ctx, approvedHash)
+	if err != nil {
+		if domainservice.IsNotFoundError(err) {
+			// the approved hash does not belong to any plan of this operator
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot load approved plan %q: %w", approvedHash, err)
+	}
+	return approvedPlan.Contains(plan), nil
+}
+
+func (useCase *PlanApprovalUseCase) markPlanApproved(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	conditionChanged := blueprint.MarkPlanApproved()
+	if conditionChanged {
+		err := useCase.blueprintSpecRepo.Update(ctx, blueprint)
+		if err != nil {
+			return fmt.Errorf("cannot update blueprint after plan approval: %w", err)
+		}
+	}
+	return nil
+}
+
+func (useCase *PlanApprovalUseCase) requestApproval(ctx context.Context, blueprint *domain.BlueprintSpec, plan domain.Plan) error {
+	logger := log.FromContext(ctx).WithName("PlanApprovalUseCase.requestApproval")
+	hash := plan.Hash()
+
+	err := useCase.planRepo.Create(ctx, plan)
+	var conflictErr *domainservice.ConflictError
+	if err != nil && !errors.As(err, &conflictErr) {
+		return fmt.Errorf("cannot store plan %q for approval: %w", hash, err)
+	}
+
+	conditionChanged := blueprint.MarkPlanWaitingForApproval(plan, blueprint.Config.ApprovedPlanHash)
+	if conditionChanged {
+		logger.Info("plan needs to be approved", "plan", hash)
+		err = useCase.blueprintSpecRepo.Update(ctx, blueprint)
+		if err != nil {
+			return fmt.Errorf("cannot update blueprint after storing plan %q: %w", hash, err)
+		}
+	}
+
+	return &domain.PlanNotApprovedError{
+		Message: fmt.Sprintf("plan %q needs to be approved before it gets applied", hash),
+	}
+}
@@ -0,0 +1,250 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+var testPlanKey = []byte("plan-key")
+
+func TestPlanApprovalUseCase_CheckPlanApproval(t *testing.T) {
+	upgradeBlueprint := func(approvedHash string) *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id: "blueprint",
+			Config: domain.BlueprintConfiguration{
+				RequirePlanApproval: true,
+				ApprovedPlanHash:    approvedHash,
+			},
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{
+					{
+						DoguName:      "postgresql",
+						Actual:        domain.DoguDiffState{Version: &version3211},
+						Expected:      domain.DoguDiffState{Version: &version3212},
+						NeededActions: []domain.Action{domain.ActionUpgrade},
+					},
+				},
+			},
+		}
+	}
+	getPlan := func(t *testing.T, blueprint *domain.BlueprintSpec) domain.Plan {
+		plan, err := blueprint.GetPlan(testPlanKey)
+		require.NoError(t, err)
+		return plan
+	}
+	planKeyRepo := func(t *testing.T) *mockPlanKeyRepository {
+		planKeyRepoMock := newMockPlanKeyRepository(t)
+		planKeyRepoMock.EXPECT().GetOrCreate(testCtx).Return(testPlanKey, nil)
+		return planKeyRepoMock
+	}
+
+	t.Run("nothing to do if no approval is required", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("")
+		blueprint.Config.RequirePlanApproval = false
+		sut := NewPlanApprovalUseCase(newMockBlueprintSpecRepository(t), newMockPlanRepository(t), newMockPlanKeyRepository(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("nothing to do without changes", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Config: domain.BlueprintConfiguration{RequirePlanApproval: true}}
+		sut := NewPlanApprovalUseCase(newMockBlueprintSpecRepository(t), newMockPlanRepository(t), newMockPlanKeyRepository(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("store plan and wait for approval", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("")
+		plan := getPlan(t, blueprint)
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Create(testCtx, plan).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPlanApprovalUseCase(blueprintRepoMock, planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		var notApprovedErr *domain.PlanNotApprovedError
+		require.ErrorAs(t, err, &notApprovedErr)
+		assert.ErrorContains(t, err, "plan \""+plan.Hash()+"\" needs to be approved before it gets applied")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionPlanApproved))
+		require.Len(t, blueprint.Events, 1)
+		assert.Equal(t, domain.PlanApprovalRequiredEvent{Hash: plan.Hash(), ChangeCount: 1}, blueprint.Events[0])
+	})
+
+	t.Run("wait for approval of already stored plan without update", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("")
+		plan := getPlan(t, blueprint)
+		blueprint.MarkPlanWaitingForApproval(plan, "")
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Create(testCtx, plan).Return(&domainservice.ConflictError{})
+		sut := NewPlanApprovalUseCase(newMockBlueprintSpecRepository(t), planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		var notApprovedErr *domain.PlanNotApprovedError
+		require.ErrorAs(t, err, &notApprovedErr)
+	})
+
+	t.Run("continue with approved plan", func(t *testing.T) {
+		// given
+		plan := getPlan(t, upgradeBlueprint(""))
+		blueprint := upgradeBlueprint(plan.Hash())
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Get(testCtx, plan.Hash()).Return(plan, nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPlanApprovalUseCase(blueprintRepoMock, planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionTrue(blueprint.Conditions, domain.ConditionPlanApproved))
+	})
+
+	t.Run("continue with partially applied plan", func(t *testing.T) {
+		// given
+		approvedPlan := getPlan(t, upgradeBlueprint(""))
+		approvedPlan.Changes = append(approvedPlan.Changes, "an already applied change")
+		blueprint := upgradeBlueprint(approvedPlan.Hash())
+		blueprint.MarkPlanApproved()
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Get(testCtx, approvedPlan.Hash()).Return(approvedPlan, nil)
+		sut := NewPlanApprovalUseCase(newMockBlueprintSpecRepository(t), planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("refuse drifted plan", func(t *testing.T) {
+		// given
+		approvedPlan := domain.Plan{BlueprintId: "blueprint", Changes: []string{"another change"}}
+		blueprint := upgradeBlueprint(approvedPlan.Hash())
+		plan := getPlan(t, blueprint)
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Get(testCtx, approvedPlan.Hash()).Return(approvedPlan, nil)
+		planRepoMock.EXPECT().Create(testCtx, plan).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPlanApprovalUseCase(blueprintRepoMock, planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		var notApprovedErr *domain.PlanNotApprovedError
+		require.ErrorAs(t, err, &notApprovedErr)
+		condition := meta.FindStatusCondition(blueprint.Conditions, domain.ConditionPlanApproved)
+		require.NotNil(t, condition)
+		assert.Equal(t, "ApprovedPlanOutdated", condition.Reason)
+	})
+
+	t.Run("refuse unknown approved plan", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("unknown")
+		plan := getPlan(t, blueprint)
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Get(testCtx, "unknown").Return(domain.Plan{}, domainservice.NewNotFoundError(nil, "not found"))
+		planRepoMock.EXPECT().Create(testCtx, plan).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPlanApprovalUseCase(blueprintRepoMock, planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		var notApprovedErr *domain.PlanNotApprovedError
+		require.ErrorAs(t, err, &notApprovedErr)
+	})
+
+	t.Run("fail to load plan key", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("")
+		planKeyRepoMock := newMockPlanKeyRepository(t)
+		planKeyRepoMock.EXPECT().GetOrCreate(testCtx).Return(nil, assert.AnError)
+		sut := NewPlanApprovalUseCase(newMockBlueprintSpecRepository(t), newMockPlanRepository(t), planKeyRepoMock)
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot check plan approval")
+	})
+
+	t.Run("fail to load approved plan", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("1234")
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Get(testCtx, "1234").Return(domain.Plan{}, assert.AnError)
+		sut := NewPlanApprovalUseCase(newMockBlueprintSpecRepository(t), planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot load approved plan \"1234\"")
+	})
+
+	t.Run("fail to store plan", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("")
+		plan := getPlan(t, blueprint)
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Create(testCtx, plan).Return(assert.AnError)
+		sut := NewPlanApprovalUseCase(newMockBlueprintSpecRepository(t), planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot store plan")
+	})
+
+	t.Run("fail to update blueprint", func(t *testing.T) {
+		// given
+		blueprint := upgradeBlueprint("")
+		plan := getPlan(t, blueprint)
+		planRepoMock := newMockPlanRepository(t)
+		planRepoMock.EXPECT().Create(testCtx, plan).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(assert.AnError)
+		sut := NewPlanApprovalUseCase(blueprintRepoMock, planRepoMock, planKeyRepo(t))
+
+		// when
+		err := sut.CheckPlanApproval(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot update blueprint after storing plan")
+	})
+}
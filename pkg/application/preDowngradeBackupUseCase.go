@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PreDowngradeBackupUseCase makes sure that there is a backup of the ecosystem before any dogu gets downgraded.
+type PreDowngradeBackupUseCase struct {
+	blueprintSpecRepo blueprintSpecRepository
+	backupRepo        backupRepository
+}
+
+func NewPreDowngradeBackupUseCase(
+	blueprintSpecRepo blueprintSpecRepository,
+	backupRepo backupRepository,
+) *PreDowngradeBackupUseCase {
+	return &PreDowngradeBackupUseCase{
+		blueprintSpecRepo: blueprintSpecRepo,
+		backupRepo:        backupRepo,
+	}
+}
+
+// EnsurePreDowngradeBackup starts a backup if the blueprint downgrades dogus and checks if this backup is completed.
+// It has to be called before anything gets applied, so that the backup contains the state before the blueprint.
+// returns nil if there are no downgrades or the backup is completed.
+// returns a domain.BackupInProgressError if the backup is not completed yet.
+// returns a domainservice.ConflictError if there was a concurrent update to the blueprint or
+// returns any other error if the backup failed or could not be started. This is also set in the blueprint conditions.
+func (useCase *PreDowngradeBackupUseCase) EnsurePreDowngradeBackup(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	err := useCase.ensureBackup(ctx, blueprint)
+	var backupInProgressErr *domain.BackupInProgressError
+	if err == nil || errors.As(err, &backupInProgressErr) {
+		return err
+	}
+	return useCase.handleFailedBackup(ctx, blueprint, err)
+}
+
+func (useCase *PreDowngradeBackupUseCase) ensureBackup(ctx context.Context, blueprint *domain.BlueprintSpec) error {
+	if len(blueprint.StateDiff.DoguDiffs.GetDowngrades()) == 0 {
+		return nil
+	}
+
+	backupName := blueprint.GetPreDowngradeBackupName()
+	backup, err := useCase.backupRepo.Get(ctx, backupName)
+	if err != nil {
+		if domainservice.IsNotFoundError(err) {
+			return useCase.startBackup(ctx, blueprint, backupName)
+		}
+		return fmt.Errorf("cannot check backup %q before downgrading dogus: %w", backupName, err)
+	}
+
+	if backup.IsFailed() {
+		return fmt.Errorf("backup %q before downgrading dogus failed, delete the backup to retry", backupName)
+	}
+	if !backup.IsCompleted() {
+		return &domain.BackupInProgressError{
+			Message: fmt.Sprintf("waiting for backup %q to complete before downgrading dogus", backupName),
+		}
+	}
+	return nil
+}
+
+func (useCase *PreDowngradeBackupUseCase) startBackup(ctx context.Context, blueprint *domain.BlueprintSpec, backupName string) error {
+	logger := log.FromContext(ctx).WithName("PreDowngradeBackupUseCase.startBackup")
+	logger.Info("start backup before downgrading dogus", "backup", backupName)
+
+	err := useCase.backupRepo.Create(ctx, ecosystem.NewBackup(backupName))
+	if err != nil {
+		return fmt.Errorf("cannot start backup %q before downgrading dogus: %w", backupName, err)
+	}
+
+	blueprint.MarkPreDowngradeBackupStarted(backupName)
+	err = useCase.blueprintSpecRepo.Update(ctx, blueprint)
+	if err != nil {
+		return fmt.Errorf("cannot update blueprint after starting backup %q: %w", backupName, err)
+	}
+
+	return &domain.BackupInProgressError{
+		Message: fmt.Sprintf("started backup %q, waiting for it to complete before downgrading dogus", backupName),
+	}
+}
+
+// handleFailedBackup marks the failed dogu apply, as no dogu gets downgraded without the backup.
+func (useCase *PreDowngradeBackupUseCase) handleFailedBackup(ctx context.Context, blueprint *domain.BlueprintSpec, err error) error {
+	changed := blueprint.SetLastApplySucceededConditionOnError(domain.ReasonLastApplyErrorAtDogus, err)
+	if changed {
+		repoErr := useCase.blueprintSpecRepo.Update(ctx, blueprint)
+		if repoErr != nil {
+			return fmt.Errorf("cannot mark backup before downgrading dogus as failed: %w", errors.Join(repoErr, err))
+		}
+	}
+	return err
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+func TestPreDowngradeBackupUseCase_EnsurePreDowngradeBackup(t *testing.T) {
+	downgradeBlueprint := func() *domain.BlueprintSpec {
+		return &domain.BlueprintSpec{
+			Id: "blueprint",
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{
+					{
+						DoguName:      "postgresql",
+						Actual:        domain.DoguDiffState{Version: &version3212},
+						Expected:      domain.DoguDiffState{Version: &version3211},
+						NeededActions: []domain.Action{domain.ActionDowngrade},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("nothing to do without downgrades", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{
+			StateDiff: domain.StateDiff{
+				DoguDiffs: domain.DoguDiffs{
+					{DoguName: "postgresql", NeededActions: []domain.Action{domain.ActionUpgrade}},
+				},
+			},
+		}
+		sut := NewPreDowngradeBackupUseCase(newMockBlueprintSpecRepository(t), newMockBackupRepository(t))
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("start backup if it does not exist yet", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupName := blueprint.GetPreDowngradeBackupName()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, backupName).Return(nil, domainservice.NewNotFoundError(nil, "not found"))
+		backupRepoMock.EXPECT().Create(testCtx, ecosystem.NewBackup(backupName)).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPreDowngradeBackupUseCase(blueprintRepoMock, backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		var backupInProgressErr *domain.BackupInProgressError
+		require.ErrorAs(t, err, &backupInProgressErr)
+		assert.ErrorContains(t, err, "started backup")
+		require.Len(t, blueprint.Events, 1)
+		assert.Equal(t, domain.PreDowngradeBackupStartedEvent{
+			BackupName: backupName,
+			Downgrades: blueprint.StateDiff.DoguDiffs,
+		}, blueprint.Events[0])
+	})
+
+	t.Run("fail to start backup", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupName := blueprint.GetPreDowngradeBackupName()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, backupName).Return(nil, domainservice.NewNotFoundError(nil, "not found"))
+		backupRepoMock.EXPECT().Create(testCtx, ecosystem.NewBackup(backupName)).Return(assert.AnError)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPreDowngradeBackupUseCase(blueprintRepoMock, backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot start backup")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionLastApplySucceeded))
+		require.Len(t, blueprint.Events, 1)
+		assert.Equal(t, domain.NewExecutionFailedEvent(err), blueprint.Events[0])
+	})
+
+	t.Run("fail to update blueprint after starting backup", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupName := blueprint.GetPreDowngradeBackupName()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, backupName).Return(nil, domainservice.NewNotFoundError(nil, "not found"))
+		backupRepoMock.EXPECT().Create(testCtx, ecosystem.NewBackup(backupName)).Return(nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(assert.AnError)
+		sut := NewPreDowngradeBackupUseCase(blueprintRepoMock, backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot update blueprint after starting backup")
+	})
+
+	t.Run("fail to load backup", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, blueprint.GetPreDowngradeBackupName()).Return(nil, assert.AnError)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPreDowngradeBackupUseCase(blueprintRepoMock, backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "cannot check backup")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionLastApplySucceeded))
+	})
+
+	t.Run("fail to mark failed backup in blueprint", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, blueprint.GetPreDowngradeBackupName()).Return(nil, assert.AnError)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		repoErr := errors.New("update failed")
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(repoErr)
+		sut := NewPreDowngradeBackupUseCase(blueprintRepoMock, backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		require.ErrorIs(t, err, repoErr)
+		assert.ErrorContains(t, err, "cannot mark backup before downgrading dogus as failed")
+	})
+
+	t.Run("wait for backup in progress", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupName := blueprint.GetPreDowngradeBackupName()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, backupName).Return(&ecosystem.Backup{Name: backupName, Status: ecosystem.BackupStatusInProgress}, nil)
+		sut := NewPreDowngradeBackupUseCase(newMockBlueprintSpecRepository(t), backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		var backupInProgressErr *domain.BackupInProgressError
+		require.ErrorAs(t, err, &backupInProgressErr)
+		assert.ErrorContains(t, err, "waiting for backup")
+		assert.Empty(t, blueprint.Events)
+	})
+
+	t.Run("fail on failed backup", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupName := blueprint.GetPreDowngradeBackupName()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, backupName).Return(&ecosystem.Backup{Name: backupName, Status: ecosystem.BackupStatusFailed}, nil)
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+		sut := NewPreDowngradeBackupUseCase(blueprintRepoMock, backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		require.Error(t, err)
+		var backupInProgressErr *domain.BackupInProgressError
+		assert.False(t, errors.As(err, &backupInProgressErr))
+		assert.ErrorContains(t, err, "failed, delete the backup to retry")
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, domain.ConditionLastApplySucceeded))
+	})
+
+	t.Run("ok if backup is completed", func(t *testing.T) {
+		// given
+		blueprint := downgradeBlueprint()
+		backupName := blueprint.GetPreDowngradeBackupName()
+		backupRepoMock := newMockBackupRepository(t)
+		backupRepoMock.EXPECT().Get(testCtx, backupName).Return(&ecosystem.Backup{Name: backupName, Status: ecosystem.BackupStatusCompleted}, nil)
+		sut := NewPreDowngradeBackupUseCase(newMockBlueprintSpecRepository(t), backupRepoMock)
+
+		// when
+		err := sut.EnsurePreDowngradeBackup(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+	})
+}
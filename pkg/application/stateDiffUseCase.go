@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
@@ -24,6 +26,7 @@ type StateDiffUseCase struct {
 	sensitiveConfigRefReader sensitiveConfigRefReader
 	configRefReader          configRefReader
 	debugModeRepo            debugModeRepository
+	configOwnershipRepo      configOwnershipRepository
 }
 
 func NewStateDiffUseCase(
@@ -35,6 +38,7 @@ func NewStateDiffUseCase(
 	sensitiveConfigRefReader domainservice.SensitiveConfigRefReader,
 	configRefReader domainservice.ConfigRefReader,
 	debugModeRepo domainservice.DebugModeRepository,
+	configOwnershipRepo domainservice.ConfigOwnershipRepository,
 ) *StateDiffUseCase {
 	return &StateDiffUseCase{
 		blueprintSpecRepo:        blueprintSpecRepo,
@@ -45,6 +49,7 @@ func NewStateDiffUseCase(
 		sensitiveConfigRefReader: sensitiveConfigRefReader,
 		configRefReader:          configRefReader,
 		debugModeRepo:            debugModeRepo,
+		configOwnershipRepo:      configOwnershipRepo,
 	}
 }
 
@@ -71,8 +76,15 @@ func (useCase *StateDiffUseCase) DetermineStateDiff(ctx context.Context, bluepri
 		return err
 	}
 
+	logger.V(2).Info("load config ownership")
+	loadedOwnership, err := useCase.loadConfigOwnership(ctx, blueprint.Id)
+	if err != nil {
+		return fmt.Errorf("could not determine state diff: %w", err)
+	}
+	blueprint.ConfigOwnership = loadedOwnership
+
 	logger.V(2).Info("collect ecosystem state for state diff")
-	ecosystemState, err := useCase.collectEcosystemState(ctx, blueprint.EffectiveBlueprint)
+	ecosystemState, err := useCase.collectEcosystemState(ctx, blueprint.EffectiveBlueprint, blueprint.Config.ConfigTemplatesEnabled(), loadedOwnership)
 	if err != nil {
 		return fmt.Errorf("could not determine state diff: %w", err)
 	}
@@ -115,7 +127,16 @@ func (useCase *StateDiffUseCase) determineDebugModeState(ctx context.Context, lo
 	return isDebugModeActive, nil
 }
 
-func (useCase *StateDiffUseCase) collectEcosystemState(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) (ecosystem.EcosystemState, error) {
+// loadConfigOwnership returns the config ownership of the blueprint. A blueprint, which was never applied, owns no config.
+func (useCase *StateDiffUseCase) loadConfigOwnership(ctx context.Context, blueprintId string) (domain.ConfigOwnership, error) {
+	ownership, err := useCase.configOwnershipRepo.Get(ctx, blueprintId)
+	if err != nil && !domainservice.IsNotFoundError(err) {
+		return domain.ConfigOwnership{}, fmt.Errorf("cannot load config ownership: %w", err)
+	}
+	return ownership, nil
+}
+
+func (useCase *StateDiffUseCase) collectEcosystemState(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint, templatesEnabled bool, ownership domain.ConfigOwnership) (ecosystem.EcosystemState, error) {
 	logger := log.FromContext(ctx).WithName("StateDiffUseCase.collectEcosystemState")
 
 	// TODO: collect ecosystem state in parallel (like for ecosystem health) if we have time
@@ -127,10 +148,10 @@ func (useCase *StateDiffUseCase) collectEcosystemState(ctx context.Context, effe
 	globalConfig, globalConfigErr := useCase.globalConfigRepo.Get(ctx)
 
 	logger.V(2).Info("collect needed dogu config")
-	configByDogu, doguConfigErr := useCase.doguConfigRepo.GetAllExisting(ctx, effectiveBlueprint.Config.GetDogusWithChangedConfig())
+	configByDogu, doguConfigErr := useCase.doguConfigRepo.GetAllExisting(ctx, getDogusToLoadConfig(effectiveBlueprint.Config, templatesEnabled, ownership))
 
 	logger.V(2).Info("collect needed sensitive dogu config")
-	sensitiveConfigByDogu, sensitiveConfigErr := useCase.sensitiveDoguConfigRepo.GetAllExisting(ctx, effectiveBlueprint.Config.GetDogusWithChangedSensitiveConfig())
+	sensitiveConfigByDogu, sensitiveConfigErr := useCase.sensitiveDoguConfigRepo.GetAllExisting(ctx, appendMissingDogus(effectiveBlueprint.Config.GetDogusWithChangedSensitiveConfig(), ownership.GetDogusWithOwnedSensitiveConfig()))
 
 	joinedError := errors.Join(doguErr, globalConfigErr, doguConfigErr, sensitiveConfigErr)
 	if joinedError != nil {
@@ -145,6 +166,25 @@ func (useCase *StateDiffUseCase) collectEcosystemState(ctx context.Context, effe
 	}, nil
 }
 
+// getDogusToLoadConfig returns the dogus with config in the blueprint, the dogus whose config is referenced in config templates
+// and the dogus with owned config, which may have to be removed.
+func getDogusToLoadConfig(blueprintConfig domain.Config, templatesEnabled bool, ownership domain.ConfigOwnership) []cescommons.SimpleName {
+	dogus := blueprintConfig.GetDogusWithChangedConfig()
+	if templatesEnabled {
+		dogus = appendMissingDogus(dogus, blueprintConfig.GetDogusReferencedByTemplates())
+	}
+	return appendMissingDogus(dogus, ownership.GetDogusWithOwnedConfig())
+}
+
+func appendMissingDogus(dogus []cescommons.SimpleName, additionalDogus []cescommons.SimpleName) []cescommons.SimpleName {
+	for _, dogu := range additionalDogus {
+		if !slices.Contains(dogus, dogu) {
+			dogus = append(dogus, dogu)
+		}
+	}
+	return dogus
+}
+
 func (useCase *StateDiffUseCase) loadReferencedDoguConfig(ctx context.Context, blueprint *domain.BlueprintSpec) (map[common.DoguConfigKey]common.SensitiveDoguConfigValue, map[common.DoguConfigKey]common.DoguConfigValue, error) {
 	secretRef := blueprint.EffectiveBlueprint.Config.GetSensitiveConfigReferences()
 	referencedSensitiveConfig, err := useCase.sensitiveConfigRefReader.GetValues(
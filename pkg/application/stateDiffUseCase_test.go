@@ -11,6 +11,7 @@ import (
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	"github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -45,6 +46,13 @@ var (
 	val3                    = "val3"
 )
 
+// newEmptyConfigOwnershipRepoMock returns a repository for a blueprint, which was never applied and therefore owns no config.
+func newEmptyConfigOwnershipRepoMock(t *testing.T) *mockConfigOwnershipRepository {
+	repoMock := newMockConfigOwnershipRepository(t)
+	repoMock.EXPECT().Get(mock.Anything, mock.Anything).Return(domain.ConfigOwnership{}, domainservice.NewNotFoundError(nil, "not found")).Maybe()
+	return repoMock
+}
+
 func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 	t.Run("should fail to get installed dogus", func(t *testing.T) {
 		// given
@@ -78,7 +86,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 
 		debugModeRepoMock := newMockDebugModeRepository(t)
 
-		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -120,7 +128,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 			Return(map[common.GlobalConfigKey]config.Value{}, nil)
 		debugModeRepoMock := newMockDebugModeRepository(t)
 
-		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -172,7 +180,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 			Return(map[common.GlobalConfigKey]config.Value{}, nil)
 		debugModeRepoMock := newMockDebugModeRepository(t)
 
-		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -219,7 +227,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 			Return(map[common.GlobalConfigKey]config.Value{}, nil)
 		debugModeRepoMock := newMockDebugModeRepository(t)
 
-		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -270,7 +278,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugModeRepoMock := newMockDebugModeRepository(t)
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, nil)
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -345,7 +353,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugModeRepoMock := newMockDebugModeRepository(t)
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, nil)
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -444,7 +452,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugModeRepoMock := newMockDebugModeRepository(t)
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, nil)
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -535,7 +543,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugModeRepoMock := newMockDebugModeRepository(t)
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, nil)
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -641,7 +649,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugModeRepoMock := newMockDebugModeRepository(t)
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, nil)
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -708,7 +716,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugModeRepoMock := newMockDebugModeRepository(t)
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, &domainservice.NotFoundError{})
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -782,7 +790,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugMode := ecosystem.DebugMode{Phase: "WaitForRollback"}
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(&debugMode, nil)
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -865,7 +873,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugMode := ecosystem.DebugMode{Phase: ecosystem.DebugModeStatusComplete}
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(&debugMode, nil)
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -930,7 +938,7 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		debugModeRepoMock := newMockDebugModeRepository(t)
 		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, &domainservice.InternalError{Message: "test-error"})
 
-		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
 		err := sut.DetermineStateDiff(testCtx, blueprint)
@@ -941,6 +949,88 @@ func TestStateDiffUseCase_DetermineStateDiff(t *testing.T) {
 		assert.ErrorAs(t, err, &errorToCheck)
 		assert.ErrorContains(t, err, "cannot calculate effective blueprint due to an error when loading the debug mode cr")
 	})
+	t.Run("should fail to load config ownership", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Id: "testBlueprint1"}
+
+		sensitiveConfigRefReaderMock := newMockSensitiveConfigRefReader(t)
+		sensitiveConfigRefReaderMock.EXPECT().
+			GetValues(testCtx, map[common.DoguConfigKey]domain.SensitiveValueRef{}).
+			Return(map[common.DoguConfigKey]config.Value{}, nil)
+		sensitiveConfigRefReaderMock.EXPECT().
+			GetGlobalValues(testCtx, map[common.GlobalConfigKey]domain.SensitiveValueRef{}).
+			Return(map[common.GlobalConfigKey]config.Value{}, nil)
+		configRefReaderMock := newMockConfigRefReader(t)
+		configRefReaderMock.EXPECT().
+			GetValues(testCtx, map[common.DoguConfigKey]domain.ConfigValueRef{}).
+			Return(map[common.DoguConfigKey]config.Value{}, nil)
+		configRefReaderMock.EXPECT().
+			GetGlobalValues(testCtx, map[common.GlobalConfigKey]domain.ConfigValueRef{}).
+			Return(map[common.GlobalConfigKey]config.Value{}, nil)
+		ownershipRepoMock := newMockConfigOwnershipRepository(t)
+		ownershipRepoMock.EXPECT().Get(testCtx, "testBlueprint1").Return(domain.ConfigOwnership{}, internalTestError)
+
+		sut := NewStateDiffUseCase(nil, nil, nil, nil, nil, sensitiveConfigRefReaderMock, configRefReaderMock, nil, ownershipRepoMock)
+
+		// when
+		err := sut.DetermineStateDiff(testCtx, blueprint)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, internalTestError)
+		assert.ErrorContains(t, err, "could not determine state diff: cannot load config ownership")
+	})
+	t.Run("should remove owned config without saving the ownership", func(t *testing.T) {
+		// given
+		blueprint := &domain.BlueprintSpec{Id: "testBlueprint1", Conditions: []domain.Condition{}}
+
+		blueprintRepoMock := newMockBlueprintSpecRepository(t)
+		blueprintRepoMock.EXPECT().Update(testCtx, blueprint).Return(nil)
+
+		doguInstallRepoMock := newMockDoguInstallationRepository(t)
+		doguInstallRepoMock.EXPECT().GetAll(testCtx).Return(map[cescommons.SimpleName]*ecosystem.DoguInstallation{}, nil)
+		globalConfigRepoMock := newMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(config.CreateGlobalConfig(map[config.Key]config.Value{}), nil)
+		doguConfigRepoMock := newMockDoguConfigRepository(t)
+		doguConfigRepoMock.EXPECT().GetAllExisting(testCtx, []cescommons.SimpleName{ldap}).Return(map[cescommons.SimpleName]config.DoguConfig{
+			ldap: config.CreateDoguConfig(ldap, map[config.Key]config.Value{"ldapKey1": "val1"}),
+		}, nil)
+		sensitiveDoguConfigRepoMock := newMockSensitiveDoguConfigRepository(t)
+		sensitiveDoguConfigRepoMock.EXPECT().GetAllExisting(testCtx, nilDoguNameList).Return(map[cescommons.SimpleName]config.DoguConfig{}, nil)
+		sensitiveConfigRefReaderMock := newMockSensitiveConfigRefReader(t)
+		sensitiveConfigRefReaderMock.EXPECT().
+			GetValues(testCtx, map[common.DoguConfigKey]domain.SensitiveValueRef{}).
+			Return(map[common.DoguConfigKey]config.Value{}, nil)
+		sensitiveConfigRefReaderMock.EXPECT().
+			GetGlobalValues(testCtx, map[common.GlobalConfigKey]domain.SensitiveValueRef{}).
+			Return(map[common.GlobalConfigKey]config.Value{}, nil)
+		configRefReaderMock := newMockConfigRefReader(t)
+		configRefReaderMock.EXPECT().
+			GetValues(testCtx, map[common.DoguConfigKey]domain.ConfigValueRef{}).
+			Return(map[common.DoguConfigKey]config.Value{}, nil)
+		configRefReaderMock.EXPECT().
+			GetGlobalValues(testCtx, map[common.GlobalConfigKey]domain.ConfigValueRef{}).
+			Return(map[common.GlobalConfigKey]config.Value{}, nil)
+		debugModeRepoMock := newMockDebugModeRepository(t)
+		debugModeRepoMock.EXPECT().GetSingleton(testCtx).Return(nil, nil)
+		ownershipRepoMock := newMockConfigOwnershipRepository(t)
+		ownershipRepoMock.EXPECT().Get(testCtx, "testBlueprint1").Return(domain.ConfigOwnership{
+			DoguConfig: []common.DoguConfigKey{ldapConfigKey1, ldapConfigKey2},
+		}, nil)
+		// the ownership is only saved with the apply, so that it is unchanged for stopped blueprints, plans and dry runs
+		sut := NewStateDiffUseCase(blueprintRepoMock, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, ownershipRepoMock)
+
+		// when
+		err := sut.DetermineStateDiff(testCtx, blueprint)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, blueprint.StateDiff.DoguConfigDiffs[ldap], 1)
+		assert.Equal(t, ldapConfigKey1, blueprint.StateDiff.DoguConfigDiffs[ldap][0].Key)
+		assert.Equal(t, domain.ConfigActionRemove, blueprint.StateDiff.DoguConfigDiffs[ldap][0].NeededAction)
+		assert.Equal(t, []common.DoguConfigKey{ldapConfigKey1, ldapConfigKey2}, blueprint.ConfigOwnership.DoguConfig)
+		assert.True(t, blueprint.GetAppliedConfigOwnership().Equal(domain.ConfigOwnership{}))
+	})
 }
 
 func mustParseVersion(t *testing.T, raw string) core.Version {
@@ -1023,10 +1113,10 @@ func TestStateDiffUseCase_collectEcosystemState(t *testing.T) {
 		sensitiveConfigRefReaderMock := newMockSensitiveConfigRefReader(t)
 		debugModeRepoMock := newMockDebugModeRepository(t)
 
-		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
-		ecosystemState, err := sut.collectEcosystemState(testCtx, effectiveBlueprint)
+		ecosystemState, err := sut.collectEcosystemState(testCtx, effectiveBlueprint, false, domain.ConfigOwnership{})
 
 		// then
 		assert.NoError(t, err)
@@ -1103,10 +1193,10 @@ func TestStateDiffUseCase_collectEcosystemState(t *testing.T) {
 		configRefReaderMock := newMockConfigRefReader(t)
 		debugModeRepoMock := newMockDebugModeRepository(t)
 
-		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock)
+		sut := NewStateDiffUseCase(nil, doguInstallRepoMock, globalConfigRepoMock, doguConfigRepoMock, sensitiveDoguConfigRepoMock, sensitiveConfigRefReaderMock, configRefReaderMock, debugModeRepoMock, newEmptyConfigOwnershipRepoMock(t))
 
 		// when
-		ecosystemState, err := sut.collectEcosystemState(testCtx, effectiveBlueprint)
+		ecosystemState, err := sut.collectEcosystemState(testCtx, effectiveBlueprint, false, domain.ConfigOwnership{})
 
 		// then
 		assert.ErrorIs(t, err, internalTestError)
@@ -1114,3 +1204,20 @@ func TestStateDiffUseCase_collectEcosystemState(t *testing.T) {
 		assert.Equal(t, ecosystem.EcosystemState{}, ecosystemState)
 	})
 }
+
+func Test_getDogusToLoadConfig(t *testing.T) {
+	url := config.Value("{{ .Dogus.nexus.url }} {{ .Dogus.redmine.url }}")
+	blueprintConfig := domain.Config{
+		Dogus: domain.DoguConfig{
+			"redmine": {{Key: "url", Value: &url}},
+		},
+	}
+
+	ownership := domain.ConfigOwnership{DoguConfig: []common.DoguConfigKey{
+		{DoguName: "scm", Key: "removed"},
+	}}
+
+	assert.Equal(t, []cescommons.SimpleName{"redmine", "nexus", "scm"}, getDogusToLoadConfig(blueprintConfig, true, ownership))
+	// without enabled templates, the value is no template
+	assert.Equal(t, []cescommons.SimpleName{"redmine", "scm"}, getDogusToLoadConfig(blueprintConfig, false, ownership))
+}
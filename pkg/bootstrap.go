@@ -1,22 +1,32 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 
 	adapterconfigk8s "github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/config/kubernetes"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/backupcr"
 	v2 "github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/blueprintcr/v3"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/componentcr"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/configref"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/debugmodecr"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/outdatedcm"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/ownershipcm"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/plancm"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/restorecr"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/revisioncm"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/kubernetes/sensitiveconfigref"
 	"github.com/cloudogu/k8s-registry-lib/dogu"
 	"github.com/cloudogu/k8s-registry-lib/repository"
 	remotedogudescriptor "github.com/cloudogu/remote-dogu-descriptor-lib/repository"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/cesapp-lib/remote"
 	restoreEcoClient "github.com/cloudogu/k8s-backup-lib/api/ecosystem"
 	adapterk8s "github.com/cloudogu/k8s-blueprint-lib/v3/client"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/adapter/doguregistry"
@@ -27,11 +37,15 @@ import (
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domainservice"
 	debugModeClient "github.com/cloudogu/k8s-debug-mode-cr-lib/pkg/client/v1"
 	doguEcoClient "github.com/cloudogu/k8s-dogu-lib/v2/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 // ApplicationContext contains vital application parts for this operator.
 type ApplicationContext struct {
 	BlueprintReconciler *reconciler.BlueprintReconciler
+	// RegistryAccessReconciler is nil if the access to the remote dogu registry is not read from secrets.
+	RegistryAccessReconciler *reconciler.RegistryAccessReconciler
 }
 
 // Bootstrap creates the ApplicationContext and does all dependency injection of the whole application.
@@ -55,13 +69,17 @@ func Bootstrap(restConfig *rest.Config, eventRecorder record.EventRecorder, oper
 	if err != nil {
 		return nil, fmt.Errorf("failed to create restore interface: %w", err)
 	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic interface: %w", err)
+	}
 	blueprintRepo := v2.NewBlueprintSpecRepository(
 		blueprintInterface,
 		ecosystemClientSet.EcosystemV1Alpha1().BlueprintMasks(operatorConfig.Namespace),
 		eventRecorder,
 	)
 
-	remoteDoguRegistry, err := createRemoteDoguRegistry(ecosystemClientSet, operatorConfig.Namespace)
+	remoteDoguRegistry, registryAccessReconciler, err := createRemoteDoguRegistry(ecosystemClientSet, operatorConfig.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +87,8 @@ func Bootstrap(restConfig *rest.Config, eventRecorder record.EventRecorder, oper
 	k8sDoguConfigRepo := repository.NewDoguConfigRepository(ecosystemClientSet.CoreV1().ConfigMaps(operatorConfig.Namespace))
 	doguConfigRepo := adapterconfigk8s.NewDoguConfigRepository(*k8sDoguConfigRepo)
 	k8sSensitiveDoguConfigRepo := repository.NewSensitiveDoguConfigRepository(ecosystemClientSet.CoreV1().Secrets(operatorConfig.Namespace))
+	ownershipRepo := ownershipcm.NewOwnershipRepo(ecosystemClientSet.CoreV1().ConfigMaps(operatorConfig.Namespace), blueprintInterface)
+	outdatedRepo := outdatedcm.NewOutdatedRepo(ecosystemClientSet.CoreV1().ConfigMaps(operatorConfig.Namespace), blueprintInterface)
 	sensitiveDoguConfigRepo := adapterconfigk8s.NewSensitiveDoguConfigRepository(*k8sSensitiveDoguConfigRepo)
 	sensitiveConfigRefReader := sensitiveconfigref.NewSecretRefReader(ecosystemClientSet.CoreV1().Secrets(operatorConfig.Namespace))
 	configMapRefReader := configref.NewConfigMapRefReader(ecosystemClientSet.CoreV1().ConfigMaps(operatorConfig.Namespace))
@@ -78,21 +98,32 @@ func Bootstrap(restConfig *rest.Config, eventRecorder record.EventRecorder, oper
 	doguRepo := dogucr.NewDoguInstallationRepo(dogusInterface.Dogus(operatorConfig.Namespace))
 	debugModeRepo := debugmodecr.NewDebugModeRepo(debugModeClientSet.DebugMode(operatorConfig.Namespace))
 	restoreRepo := restorecr.NewRestoreRepo(restoreClientSet.Restores(operatorConfig.Namespace))
+	backupRepo := backupcr.NewBackupRepo(restoreClientSet.Backups(operatorConfig.Namespace))
+	componentRepo := componentcr.NewComponentInstallationRepo(dynamicClient.Resource(componentcr.ComponentResource).Namespace(operatorConfig.Namespace))
+	planRepo := plancm.NewPlanRepo(ecosystemClientSet.CoreV1().ConfigMaps(operatorConfig.Namespace), blueprintInterface)
+	planKeyRepo := plancm.NewPlanKeyRepo(ecosystemClientSet.CoreV1().Secrets(operatorConfig.Namespace))
+	revisionRepo := revisioncm.NewRevisionRepo(ecosystemClientSet.CoreV1().ConfigMaps(operatorConfig.Namespace), ecosystemClientSet.CoreV1().Secrets(operatorConfig.Namespace), blueprintInterface)
 
 	initialBlueprintStateUseCase := application.NewInitiateBlueprintStatusUseCase(blueprintRepo)
 	validateDependenciesUseCase := domainservice.NewValidateDependenciesDomainUseCase(remoteDoguRegistry, operatorConfig.AuthRegistrationEnabled, operatorConfig.DisablePostfixDependencyCheck)
 	validateMountsUseCase := domainservice.NewValidateAdditionalMountsDomainUseCase(remoteDoguRegistry)
 	validateStorageClassUseCase := domainservice.NewValidateStorageClassDomainUseCase(doguRepo)
-	blueprintValidationUseCase := application.NewBlueprintSpecValidationUseCase(blueprintRepo, validateDependenciesUseCase, validateMountsUseCase, validateStorageClassUseCase)
-	effectiveBlueprintUseCase := application.NewEffectiveBlueprintUseCase(blueprintRepo)
-	stateDiffUseCase := application.NewStateDiffUseCase(blueprintRepo, doguRepo, globalConfigRepo, doguConfigRepo, sensitiveDoguConfigRepo, sensitiveConfigRefReader, configMapRefReader, debugModeRepo)
-	doguInstallationUseCase := application.NewDoguInstallationUseCase(blueprintRepo, doguRepo, globalConfigRepo, doguConfigRepo, sensitiveDoguConfigRepo)
-	ecosystemHealthUseCase := application.NewEcosystemHealthUseCase(doguInstallationUseCase, blueprintRepo)
+	validateDoguConfigUseCase := domainservice.NewValidateDoguConfigDomainUseCase(remoteDoguRegistry)
+	blueprintValidationUseCase := application.NewBlueprintSpecValidationUseCase(blueprintRepo, validateDependenciesUseCase, validateMountsUseCase, validateStorageClassUseCase, validateDoguConfigUseCase)
+	resolveDoguVersionsUseCase := domainservice.NewResolveDoguVersionsDomainUseCase(remoteDoguRegistry, doguRepo)
+	effectiveBlueprintUseCase := application.NewEffectiveBlueprintUseCase(blueprintRepo, resolveDoguVersionsUseCase, validateDependenciesUseCase)
+	stateDiffUseCase := application.NewStateDiffUseCase(blueprintRepo, doguRepo, globalConfigRepo, doguConfigRepo, sensitiveDoguConfigRepo, sensitiveConfigRefReader, configMapRefReader, debugModeRepo, ownershipRepo)
+	sortDoguDiffsUseCase := domainservice.NewSortDoguDiffsDomainUseCase(remoteDoguRegistry)
+	doguInstallationUseCase := application.NewDoguInstallationUseCase(blueprintRepo, doguRepo, globalConfigRepo, doguConfigRepo, sensitiveDoguConfigRepo, sortDoguDiffsUseCase)
+	ecosystemHealthUseCase := application.NewEcosystemHealthUseCase(doguInstallationUseCase, componentRepo, blueprintRepo)
 	restoreInProgressUseCase := application.NewRestoreInProgressUseCase(restoreRepo)
 	completeBlueprintSpecUseCase := application.NewCompleteBlueprintUseCase(blueprintRepo)
+	preDowngradeBackupUseCase := application.NewPreDowngradeBackupUseCase(blueprintRepo, backupRepo)
 	applyDogusUseCase := application.NewApplyDogusUseCase(blueprintRepo, doguInstallationUseCase)
-	ConfigUseCase := application.NewEcosystemConfigUseCase(blueprintRepo, doguConfigRepo, sensitiveDoguConfigRepo, globalConfigRepo, doguRepo)
+	ConfigUseCase := application.NewEcosystemConfigUseCase(blueprintRepo, doguConfigRepo, sensitiveDoguConfigRepo, globalConfigRepo, doguRepo, revisionRepo, ownershipRepo)
+	configRollbackUseCase := application.NewConfigRollbackUseCase(blueprintRepo, revisionRepo, doguConfigRepo, sensitiveDoguConfigRepo, globalConfigRepo)
 	dogusUpToDateUseCase := application.NewDogusUpToDateUseCase(blueprintRepo, doguInstallationUseCase)
+	planApprovalUseCase := application.NewPlanApprovalUseCase(blueprintRepo, planRepo, planKeyRepo)
 
 	preparationUseCases := application.NewBlueprintPreparationUseCase(
 		initialBlueprintStateUseCase,
@@ -103,41 +134,141 @@ func Bootstrap(restConfig *rest.Config, eventRecorder record.EventRecorder, oper
 		restoreInProgressUseCase,
 	)
 	applyUseCases := application.NewBlueprintApplyUseCase(
+		planApprovalUseCase,
+		preDowngradeBackupUseCase,
 		completeBlueprintSpecUseCase,
 		ConfigUseCase,
 		applyDogusUseCase,
 		ecosystemHealthUseCase,
 		dogusUpToDateUseCase,
 	)
-	blueprintChangeUseCase := application.NewBlueprintSpecChangeUseCase(blueprintRepo, preparationUseCases, applyUseCases)
+	blueprintChangeUseCase := application.NewBlueprintSpecChangeUseCase(blueprintRepo, preparationUseCases, applyUseCases, configRollbackUseCase)
 	debounceWindow, err := config.GetDebounceWindow()
 	if err != nil {
 		return nil, err
 	}
-	blueprintReconciler := reconciler.NewBlueprintReconciler(blueprintChangeUseCase, blueprintRepo, operatorConfig.Namespace, debounceWindow)
+	autoUpgradePollInterval, err := config.GetAutoUpgradePollInterval()
+	if err != nil {
+		return nil, err
+	}
+	autoUpgradeUseCase := application.NewAutoUpgradeUseCase(blueprintRepo, remoteDoguRegistry)
+	registryPoller := reconciler.NewRegistryPoller(autoUpgradeUseCase, blueprintRepo, operatorConfig.Namespace, autoUpgradePollInterval)
+	outdatedReportInterval, err := config.GetOutdatedReportInterval()
+	if err != nil {
+		return nil, err
+	}
+	outdatedDogusUseCase := application.NewOutdatedDogusUseCase(blueprintRepo, remoteDoguRegistry, outdatedRepo)
+	outdatedReporter := reconciler.NewOutdatedReporter(outdatedDogusUseCase, blueprintRepo, outdatedReportInterval)
+	blueprintReconciler := reconciler.NewBlueprintReconciler(blueprintChangeUseCase, blueprintRepo, operatorConfig.Namespace, debounceWindow, registryPoller, outdatedReporter)
 
 	return &ApplicationContext{
-		BlueprintReconciler: blueprintReconciler,
+		BlueprintReconciler:      blueprintReconciler,
+		RegistryAccessReconciler: registryAccessReconciler,
 	}, nil
 }
 
-func createRemoteDoguRegistry(clientSet *adapterk8s.ClientSet, namespace string) (*doguregistry.DoguDescriptorRepository, error) {
-	remoteConfig, err := config.GetRemoteConfiguration()
+func createRemoteDoguRegistry(clientSet *adapterk8s.ClientSet, namespace string) (domainservice.RemoteDoguRegistry, *reconciler.RegistryAccessReconciler, error) {
+	bundleConfig, err := config.GetDoguRegistryBundleConfiguration()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dogu bundle config: %w", err)
+	}
+	if bundleConfig != nil {
+		bundleRegistry, err := doguregistry.NewBundleDoguRegistry(bundleConfig.Path, bundleConfig.PublicKeyPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create dogu registry from bundle: %w", err)
+		}
+		return bundleRegistry, nil, nil
+	}
+
+	remoteConfigs, err := config.GetRemoteConfigurations()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get remote dogu registry config: %w", err)
+		return nil, nil, fmt.Errorf("failed to get remote dogu registry config: %w", err)
 	}
 
-	remoteCreds, err := config.GetRemoteCredentials()
+	openDuration, err := config.GetRegistryCircuitBreakerOpenDuration()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get remote dogu registry credentials: %w", err)
+		return nil, nil, err
+	}
+
+	createEndpoints := func(access doguregistry.RegistryAccess) ([]doguregistry.RegistryEndpoint, error) {
+		return createRegistryEndpoints(remoteConfigs, access)
+	}
+	defaultAccess := doguregistry.RegistryAccess{ProxyURL: config.GetProxyURL()}
+	secretsConfig := config.GetDoguRegistrySecretsConfiguration()
+	if secretsConfig == nil || secretsConfig.CredentialsSecret == "" {
+		remoteCreds, err := config.GetRemoteCredentials()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get remote dogu registry credentials: %w", err)
+		}
+		defaultAccess.Credentials = *remoteCreds
+	}
+
+	var accessReconciler *reconciler.RegistryAccessReconciler
+	var failoverRemote *doguregistry.FailoverRemote
+	if secretsConfig == nil {
+		endpoints, err := createEndpoints(defaultAccess)
+		if err != nil {
+			return nil, nil, err
+		}
+		failoverRemote = doguregistry.NewFailoverRemote(endpoints, openDuration)
+	} else {
+		failoverRemote = doguregistry.NewFailoverRemote(nil, openDuration)
+		accessRefresher := doguregistry.NewAccessRefresher(
+			failoverRemote,
+			createEndpoints,
+			clientSet.CoreV1().Secrets(namespace),
+			doguregistry.AccessSecrets{CredentialsSecret: secretsConfig.CredentialsSecret, ProxySecret: secretsConfig.ProxySecret},
+			defaultAccess,
+		)
+		// the manager is not started yet, so the access is read once before the first reconciliation
+		err = accessRefresher.RefreshAccess(context.Background())
+		if err != nil {
+			// the registry is reported as unreachable on the blueprint until the secrets are fixed and refreshed
+			log.Log.WithName("bootstrap").Error(err, "cannot read access to the remote dogu registry, the registry is unreachable until the secrets are valid")
+		}
+		accessReconciler = reconciler.NewRegistryAccessReconciler(accessRefresher, namespace, secretsConfig.CredentialsSecret, secretsConfig.ProxySecret)
 	}
 
-	doguRemoteRepository, err := remotedogudescriptor.NewRemoteDoguDescriptorRepository(remoteConfig, remoteCreds)
 	doguLocalRepository := dogu.NewLocalDoguDescriptorRepository(clientSet.CoreV1().ConfigMaps(namespace))
+	doguDescriptorRepository := doguregistry.NewDoguDescriptorRepository(failoverRemote, doguLocalRepository, failoverRemote)
+	cachingDoguRegistry, err := doguregistry.NewCachingDoguRegistry(doguDescriptorRepository, metrics.Registry)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new remote dogu repository: %w", err)
+		return nil, nil, err
+	}
+	return cachingDoguRegistry, accessReconciler, nil
+}
+
+func createRegistryEndpoints(remoteConfigs []*core.Remote, access doguregistry.RegistryAccess) ([]doguregistry.RegistryEndpoint, error) {
+	proxySettings, err := config.ParseProxySettings(access.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]doguregistry.RegistryEndpoint, 0, len(remoteConfigs))
+	for _, remoteConfig := range remoteConfigs {
+		endpointConfig := *remoteConfig
+		endpointConfig.ProxySettings = proxySettings
+		credentials := access.Credentials
+
+		doguRemoteRepository, err := remotedogudescriptor.NewRemoteDoguDescriptorRepository(&endpointConfig, &credentials)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new remote dogu repository for endpoint %q: %w", endpointConfig.Endpoint, err)
+		}
+
+		remoteRegistry, err := remote.New(&endpointConfig, &credentials)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new remote dogu registry for endpoint %q: %w", endpointConfig.Endpoint, err)
+		}
+		// the file cache would hide the errors of the registry, e.g. a missing dogu, behind the error of the cache
+		// and the available versions must be current anyway
+		remoteRegistry.SetUseCache(false)
+		endpoints = append(endpoints, doguregistry.RegistryEndpoint{
+			URL:                  endpointConfig.Endpoint,
+			DescriptorRepository: doguRemoteRepository,
+			Registry:             remoteRegistry,
+		})
 	}
-	return doguregistry.NewDoguDescriptorRepository(doguRemoteRepository, doguLocalRepository), nil
+	return endpoints, nil
 }
 
 func createEcosystemClientSet(restConfig *rest.Config) (*adapterk8s.ClientSet, error) {
@@ -23,6 +23,13 @@ const (
 	debounceWindowEnvVar = "DEBOUNCE_WINDOW"
 )
 
+const (
+	autoUpgradePollIntervalEnvVar  = "AUTO_UPGRADE_POLL_INTERVAL"
+	defaultAutoUpgradePollInterval = time.Hour
+	outdatedReportIntervalEnvVar   = "OUTDATED_REPORT_INTERVAL"
+	defaultOutdatedReportInterval  = 24 * time.Hour
+)
+
 const (
 	doguRegistryEndpointEnvVar  = "DOGU_REGISTRY_ENDPOINT"
 	doguRegistryUsernameEnvVar  = "DOGU_REGISTRY_USERNAME"
@@ -30,6 +37,23 @@ const (
 	doguRegistryURLSchemaEnvVar = "DOGU_REGISTRY_URLSCHEMA"
 )
 
+const (
+	doguRegistryFailoverEndpointsEnvVar          = "DOGU_REGISTRY_FAILOVER_ENDPOINTS"
+	doguRegistryCircuitBreakerOpenDurationEnvVar = "DOGU_REGISTRY_CIRCUIT_BREAKER_OPEN_DURATION"
+	defaultCircuitBreakerOpenDuration            = time.Minute
+)
+
+const (
+	doguRegistryCredentialsSecretEnvVar = "DOGU_REGISTRY_CREDENTIALS_SECRET"
+	doguRegistryProxySecretEnvVar       = "DOGU_REGISTRY_PROXY_SECRET"
+	proxyURLEnvVar                      = "PROXY_URL"
+)
+
+const (
+	doguRegistryBundlePathEnvVar          = "DOGU_REGISTRY_BUNDLE_PATH"
+	doguRegistryBundlePublicKeyPathEnvVar = "DOGU_REGISTRY_BUNDLE_PUBLIC_KEY_PATH"
+)
+
 // feature flags
 const (
 	authRegistrationEnabledEnvVar       = "AUTH_REGISTRATION_ENABLED"
@@ -136,31 +160,80 @@ func GetRemoteConfiguration() (*core.Remote, error) {
 		return nil, err
 	}
 
+	proxySettings, err := ParseProxySettings(GetProxyURL())
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.Remote{
+		Endpoint:      trimEndpoint(endpoint, urlSchema),
+		CacheDir:      registryCacheDir,
+		URLSchema:     urlSchema,
+		ProxySettings: proxySettings,
+	}, nil
+}
+
+func trimEndpoint(endpoint string, urlSchema string) string {
 	if urlSchema == "default" {
 		// trim suffix 'dogus' or 'dogus/' to provide maximum compatibility with the old remote configuration of the operator
 		endpoint = strings.TrimSuffix(endpoint, "dogus/")
 		endpoint = strings.TrimSuffix(endpoint, "dogus")
 	}
+	return endpoint
+}
 
-	proxyURL, b := os.LookupEnv("PROXY_URL")
+// GetRemoteConfigurations returns the remote configuration of the primary registry endpoint followed by the
+// configurations of all failover endpoints in their configured order.
+// The failover endpoints share the url schema, proxy and credentials of the primary endpoint.
+func GetRemoteConfigurations() ([]*core.Remote, error) {
+	primaryConfig, err := GetRemoteConfiguration()
+	if err != nil {
+		return nil, err
+	}
 
-	proxySettings := core.ProxySettings{}
-	if b && len(proxyURL) > 0 {
-		var err error
-		if proxySettings, err = configureProxySettings(proxyURL); err != nil {
-			return nil, err
+	remoteConfigs := []*core.Remote{primaryConfig}
+	failoverEndpoints, _ := os.LookupEnv(doguRegistryFailoverEndpointsEnvVar)
+	for _, endpoint := range strings.Split(failoverEndpoints, ",") {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			continue
 		}
+		failoverConfig := *primaryConfig
+		failoverConfig.Endpoint = trimEndpoint(endpoint, primaryConfig.URLSchema)
+		// each endpoint needs its own cache, as the cache is not separated by endpoint
+		failoverConfig.CacheDir = fmt.Sprintf("%s-%d", registryCacheDir, len(remoteConfigs))
+		remoteConfigs = append(remoteConfigs, &failoverConfig)
 	}
+	return remoteConfigs, nil
+}
 
-	return &core.Remote{
-		Endpoint:      endpoint,
-		CacheDir:      registryCacheDir,
-		URLSchema:     urlSchema,
-		ProxySettings: proxySettings,
-	}, nil
+// GetRegistryCircuitBreakerOpenDuration returns the duration in which an unreachable endpoint of the remote dogu
+// registry is skipped before it gets tried again.
+func GetRegistryCircuitBreakerOpenDuration() (time.Duration, error) {
+	durationString, found := os.LookupEnv(doguRegistryCircuitBreakerOpenDurationEnvVar)
+	if !found {
+		log.Info(fmt.Sprintf("Environment variable %s not set. Using default of %s", doguRegistryCircuitBreakerOpenDurationEnvVar, defaultCircuitBreakerOpenDuration))
+		return defaultCircuitBreakerOpenDuration, nil
+	}
+	duration, err := time.ParseDuration(durationString)
+	if err != nil {
+		return time.Duration(0), fmt.Errorf("failed to parse env var [%s] to duration: %w", doguRegistryCircuitBreakerOpenDurationEnvVar, err)
+	}
+	return duration, nil
 }
 
-func configureProxySettings(proxyURL string) (core.ProxySettings, error) {
+// GetProxyURL returns the configured proxy url or an empty string if no proxy is used.
+func GetProxyURL() string {
+	proxyURL, _ := os.LookupEnv(proxyURLEnvVar)
+	return proxyURL
+}
+
+// ParseProxySettings creates the proxy settings for the given proxy url. The proxy is disabled for an empty url.
+func ParseProxySettings(proxyURL string) (core.ProxySettings, error) {
+	if proxyURL == "" {
+		return core.ProxySettings{}, nil
+	}
+
 	parsedURL, err := url.Parse(proxyURL)
 	if err != nil {
 		return core.ProxySettings{}, fmt.Errorf("invalid proxy url: %w", err)
@@ -204,6 +277,54 @@ func GetRemoteCredentials() (*core.Credentials, error) {
 	}, nil
 }
 
+// DoguRegistrySecretsConfig references the secrets with the access to the remote dogu registry.
+// The secrets are watched, so that changed credentials are used without a restart.
+type DoguRegistrySecretsConfig struct {
+	// CredentialsSecret is the name of the secret with the credentials or empty if they are read from the environment.
+	CredentialsSecret string
+	// ProxySecret is the name of the secret with the proxy url or empty if it is read from the environment.
+	ProxySecret string
+}
+
+// GetDoguRegistrySecretsConfiguration returns the secrets with the access to the remote dogu registry or
+// nil if no secret is configured and the access is read from the environment once at startup.
+func GetDoguRegistrySecretsConfiguration() *DoguRegistrySecretsConfig {
+	credentialsSecret, _ := os.LookupEnv(doguRegistryCredentialsSecretEnvVar)
+	proxySecret, _ := os.LookupEnv(doguRegistryProxySecretEnvVar)
+	if credentialsSecret == "" && proxySecret == "" {
+		return nil
+	}
+	return &DoguRegistrySecretsConfig{
+		CredentialsSecret: credentialsSecret,
+		ProxySecret:       proxySecret,
+	}
+}
+
+// DoguRegistryBundleConfig contains the location of a local dogu bundle which replaces the remote dogu registry.
+type DoguRegistryBundleConfig struct {
+	// Path is the path of the bundle directory or the gzipped bundle tarball.
+	Path string
+	// PublicKeyPath is the path of the PEM encoded ed25519 public key to verify the signature of the bundle index.
+	PublicKeyPath string
+}
+
+// GetDoguRegistryBundleConfiguration returns the configuration of the local dogu bundle or
+// nil if no bundle is configured and the remote dogu registry should be used.
+func GetDoguRegistryBundleConfiguration() (*DoguRegistryBundleConfig, error) {
+	bundlePath, found := os.LookupEnv(doguRegistryBundlePathEnvVar)
+	if !found || bundlePath == "" {
+		return nil, nil
+	}
+	publicKeyPath, err := getRequiredEnvVar(doguRegistryBundlePublicKeyPathEnvVar)
+	if err != nil {
+		return nil, fmt.Errorf("the public key of the dogu bundle is required: %w", err)
+	}
+	return &DoguRegistryBundleConfig{
+		Path:          bundlePath,
+		PublicKeyPath: publicKeyPath,
+	}, nil
+}
+
 func GetDebounceWindow() (time.Duration, error) {
 	windowSecString, err := getRequiredEnvVar(debounceWindowEnvVar)
 	if err != nil {
@@ -216,6 +337,36 @@ func GetDebounceWindow() (time.Duration, error) {
 	return window, nil
 }
 
+// GetAutoUpgradePollInterval returns the interval in which the remote dogu registry gets checked for new dogu releases
+// matching the auto upgrade policies of the blueprint. A duration of 0 disables polling.
+func GetAutoUpgradePollInterval() (time.Duration, error) {
+	intervalString, found := os.LookupEnv(autoUpgradePollIntervalEnvVar)
+	if !found {
+		log.Info(fmt.Sprintf("Environment variable %s not set. Using default of %s", autoUpgradePollIntervalEnvVar, defaultAutoUpgradePollInterval))
+		return defaultAutoUpgradePollInterval, nil
+	}
+	interval, err := time.ParseDuration(intervalString)
+	if err != nil {
+		return time.Duration(0), fmt.Errorf("failed to parse env var [%s] to duration: %w", autoUpgradePollIntervalEnvVar, err)
+	}
+	return interval, nil
+}
+
+// GetOutdatedReportInterval returns the interval in which the dogus of the blueprint get compared with the newest releases
+// in the remote dogu registry. A duration of 0 disables the report.
+func GetOutdatedReportInterval() (time.Duration, error) {
+	intervalString, found := os.LookupEnv(outdatedReportIntervalEnvVar)
+	if !found {
+		log.Info(fmt.Sprintf("Environment variable %s not set. Using default of %s", outdatedReportIntervalEnvVar, defaultOutdatedReportInterval))
+		return defaultOutdatedReportInterval, nil
+	}
+	interval, err := time.ParseDuration(intervalString)
+	if err != nil {
+		return time.Duration(0), fmt.Errorf("failed to parse env var [%s] to duration: %w", outdatedReportIntervalEnvVar, err)
+	}
+	return interval, nil
+}
+
 func getAuthRegistrationEnabled() bool {
 	authRegistrationEnabledStr, found := os.LookupEnv(authRegistrationEnabledEnvVar)
 	if !found {
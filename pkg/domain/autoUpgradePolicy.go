@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+// AutoUpgradePolicy defines which new releases of a dogu get applied automatically
+// on top of the version in the blueprint.
+type AutoUpgradePolicy string
+
+const (
+	// AutoUpgradePatch applies new releases with the same major and minor version.
+	AutoUpgradePatch AutoUpgradePolicy = "patch"
+	// AutoUpgradeMinor applies new releases with the same major version.
+	AutoUpgradeMinor AutoUpgradePolicy = "minor"
+)
+
+// AutoUpgradePolicies contains the AutoUpgradePolicy of every dogu which should be upgraded automatically.
+type AutoUpgradePolicies map[cescommons.SimpleName]AutoUpgradePolicy
+
+// VersionConstraint returns the constraint for all versions which may be applied automatically,
+// starting with the given version from the blueprint.
+func (policy AutoUpgradePolicy) VersionConstraint(blueprintVersion core.Version) (VersionConstraint, error) {
+	switch policy {
+	case AutoUpgradePatch:
+		return ParseVersionConstraint(fmt.Sprintf(">=%s <%d.%d", blueprintVersion.Raw, blueprintVersion.Major, blueprintVersion.Minor+1))
+	case AutoUpgradeMinor:
+		return ParseVersionConstraint(fmt.Sprintf(">=%s <%d", blueprintVersion.Raw, blueprintVersion.Major+1))
+	default:
+		return VersionConstraint{}, fmt.Errorf("unknown auto upgrade policy %q", policy)
+	}
+}
+
+// validate checks that every policy is known and belongs to a present dogu with an exact version in the blueprint.
+func (policies AutoUpgradePolicies) validate(dogus []Dogu) error {
+	var errs []error
+	for _, doguName := range slices.Sorted(maps.Keys(policies)) {
+		policy := policies[doguName]
+		if policy != AutoUpgradePatch && policy != AutoUpgradeMinor {
+			errs = append(errs, fmt.Errorf("auto upgrade policy %q of dogu %q must be one of %q, %q", policy, doguName, AutoUpgradePatch, AutoUpgradeMinor))
+		}
+		dogu, found := FindDoguByName(dogus, doguName)
+		if !found || dogu.Absent {
+			errs = append(errs, fmt.Errorf("dogu %q with auto upgrade policy is not present in the blueprint", doguName))
+		} else if dogu.VersionConstraint != nil {
+			errs = append(errs, fmt.Errorf("dogu %q cannot have an auto upgrade policy and a version constraint", doguName))
+		}
+	}
+	return errors.Join(errs...)
+}
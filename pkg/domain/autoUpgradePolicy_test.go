@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoUpgradePolicy_VersionConstraint(t *testing.T) {
+	available := []core.Version{
+		mustParseVersion(t, "3.2.1-1"),
+		mustParseVersion(t, "3.2.4-1"),
+		mustParseVersion(t, "3.5.0-2"),
+		mustParseVersion(t, "4.0.0-1"),
+	}
+
+	tests := []struct {
+		policy   AutoUpgradePolicy
+		wantRaw  string
+		resolved string
+	}{
+		{policy: AutoUpgradePatch, wantRaw: ">=3.2.1-1 <3.3", resolved: "3.2.4-1"},
+		{policy: AutoUpgradeMinor, wantRaw: ">=3.2.1-1 <4", resolved: "3.5.0-2"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			constraint, err := tt.policy.VersionConstraint(version3211)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRaw, constraint.Raw)
+			resolved, err := constraint.Resolve(available, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.resolved, resolved.Raw)
+		})
+	}
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := AutoUpgradePolicy("major").VersionConstraint(version3211)
+
+		assert.ErrorContains(t, err, "unknown auto upgrade policy \"major\"")
+	})
+}
+
+func TestAutoUpgradePolicies_validate(t *testing.T) {
+	constraint, err := ParseVersionConstraint("~3.2")
+	require.NoError(t, err)
+	dogus := []Dogu{
+		{Name: officialDogu1, Version: &version3211},
+		{Name: officialDogu2, VersionConstraint: &constraint},
+		{Name: officialDogu3, Absent: true},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		err := AutoUpgradePolicies{officialDogu1.SimpleName: AutoUpgradePatch}.validate(dogus)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		err := AutoUpgradePolicies{
+			officialDogu1.SimpleName: "major",
+			officialDogu2.SimpleName: AutoUpgradeMinor,
+			officialDogu3.SimpleName: AutoUpgradePatch,
+			"unknown":                AutoUpgradePatch,
+		}.validate(dogus)
+
+		assert.ErrorContains(t, err, "auto upgrade policy \"major\" of dogu \"dogu1\" must be one of \"patch\", \"minor\"")
+		assert.ErrorContains(t, err, "dogu \"dogu2\" cannot have an auto upgrade policy and a version constraint")
+		assert.ErrorContains(t, err, "dogu \"dogu3\" with auto upgrade policy is not present in the blueprint")
+		assert.ErrorContains(t, err, "dogu \"unknown\" with auto upgrade policy is not present in the blueprint")
+	})
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+)
+
+// BlueprintLayer is one of multiple blueprint resources whose dogus and config get merged into one blueprint.
+// This way, e.g. a platform team can own the base dogus and config while product teams own their own dogus.
+type BlueprintLayer struct {
+	// Id identifies the blueprint resource of this layer.
+	Id string
+	// Priority decides which layer wins if layers define the same dogu or config entry.
+	// Layers with a higher priority override layers with a lower priority.
+	// Layers with the same priority must not define the same dogu or config entry differently.
+	Priority int
+	// Blueprint contains the dogus and config of this layer.
+	Blueprint Blueprint
+}
+
+// BlueprintProvenance contains the id of the blueprint layer which set a dogu or config entry of the merged blueprint.
+type BlueprintProvenance struct {
+	Dogus        map[cescommons.SimpleName]string
+	DoguConfig   map[common.DoguConfigKey]string
+	GlobalConfig map[common.GlobalConfigKey]string
+}
+
+// compareLayers sorts layers by their priority, starting with the lowest. Layers with the same priority are sorted by their id.
+func compareLayers(a, b BlueprintLayer) int {
+	return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.Id, b.Id))
+}
+
+// mergeBlueprintLayers validates the given layers and merges them into one blueprint, starting with the lowest priority.
+// returns the merged blueprint with the provenance of all its dogus and config entries or
+// an error if a layer is invalid or layers with the same priority define a dogu or config entry differently.
+func mergeBlueprintLayers(layers []BlueprintLayer) (Blueprint, BlueprintProvenance, error) {
+	sortedLayers := slices.SortedFunc(slices.Values(layers), compareLayers)
+
+	var errs []error
+	for _, layer := range sortedLayers {
+		err := layer.Blueprint.Validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("blueprint layer %q is invalid: %w", layer.Id, err))
+		}
+	}
+	if len(errs) > 0 {
+		return Blueprint{}, BlueprintProvenance{}, errors.Join(errs...)
+	}
+
+	dogus := newLayerMerge[cescommons.SimpleName, Dogu]()
+	doguConfig := newLayerMerge[common.DoguConfigKey, ConfigEntry]()
+	globalConfig := newLayerMerge[common.GlobalConfigKey, ConfigEntry]()
+	for _, layer := range sortedLayers {
+		for _, dogu := range layer.Blueprint.Dogus {
+			errs = append(errs, dogus.add(dogu.Name.SimpleName, dogu, layer, fmt.Sprintf("dogu %q", dogu.Name.SimpleName)))
+		}
+		for _, doguName := range slices.Sorted(maps.Keys(layer.Blueprint.Config.Dogus)) {
+			for _, entry := range layer.Blueprint.Config.Dogus[doguName] {
+				key := common.DoguConfigKey{DoguName: doguName, Key: entry.Key}
+				errs = append(errs, doguConfig.add(key, entry, layer, fmt.Sprintf("config %s", key)))
+			}
+		}
+		for _, entry := range layer.Blueprint.Config.Global {
+			errs = append(errs, globalConfig.add(entry.Key, entry, layer, fmt.Sprintf("global config key %q", entry.Key)))
+		}
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		return Blueprint{}, BlueprintProvenance{}, fmt.Errorf("blueprint layers are conflicting: %w", err)
+	}
+
+	merged := Blueprint{Dogus: dogus.values()}
+	for _, key := range doguConfig.keys {
+		if merged.Config.Dogus == nil {
+			merged.Config.Dogus = DoguConfig{}
+		}
+		merged.Config.Dogus[key.DoguName] = append(merged.Config.Dogus[key.DoguName], doguConfig.entries[key])
+	}
+	merged.Config.Global = globalConfig.values()
+
+	provenance := BlueprintProvenance{
+		Dogus:        dogus.provenance(),
+		DoguConfig:   doguConfig.provenance(),
+		GlobalConfig: globalConfig.provenance(),
+	}
+	return merged, provenance, nil
+}
+
+// layerMerge merges the values of multiple layers by their key and keeps the order in which the keys were added first.
+type layerMerge[K comparable, V any] struct {
+	keys    []K
+	entries map[K]V
+	layers  map[K]BlueprintLayer
+}
+
+func newLayerMerge[K comparable, V any]() *layerMerge[K, V] {
+	return &layerMerge[K, V]{
+		entries: map[K]V{},
+		layers:  map[K]BlueprintLayer{},
+	}
+}
+
+// add sets the value of the key to the value of the given layer.
+// Layers have to be added in order of their priority, starting with the lowest.
+func (merge *layerMerge[K, V]) add(key K, value V, layer BlueprintLayer, description string) error {
+	previousLayer, found := merge.layers[key]
+	if !found {
+		merge.keys = append(merge.keys, key)
+	} else if previousLayer.Priority == layer.Priority {
+		if !reflect.DeepEqual(merge.entries[key], value) {
+			return fmt.Errorf("%s is defined differently by blueprints %q and %q with the same priority %d",
+				description, previousLayer.Id, layer.Id, layer.Priority)
+		}
+		// the same value is set by multiple layers, the first one stays the origin
+		return nil
+	}
+	merge.entries[key] = value
+	merge.layers[key] = layer
+	return nil
+}
+
+func (merge *layerMerge[K, V]) values() []V {
+	var values []V
+	for _, key := range merge.keys {
+		values = append(values, merge.entries[key])
+	}
+	return values
+}
+
+func (merge *layerMerge[K, V]) provenance() map[K]string {
+	provenance := make(map[K]string, len(merge.layers))
+	for key, layer := range merge.layers {
+		provenance[key] = layer.Id
+	}
+	return provenance
+}
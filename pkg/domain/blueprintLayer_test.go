@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mergeBlueprintLayers(t *testing.T) {
+	value1 := libconfig.Value("value1")
+	value2 := libconfig.Value("value2")
+
+	t.Run("should override lower priorities", func(t *testing.T) {
+		// given
+		base := BlueprintLayer{
+			Id: "platform-base",
+			Blueprint: Blueprint{
+				Dogus: []Dogu{
+					{Name: officialDogu1, Version: &version3211},
+					{Name: officialDogu2, Version: &version3211},
+				},
+				Config: Config{
+					Dogus: DoguConfig{
+						"dogu1": {{Key: "key1", Value: &value1}, {Key: "key2", Value: &value1}},
+					},
+					Global: GlobalConfigEntries{{Key: "fqdn", Value: &value1}},
+				},
+			},
+		}
+		team := BlueprintLayer{
+			Id:       "team-a",
+			Priority: 10,
+			Blueprint: Blueprint{
+				Dogus: []Dogu{
+					{Name: officialDogu2, Version: &version3212},
+					{Name: officialDogu3, Version: &version3211},
+				},
+				Config: Config{
+					Dogus: DoguConfig{
+						"dogu1": {{Key: "key2", Value: &value2}},
+					},
+				},
+			},
+		}
+
+		// when
+		merged, provenance, err := mergeBlueprintLayers([]BlueprintLayer{team, base})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Blueprint{
+			Dogus: []Dogu{
+				{Name: officialDogu1, Version: &version3211},
+				{Name: officialDogu2, Version: &version3212},
+				{Name: officialDogu3, Version: &version3211},
+			},
+			Config: Config{
+				Dogus: DoguConfig{
+					"dogu1": {{Key: "key1", Value: &value1}, {Key: "key2", Value: &value2}},
+				},
+				Global: GlobalConfigEntries{{Key: "fqdn", Value: &value1}},
+			},
+		}, merged)
+		assert.Equal(t, BlueprintProvenance{
+			Dogus: map[cescommons.SimpleName]string{"dogu1": "platform-base", "dogu2": "team-a", "dogu3": "team-a"},
+			DoguConfig: map[common.DoguConfigKey]string{
+				{DoguName: "dogu1", Key: "key1"}: "platform-base",
+				{DoguName: "dogu1", Key: "key2"}: "team-a",
+			},
+			GlobalConfig: map[common.GlobalConfigKey]string{"fqdn": "platform-base"},
+		}, provenance)
+	})
+
+	t.Run("should allow equal definitions with the same priority", func(t *testing.T) {
+		// given
+		teamA := BlueprintLayer{Id: "team-a", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}}
+		teamB := BlueprintLayer{Id: "team-b", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}}
+
+		// when
+		merged, provenance, err := mergeBlueprintLayers([]BlueprintLayer{teamB, teamA})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Dogu{{Name: officialDogu1, Version: &version3211}}, merged.Dogus)
+		assert.Equal(t, "team-a", provenance.Dogus["dogu1"])
+	})
+
+	t.Run("should detect conflicts with the same priority", func(t *testing.T) {
+		// given
+		teamA := BlueprintLayer{
+			Id: "team-a",
+			Blueprint: Blueprint{
+				Dogus:  []Dogu{{Name: officialDogu1, Version: &version3211}},
+				Config: Config{Global: GlobalConfigEntries{{Key: "fqdn", Value: &value1}}},
+			},
+		}
+		teamB := BlueprintLayer{
+			Id: "team-b",
+			Blueprint: Blueprint{
+				Dogus: []Dogu{{Name: officialDogu1, Version: &version3212}},
+				Config: Config{
+					Dogus:  DoguConfig{"dogu1": {{Key: "key1", Value: &value1}}},
+					Global: GlobalConfigEntries{{Key: "fqdn", Value: &value2}},
+				},
+			},
+		}
+		teamC := BlueprintLayer{
+			Id:        "team-c",
+			Blueprint: Blueprint{Config: Config{Dogus: DoguConfig{"dogu1": {{Key: "key1", Absent: true}}}}},
+		}
+
+		// when
+		_, _, err := mergeBlueprintLayers([]BlueprintLayer{teamA, teamB, teamC})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "blueprint layers are conflicting")
+		assert.ErrorContains(t, err, "dogu \"dogu1\" is defined differently by blueprints \"team-a\" and \"team-b\" with the same priority 0")
+		assert.ErrorContains(t, err, "global config key \"fqdn\" is defined differently by blueprints \"team-a\" and \"team-b\" with the same priority 0")
+		assert.ErrorContains(t, err, "config key \"key1\" of dogu \"dogu1\" is defined differently by blueprints \"team-b\" and \"team-c\" with the same priority 0")
+	})
+
+	t.Run("should fail on invalid layer", func(t *testing.T) {
+		// given
+		layer := BlueprintLayer{Id: "team-a", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1}}}}
+
+		// when
+		_, _, err := mergeBlueprintLayers([]BlueprintLayer{layer})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "blueprint layer \"team-a\" is invalid")
+	})
+}
+
+func TestBlueprintSpec_BaseBlueprintId(t *testing.T) {
+	t.Run("should be own id without layers", func(t *testing.T) {
+		spec := BlueprintSpec{Id: "team-a"}
+
+		assert.Equal(t, "team-a", spec.BaseBlueprintId())
+	})
+	t.Run("should be layer with lowest priority", func(t *testing.T) {
+		spec := BlueprintSpec{Id: "team-a", Layers: []BlueprintLayer{
+			{Id: "team-a", Priority: 10},
+			{Id: "platform-base", Priority: 0},
+			{Id: "a-base", Priority: 0},
+		}}
+
+		assert.Equal(t, "a-base", spec.BaseBlueprintId())
+	})
+}
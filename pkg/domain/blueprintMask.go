@@ -11,9 +11,14 @@ import (
 // applying it to a CES system via a blueprint upgrade. The blueprint mask does not change the blueprint
 // itself, but is applied to the information in it to generate a new, effective blueprint.
 type BlueprintMask struct {
+	// Name identifies the mask in messages if there are multiple masks. It is empty for the mask of the blueprint itself.
+	Name string
 	// Dogus contains a set of dogus which alters the states of the dogus in the blueprint this mask is applied on.
 	// The names and target states of all dogus must not be empty.
 	Dogus []MaskDogu
+	// Config contains dogu and global config entries which override the config entries of the blueprint with the same key.
+	// Absent entries remove the config entries of the blueprint.
+	Config Config
 }
 
 // Validate checks the structure and data of a blueprint mask and returns an error if there are any problems
@@ -21,6 +26,7 @@ func (blueprintMask *BlueprintMask) Validate() error {
 	errorList := []error{
 		blueprintMask.validateDogus(),
 		blueprintMask.validateDoguUniqueness(),
+		blueprintMask.Config.validate(),
 	}
 	err := errors.Join(errorList...)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,3 +45,21 @@ func Test_ValidateWithDuplicatedDoguNames(t *testing.T) {
 
 	require.NotNil(t, err, "Multiple definitions for the same dogu should lead to an error")
 }
+
+func Test_ValidateWithInvalidConfig(t *testing.T) {
+	blueprintMask := BlueprintMask{
+		Config: Config{
+			Dogus: DoguConfig{
+				"dogu1": {{Key: "key1", Absent: true, Value: &val1}},
+			},
+			Global: GlobalConfigEntries{{Key: ""}},
+		},
+	}
+
+	err := blueprintMask.Validate()
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "blueprint mask is invalid")
+	assert.ErrorContains(t, err, "config for dogu \"dogu1\" is invalid: absent entries cannot have value or secretRef")
+	assert.ErrorContains(t, err, "key for global config should not be empty")
+}
@@ -0,0 +1,314 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxBlueprintRevisions is the number of revisions which are kept per blueprint. Older revisions get deleted,
+// so the config can only be rolled back to the last MaxBlueprintRevisions revisions.
+const MaxBlueprintRevisions = 10
+
+// BlueprintRevision records an apply of a blueprint which changed the config or the dogus of the ecosystem.
+// It contains the values of all changed config entries before the apply, so that the config can be rolled back to
+// any earlier revision.
+type BlueprintRevision struct {
+	BlueprintId string
+	// Number counts the revisions of a blueprint, starting with 1.
+	Number int
+	// RolledBackTo is the number of the revision whose config got restored, if this revision is a rollback.
+	RolledBackTo *int
+	// EffectiveBlueprint documents the blueprint which got applied.
+	EffectiveBlueprint EffectiveBlueprint
+	// StateDiff documents the changes which got applied. It does not contain sensitive values.
+	StateDiff StateDiff
+	// PreviousConfig contains the states of all config entries changed by this revision before they were changed.
+	PreviousConfig RevisionConfig
+}
+
+// RevisionConfig contains the states of config entries. Entries which did not exist have a state without a value.
+type RevisionConfig struct {
+	DoguConfig          map[common.DoguConfigKey]ConfigValueState
+	SensitiveDoguConfig map[common.DoguConfigKey]ConfigValueState
+	GlobalConfig        map[common.GlobalConfigKey]ConfigValueState
+}
+
+// NewBlueprintRevision creates the revision with the given number for the changes in the state diff.
+// The actual states of all changed config entries become the previous config of the revision.
+func NewBlueprintRevision(blueprintId string, number int, effectiveBlueprint EffectiveBlueprint, diff StateDiff) BlueprintRevision {
+	previousConfig := newRevisionConfig()
+	for _, doguConfigDiffs := range diff.DoguConfigDiffs {
+		for _, configDiff := range doguConfigDiffs {
+			if configDiff.NeededAction != ConfigActionNone {
+				previousConfig.DoguConfig[configDiff.Key] = ConfigValueState(configDiff.Actual)
+			}
+		}
+	}
+	for _, doguConfigDiffs := range diff.SensitiveDoguConfigDiffs {
+		for _, configDiff := range doguConfigDiffs {
+			if configDiff.NeededAction != ConfigActionNone {
+				previousConfig.SensitiveDoguConfig[configDiff.Key] = ConfigValueState(configDiff.Actual)
+			}
+		}
+	}
+	for _, configDiff := range diff.GlobalConfigDiffs {
+		if configDiff.NeededAction != ConfigActionNone {
+			previousConfig.GlobalConfig[configDiff.Key] = ConfigValueState(configDiff.Actual)
+		}
+	}
+
+	return BlueprintRevision{
+		BlueprintId:        blueprintId,
+		Number:             number,
+		EffectiveBlueprint: effectiveBlueprint,
+		StateDiff:          diff,
+		PreviousConfig:     previousConfig,
+	}
+}
+
+func newRevisionConfig() RevisionConfig {
+	return RevisionConfig{
+		DoguConfig:          map[common.DoguConfigKey]ConfigValueState{},
+		SensitiveDoguConfig: map[common.DoguConfigKey]ConfigValueState{},
+		GlobalConfig:        map[common.GlobalConfigKey]ConfigValueState{},
+	}
+}
+
+// Len returns the number of config entries in the revision config.
+func (revisionConfig RevisionConfig) Len() int {
+	return len(revisionConfig.DoguConfig) + len(revisionConfig.SensitiveDoguConfig) + len(revisionConfig.GlobalConfig)
+}
+
+// GetDogus returns the names of all dogus with normal or sensitive config entries in the revision config.
+func (revisionConfig RevisionConfig) GetDogus() []cescommons.SimpleName {
+	var dogus []cescommons.SimpleName
+	for key := range revisionConfig.DoguConfig {
+		dogus = append(dogus, key.DoguName)
+	}
+	for key := range revisionConfig.SensitiveDoguConfig {
+		dogus = append(dogus, key.DoguName)
+	}
+	slices.Sort(dogus)
+	return slices.Compact(dogus)
+}
+
+// NewConfigSnapshot returns the actual states of all config entries which get changed by the state diff,
+// so that they can be restored if the config apply fails halfway.
+func NewConfigSnapshot(
+	diff StateDiff,
+	actualDoguConfig map[cescommons.SimpleName]config.DoguConfig,
+	actualSensitiveDoguConfig map[cescommons.SimpleName]config.DoguConfig,
+	actualGlobalConfig config.GlobalConfig,
+) RevisionConfig {
+	snapshot := newRevisionConfig()
+	for _, doguConfigDiffs := range diff.DoguConfigDiffs {
+		for _, configDiff := range doguConfigDiffs {
+			if configDiff.NeededAction != ConfigActionNone {
+				snapshot.DoguConfig[configDiff.Key] = getDoguConfigValueState(actualDoguConfig, configDiff.Key)
+			}
+		}
+	}
+	for _, doguConfigDiffs := range diff.SensitiveDoguConfigDiffs {
+		for _, configDiff := range doguConfigDiffs {
+			if configDiff.NeededAction != ConfigActionNone {
+				snapshot.SensitiveDoguConfig[configDiff.Key] = getDoguConfigValueState(actualSensitiveDoguConfig, configDiff.Key)
+			}
+		}
+	}
+	for _, configDiff := range diff.GlobalConfigDiffs {
+		if configDiff.NeededAction != ConfigActionNone {
+			state := ConfigValueState{}
+			if value, exists := actualGlobalConfig.Get(configDiff.Key); exists {
+				state = ConfigValueState{Value: (*string)(&value), Exists: true}
+			}
+			snapshot.GlobalConfig[configDiff.Key] = state
+		}
+	}
+	return snapshot
+}
+
+func getDoguConfigValueState(actualConfig map[cescommons.SimpleName]config.DoguConfig, key common.DoguConfigKey) ConfigValueState {
+	value, exists := actualConfig[key.DoguName].Get(key.Key)
+	if !exists {
+		return ConfigValueState{}
+	}
+	return ConfigValueState{Value: (*string)(&value), Exists: true}
+}
+
+// GetLatestRevision returns the revision with the highest number or false if there are no revisions.
+func GetLatestRevision(revisions []BlueprintRevision) (BlueprintRevision, bool) {
+	if len(revisions) == 0 {
+		return BlueprintRevision{}, false
+	}
+	return slices.MaxFunc(revisions, compareRevisions), true
+}
+
+func getOldestRevision(revisions []BlueprintRevision) (BlueprintRevision, bool) {
+	if len(revisions) == 0 {
+		return BlueprintRevision{}, false
+	}
+	return slices.MinFunc(revisions, compareRevisions), true
+}
+
+// GetExpiredRevisions returns all revisions except the newest MaxBlueprintRevisions ones,
+// so that the stored revisions do not grow without bound.
+func GetExpiredRevisions(revisions []BlueprintRevision) []BlueprintRevision {
+	if len(revisions) <= MaxBlueprintRevisions {
+		return nil
+	}
+	sortedRevisions := slices.SortedFunc(slices.Values(revisions), compareRevisions)
+	return sortedRevisions[:len(sortedRevisions)-MaxBlueprintRevisions]
+}
+
+func compareRevisions(a, b BlueprintRevision) int {
+	return cmp.Compare(a.Number, b.Number)
+}
+
+// GetConfigToRestore returns the states the config entries must have to restore the config of the target revision.
+// These are the previous states of all config entries changed by later revisions. If multiple later revisions changed
+// the same entry, the previous state of the earliest one counts.
+// The target revision 0 restores the config before the first revision.
+// Returns an error if the target revision does not exist.
+func GetConfigToRestore(revisions []BlueprintRevision, targetRevision int) (RevisionConfig, error) {
+	latestNumber := 0
+	if latest, found := GetLatestRevision(revisions); found {
+		latestNumber = latest.Number
+	}
+	if targetRevision < 0 || targetRevision > latestNumber {
+		return RevisionConfig{}, fmt.Errorf("cannot restore revision %d as the latest revision is %d", targetRevision, latestNumber)
+	}
+	// restoring a revision needs the previous config of all later revisions
+	if oldest, found := getOldestRevision(revisions); found && targetRevision < oldest.Number-1 {
+		return RevisionConfig{}, fmt.Errorf("cannot restore revision %d as the revisions before %d were deleted", targetRevision, oldest.Number)
+	}
+
+	configToRestore := newRevisionConfig()
+	// start with the latest revision, so that earlier revisions override the states of later ones
+	for _, revision := range slices.Backward(slices.SortedFunc(slices.Values(revisions), compareRevisions)) {
+		if revision.Number <= targetRevision {
+			break
+		}
+		maps.Copy(configToRestore.DoguConfig, revision.PreviousConfig.DoguConfig)
+		maps.Copy(configToRestore.SensitiveDoguConfig, revision.PreviousConfig.SensitiveDoguConfig)
+		maps.Copy(configToRestore.GlobalConfig, revision.PreviousConfig.GlobalConfig)
+	}
+	return configToRestore, nil
+}
+
+// NewRestoreStateDiff creates the config diffs which restore the given config.
+// The actual config has to contain the config of all dogus in the config to restore.
+func NewRestoreStateDiff(
+	configToRestore RevisionConfig,
+	actualDoguConfig map[cescommons.SimpleName]config.DoguConfig,
+	actualSensitiveDoguConfig map[cescommons.SimpleName]config.DoguConfig,
+	actualGlobalConfig config.GlobalConfig,
+) StateDiff {
+	diff := StateDiff{
+		DoguConfigDiffs:          determineRestoreDoguConfigDiffs(configToRestore.DoguConfig, actualDoguConfig),
+		SensitiveDoguConfigDiffs: determineRestoreDoguConfigDiffs(configToRestore.SensitiveDoguConfig, actualSensitiveDoguConfig),
+	}
+	for _, key := range slices.Sorted(maps.Keys(configToRestore.GlobalConfig)) {
+		expected := configToRestore.GlobalConfig[key]
+		var actualValue *common.GlobalConfigValue
+		actualEntry, actualExists := actualGlobalConfig.Get(key)
+		if actualExists {
+			actualValue = &actualEntry
+		}
+		configDiff := newGlobalConfigEntryDiff(key, actualValue, actualExists, (*common.GlobalConfigValue)(expected.Value), expected.Exists)
+		if configDiff.NeededAction != ConfigActionNone {
+			diff.GlobalConfigDiffs = append(diff.GlobalConfigDiffs, configDiff)
+		}
+	}
+	return diff
+}
+
+func determineRestoreDoguConfigDiffs(
+	configToRestore map[common.DoguConfigKey]ConfigValueState,
+	actualConfig map[cescommons.SimpleName]config.DoguConfig,
+) map[cescommons.SimpleName]DoguConfigDiffs {
+	diffs := map[cescommons.SimpleName]DoguConfigDiffs{}
+	keys := slices.SortedFunc(maps.Keys(configToRestore), func(a, b common.DoguConfigKey) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	for _, key := range keys {
+		expected := configToRestore[key]
+		var actualValue *config.Value
+		actualEntry, actualExists := actualConfig[key.DoguName].Get(key.Key)
+		if actualExists {
+			actualValue = &actualEntry
+		}
+		configDiff := newDoguConfigEntryDiff(key, actualValue, actualExists, (*common.DoguConfigValue)(expected.Value), expected.Exists)
+		if configDiff.NeededAction != ConfigActionNone {
+			diffs[key.DoguName] = append(diffs[key.DoguName], configDiff)
+		}
+	}
+	return diffs
+}
+
+// CreateRevision records the applied state diff as a new revision with the given number.
+// The previous config is the snapshot of the changed config entries taken right before they got applied.
+func (spec *BlueprintSpec) CreateRevision(number int, previousConfig RevisionConfig) BlueprintRevision {
+	revision := NewBlueprintRevision(spec.Id, number, spec.EffectiveBlueprint, spec.StateDiff)
+	if previousConfig.Len() > 0 {
+		revision.PreviousConfig = previousConfig
+	}
+	spec.Events = append(spec.Events, BlueprintRevisionCreatedEvent{Revision: number, ChangedEntries: revision.PreviousConfig.Len()})
+	return revision
+}
+
+// RollbackConfig records the restore of the config of the target revision as a new revision with the given number.
+// The actual states of the restored config entries become the previous config of the new revision,
+// so that the rollback itself can be rolled back.
+func (spec *BlueprintSpec) RollbackConfig(number int, targetRevision int, restoreDiff StateDiff) BlueprintRevision {
+	revision := NewBlueprintRevision(spec.Id, number, spec.EffectiveBlueprint, restoreDiff)
+	revision.RolledBackTo = &targetRevision
+	spec.Events = append(spec.Events, ConfigRolledBackEvent{
+		Revision:       number,
+		TargetRevision: targetRevision,
+		ChangedEntries: revision.PreviousConfig.Len(),
+	})
+	return revision
+}
+
+// IsConfigRollbackExecuted checks if the requested rollback in BlueprintConfiguration.RollbackToRevision was already executed.
+// The request stays set until it is removed from the blueprint, so it must not be executed again, e.g. after a later apply.
+func (spec *BlueprintSpec) IsConfigRollbackExecuted() bool {
+	target := spec.Config.RollbackToRevision
+	condition := meta.FindStatusCondition(spec.Conditions, ConditionConfigRolledBack)
+	return target != nil && condition != nil && condition.Message == configRolledBackMessage(*target)
+}
+
+// MarkConfigRolledBack records that the requested rollback in BlueprintConfiguration.RollbackToRevision was executed.
+func (spec *BlueprintSpec) MarkConfigRolledBack() {
+	if spec.Config.RollbackToRevision == nil {
+		return
+	}
+	meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionConfigRolledBack,
+		Status:  metav1.ConditionTrue,
+		Reason:  "RolledBack",
+		Message: configRolledBackMessage(*spec.Config.RollbackToRevision),
+	})
+}
+
+// ResetConfigRollback removes the record of the executed rollback if the request was removed from the blueprint,
+// so that the same revision can be requested again later.
+// returns true if the record was removed.
+func (spec *BlueprintSpec) ResetConfigRollback() bool {
+	if spec.Config.RollbackToRevision != nil {
+		return false
+	}
+	return meta.RemoveStatusCondition(&spec.Conditions, ConditionConfigRolledBack)
+}
+
+func configRolledBackMessage(targetRevision int) string {
+	return fmt.Sprintf("restored the config of revision %d, remove the rollback request to apply the blueprint again", targetRevision)
+}
@@ -0,0 +1,304 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+)
+
+var (
+	revisionValue1 = "value1"
+	revisionValue2 = "value2"
+	revisionKey1   = common.DoguConfigKey{DoguName: "redmine", Key: "key1"}
+	revisionKey2   = common.DoguConfigKey{DoguName: "cas", Key: "key2"}
+)
+
+func TestNewBlueprintRevision(t *testing.T) {
+	diff := StateDiff{
+		DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+			"redmine": {
+				{Key: revisionKey1, Actual: DoguConfigValueState{Value: &revisionValue1, Exists: true}, NeededAction: ConfigActionSet},
+				{Key: common.DoguConfigKey{DoguName: "redmine", Key: "unchanged"}, NeededAction: ConfigActionNone},
+			},
+		},
+		SensitiveDoguConfigDiffs: map[cescommons.SimpleName]SensitiveDoguConfigDiffs{
+			"cas": {
+				{Key: revisionKey2, Actual: DoguConfigValueState{}, NeededAction: ConfigActionSet},
+			},
+		},
+		GlobalConfigDiffs: GlobalConfigDiffs{
+			{Key: "fqdn", Actual: GlobalConfigValueState{Value: &revisionValue2, Exists: true}, NeededAction: ConfigActionRemove},
+		},
+	}
+	effectiveBlueprint := EffectiveBlueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}
+
+	revision := NewBlueprintRevision("my-blueprint", 3, effectiveBlueprint, diff)
+
+	assert.Equal(t, BlueprintRevision{
+		BlueprintId:        "my-blueprint",
+		Number:             3,
+		EffectiveBlueprint: effectiveBlueprint,
+		StateDiff:          diff,
+		PreviousConfig: RevisionConfig{
+			DoguConfig:          map[common.DoguConfigKey]ConfigValueState{revisionKey1: {Value: &revisionValue1, Exists: true}},
+			SensitiveDoguConfig: map[common.DoguConfigKey]ConfigValueState{revisionKey2: {}},
+			GlobalConfig:        map[common.GlobalConfigKey]ConfigValueState{"fqdn": {Value: &revisionValue2, Exists: true}},
+		},
+	}, revision)
+	assert.Equal(t, 3, revision.PreviousConfig.Len())
+	assert.Equal(t, []cescommons.SimpleName{"cas", "redmine"}, revision.PreviousConfig.GetDogus())
+}
+
+func TestGetLatestRevision(t *testing.T) {
+	t.Run("no revisions", func(t *testing.T) {
+		_, found := GetLatestRevision(nil)
+
+		assert.False(t, found)
+	})
+	t.Run("highest number", func(t *testing.T) {
+		latest, found := GetLatestRevision([]BlueprintRevision{{Number: 1}, {Number: 3}, {Number: 2}})
+
+		assert.True(t, found)
+		assert.Equal(t, 3, latest.Number)
+	})
+}
+
+func TestGetConfigToRestore(t *testing.T) {
+	revisions := []BlueprintRevision{
+		{Number: 2, PreviousConfig: RevisionConfig{
+			DoguConfig:   map[common.DoguConfigKey]ConfigValueState{revisionKey1: {Value: &revisionValue2, Exists: true}},
+			GlobalConfig: map[common.GlobalConfigKey]ConfigValueState{"fqdn": {Value: &revisionValue2, Exists: true}},
+		}},
+		{Number: 1, PreviousConfig: RevisionConfig{
+			DoguConfig: map[common.DoguConfigKey]ConfigValueState{revisionKey1: {}},
+		}},
+		{Number: 3, PreviousConfig: RevisionConfig{
+			SensitiveDoguConfig: map[common.DoguConfigKey]ConfigValueState{revisionKey2: {Value: &revisionValue1, Exists: true}},
+		}},
+	}
+
+	t.Run("restore config before the first revision", func(t *testing.T) {
+		result, err := GetConfigToRestore(revisions, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, RevisionConfig{
+			DoguConfig:          map[common.DoguConfigKey]ConfigValueState{revisionKey1: {}},
+			SensitiveDoguConfig: map[common.DoguConfigKey]ConfigValueState{revisionKey2: {Value: &revisionValue1, Exists: true}},
+			GlobalConfig:        map[common.GlobalConfigKey]ConfigValueState{"fqdn": {Value: &revisionValue2, Exists: true}},
+		}, result)
+	})
+	t.Run("restore config of intermediate revision", func(t *testing.T) {
+		result, err := GetConfigToRestore(revisions, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, RevisionConfig{
+			DoguConfig:          map[common.DoguConfigKey]ConfigValueState{},
+			SensitiveDoguConfig: map[common.DoguConfigKey]ConfigValueState{revisionKey2: {Value: &revisionValue1, Exists: true}},
+			GlobalConfig:        map[common.GlobalConfigKey]ConfigValueState{},
+		}, result)
+	})
+	t.Run("restore latest revision", func(t *testing.T) {
+		result, err := GetConfigToRestore(revisions, 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Len())
+	})
+	t.Run("unknown revision", func(t *testing.T) {
+		_, err := GetConfigToRestore(revisions, 4)
+
+		assert.ErrorContains(t, err, "cannot restore revision 4 as the latest revision is 3")
+	})
+	t.Run("deleted revision", func(t *testing.T) {
+		_, err := GetConfigToRestore(revisions[:1], 0)
+
+		assert.ErrorContains(t, err, "cannot restore revision 0 as the revisions before 2 were deleted")
+	})
+	t.Run("restore revision before the oldest kept revision", func(t *testing.T) {
+		result, err := GetConfigToRestore(revisions[:1], 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, revisions[0].PreviousConfig.DoguConfig, result.DoguConfig)
+	})
+	t.Run("no revisions", func(t *testing.T) {
+		_, err := GetConfigToRestore(nil, 1)
+
+		assert.ErrorContains(t, err, "cannot restore revision 1 as the latest revision is 0")
+	})
+}
+
+func TestGetExpiredRevisions(t *testing.T) {
+	t.Run("keep all revisions up to the limit", func(t *testing.T) {
+		var revisions []BlueprintRevision
+		for number := MaxBlueprintRevisions; number > 0; number-- {
+			revisions = append(revisions, BlueprintRevision{Number: number})
+		}
+
+		assert.Empty(t, GetExpiredRevisions(revisions))
+	})
+	t.Run("expire oldest revisions", func(t *testing.T) {
+		var revisions []BlueprintRevision
+		for number := MaxBlueprintRevisions + 2; number > 0; number-- {
+			revisions = append(revisions, BlueprintRevision{Number: number})
+		}
+
+		assert.Equal(t, []BlueprintRevision{{Number: 1}, {Number: 2}}, GetExpiredRevisions(revisions))
+	})
+}
+
+func TestNewRestoreStateDiff(t *testing.T) {
+	configToRestore := RevisionConfig{
+		DoguConfig: map[common.DoguConfigKey]ConfigValueState{
+			revisionKey1:                            {Value: &revisionValue1, Exists: true},
+			{DoguName: "redmine", Key: "unchanged"}: {Value: &revisionValue1, Exists: true},
+		},
+		SensitiveDoguConfig: map[common.DoguConfigKey]ConfigValueState{
+			revisionKey2: {},
+		},
+		GlobalConfig: map[common.GlobalConfigKey]ConfigValueState{
+			"fqdn": {Value: &revisionValue1, Exists: true},
+		},
+	}
+	actualDoguConfig := map[cescommons.SimpleName]config.DoguConfig{
+		"redmine": config.CreateDoguConfig("redmine", map[config.Key]config.Value{"key1": "value2", "unchanged": "value1"}),
+	}
+	actualSensitiveDoguConfig := map[cescommons.SimpleName]config.DoguConfig{
+		"cas": config.CreateDoguConfig("cas", map[config.Key]config.Value{"key2": "value2"}),
+	}
+	entries, _ := config.MapToEntries(map[string]any{})
+
+	diff := NewRestoreStateDiff(configToRestore, actualDoguConfig, actualSensitiveDoguConfig, config.CreateGlobalConfig(entries))
+
+	assert.Equal(t, StateDiff{
+		DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+			"redmine": {{
+				Key:          revisionKey1,
+				Actual:       DoguConfigValueState{Value: &revisionValue2, Exists: true},
+				Expected:     DoguConfigValueState{Value: &revisionValue1, Exists: true},
+				NeededAction: ConfigActionSet,
+			}},
+		},
+		SensitiveDoguConfigDiffs: map[cescommons.SimpleName]SensitiveDoguConfigDiffs{
+			"cas": {{
+				Key:          revisionKey2,
+				Actual:       DoguConfigValueState{Value: &revisionValue2, Exists: true},
+				NeededAction: ConfigActionRemove,
+			}},
+		},
+		GlobalConfigDiffs: GlobalConfigDiffs{{
+			Key:          "fqdn",
+			Expected:     GlobalConfigValueState{Value: &revisionValue1, Exists: true},
+			NeededAction: ConfigActionSet,
+		}},
+	}, diff)
+}
+
+func TestNewConfigSnapshot(t *testing.T) {
+	diff := StateDiff{
+		DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+			"redmine": {
+				{Key: revisionKey1, NeededAction: ConfigActionSet},
+				{Key: common.DoguConfigKey{DoguName: "redmine", Key: "unchanged"}, NeededAction: ConfigActionNone},
+			},
+		},
+		SensitiveDoguConfigDiffs: map[cescommons.SimpleName]SensitiveDoguConfigDiffs{
+			"cas": {
+				{Key: revisionKey2, NeededAction: ConfigActionSet},
+			},
+		},
+		GlobalConfigDiffs: GlobalConfigDiffs{
+			{Key: "fqdn", NeededAction: ConfigActionRemove},
+		},
+	}
+	actualDoguConfig := map[cescommons.SimpleName]config.DoguConfig{
+		"redmine": config.CreateDoguConfig("redmine", map[config.Key]config.Value{"key1": "value1", "unchanged": "value1"}),
+	}
+	entries, _ := config.MapToEntries(map[string]any{"fqdn": "value2"})
+
+	snapshot := NewConfigSnapshot(diff, actualDoguConfig, map[cescommons.SimpleName]config.DoguConfig{}, config.CreateGlobalConfig(entries))
+
+	assert.Equal(t, RevisionConfig{
+		DoguConfig:          map[common.DoguConfigKey]ConfigValueState{revisionKey1: {Value: &revisionValue1, Exists: true}},
+		SensitiveDoguConfig: map[common.DoguConfigKey]ConfigValueState{revisionKey2: {}},
+		GlobalConfig:        map[common.GlobalConfigKey]ConfigValueState{"fqdn": {Value: &revisionValue2, Exists: true}},
+	}, snapshot)
+}
+
+func TestBlueprintSpec_CreateRevision(t *testing.T) {
+	spec := &BlueprintSpec{
+		Id: "my-blueprint",
+		StateDiff: StateDiff{GlobalConfigDiffs: GlobalConfigDiffs{
+			{Key: "fqdn", NeededAction: ConfigActionSet},
+		}},
+	}
+
+	previousConfig := RevisionConfig{
+		DoguConfig:   map[common.DoguConfigKey]ConfigValueState{revisionKey1: {Value: &revisionValue1, Exists: true}},
+		GlobalConfig: map[common.GlobalConfigKey]ConfigValueState{"fqdn": {Value: &revisionValue2, Exists: true}},
+	}
+
+	revision := spec.CreateRevision(1, previousConfig)
+
+	assert.Equal(t, "my-blueprint", revision.BlueprintId)
+	assert.Equal(t, 1, revision.Number)
+	assert.Nil(t, revision.RolledBackTo)
+	assert.Equal(t, previousConfig, revision.PreviousConfig)
+	assert.Equal(t, []Event{BlueprintRevisionCreatedEvent{Revision: 1, ChangedEntries: 2}}, spec.Events)
+}
+
+func TestBlueprintSpec_CreateRevision_withoutConfigChanges(t *testing.T) {
+	spec := &BlueprintSpec{
+		Id:        "my-blueprint",
+		StateDiff: StateDiff{DoguDiffs: DoguDiffs{{DoguName: "redmine", NeededActions: []Action{ActionUpgrade}}}},
+	}
+
+	revision := spec.CreateRevision(2, RevisionConfig{})
+
+	assert.Equal(t, spec.StateDiff, revision.StateDiff)
+	assert.Equal(t, 0, revision.PreviousConfig.Len())
+	assert.NotNil(t, revision.PreviousConfig.GlobalConfig)
+	assert.Equal(t, []Event{BlueprintRevisionCreatedEvent{Revision: 2, ChangedEntries: 0}}, spec.Events)
+}
+
+func TestBlueprintSpec_RollbackConfig(t *testing.T) {
+	spec := &BlueprintSpec{Id: "my-blueprint"}
+	restoreDiff := StateDiff{GlobalConfigDiffs: GlobalConfigDiffs{
+		{Key: "fqdn", Actual: GlobalConfigValueState{Value: &revisionValue2, Exists: true}, NeededAction: ConfigActionRemove},
+	}}
+
+	revision := spec.RollbackConfig(4, 1, restoreDiff)
+
+	assert.Equal(t, 4, revision.Number)
+	require.NotNil(t, revision.RolledBackTo)
+	assert.Equal(t, 1, *revision.RolledBackTo)
+	assert.Equal(t, map[common.GlobalConfigKey]ConfigValueState{"fqdn": {Value: &revisionValue2, Exists: true}}, revision.PreviousConfig.GlobalConfig)
+	assert.Equal(t, []Event{ConfigRolledBackEvent{Revision: 4, TargetRevision: 1, ChangedEntries: 1}}, spec.Events)
+}
+
+func TestBlueprintSpec_MarkConfigRolledBack(t *testing.T) {
+	spec := &BlueprintSpec{Config: BlueprintConfiguration{Stopped: true, RollbackToRevision: ptr.To(1)}}
+	assert.False(t, spec.IsConfigRollbackExecuted())
+
+	spec.MarkConfigRolledBack()
+
+	assert.True(t, spec.IsConfigRollbackExecuted())
+	assert.True(t, meta.IsStatusConditionTrue(spec.Conditions, ConditionConfigRolledBack))
+
+	// the record stays while the request is set, even if it changes, so that it cannot be executed twice
+	assert.False(t, spec.ResetConfigRollback())
+	spec.Config.RollbackToRevision = ptr.To(2)
+	assert.False(t, spec.IsConfigRollbackExecuted(), "another revision was requested")
+	assert.False(t, spec.ResetConfigRollback())
+
+	// the same revision can be requested again after the request was removed
+	spec.Config.RollbackToRevision = nil
+	assert.True(t, spec.ResetConfigRollback())
+	assert.Nil(t, meta.FindStatusCondition(spec.Conditions, ConditionConfigRolledBack))
+	spec.Config.RollbackToRevision = ptr.To(1)
+	assert.False(t, spec.IsConfigRollbackExecuted())
+}
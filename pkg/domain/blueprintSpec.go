@@ -1,10 +1,13 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"maps"
 	"slices"
+	"strings"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/cesapp-lib/core"
@@ -17,14 +20,28 @@ import (
 )
 
 type BlueprintSpec struct {
-	Id                 string
-	DisplayName        string
-	Blueprint          Blueprint
-	BlueprintMask      BlueprintMask
+	Id          string
+	DisplayName string
+	Blueprint   Blueprint
+	// Layers contains all blueprint resources, including this one, whose dogus and config get merged into the Blueprint.
+	// The Blueprint is used as it is if there are no layers.
+	Layers []BlueprintLayer
+	// Provenance contains the layer which set each dogu and config entry of the merged Blueprint.
+	Provenance    BlueprintProvenance
+	BlueprintMask BlueprintMask
+	// AdditionalMasks get applied in their order after the BlueprintMask. Later masks take precedence over earlier masks.
+	AdditionalMasks    []BlueprintMask
 	EffectiveBlueprint EffectiveBlueprint
 	StateDiff          StateDiff
-	Config             BlueprintConfiguration
-	Conditions         []Condition
+	// ConfigOwnership contains the config keys set by the last apply of this blueprint.
+	// It gets loaded before the state diff is determined and saved after the config was applied.
+	// Determining the state diff does not change it, see GetAppliedConfigOwnership.
+	ConfigOwnership ConfigOwnership
+	// vanishedOwnedConfig contains the owned config keys which are neither in the blueprint nor in the ecosystem anymore.
+	// They get disowned with the next apply.
+	vanishedOwnedConfig ConfigOwnership
+	Config              BlueprintConfiguration
+	Conditions          []Condition
 	// PersistenceContext can hold generic values needed for persistence with repositories, e.g. version counters or transaction contexts.
 	// This field has a generic map type as the values within it highly depend on the used type of repository.
 	// This field should be ignored in the whole domain.
@@ -40,9 +57,33 @@ const (
 	ConditionEcosystemHealthy   = bpv3.ConditionEcosystemHealthy
 	ConditionCompleted          = bpv3.ConditionCompleted
 	ConditionLastApplySucceeded = bpv3.ConditionLastApplySucceeded
+	// ConditionRolloutWave is only set if the blueprint defines rollout waves.
+	ConditionRolloutWave = "RolloutWave"
+	// ConditionPlanApproved is only set if the blueprint requires a plan approval.
+	ConditionPlanApproved = "PlanApproved"
+	// ConditionDependenciesAutoAdded is only set if the blueprint completes missing dependencies.
+	ConditionDependenciesAutoAdded = "DependenciesAutoAdded"
+	// ConditionDogusPruned is only set if the blueprint prunes dogus.
+	// It lists the dogus of the state diff, which get uninstalled as they are pruned, together with the reason.
+	ConditionDogusPruned = "DogusPruned"
+	// ConditionRegistryReachable is only set if the remote dogu registry was unreachable once.
+	// It shows whether any endpoint of the remote dogu registry was reachable in the last reconciliation.
+	ConditionRegistryReachable = "RegistryReachable"
+	// ConditionLayersMerged is only set if the blueprint is merged from multiple blueprint layers.
+	// It shows the merged layers and which layer set the dogus and config entries of the merged blueprint.
+	ConditionLayersMerged = "LayersMerged"
+	// ConditionConfigRolledBack is only set while the rollback-to-revision request of a blueprint was executed.
+	// It records the executed request, so that the rollback is not executed again as long as the request stays set.
+	ConditionConfigRolledBack = "ConfigRolledBack"
 
 	ReasonLastApplyErrorAtDogus  = "DoguApplyFailure"
 	ReasonLastApplyErrorAtConfig = "ConfigApplyFailure"
+	// ReasonLastApplyErrorAtConfigRolledBack means that the config apply failed, but all of its changes were rolled back.
+	ReasonLastApplyErrorAtConfigRolledBack = "ConfigApplyFailureRolledBack"
+	// ReasonLastApplyErrorAtRevision means that everything was applied, but the revision of the apply could not be stored.
+	ReasonLastApplyErrorAtRevision = "RevisionFailure"
+	// ReasonInvalidConfigTemplates means that the config templates of the blueprint cannot be resolved.
+	ReasonInvalidConfigTemplates = "InvalidConfigTemplates"
 
 	loggingKey = "logging/root"
 )
@@ -57,8 +98,32 @@ var (
 type BlueprintConfiguration struct {
 	// IgnoreDoguHealth forces blueprint upgrades even if dogus are unhealthy
 	IgnoreDoguHealth bool
+	// IgnoreComponentHealth forces blueprint upgrades even if components are unhealthy
+	IgnoreComponentHealth bool
 	// AllowDoguNamespaceSwitch allows the blueprint upgrade to switch a dogus namespace
 	AllowDoguNamespaceSwitch bool
+	// AllowDoguDowngrades allows the blueprint upgrade to downgrade dogus. A backup is created before any dogu gets downgraded.
+	AllowDoguDowngrades bool
+	// RolloutWaves defines in which groups the dogu changes get applied. All changes get applied at once by default.
+	RolloutWaves RolloutWaves
+	// RequirePlanApproval lets the blueprint only apply changes if the hash of their Plan is approved in ApprovedPlanHash.
+	RequirePlanApproval bool
+	// ApprovedPlanHash is the hash of the Plan which may be applied, see Plan.Hash.
+	ApprovedPlanHash string
+	// RollbackToRevision restores the config of this BlueprintRevision. It is only allowed while the blueprint is Stopped,
+	// as the blueprint would apply its config again otherwise.
+	RollbackToRevision *int
+	// AutoUpgradePolicies lets the operator apply new releases of the given dogus without changing the blueprint.
+	AutoUpgradePolicies AutoUpgradePolicies
+	// Parameters can be referenced in templates of config values, e.g. {{ .Params.mailDomain }}.
+	Parameters map[string]string
+	// EnforcementModes lets the blueprint only report differences of the given dogus and config entries instead of overwriting them.
+	EnforcementModes EnforcementModes
+	// PruneDogus uninstalls dogus which were installed by this blueprint, if they are removed from it.
+	// Dogus installed in any other way are never uninstalled because of this.
+	PruneDogus bool
+	// CompleteDependencies adds missing dependencies of the dogus to the effective blueprint instead of rejecting the blueprint.
+	CompleteDependencies bool
 	// Stopped lets the user test a blueprint run to check if all attributes of the blueprint are correct and avoid a result with a failure state.
 	Stopped bool
 }
@@ -74,31 +139,126 @@ func (spec *BlueprintSpec) ValidateStatically() error {
 		errorList = append(errorList, errors.New("blueprint spec doesn't have an ID"))
 	}
 	errorList = append(errorList, spec.Blueprint.Validate())
-	errorList = append(errorList, spec.BlueprintMask.Validate())
-	errorList = append(errorList, spec.validateMaskAgainstBlueprint())
+	for _, mask := range spec.masks() {
+		errorList = append(errorList, describeMaskError(mask, mask.Validate()))
+		errorList = append(errorList, describeMaskError(mask, spec.validateMaskAgainstBlueprint(mask)))
+	}
+	errorList = append(errorList, spec.Config.RolloutWaves.Validate())
+	errorList = append(errorList, spec.Config.validateRollback())
+	errorList = append(errorList, spec.Config.AutoUpgradePolicies.validate(spec.Blueprint.Dogus))
+	errorList = append(errorList, validateConfigTemplates(spec.Blueprint.Config, spec.Config.Parameters))
+	errorList = append(errorList, spec.Config.EnforcementModes.validate())
 	err := errors.Join(errorList...)
 	if err != nil {
-		err = &InvalidBlueprintError{
-			WrappedError: err,
-			Message:      "blueprint spec is invalid",
-		}
-		spec.Events = append(spec.Events, BlueprintSpecInvalidEvent{ValidationError: err})
-		meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
-			Type:    ConditionValid,
-			Status:  metav1.ConditionFalse,
-			Reason:  "Invalid",
-			Message: err.Error(),
-		})
+		return spec.markInvalid(err)
 	}
 	// Do not set condition to true here.
 	// We reuse the condition for the dynamic validation.
 	// If the blueprint is completely consistent and valid can only be decided there
+	return nil
+}
+
+// markInvalid sets the ConditionValid to false because of the given static validation error.
+// returns the error as domain.InvalidBlueprintError.
+func (spec *BlueprintSpec) markInvalid(validationErr error) error {
+	err := &InvalidBlueprintError{
+		WrappedError: validationErr,
+		Message:      "blueprint spec is invalid",
+	}
+	spec.Events = append(spec.Events, BlueprintSpecInvalidEvent{ValidationError: err})
+	meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionValid,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Invalid",
+		Message: err.Error(),
+	})
 	return err
 }
 
-func (spec *BlueprintSpec) validateMaskAgainstBlueprint() error {
+// BaseBlueprintId returns the id of the blueprint resource which applies the merged blueprint of all layers.
+// This is the layer with the lowest priority. Its options and mask are used for the merged blueprint,
+// while the options and masks of all other layers are ignored.
+func (spec *BlueprintSpec) BaseBlueprintId() string {
+	if len(spec.Layers) == 0 {
+		return spec.Id
+	}
+	return slices.MinFunc(spec.Layers, compareLayers).Id
+}
+
+// MergeLayers replaces the Blueprint with the merged blueprint of all layers, so that the merged blueprint gets
+// validated and applied. Invalid layers of other blueprints are skipped, so that they cannot block this blueprint.
+// The ConditionLayersMerged shows the merged layers with the provenance of the merged blueprint.
+// The BlueprintLayersMergedEvent is only published if the condition changes.
+// returns a domain.InvalidBlueprintError if the own layer is invalid or the layers are conflicting.
+func (spec *BlueprintSpec) MergeLayers() error {
+	if len(spec.Layers) == 0 {
+		meta.RemoveStatusCondition(&spec.Conditions, ConditionLayersMerged)
+		return nil
+	}
+
+	layers, skippedLayersErr := spec.mergeableLayers()
+	merged, provenance, err := mergeBlueprintLayers(layers)
+	if err != nil {
+		return spec.markInvalid(err)
+	}
+	spec.Blueprint = merged
+	spec.Provenance = provenance
+
+	event := BlueprintLayersMergedEvent{Layers: layers, Provenance: provenance, SkippedLayersError: skippedLayersErr}
+	conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionLayersMerged,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Merged",
+		Message: event.Message(),
+	})
+	if conditionChanged {
+		spec.Events = append(spec.Events, event)
+	}
+	return nil
+}
+
+// mergeableLayers returns the layers which get merged into the Blueprint. Invalid layers of other blueprints are skipped,
+// so that they cannot block this blueprint. The own layer is always returned, as its errors are reported by the validation.
+// returns an error describing the skipped layers or nil if no layer was skipped.
+func (spec *BlueprintSpec) mergeableLayers() ([]BlueprintLayer, error) {
+	var layers []BlueprintLayer
+	var invalidLayerErrs []error
+	for _, layer := range spec.Layers {
+		if layer.Id != spec.Id {
+			err := layer.Blueprint.Validate()
+			if err != nil {
+				invalidLayerErrs = append(invalidLayerErrs, fmt.Errorf("blueprint layer %q is invalid: %w", layer.Id, err))
+				continue
+			}
+		}
+		layers = append(layers, layer)
+	}
+	return layers, errors.Join(invalidLayerErrs...)
+}
+
+// hasSkippedLayers checks if any layer is not part of the merged Blueprint because it is invalid.
+// The dogus and config of such a layer are missing in the effective blueprint, so they must not be pruned or disowned.
+func (spec *BlueprintSpec) hasSkippedLayers() bool {
+	_, skippedLayersErr := spec.mergeableLayers()
+	return skippedLayersErr != nil
+}
+
+// masks returns the BlueprintMask and all AdditionalMasks in the order in which they get applied.
+func (spec *BlueprintSpec) masks() []BlueprintMask {
+	return append([]BlueprintMask{spec.BlueprintMask}, spec.AdditionalMasks...)
+}
+
+// describeMaskError names the mask in the given error if there are multiple masks.
+func describeMaskError(mask BlueprintMask, err error) error {
+	if err == nil || mask.Name == "" {
+		return err
+	}
+	return fmt.Errorf("mask %q: %w", mask.Name, err)
+}
+
+func (spec *BlueprintSpec) validateMaskAgainstBlueprint(mask BlueprintMask) error {
 	var errorList []error
-	for _, doguMask := range spec.BlueprintMask.Dogus {
+	for _, doguMask := range mask.Dogus {
 		dogu, found := FindDoguByName(spec.Blueprint.Dogus, cescommons.SimpleName(doguMask.Name.SimpleName))
 		if !found {
 			errorList = append(errorList, fmt.Errorf("dogu %q is missing in the blueprint", doguMask.Name))
@@ -153,7 +313,7 @@ func (spec *BlueprintSpec) CalculateEffectiveBlueprint() error {
 		return err
 	}
 
-	effectiveConfig := spec.removeConfigForMaskedDogus()
+	effectiveConfig := spec.removeConfigForMaskedDogus(spec.applyMaskConfig())
 
 	spec.EffectiveBlueprint = EffectiveBlueprint{
 		Dogus:  effectiveDogus,
@@ -175,6 +335,116 @@ func (spec *BlueprintSpec) CalculateEffectiveBlueprint() error {
 	return nil
 }
 
+// ResolveDoguVersions sets the concrete versions for the dogus with a version constraint in the effective blueprint.
+// resolvedVersions contains the resolved versions by simple dogu name. If the version constraints could not be resolved,
+// the blueprint gets marked as invalid and the given resolveError is returned.
+func (spec *BlueprintSpec) ResolveDoguVersions(resolvedVersions map[cescommons.SimpleName]core.Version, resolveError error) error {
+	if resolveError != nil {
+		conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+			Type:    ConditionValid,
+			Status:  metav1.ConditionFalse,
+			Reason:  "UnresolvableVersionConstraint",
+			Message: resolveError.Error(),
+		})
+		if conditionChanged {
+			spec.Events = append(spec.Events, BlueprintSpecInvalidEvent{ValidationError: resolveError})
+		}
+		return resolveError
+	}
+
+	for i, dogu := range spec.EffectiveBlueprint.Dogus {
+		resolvedVersion, found := resolvedVersions[dogu.Name.SimpleName]
+		if dogu.VersionConstraint != nil && found {
+			spec.EffectiveBlueprint.Dogus[i].Version = &resolvedVersion
+		}
+	}
+	return nil
+}
+
+// AddMissingDependencies adds the given dogus to the effective blueprint, as they are missing dependencies of other dogus.
+// The added dogus are marked as AutoAdded and listed in the ConditionDependenciesAutoAdded, so that they can be moved to the blueprint.
+// The condition is removed if the blueprint does not complete missing dependencies.
+// If the missing dependencies could not be determined, the blueprint gets marked as invalid and the given completionError is returned.
+func (spec *BlueprintSpec) AddMissingDependencies(missingDependencies []Dogu, completionError error) error {
+	if completionError != nil {
+		conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+			Type:    ConditionValid,
+			Status:  metav1.ConditionFalse,
+			Reason:  "UnresolvableDependencies",
+			Message: completionError.Error(),
+		})
+		if conditionChanged {
+			spec.Events = append(spec.Events, BlueprintSpecInvalidEvent{ValidationError: completionError})
+		}
+		return completionError
+	}
+	if !spec.Config.CompleteDependencies {
+		meta.RemoveStatusCondition(&spec.Conditions, ConditionDependenciesAutoAdded)
+		return nil
+	}
+	if len(missingDependencies) == 0 {
+		meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+			Type:    ConditionDependenciesAutoAdded,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoDependenciesMissing",
+			Message: "all dependencies of the dogus are part of the blueprint",
+		})
+		return nil
+	}
+
+	for _, dogu := range missingDependencies {
+		dogu.AutoAdded = true
+		spec.EffectiveBlueprint.Dogus = append(spec.EffectiveBlueprint.Dogus, dogu)
+	}
+	conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionDependenciesAutoAdded,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DependenciesAdded",
+		Message: dependenciesAutoAddedMessagePrefix + formatDoguVersions(missingDependencies),
+	})
+	if conditionChanged {
+		spec.Events = append(spec.Events, DependenciesAutoAddedEvent{AddedDogus: missingDependencies})
+	}
+	return nil
+}
+
+// dependenciesAutoAddedMessagePrefix precedes the added dogus in the message of the ConditionDependenciesAutoAdded.
+const dependenciesAutoAddedMessagePrefix = "missing dependencies were added to the effective blueprint: "
+
+// GetAutoAddedDogus returns the names of the dogus listed in the ConditionDependenciesAutoAdded.
+// Repositories can use this to restore Dogu.AutoAdded of the effective blueprint, if they cannot persist it otherwise.
+func GetAutoAddedDogus(conditions []Condition) []cescommons.QualifiedName {
+	condition := meta.FindStatusCondition(conditions, ConditionDependenciesAutoAdded)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return nil
+	}
+	formattedDogus, found := strings.CutPrefix(condition.Message, dependenciesAutoAddedMessagePrefix)
+	if !found {
+		return nil
+	}
+
+	var doguNames []cescommons.QualifiedName
+	for _, formattedDogu := range strings.Split(formattedDogus, ", ") {
+		rawName, _, _ := strings.Cut(formattedDogu, ":")
+		doguName, err := cescommons.QualifiedNameFromString(rawName)
+		if err == nil {
+			doguNames = append(doguNames, doguName)
+		}
+	}
+	return doguNames
+}
+
+// formatDoguVersions returns the qualified names and versions of the given dogus, e.g. "official/postgresql:14.15-2, official/cas:7.0.8-1".
+func formatDoguVersions(dogus []Dogu) string {
+	formattedDogus := util.Map(dogus, func(dogu Dogu) string {
+		if dogu.Version == nil {
+			return dogu.Name.String()
+		}
+		return fmt.Sprintf("%s:%s", dogu.Name, dogu.Version.Raw)
+	})
+	return strings.Join(formattedDogus, ", ")
+}
+
 // removeLogLevelChangesFromConfig creates a copy of the given config with all
 // logging configuration entries removed from dogu configs. This is used in
 // debug mode to prevent log level changes from being applied.
@@ -210,46 +480,138 @@ func (spec *BlueprintSpec) calculateEffectiveDogus() ([]Dogu, error) {
 
 func (spec *BlueprintSpec) calculateEffectiveDogu(dogu Dogu) (Dogu, error) {
 	effectiveDogu := Dogu{
-		Name:             dogu.Name,
-		Version:          dogu.Version,
-		Absent:           dogu.Absent,
-		MinVolumeSize:    dogu.MinVolumeSize,
-		StorageClassName: dogu.StorageClassName,
-		AdditionalMounts: dogu.AdditionalMounts,
-	}
-	maskDogu, noMaskDoguErr := spec.BlueprintMask.FindDoguByName(dogu.Name.SimpleName)
-	if noMaskDoguErr == nil {
+		Name:               dogu.Name,
+		Version:            dogu.Version,
+		VersionConstraint:  dogu.VersionConstraint,
+		Absent:             dogu.Absent,
+		MinVolumeSize:      dogu.MinVolumeSize,
+		StorageClassName:   dogu.StorageClassName,
+		AdditionalMounts:   dogu.AdditionalMounts,
+		ReverseProxyConfig: dogu.ReverseProxyConfig,
+	}
+	versionPinnedByMask := false
+	for _, mask := range spec.masks() {
+		maskDogu, noMaskDoguErr := mask.FindDoguByName(dogu.Name.SimpleName)
+		if noMaskDoguErr != nil {
+			continue
+		}
 		emptyVersion := core.Version{}
 		if maskDogu.Version != emptyVersion {
 			effectiveDogu.Version = &maskDogu.Version
+			// the exact version of the mask takes precedence over the constraint of the blueprint
+			effectiveDogu.VersionConstraint = nil
+			versionPinnedByMask = true
 		}
-		if maskDogu.Name.Namespace != dogu.Name.Namespace {
+		if maskDogu.Name.Namespace != effectiveDogu.Name.Namespace {
 			if spec.Config.AllowDoguNamespaceSwitch {
 				effectiveDogu.Name.Namespace = maskDogu.Name.Namespace
 			} else {
 				return Dogu{}, fmt.Errorf(
-					"changing the dogu namespace is forbidden by default and can be allowed by a flag: %q -> %q", dogu.Name, maskDogu.Name)
+					"changing the dogu namespace is forbidden by default and can be allowed by a flag: %q -> %q", effectiveDogu.Name, maskDogu.Name)
 			}
 		}
 		effectiveDogu.Absent = maskDogu.Absent
 	}
 
+	policy, hasPolicy := spec.Config.AutoUpgradePolicies[dogu.Name.SimpleName]
+	if hasPolicy && !versionPinnedByMask && !effectiveDogu.Absent && effectiveDogu.Version != nil {
+		constraint, err := policy.VersionConstraint(*effectiveDogu.Version)
+		if err != nil {
+			return Dogu{}, fmt.Errorf("cannot apply auto upgrade policy of dogu %q: %w", dogu.Name, err)
+		}
+		effectiveDogu.VersionConstraint = &constraint
+		effectiveDogu.AutoUpgradePolicy = policy
+	}
+
 	return effectiveDogu, nil
 }
 
+// GetAutoUpgradeDogus returns all dogus of the effective blueprint which get upgraded automatically.
+// Their VersionConstraint contains the versions which may be applied.
+// The effective blueprint of the BlueprintSpec itself stays untouched.
+func (spec *BlueprintSpec) GetAutoUpgradeDogus() ([]Dogu, error) {
+	blueprint := spec.Blueprint
+	if len(spec.Layers) > 0 {
+		var err error
+		// use the same layers as MergeLayers, so that invalid layers of other blueprints are skipped here as well
+		layers, _ := spec.mergeableLayers()
+		blueprint, _, err = mergeBlueprintLayers(layers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var autoUpgradeDogus []Dogu
+	for _, dogu := range blueprint.Dogus {
+		effectiveDogu, err := spec.calculateEffectiveDogu(dogu)
+		if err != nil {
+			return nil, err
+		}
+		if effectiveDogu.AutoUpgradePolicy != "" {
+			autoUpgradeDogus = append(autoUpgradeDogus, effectiveDogu)
+		}
+	}
+	return autoUpgradeDogus, nil
+}
+
+// applyMaskConfig returns the config of the blueprint with the config entries of all masks applied in their order.
+// A config entry of a mask replaces the config entry with the same key or gets added if there is none.
+func (spec *BlueprintSpec) applyMaskConfig() Config {
+	config := Config{
+		// clone the map to not modify the config of the blueprint itself
+		Dogus:  maps.Clone(spec.Blueprint.Config.Dogus),
+		Global: spec.Blueprint.Config.Global,
+	}
+	for _, mask := range spec.masks() {
+		for doguName, overrides := range mask.Config.Dogus {
+			if config.Dogus == nil {
+				config.Dogus = DoguConfig{}
+			}
+			config.Dogus[doguName] = DoguConfigEntries(overrideConfigEntries(ConfigEntries(config.Dogus[doguName]), ConfigEntries(overrides)))
+		}
+		config.Global = GlobalConfigEntries(overrideConfigEntries(ConfigEntries(config.Global), ConfigEntries(mask.Config.Global)))
+	}
+	return config
+}
+
+// overrideConfigEntries returns a copy of the given entries where the overrides replace the entries with the same key.
+// Overrides without a corresponding entry get appended.
+func overrideConfigEntries(entries ConfigEntries, overrides ConfigEntries) ConfigEntries {
+	if len(overrides) == 0 {
+		return entries
+	}
+	result := slices.Clone(entries)
+	for _, override := range overrides {
+		index := slices.IndexFunc(result, func(entry ConfigEntry) bool { return entry.Key == override.Key })
+		if index == -1 {
+			result = append(result, override)
+		} else {
+			result[index] = override
+		}
+	}
+	return result
+}
+
 // It is not allowed to have config without the corresponding dogu, so this will clean up the unnecessary config.
-func (spec *BlueprintSpec) removeConfigForMaskedDogus() Config {
-	effectiveDoguConfig := maps.Clone(spec.Blueprint.Config.Dogus)
+func (spec *BlueprintSpec) removeConfigForMaskedDogus(config Config) Config {
+	effectiveDoguConfig := maps.Clone(config.Dogus)
 
-	for _, dogu := range spec.BlueprintMask.Dogus {
-		if dogu.Absent {
-			delete(effectiveDoguConfig, dogu.Name.SimpleName)
+	// later masks can make a dogu present again, so only the last mask of a dogu counts
+	absentByMask := map[cescommons.SimpleName]bool{}
+	for _, mask := range spec.masks() {
+		for _, dogu := range mask.Dogus {
+			absentByMask[dogu.Name.SimpleName] = dogu.Absent
+		}
+	}
+	for doguName, absent := range absentByMask {
+		if absent {
+			delete(effectiveDoguConfig, doguName)
 		}
 	}
 
 	return Config{
 		Dogus:  effectiveDoguConfig,
-		Global: spec.Blueprint.Config.Global,
+		Global: config.Global,
 	}
 }
 
@@ -266,12 +628,40 @@ func (spec *BlueprintSpec) MissingConfigReferences(error error) {
 	}
 }
 
+// setDogusPrunedCondition lists the pruned dogus of the state diff with their reason in the ConditionDogusPruned,
+// as the state diff of the blueprint CRD has no field for the reason of a dogu diff.
+// The condition is removed if the blueprint does not prune dogus.
+func (spec *BlueprintSpec) setDogusPrunedCondition() {
+	if !spec.Config.PruneDogus {
+		meta.RemoveStatusCondition(&spec.Conditions, ConditionDogusPruned)
+		return
+	}
+	prunedDogus := spec.StateDiff.DoguDiffs.getPrunedDogus()
+	if len(prunedDogus) == 0 {
+		meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+			Type:    ConditionDogusPruned,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoDogusPruned",
+			Message: "no dogu installed by the blueprint was removed from it",
+		})
+		return
+	}
+	meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionDogusPruned,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DogusPruned",
+		Message: fmt.Sprintf("uninstall dogus %v: %s", prunedDogus, DoguDiffReasonPruned),
+	})
+}
+
 // DetermineStateDiff creates the StateDiff between the blueprint and the actual state of the ecosystem.
 // if sth. is not in the lists of installed things, it is considered not installed.
 // installedDogus are a map in the form of simpleDoguName->*DoguInstallation. There should be no nil values.
 // The StateDiff is an 'as is' representation, therefore no error is thrown, e.g. if dogu namespaces are different and namespace changes are not allowed.
 // If there are not allowed actions should be considered at the start of the execution of the blueprint.
-// returns an error if the BlueprintSpec is not in the necessary state to determine the stateDiff.
+// Templates in config values get resolved with the blueprint parameters and the referenced config first.
+// returns an error if the BlueprintSpec is not in the necessary state to determine the stateDiff or
+// a domain.InvalidBlueprintError if the config templates cannot be resolved.
 func (spec *BlueprintSpec) DetermineStateDiff(
 	ecosystemState ecosystem.EcosystemState,
 	referencedSensitiveConfig map[common.DoguConfigKey]common.SensitiveDoguConfigValue,
@@ -280,11 +670,31 @@ func (spec *BlueprintSpec) DetermineStateDiff(
 	referencedGlobalConfig map[common.GlobalConfigKey]common.GlobalConfigValue,
 	isDebugModeActive bool,
 ) error {
-	doguDiffs := determineDoguDiffs(spec.EffectiveBlueprint.Dogus, ecosystemState.InstalledDogus)
+	// skipped layers are missing in the effective blueprint, so pruning would remove their dogus and config
+	skippedLayers := spec.hasSkippedLayers()
+	var prunedBlueprintId string
+	if spec.Config.PruneDogus && !skippedLayers {
+		prunedBlueprintId = spec.Id
+	}
+	doguDiffs := determineDoguDiffs(spec.EffectiveBlueprint.Dogus, ecosystemState.InstalledDogus, prunedBlueprintId)
 	config := spec.EffectiveBlueprint.Config
 	if isDebugModeActive {
 		config = removeLogLevelChangesFromConfig(config)
 	}
+	config, err := resolveConfigTemplates(config, spec.Config.Parameters, ecosystemState, referencedConfig, referencedGlobalConfig)
+	if err != nil {
+		invalidBlueprintError := &InvalidBlueprintError{WrappedError: err, Message: "cannot resolve config templates"}
+		conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+			Type:    ConditionExecutable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInvalidConfigTemplates,
+			Message: invalidBlueprintError.Error(),
+		})
+		if conditionChanged {
+			spec.Events = append(spec.Events, BlueprintSpecInvalidEvent{ValidationError: invalidBlueprintError})
+		}
+		return invalidBlueprintError
+	}
 	doguConfigDiffs, sensitiveDoguConfigDiffs, globalConfigDiffs := determineConfigDiffs(
 		config,
 		ecosystemState.GlobalConfig,
@@ -302,10 +712,16 @@ func (spec *BlueprintSpec) DetermineStateDiff(
 		SensitiveDoguConfigDiffs: sensitiveDoguConfigDiffs,
 		GlobalConfigDiffs:        globalConfigDiffs,
 	}
+	if !skippedLayers {
+		spec.determineDisownedConfigDiffs(ecosystemState)
+	}
+	spec.setDogusPrunedCondition()
+	spec.reportDrift()
 
 	spec.resetCompletedConditionAfterStateDiff()
 	if spec.StateDiff.DoguDiffs.HasChanges() {
 		spec.Events = append(spec.Events, newStateDiffEvent(spec.StateDiff))
+		spec.Events = append(spec.Events, spec.getAutoUpgradeEvents()...)
 	}
 
 	invalidBlueprintError := spec.validateStateDiff()
@@ -330,6 +746,30 @@ func (spec *BlueprintSpec) DetermineStateDiff(
 	return nil
 }
 
+// getAutoUpgradeEvents returns an event for every dogu upgrade in the state diff which is caused by an auto upgrade
+// policy instead of the version in the blueprint.
+func (spec *BlueprintSpec) getAutoUpgradeEvents() []Event {
+	var events []Event
+	for _, diff := range spec.StateDiff.DoguDiffs {
+		if !slices.Contains(diff.NeededActions, ActionUpgrade) {
+			continue
+		}
+		effectiveDogu, found := FindDoguByName(spec.EffectiveBlueprint.Dogus, diff.DoguName)
+		blueprintDogu, _ := FindDoguByName(spec.Blueprint.Dogus, diff.DoguName)
+		if !found || effectiveDogu.AutoUpgradePolicy == "" || blueprintDogu.Version == nil || effectiveDogu.Version == nil ||
+			!effectiveDogu.Version.IsNewerThan(*blueprintDogu.Version) {
+			continue
+		}
+		events = append(events, DoguAutoUpgradeEvent{
+			DoguName:         diff.DoguName,
+			BlueprintVersion: *blueprintDogu.Version,
+			TargetVersion:    *effectiveDogu.Version,
+			Policy:           effectiveDogu.AutoUpgradePolicy,
+		})
+	}
+	return events
+}
+
 // HandleHealthResult sets the healthCondition accordingly to the healthResult and a possible error.
 // if an error is given, the condition will be set to unknown.
 // The function returns true if the condition changed, otherwise false.
@@ -346,7 +786,8 @@ func (spec *BlueprintSpec) HandleHealthResult(healthResult ecosystem.HealthResul
 
 	if healthResult.AllHealthy() {
 		event := EcosystemHealthyEvent{
-			doguHealthIgnored: spec.Config.IgnoreDoguHealth,
+			doguHealthIgnored:      spec.Config.IgnoreDoguHealth,
+			componentHealthIgnored: spec.Config.IgnoreComponentHealth,
 		}
 		conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
 			Type:    ConditionEcosystemHealthy,
@@ -418,6 +859,9 @@ func (spec *BlueprintSpec) validateDoguDiffActions(diff DoguDiff) []error {
 			if action == ActionSwitchDoguNamespace && spec.Config.AllowDoguNamespaceSwitch {
 				return nil
 			}
+			if action == ActionDowngrade && spec.Config.AllowDoguDowngrades {
+				return nil
+			}
 
 			return getActionNotAllowedError(action, diff.DoguName)
 		}
@@ -478,6 +922,26 @@ func (spec *BlueprintSpec) MarkBlueprintStopped() {
 	spec.Events = append(spec.Events, BlueprintStoppedEvent{})
 }
 
+// GetPreDowngradeBackupName returns the name of the backup which has to be completed before the dogus in the
+// state diff can be downgraded. The name only changes if the downgrades change, so that a restarted reconciliation
+// does not create a new backup.
+func (spec *BlueprintSpec) GetPreDowngradeBackupName() string {
+	downgrades := util.Map(spec.StateDiff.DoguDiffs.GetDowngrades(), func(diff DoguDiff) string {
+		return fmt.Sprintf("%s:%s->%s", diff.DoguName, diff.Actual.getSafeVersionString(), diff.Expected.getSafeVersionString())
+	})
+	slices.Sort(downgrades)
+	hash := sha256.Sum256([]byte(spec.Id + "\n" + strings.Join(downgrades, "\n")))
+	return "blueprint-downgrade-" + hex.EncodeToString(hash[:])[:10]
+}
+
+// MarkPreDowngradeBackupStarted informs the user that a backup was started before dogus get downgraded.
+func (spec *BlueprintSpec) MarkPreDowngradeBackupStarted(backupName string) {
+	spec.Events = append(spec.Events, PreDowngradeBackupStartedEvent{
+		BackupName: backupName,
+		Downgrades: spec.StateDiff.DoguDiffs.GetDowngrades(),
+	})
+}
+
 func (spec *BlueprintSpec) MarkDogusApplied(isDogusApplied bool, err error) bool {
 	if isDogusApplied {
 		spec.Events = append(spec.Events, DogusAppliedEvent{Diffs: spec.StateDiff.DoguDiffs})
@@ -485,3 +949,110 @@ func (spec *BlueprintSpec) MarkDogusApplied(isDogusApplied bool, err error) bool
 	conditionChanged := spec.SetLastApplySucceededConditionOnError(ReasonLastApplyErrorAtDogus, err)
 	return conditionChanged
 }
+
+// MarkRolloutWaveApplied sets the ConditionRolloutWave after the dogus of one rollout wave were applied.
+// pendingWaves is the number of waves which still need to be applied after this one.
+func (spec *BlueprintSpec) MarkRolloutWaveApplied(wave DoguDiffs, pendingWaves int) bool {
+	spec.Events = append(spec.Events, RolloutWaveAppliedEvent{Diffs: wave, PendingWaves: pendingWaves})
+	return meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionRolloutWave,
+		Status:  metav1.ConditionFalse,
+		Reason:  "WaveApplied",
+		Message: fmt.Sprintf("Applied rollout wave with dogus %v. %d more waves pending.", wave.GetDoguNames(), pendingWaves),
+	})
+}
+
+func (config BlueprintConfiguration) validateRollback() error {
+	if config.RollbackToRevision == nil {
+		return nil
+	}
+	if *config.RollbackToRevision < 0 {
+		return fmt.Errorf("cannot roll back to the negative revision %d", *config.RollbackToRevision)
+	}
+	if !config.Stopped {
+		return fmt.Errorf("rolling back the config to revision %d is only allowed while the blueprint is stopped, "+
+			"otherwise the blueprint would apply its config again", *config.RollbackToRevision)
+	}
+	return nil
+}
+
+// MarkAllRolloutWavesApplied sets the ConditionRolloutWave to true as there are no dogu changes left to apply.
+func (spec *BlueprintSpec) MarkAllRolloutWavesApplied() bool {
+	return meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionRolloutWave,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AllWavesApplied",
+		Message: "All rollout waves are applied.",
+	})
+}
+
+// GetPlan returns the Plan for the current state diff. Sensitive values get hashed with the given key, see NewPlan.
+func (spec *BlueprintSpec) GetPlan(sensitiveValueKey []byte) (Plan, error) {
+	return NewPlan(spec.Id, spec.StateDiff, sensitiveValueKey)
+}
+
+// MarkPlanWaitingForApproval sets the ConditionPlanApproved to false as the plan has to be approved before it gets applied.
+// approvedHash is the hash of a previously approved plan which does not contain the changes of the plan anymore.
+// It is empty if no plan was approved yet.
+func (spec *BlueprintSpec) MarkPlanWaitingForApproval(plan Plan, approvedHash string) bool {
+	hash := plan.Hash()
+	reason := "WaitingForApproval"
+	message := fmt.Sprintf("Plan %s with %d change(s) needs to be approved.", hash, len(plan.Changes))
+	if approvedHash != "" {
+		reason = "ApprovedPlanOutdated"
+		message = fmt.Sprintf(
+			"The approved plan %s does not contain all changes anymore as the blueprint or the ecosystem has changed. Plan %s with %d change(s) needs to be approved.",
+			approvedHash, hash, len(plan.Changes),
+		)
+	}
+
+	conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionPlanApproved,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if conditionChanged {
+		spec.Events = append(spec.Events, PlanApprovalRequiredEvent{Hash: hash, ChangeCount: len(plan.Changes)})
+	}
+	return conditionChanged
+}
+
+// MarkRegistryUnreachable sets the ConditionRegistryReachable to false as no endpoint of the remote dogu registry
+// was reachable. An event gets published if the registry was reachable before.
+func (spec *BlueprintSpec) MarkRegistryUnreachable(err error) bool {
+	conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionRegistryReachable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Unreachable",
+		Message: err.Error(),
+	})
+	if conditionChanged {
+		spec.Events = append(spec.Events, RegistryUnreachableEvent{Error: err})
+	}
+	return conditionChanged
+}
+
+// MarkRegistryReachable sets the ConditionRegistryReachable to true as the remote dogu registry was reachable.
+// The condition is not added if the registry was never unreachable.
+func (spec *BlueprintSpec) MarkRegistryReachable() bool {
+	if meta.FindStatusCondition(spec.Conditions, ConditionRegistryReachable) == nil {
+		return false
+	}
+	return meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionRegistryReachable,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reachable",
+		Message: "The remote dogu registry is reachable.",
+	})
+}
+
+// MarkPlanApproved sets the ConditionPlanApproved to true as the changes are part of the approved plan.
+func (spec *BlueprintSpec) MarkPlanApproved() bool {
+	return meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionPlanApproved,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Approved",
+		Message: fmt.Sprintf("Plan %s is approved.", spec.Config.ApprovedPlanHash),
+	})
+}
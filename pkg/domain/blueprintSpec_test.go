@@ -8,6 +8,7 @@ import (
 	libconfig "github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/google/go-cmp/cmp"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/stretchr/testify/assert"
@@ -78,6 +79,222 @@ func Test_BlueprintSpec_Validate_combineErrors(t *testing.T) {
 	assert.ErrorContains(t, err, "blueprint mask is invalid")
 }
 
+func Test_BlueprintSpec_Validate_invalidRolloutWaves(t *testing.T) {
+	spec := BlueprintSpec{
+		Id:     "29.11.2023",
+		Config: BlueprintConfiguration{RolloutWaves: RolloutWaves{Explicit: [][]cescommons.SimpleName{{"ldap"}, {"ldap"}}}},
+	}
+
+	err := spec.ValidateStatically()
+
+	var invalidError *InvalidBlueprintError
+	assert.ErrorAs(t, err, &invalidError)
+	assert.ErrorContains(t, err, "dogu \"ldap\" is part of more than one rollout wave")
+}
+
+func TestBlueprintSpec_MergeLayers(t *testing.T) {
+	t.Run("should merge layers", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id:            "team-a",
+			Blueprint:     Blueprint{Dogus: []Dogu{{Name: officialDogu2, Version: &version3211}}},
+			BlueprintMask: BlueprintMask{Dogus: []MaskDogu{{Name: officialDogu1, Absent: true}}},
+			Layers: []BlueprintLayer{
+				{Id: "team-a", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu2, Version: &version3211}}}},
+				{Id: "platform-base", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}},
+			},
+		}
+
+		err := spec.MergeLayers()
+
+		require.NoError(t, err)
+		assert.Equal(t, []Dogu{{Name: officialDogu1, Version: &version3211}, {Name: officialDogu2, Version: &version3211}}, spec.Blueprint.Dogus)
+		assert.Equal(t, "platform-base", spec.Provenance.Dogus[officialDogu1.SimpleName])
+		require.Len(t, spec.Events, 1)
+		assert.Equal(t, "BlueprintLayersMerged", spec.Events[0].Name())
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionLayersMerged)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, spec.Events[0].Message(), condition.Message)
+
+		// the merged blueprint is valid
+		require.NoError(t, spec.ValidateStatically())
+	})
+
+	t.Run("should publish the merge only if it changed", func(t *testing.T) {
+		layers := []BlueprintLayer{
+			{Id: "team-a", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu2, Version: &version3211}}}},
+			{Id: "platform-base", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}},
+		}
+		spec := BlueprintSpec{Id: "team-a", Layers: layers}
+		require.NoError(t, spec.MergeLayers())
+		spec.Events = nil
+
+		// when merged again, e.g. in the next reconciliation
+		err := spec.MergeLayers()
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, spec.Events)
+
+		// when the provenance changes
+		spec.Layers = append(layers, BlueprintLayer{Id: "team-b", Priority: 20, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3212}}}})
+		err = spec.MergeLayers()
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, spec.Events, 1)
+		assert.Equal(t, "team-b", spec.Provenance.Dogus[officialDogu1.SimpleName])
+	})
+
+	t.Run("should skip invalid layers of other blueprints", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id: "team-a",
+			Layers: []BlueprintLayer{
+				{Id: "team-a", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu2, Version: &version3211}}}},
+				{Id: "team-b", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1}}}},
+				{Id: "platform-base", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}},
+			},
+		}
+
+		err := spec.MergeLayers()
+
+		require.NoError(t, err)
+		assert.Equal(t, []Dogu{{Name: officialDogu1, Version: &version3211}, {Name: officialDogu2, Version: &version3211}}, spec.Blueprint.Dogus)
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionLayersMerged)
+		require.NotNil(t, condition)
+		assert.Contains(t, condition.Message, "skipped invalid blueprint layers: blueprint layer \"team-b\" is invalid")
+	})
+
+	t.Run("should be invalid if the own layer is invalid", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id: "team-a",
+			Layers: []BlueprintLayer{
+				{Id: "team-a", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1}}}},
+				{Id: "platform-base", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu2, Version: &version3211}}}},
+			},
+		}
+
+		err := spec.MergeLayers()
+
+		var invalidError *InvalidBlueprintError
+		assert.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "blueprint layer \"team-a\" is invalid")
+		assert.True(t, meta.IsStatusConditionFalse(spec.Conditions, ConditionValid))
+	})
+
+	t.Run("should be invalid on conflicting layers", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id: "team-a",
+			Layers: []BlueprintLayer{
+				{Id: "team-a", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3212}}}},
+				{Id: "team-b", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}},
+			},
+		}
+
+		err := spec.MergeLayers()
+
+		var invalidError *InvalidBlueprintError
+		assert.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "dogu \"dogu1\" is defined differently by blueprints \"team-a\" and \"team-b\"")
+		assert.Empty(t, spec.Provenance.Dogus)
+		assert.True(t, meta.IsStatusConditionFalse(spec.Conditions, ConditionValid))
+		require.Len(t, spec.Events, 1)
+		assert.Equal(t, "BlueprintSpecInvalid", spec.Events[0].Name())
+	})
+
+	t.Run("should remove the condition without layers", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id:         "team-a",
+			Conditions: []Condition{{Type: ConditionLayersMerged, Status: metav1.ConditionTrue}},
+		}
+
+		err := spec.MergeLayers()
+
+		require.NoError(t, err)
+		assert.Nil(t, meta.FindStatusCondition(spec.Conditions, ConditionLayersMerged))
+		assert.Empty(t, spec.Events)
+	})
+}
+
+func Test_BlueprintSpec_Validate_additionalMasks(t *testing.T) {
+	spec := BlueprintSpec{
+		Id:        "29.11.2023",
+		Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}},
+		AdditionalMasks: []BlueprintMask{
+			{Name: "site-a", Dogus: []MaskDogu{{Name: officialDogu2, Absent: true}}},
+		},
+	}
+
+	err := spec.ValidateStatically()
+
+	var invalidError *InvalidBlueprintError
+	assert.ErrorAs(t, err, &invalidError)
+	assert.ErrorContains(t, err, "mask \"site-a\": blueprint mask does not match the blueprint: dogu \"official/dogu2\" is missing in the blueprint")
+}
+
+func Test_BlueprintSpec_Validate_rollback(t *testing.T) {
+	t.Run("should allow rollback of stopped blueprint", func(t *testing.T) {
+		revision := 0
+		spec := BlueprintSpec{Id: "29.11.2023", Config: BlueprintConfiguration{Stopped: true, RollbackToRevision: &revision}}
+
+		err := spec.ValidateStatically()
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should be invalid if blueprint is not stopped", func(t *testing.T) {
+		revision := 2
+		spec := BlueprintSpec{Id: "29.11.2023", Config: BlueprintConfiguration{RollbackToRevision: &revision}}
+
+		err := spec.ValidateStatically()
+
+		var invalidError *InvalidBlueprintError
+		assert.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "rolling back the config to revision 2 is only allowed while the blueprint is stopped")
+	})
+
+	t.Run("should be invalid on negative revision", func(t *testing.T) {
+		revision := -1
+		spec := BlueprintSpec{Id: "29.11.2023", Config: BlueprintConfiguration{Stopped: true, RollbackToRevision: &revision}}
+
+		err := spec.ValidateStatically()
+
+		var invalidError *InvalidBlueprintError
+		assert.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "cannot roll back to the negative revision -1")
+	})
+}
+
+func Test_BlueprintSpec_Validate_configTemplates(t *testing.T) {
+	t.Run("reject undeclared parameters", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id: "29.11.2023",
+			Blueprint: Blueprint{Config: Config{
+				Global: GlobalConfigEntries{{Key: "mail", Value: templateValue("admin@{{ .Params.mailDomain }}")}},
+			}},
+			Config: BlueprintConfiguration{Parameters: map[string]string{"domain": "example.com"}},
+		}
+
+		err := spec.ValidateStatically()
+
+		var invalidError *InvalidBlueprintError
+		assert.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "global config \"mail\" references the undeclared parameter \"mailDomain\"")
+	})
+	t.Run("values are no templates without parameters", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id: "29.11.2023",
+			Blueprint: Blueprint{Config: Config{
+				Global: GlobalConfigEntries{{Key: "mail", Value: templateValue("admin@{{ .Params.mailDomain }} {{")}},
+			}},
+		}
+
+		err := spec.ValidateStatically()
+
+		assert.NoError(t, err)
+	})
+}
+
 func Test_BlueprintSpec_validateMaskAgainstBlueprint(t *testing.T) {
 	t.Run("mask for dogu which is not in blueprint", func(t *testing.T) {
 		spec := BlueprintSpec{
@@ -85,7 +302,7 @@ func Test_BlueprintSpec_validateMaskAgainstBlueprint(t *testing.T) {
 			BlueprintMask: BlueprintMask{Dogus: []MaskDogu{{Name: officialNexus}}},
 		}
 
-		err := spec.validateMaskAgainstBlueprint()
+		err := spec.validateMaskAgainstBlueprint(spec.BlueprintMask)
 
 		assert.ErrorContains(t, err, "blueprint mask does not match the blueprint")
 		assert.ErrorContains(t, err, "dogu \"official/nexus\" is missing in the blueprint")
@@ -97,7 +314,7 @@ func Test_BlueprintSpec_validateMaskAgainstBlueprint(t *testing.T) {
 			Config:        BlueprintConfiguration{AllowDoguNamespaceSwitch: true},
 		}
 
-		err := spec.validateMaskAgainstBlueprint()
+		err := spec.validateMaskAgainstBlueprint(spec.BlueprintMask)
 
 		require.Nil(t, err)
 	})
@@ -108,7 +325,7 @@ func Test_BlueprintSpec_validateMaskAgainstBlueprint(t *testing.T) {
 			Config:        BlueprintConfiguration{AllowDoguNamespaceSwitch: false},
 		}
 
-		err := spec.validateMaskAgainstBlueprint()
+		err := spec.validateMaskAgainstBlueprint(spec.BlueprintMask)
 
 		assert.ErrorContains(t, err, "blueprint mask does not match the blueprint")
 		assert.ErrorContains(t, err, "namespace switch is not allowed by default for dogu \"premium/nexus\": activate the feature flag for that")
@@ -119,7 +336,7 @@ func Test_BlueprintSpec_validateMaskAgainstBlueprint(t *testing.T) {
 			BlueprintMask: BlueprintMask{Dogus: []MaskDogu{{Name: officialNexus, Absent: false}}},
 		}
 
-		err := spec.validateMaskAgainstBlueprint()
+		err := spec.validateMaskAgainstBlueprint(spec.BlueprintMask)
 
 		assert.ErrorContains(t, err, "blueprint mask does not match the blueprint")
 		assert.ErrorContains(t, err, "absent dogu \"nexus\" cannot be present in blueprint mask")
@@ -167,6 +384,59 @@ func Test_BlueprintSpec_CalculateEffectiveBlueprint(t *testing.T) {
 		assert.Equal(t, Dogu{Name: officialDogu2, Version: &version3211, Absent: false}, spec.EffectiveBlueprint.Dogus[1])
 	})
 
+	t.Run("mask version overrides version constraint", func(t *testing.T) {
+		constraint, err := ParseVersionConstraint("~3.2")
+		require.NoError(t, err)
+		dogus := []Dogu{
+			{Name: officialDogu1, VersionConstraint: &constraint},
+			{Name: officialDogu2, VersionConstraint: &constraint},
+		}
+		maskedDogus := []MaskDogu{
+			{Name: officialDogu1, Version: version3212},
+		}
+
+		spec := BlueprintSpec{
+			Blueprint:     Blueprint{Dogus: dogus},
+			BlueprintMask: BlueprintMask{Dogus: maskedDogus},
+		}
+		err = spec.CalculateEffectiveBlueprint()
+
+		require.NoError(t, err)
+		require.Equal(t, 2, len(spec.EffectiveBlueprint.Dogus))
+		assert.Equal(t, Dogu{Name: officialDogu1, Version: &version3212}, spec.EffectiveBlueprint.Dogus[0])
+		assert.Equal(t, Dogu{Name: officialDogu2, VersionConstraint: &constraint}, spec.EffectiveBlueprint.Dogus[1])
+	})
+
+	t.Run("auto upgrade policy", func(t *testing.T) {
+		dogus := []Dogu{
+			{Name: officialDogu1, Version: &version3211},
+			{Name: officialDogu2, Version: &version3211},
+			{Name: officialDogu3, Version: &version3211},
+		}
+		maskedDogus := []MaskDogu{
+			{Name: officialDogu2, Version: version3212},
+			{Name: officialDogu3, Absent: true},
+		}
+
+		spec := BlueprintSpec{
+			Blueprint:     Blueprint{Dogus: dogus},
+			BlueprintMask: BlueprintMask{Dogus: maskedDogus},
+			Config: BlueprintConfiguration{AutoUpgradePolicies: AutoUpgradePolicies{
+				officialDogu1.SimpleName: AutoUpgradePatch,
+				officialDogu2.SimpleName: AutoUpgradePatch,
+				officialDogu3.SimpleName: AutoUpgradePatch,
+			}},
+		}
+		err := spec.CalculateEffectiveBlueprint()
+
+		require.NoError(t, err)
+		expectedConstraint, err := ParseVersionConstraint(">=3.2.1-1 <3.3")
+		require.NoError(t, err)
+		assert.Equal(t, Dogu{Name: officialDogu1, Version: &version3211, VersionConstraint: &expectedConstraint, AutoUpgradePolicy: AutoUpgradePatch}, spec.EffectiveBlueprint.Dogus[0])
+		assert.Equal(t, Dogu{Name: officialDogu2, Version: &version3212}, spec.EffectiveBlueprint.Dogus[1], "mask version pins the dogu")
+		assert.Equal(t, Dogu{Name: officialDogu3, Version: &version3211, Absent: true}, spec.EffectiveBlueprint.Dogus[2], "absent dogus are not upgraded")
+	})
+
 	t.Run("make dogu absent", func(t *testing.T) {
 		dogus := []Dogu{
 			{Name: officialDogu1, Version: &version3211, Absent: false},
@@ -197,6 +467,96 @@ func Test_BlueprintSpec_CalculateEffectiveBlueprint(t *testing.T) {
 		assert.NotContains(t, spec.EffectiveBlueprint.Config.Dogus, officialDogu1.SimpleName)
 	})
 
+	t.Run("apply additional masks in order", func(t *testing.T) {
+		dogus := []Dogu{
+			{Name: officialDogu1, Version: &version3211},
+			{Name: officialDogu2, Version: &version3211},
+			{Name: officialDogu3, Version: &version3211},
+		}
+		config := Config{
+			Dogus: map[cescommons.SimpleName]DoguConfigEntries{
+				officialDogu1.SimpleName: {ConfigEntry{Key: "test", Value: &val1}},
+				officialDogu2.SimpleName: {ConfigEntry{Key: "test", Value: &val1}},
+			},
+		}
+
+		spec := BlueprintSpec{
+			Blueprint:     Blueprint{Dogus: dogus, Config: config},
+			BlueprintMask: BlueprintMask{Dogus: []MaskDogu{{Name: officialDogu3, Version: version3212}}},
+			AdditionalMasks: []BlueprintMask{
+				{Name: "no-premium", Dogus: []MaskDogu{{Name: officialDogu1, Absent: true}, {Name: officialDogu2, Absent: true}}},
+				{Name: "site-a", Dogus: []MaskDogu{{Name: officialDogu2, Version: version3213}, {Name: officialDogu3, Absent: true}}},
+			},
+		}
+		err := spec.CalculateEffectiveBlueprint()
+
+		require.NoError(t, err)
+		assert.Equal(t, []Dogu{
+			{Name: officialDogu1, Version: &version3211, Absent: true},
+			{Name: officialDogu2, Version: &version3213},
+			{Name: officialDogu3, Version: &version3212, Absent: true},
+		}, spec.EffectiveBlueprint.Dogus)
+		assert.NotContains(t, spec.EffectiveBlueprint.Config.Dogus, officialDogu1.SimpleName)
+		assert.Contains(t, spec.EffectiveBlueprint.Config.Dogus, officialDogu2.SimpleName, "later mask makes dogu present again")
+	})
+
+	t.Run("override config with masks", func(t *testing.T) {
+		dogus := []Dogu{
+			{Name: officialDogu1, Version: &version3211},
+			{Name: officialDogu2, Version: &version3211},
+		}
+		secretRef := SensitiveValueRef{SecretName: "site-a", SecretKey: "password"}
+		config := Config{
+			Dogus: DoguConfig{
+				officialDogu1.SimpleName: {{Key: "relay", Value: &val1}, {Key: "keep", Value: &val1}},
+			},
+			Global: GlobalConfigEntries{{Key: "fqdn", Value: &val1}, {Key: "mail", Value: &val1}},
+		}
+
+		spec := BlueprintSpec{
+			Blueprint: Blueprint{Dogus: dogus, Config: config},
+			BlueprintMask: BlueprintMask{Config: Config{
+				Dogus: DoguConfig{officialDogu1.SimpleName: {{Key: "relay", Value: &val2}}},
+			}},
+			AdditionalMasks: []BlueprintMask{
+				{Name: "site-a", Config: Config{
+					Dogus: DoguConfig{
+						officialDogu1.SimpleName: {{Key: "relay", Value: &val3}},
+						officialDogu2.SimpleName: {{Key: "password", Sensitive: true, SecretRef: &secretRef}},
+					},
+					Global: GlobalConfigEntries{{Key: "mail", Absent: true}, {Key: "site", Value: &val2}},
+				}},
+			},
+		}
+		err := spec.CalculateEffectiveBlueprint()
+
+		require.NoError(t, err)
+		assert.Equal(t, Config{
+			Dogus: DoguConfig{
+				officialDogu1.SimpleName: {{Key: "relay", Value: &val3}, {Key: "keep", Value: &val1}},
+				officialDogu2.SimpleName: {{Key: "password", Sensitive: true, SecretRef: &secretRef}},
+			},
+			Global: GlobalConfigEntries{{Key: "fqdn", Value: &val1}, {Key: "mail", Absent: true}, {Key: "site", Value: &val2}},
+		}, spec.EffectiveBlueprint.Config)
+		assert.Equal(t, config, spec.Blueprint.Config, "config of the blueprint must not be modified")
+		assert.Len(t, spec.Blueprint.Config.Dogus[officialDogu1.SimpleName], 2)
+		assert.Equal(t, &val1, spec.Blueprint.Config.Dogus[officialDogu1.SimpleName][0].Value)
+	})
+
+	t.Run("mask config for absent dogu is removed", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}},
+			AdditionalMasks: []BlueprintMask{
+				{Name: "site-a", Config: Config{Dogus: DoguConfig{officialDogu1.SimpleName: {{Key: "relay", Value: &val1}}}}},
+				{Name: "no-dogu1", Dogus: []MaskDogu{{Name: officialDogu1, Absent: true}}},
+			},
+		}
+		err := spec.CalculateEffectiveBlueprint()
+
+		require.NoError(t, err)
+		assert.Empty(t, spec.EffectiveBlueprint.Config.Dogus)
+	})
+
 	t.Run("change dogu namespace", func(t *testing.T) {
 		dogus := []Dogu{
 			{Name: officialNexus, Version: &version3211, Absent: false},
@@ -238,6 +598,21 @@ func Test_BlueprintSpec_CalculateEffectiveBlueprint(t *testing.T) {
 		assert.Equal(t, Dogu{Name: premiumNexus, Version: &version3211, Absent: false}, spec.EffectiveBlueprint.Dogus[0])
 	})
 
+	t.Run("later mask changes the dogu namespace of an earlier mask", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Blueprint:     Blueprint{Dogus: []Dogu{{Name: officialNexus, Version: &version3211}}},
+			BlueprintMask: BlueprintMask{Dogus: []MaskDogu{{Name: premiumNexus, Version: version3211}}},
+			AdditionalMasks: []BlueprintMask{
+				{Name: "official-only", Dogus: []MaskDogu{{Name: officialNexus, Version: version3212}}},
+			},
+			Config: BlueprintConfiguration{AllowDoguNamespaceSwitch: true},
+		}
+		err := spec.CalculateEffectiveBlueprint()
+
+		require.NoError(t, err)
+		assert.Equal(t, []Dogu{{Name: officialNexus, Version: &version3212}}, spec.EffectiveBlueprint.Dogus)
+	})
+
 	t.Run("validate only config for dogus in blueprint", func(t *testing.T) {
 		config := Config{
 			Dogus: map[cescommons.SimpleName]DoguConfigEntries{
@@ -276,6 +651,28 @@ func Test_BlueprintSpec_CalculateEffectiveBlueprint(t *testing.T) {
 		require.Nil(t, err)
 		assert.Equal(t, dogus[0], spec.EffectiveBlueprint.Dogus[0], "effective blueprint should contain dogu with all field from the original blueprint")
 	})
+
+	t.Run("add reverseProxyConfig", func(t *testing.T) {
+		maxBodySize := resource.MustParse("100Mi")
+		dogus := []Dogu{
+			{
+				Name:    officialNexus,
+				Version: &version3211,
+				ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+					MaxBodySize:   &maxBodySize,
+					RewriteTarget: "/",
+				},
+			},
+		}
+
+		spec := BlueprintSpec{
+			Blueprint: Blueprint{Dogus: dogus},
+		}
+		err := spec.CalculateEffectiveBlueprint()
+
+		require.Nil(t, err)
+		assert.Equal(t, dogus[0], spec.EffectiveBlueprint.Dogus[0], "effective blueprint should contain dogu with all field from the original blueprint")
+	})
 }
 
 func TestBlueprintSpec_MissingConfigReferences(t *testing.T) {
@@ -491,6 +888,117 @@ func TestBlueprintSpec_DetermineStateDiff(t *testing.T) {
 		require.Error(t, err)
 		assert.ErrorContains(t, err, "ldap: action \"downgrade\" is not allowed")
 	})
+	t.Run("allowed dogu downgrade", func(t *testing.T) {
+		// given
+		spec := BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{
+				Dogus: []Dogu{
+					{
+						Name: cescommons.QualifiedName{
+							Namespace:  "namespace",
+							SimpleName: "ldap",
+						},
+						Version: &version3211,
+					},
+				},
+			},
+			Config: BlueprintConfiguration{AllowDoguDowngrades: true},
+		}
+
+		clusterState := ecosystem.EcosystemState{
+			InstalledDogus: map[cescommons.SimpleName]*ecosystem.DoguInstallation{
+				"ldap": {
+					Name: cescommons.QualifiedName{
+						Namespace:  "namespace",
+						SimpleName: "ldap",
+					},
+					Version:          version3212,
+					InstalledVersion: version3212,
+				},
+			},
+		}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, map[common.DoguConfigKey]common.SensitiveDoguConfigValue{}, map[common.DoguConfigKey]common.DoguConfigValue{}, map[common.GlobalConfigKey]common.GlobalConfigValue{}, map[common.GlobalConfigKey]common.GlobalConfigValue{}, false)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionTrue(spec.Conditions, ConditionExecutable))
+		assert.Equal(t, []Action{ActionDowngrade}, spec.StateDiff.DoguDiffs[0].NeededActions)
+	})
+	t.Run("resolve config templates", func(t *testing.T) {
+		// given
+		spec := BlueprintSpec{
+			Config: BlueprintConfiguration{Parameters: map[string]string{"path": "nexus"}},
+			EffectiveBlueprint: EffectiveBlueprint{
+				Config: Config{Global: GlobalConfigEntries{{Key: "url", Value: templateValue("https://{{ .Global.fqdn }}/{{ .Params.path }}")}}},
+			},
+		}
+		globalEntries, _ := libconfig.MapToEntries(map[string]any{"fqdn": "ces.example.com"})
+		clusterState := ecosystem.EcosystemState{GlobalConfig: libconfig.CreateGlobalConfig(globalEntries)}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, spec.StateDiff.GlobalConfigDiffs, 1)
+		assert.Equal(t, "https://ces.example.com/nexus", *spec.StateDiff.GlobalConfigDiffs[0].Expected.Value)
+	})
+	t.Run("keep config templates in the effective blueprint", func(t *testing.T) {
+		// given
+		spec := BlueprintSpec{
+			Config: BlueprintConfiguration{Parameters: map[string]string{"path": "nexus"}},
+			EffectiveBlueprint: EffectiveBlueprint{
+				Config: Config{
+					Global: GlobalConfigEntries{{Key: "url", Value: templateValue("https://{{ .Global.fqdn }}/{{ .Params.path }}")}},
+					Dogus:  DoguConfig{"redmine": {{Key: "url", Value: templateValue("{{ .Global.url }}")}}},
+				},
+			},
+		}
+		expectedEffectiveBlueprint := EffectiveBlueprint{
+			Config: Config{
+				Global: GlobalConfigEntries{{Key: "url", Value: templateValue("https://{{ .Global.fqdn }}/{{ .Params.path }}")}},
+				Dogus:  DoguConfig{"redmine": {{Key: "url", Value: templateValue("{{ .Global.url }}")}}},
+			},
+		}
+		globalEntries, _ := libconfig.MapToEntries(map[string]any{"fqdn": "ces.example.com"})
+		clusterState := ecosystem.EcosystemState{GlobalConfig: libconfig.CreateGlobalConfig(globalEntries)}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, spec.StateDiff.DoguConfigDiffs["redmine"], 1)
+		assert.Equal(t, "https://ces.example.com/nexus", *spec.StateDiff.DoguConfigDiffs["redmine"][0].Expected.Value)
+		// templates must stay in the effective blueprint to be resolved again with the next ecosystem state
+		assert.Equal(t, expectedEffectiveBlueprint, spec.EffectiveBlueprint)
+	})
+
+	t.Run("invalid config templates", func(t *testing.T) {
+		// given
+		spec := BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{
+				Config: Config{Global: GlobalConfigEntries{{Key: "url", Value: templateValue("https://{{ .Global.fqdn }}")}}},
+			},
+			Config: BlueprintConfiguration{Parameters: map[string]string{}},
+		}
+
+		// when
+		err := spec.DetermineStateDiff(ecosystem.EcosystemState{}, nil, nil, nil, nil, false)
+
+		// then
+		var invalidError *InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "cannot resolve config templates: cannot resolve template of .Global.url: cannot reference .Global.fqdn as it is neither in the blueprint nor in the ecosystem")
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionExecutable)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, ReasonInvalidConfigTemplates, condition.Reason)
+		require.Len(t, spec.Events, 1)
+		assert.IsType(t, BlueprintSpecInvalidEvent{}, spec.Events[0])
+	})
 }
 
 func TestBlueprintSpec_CompletePostProcessing(t *testing.T) {
@@ -547,6 +1055,233 @@ func TestBlueprintSpec_ValidateDynamically(t *testing.T) {
 	})
 }
 
+func TestBlueprintSpec_ResolveDoguVersions(t *testing.T) {
+	constraint, err := ParseVersionConstraint("~3.2")
+	require.NoError(t, err)
+
+	t.Run("set resolved versions", func(t *testing.T) {
+		blueprint := BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{Dogus: []Dogu{
+				{Name: officialDogu1, VersionConstraint: &constraint},
+				{Name: officialDogu2, Version: &version3211},
+			}},
+		}
+
+		err := blueprint.ResolveDoguVersions(map[cescommons.SimpleName]core.Version{
+			officialDogu1.SimpleName: version3213,
+			officialDogu2.SimpleName: version3213,
+		}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, &version3213, blueprint.EffectiveBlueprint.Dogus[0].Version)
+		assert.Equal(t, &constraint, blueprint.EffectiveBlueprint.Dogus[0].VersionConstraint)
+		assert.Equal(t, &version3211, blueprint.EffectiveBlueprint.Dogus[1].Version, "dogus without constraint keep their version")
+		assert.Empty(t, blueprint.Events)
+	})
+
+	t.Run("mark blueprint invalid on error", func(t *testing.T) {
+		blueprint := BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{Dogus: []Dogu{
+				{Name: officialDogu1, VersionConstraint: &constraint},
+			}},
+		}
+
+		err := blueprint.ResolveDoguVersions(nil, assert.AnError)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, blueprint.EffectiveBlueprint.Dogus[0].Version)
+		condition := meta.FindStatusCondition(blueprint.Conditions, ConditionValid)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "UnresolvableVersionConstraint", condition.Reason)
+		require.Equal(t, 1, len(blueprint.Events))
+		assert.Equal(t, "BlueprintSpecInvalid", blueprint.Events[0].Name())
+	})
+}
+
+func TestBlueprintSpec_AddMissingDependencies(t *testing.T) {
+	t.Run("add missing dependencies as auto added", func(t *testing.T) {
+		blueprint := BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}},
+			Config:             BlueprintConfiguration{CompleteDependencies: true},
+		}
+
+		err := blueprint.AddMissingDependencies([]Dogu{{Name: officialDogu2, Version: &version3213}}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Dogu{
+			{Name: officialDogu1, Version: &version3211},
+			{Name: officialDogu2, Version: &version3213, AutoAdded: true},
+		}, blueprint.EffectiveBlueprint.Dogus)
+		condition := meta.FindStatusCondition(blueprint.Conditions, ConditionDependenciesAutoAdded)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "missing dependencies were added to the effective blueprint: official/dogu2:3.2.1-3", condition.Message)
+		require.Len(t, blueprint.Events, 1)
+		assert.Equal(t, "DependenciesAutoAdded", blueprint.Events[0].Name())
+
+		// the event is only sent if the added dogus change
+		blueprint.Events = nil
+		blueprint.EffectiveBlueprint.Dogus = blueprint.EffectiveBlueprint.Dogus[:1]
+		err = blueprint.AddMissingDependencies([]Dogu{{Name: officialDogu2, Version: &version3213}}, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, blueprint.Events)
+	})
+
+	t.Run("set condition to false if no dependency is missing", func(t *testing.T) {
+		blueprint := BlueprintSpec{Config: BlueprintConfiguration{CompleteDependencies: true}}
+
+		err := blueprint.AddMissingDependencies(nil, nil)
+
+		require.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionFalse(blueprint.Conditions, ConditionDependenciesAutoAdded))
+		assert.Empty(t, blueprint.Events)
+	})
+
+	t.Run("remove condition if dependencies are not completed", func(t *testing.T) {
+		blueprint := BlueprintSpec{Conditions: []Condition{{Type: ConditionDependenciesAutoAdded, Status: metav1.ConditionTrue}}}
+
+		err := blueprint.AddMissingDependencies(nil, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, blueprint.Conditions)
+	})
+
+	t.Run("mark blueprint invalid on error", func(t *testing.T) {
+		blueprint := BlueprintSpec{Config: BlueprintConfiguration{CompleteDependencies: true}}
+
+		err := blueprint.AddMissingDependencies(nil, assert.AnError)
+
+		require.ErrorIs(t, err, assert.AnError)
+		condition := meta.FindStatusCondition(blueprint.Conditions, ConditionValid)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "UnresolvableDependencies", condition.Reason)
+		require.Len(t, blueprint.Events, 1)
+		assert.Equal(t, "BlueprintSpecInvalid", blueprint.Events[0].Name())
+	})
+}
+
+func TestGetAutoAddedDogus(t *testing.T) {
+	t.Run("return dogus of the condition", func(t *testing.T) {
+		blueprint := BlueprintSpec{Config: BlueprintConfiguration{CompleteDependencies: true}}
+		err := blueprint.AddMissingDependencies([]Dogu{{Name: officialDogu2, Version: &version3213}, {Name: officialDogu3, Version: &version3211}}, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []cescommons.QualifiedName{officialDogu2, officialDogu3}, GetAutoAddedDogus(blueprint.Conditions))
+	})
+
+	t.Run("return nothing without added dogus", func(t *testing.T) {
+		blueprint := BlueprintSpec{Config: BlueprintConfiguration{CompleteDependencies: true}}
+		err := blueprint.AddMissingDependencies(nil, nil)
+		require.NoError(t, err)
+
+		assert.Empty(t, GetAutoAddedDogus(blueprint.Conditions))
+		assert.Empty(t, GetAutoAddedDogus(nil))
+	})
+}
+
+func TestBlueprintSpec_GetAutoUpgradeDogus(t *testing.T) {
+	t.Run("return dogus with auto upgrade policy", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Blueprint: Blueprint{Dogus: []Dogu{
+				{Name: officialDogu1, Version: &version3211},
+				{Name: officialDogu2, Version: &version3211},
+			}},
+			Config: BlueprintConfiguration{AutoUpgradePolicies: AutoUpgradePolicies{officialDogu2.SimpleName: AutoUpgradeMinor}},
+		}
+
+		dogus, err := spec.GetAutoUpgradeDogus()
+
+		require.NoError(t, err)
+		require.Len(t, dogus, 1)
+		assert.Equal(t, officialDogu2, dogus[0].Name)
+		assert.Equal(t, AutoUpgradeMinor, dogus[0].AutoUpgradePolicy)
+		assert.Equal(t, ">=3.2.1-1 <4", dogus[0].VersionConstraint.Raw)
+		assert.Empty(t, spec.EffectiveBlueprint.Dogus, "effective blueprint should stay untouched")
+	})
+
+	t.Run("return dogus of all layers", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}},
+			Layers: []BlueprintLayer{
+				{Id: "team-a", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}},
+				{Id: "platform-base", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu2, Version: &version3211}}}},
+			},
+			Config: BlueprintConfiguration{AutoUpgradePolicies: AutoUpgradePolicies{officialDogu2.SimpleName: AutoUpgradePatch}},
+		}
+
+		dogus, err := spec.GetAutoUpgradeDogus()
+
+		require.NoError(t, err)
+		require.Len(t, dogus, 1)
+		assert.Equal(t, officialDogu2, dogus[0].Name)
+		assert.Len(t, spec.Blueprint.Dogus, 1, "blueprint should stay untouched")
+	})
+
+	t.Run("skip invalid layers like the merge", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Id: "team-a",
+			Layers: []BlueprintLayer{
+				{Id: "team-a", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}},
+				{Id: "team-b", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialNexus}}}},
+				{Id: "platform-base", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu2, Version: &version3211}}}},
+			},
+			Config: BlueprintConfiguration{AutoUpgradePolicies: AutoUpgradePolicies{officialDogu2.SimpleName: AutoUpgradePatch}},
+		}
+
+		dogus, err := spec.GetAutoUpgradeDogus()
+
+		require.NoError(t, err)
+		require.Len(t, dogus, 1)
+		assert.Equal(t, officialDogu2, dogus[0].Name)
+	})
+
+	t.Run("fail on forbidden namespace switch", func(t *testing.T) {
+		spec := BlueprintSpec{
+			Blueprint:     Blueprint{Dogus: []Dogu{{Name: officialNexus, Version: &version3211}}},
+			BlueprintMask: BlueprintMask{Dogus: []MaskDogu{{Name: premiumNexus}}},
+		}
+
+		_, err := spec.GetAutoUpgradeDogus()
+
+		assert.ErrorContains(t, err, "changing the dogu namespace is forbidden by default")
+	})
+}
+
+func TestBlueprintSpec_DetermineStateDiff_autoUpgrade(t *testing.T) {
+	constraint, err := AutoUpgradePatch.VersionConstraint(version3211)
+	require.NoError(t, err)
+	spec := BlueprintSpec{
+		Blueprint: Blueprint{Dogus: []Dogu{
+			{Name: officialDogu1, Version: &version3211},
+			{Name: officialDogu2, Version: &version3211},
+		}},
+		EffectiveBlueprint: EffectiveBlueprint{Dogus: []Dogu{
+			{Name: officialDogu1, Version: &version3213, VersionConstraint: &constraint, AutoUpgradePolicy: AutoUpgradePatch},
+			{Name: officialDogu2, Version: &version3211, VersionConstraint: &constraint, AutoUpgradePolicy: AutoUpgradePatch},
+		}},
+	}
+	clusterState := ecosystem.EcosystemState{
+		InstalledDogus: map[cescommons.SimpleName]*ecosystem.DoguInstallation{
+			officialDogu1.SimpleName: {Name: officialDogu1, Version: version3211},
+			officialDogu2.SimpleName: {Name: officialDogu2, Version: version3211},
+		},
+	}
+
+	err = spec.DetermineStateDiff(clusterState, map[common.DoguConfigKey]common.SensitiveDoguConfigValue{}, map[common.DoguConfigKey]common.DoguConfigValue{}, map[common.GlobalConfigKey]common.GlobalConfigValue{}, map[common.GlobalConfigKey]common.GlobalConfigValue{}, false)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, len(spec.Events))
+	assert.Equal(t, DoguAutoUpgradeEvent{
+		DoguName:         officialDogu1.SimpleName,
+		BlueprintVersion: version3211,
+		TargetVersion:    version3213,
+		Policy:           AutoUpgradePatch,
+	}, spec.Events[1])
+}
+
 func TestBlueprintSpec_ShouldBeApplied(t *testing.T) {
 	conditionCompleted := Condition{
 		Type:   ConditionCompleted,
@@ -669,7 +1404,7 @@ func TestBlueprintSpec_HandleHealthResult(t *testing.T) {
 		condition := meta.FindStatusCondition(blueprint.Conditions, ConditionEcosystemHealthy)
 		assert.Equal(t, metav1.ConditionTrue, condition.Status)
 		assert.Equal(t, "Healthy", condition.Reason)
-		assert.Equal(t, "dogu health ignored: false", condition.Message)
+		assert.Equal(t, "dogu health ignored: false, component health ignored: false", condition.Message)
 	})
 
 	t.Run("unhealthy", func(t *testing.T) {
@@ -692,6 +1427,34 @@ func TestBlueprintSpec_HandleHealthResult(t *testing.T) {
 		assert.Contains(t, condition.Message, "1 dogu(s) are unhealthy: ldap")
 	})
 
+	t.Run("unhealthy components", func(t *testing.T) {
+		blueprint := BlueprintSpec{}
+		health := ecosystem.HealthResult{
+			ComponentHealth: ecosystem.ComponentHealthResult{
+				ComponentsByStatus: map[ecosystem.HealthStatus][]string{
+					ecosystem.UnavailableHealthStatus: {"k8s-longhorn"},
+				},
+			},
+		}
+
+		changed := blueprint.HandleHealthResult(health, nil)
+
+		assert.True(t, changed)
+		condition := meta.FindStatusCondition(blueprint.Conditions, ConditionEcosystemHealthy)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "1 component(s) are unhealthy: k8s-longhorn")
+	})
+
+	t.Run("healthy with ignored component health", func(t *testing.T) {
+		blueprint := BlueprintSpec{Config: BlueprintConfiguration{IgnoreComponentHealth: true}}
+
+		changed := blueprint.HandleHealthResult(ecosystem.HealthResult{}, nil)
+
+		assert.True(t, changed)
+		condition := meta.FindStatusCondition(blueprint.Conditions, ConditionEcosystemHealthy)
+		assert.Equal(t, "dogu health ignored: false, component health ignored: true", condition.Message)
+	})
+
 	t.Run("error given, condition Unknown", func(t *testing.T) {
 		blueprint := BlueprintSpec{}
 
@@ -813,6 +1576,59 @@ func TestBlueprintSpec_MarkBlueprintStopped(t *testing.T) {
 	})
 }
 
+func TestBlueprintSpec_GetPreDowngradeBackupName(t *testing.T) {
+	ldapDowngrade := DoguDiff{
+		DoguName:      "ldap",
+		Actual:        DoguDiffState{Version: &version3212},
+		Expected:      DoguDiffState{Version: &version3211},
+		NeededActions: []Action{ActionDowngrade},
+	}
+	casDowngrade := DoguDiff{
+		DoguName:      "cas",
+		Actual:        DoguDiffState{Version: &version3212},
+		Expected:      DoguDiffState{Version: &version3211},
+		NeededActions: []Action{ActionDowngrade},
+	}
+	postgresUpgrade := DoguDiff{
+		DoguName:      "postgresql",
+		Actual:        DoguDiffState{Version: &version3211},
+		Expected:      DoguDiffState{Version: &version3212},
+		NeededActions: []Action{ActionUpgrade},
+	}
+
+	t.Run("should be stable regardless of the diff order and other actions", func(t *testing.T) {
+		spec := &BlueprintSpec{Id: "blueprint", StateDiff: StateDiff{DoguDiffs: DoguDiffs{ldapDowngrade, casDowngrade}}}
+		otherSpec := &BlueprintSpec{Id: "blueprint", StateDiff: StateDiff{DoguDiffs: DoguDiffs{postgresUpgrade, casDowngrade, ldapDowngrade}}}
+
+		name := spec.GetPreDowngradeBackupName()
+
+		assert.Regexp(t, "^blueprint-downgrade-[0-9a-f]{10}$", name)
+		assert.Equal(t, name, otherSpec.GetPreDowngradeBackupName())
+	})
+
+	t.Run("should change if the downgrades change", func(t *testing.T) {
+		spec := &BlueprintSpec{Id: "blueprint", StateDiff: StateDiff{DoguDiffs: DoguDiffs{ldapDowngrade, casDowngrade}}}
+		otherSpec := &BlueprintSpec{Id: "blueprint", StateDiff: StateDiff{DoguDiffs: DoguDiffs{ldapDowngrade}}}
+
+		assert.NotEqual(t, spec.GetPreDowngradeBackupName(), otherSpec.GetPreDowngradeBackupName())
+	})
+}
+
+func TestBlueprintSpec_MarkPreDowngradeBackupStarted(t *testing.T) {
+	// given
+	downgrade := DoguDiff{DoguName: "ldap", NeededActions: []Action{ActionDowngrade}}
+	spec := &BlueprintSpec{
+		StateDiff: StateDiff{
+			DoguDiffs: DoguDiffs{{DoguName: "cas", NeededActions: []Action{ActionUpgrade}}, downgrade},
+		},
+	}
+	// when
+	spec.MarkPreDowngradeBackupStarted("my-backup")
+	// then
+	require.Len(t, spec.Events, 1)
+	assert.Equal(t, PreDowngradeBackupStartedEvent{BackupName: "my-backup", Downgrades: DoguDiffs{downgrade}}, spec.Events[0])
+}
+
 func TestBlueprintSpec_MarkDogusApplied(t *testing.T) {
 	t.Run("should add event if dogus are applied and no error occurred", func(t *testing.T) {
 		// given
@@ -899,3 +1715,242 @@ func TestBlueprintSpec_MarkDogusApplied(t *testing.T) {
 		require.Empty(t, spec.Events)
 	})
 }
+
+func TestBlueprintSpec_MarkRolloutWaveApplied(t *testing.T) {
+	// given
+	spec := &BlueprintSpec{}
+	wave := DoguDiffs{{DoguName: "ldap"}, {DoguName: "postgresql"}}
+	// when
+	changed := spec.MarkRolloutWaveApplied(wave, 2)
+	// then
+	assert.True(t, changed)
+	require.Len(t, spec.Events, 1)
+	assert.Equal(t, RolloutWaveAppliedEvent{Diffs: wave, PendingWaves: 2}, spec.Events[0])
+
+	condition := meta.FindStatusCondition(spec.Conditions, ConditionRolloutWave)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "WaveApplied", condition.Reason)
+	assert.Equal(t, "Applied rollout wave with dogus [ldap postgresql]. 2 more waves pending.", condition.Message)
+}
+
+func TestBlueprintSpec_MarkAllRolloutWavesApplied(t *testing.T) {
+	// given
+	spec := &BlueprintSpec{}
+	// when
+	changed := spec.MarkAllRolloutWavesApplied()
+	changedAgain := spec.MarkAllRolloutWavesApplied()
+	// then
+	assert.True(t, changed)
+	assert.False(t, changedAgain)
+	assert.Empty(t, spec.Events)
+
+	condition := meta.FindStatusCondition(spec.Conditions, ConditionRolloutWave)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "AllWavesApplied", condition.Reason)
+}
+
+func TestBlueprintSpec_MarkPlanWaitingForApproval(t *testing.T) {
+	plan := Plan{BlueprintId: "my-blueprint", Changes: []string{"a", "b"}}
+
+	t.Run("without approved plan", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{}
+		// when
+		changed := spec.MarkPlanWaitingForApproval(plan, "")
+		changedAgain := spec.MarkPlanWaitingForApproval(plan, "")
+		// then
+		assert.True(t, changed)
+		assert.False(t, changedAgain)
+		require.Len(t, spec.Events, 1)
+		assert.Equal(t, PlanApprovalRequiredEvent{Hash: plan.Hash(), ChangeCount: 2}, spec.Events[0])
+
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionPlanApproved)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "WaitingForApproval", condition.Reason)
+		assert.Equal(t, "Plan "+plan.Hash()+" with 2 change(s) needs to be approved.", condition.Message)
+	})
+
+	t.Run("with outdated approved plan", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{}
+		// when
+		changed := spec.MarkPlanWaitingForApproval(plan, "1234")
+		// then
+		assert.True(t, changed)
+		require.Len(t, spec.Events, 1)
+
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionPlanApproved)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "ApprovedPlanOutdated", condition.Reason)
+		assert.Contains(t, condition.Message, "The approved plan 1234 does not contain all changes anymore")
+		assert.Contains(t, condition.Message, "Plan "+plan.Hash()+" with 2 change(s) needs to be approved.")
+	})
+}
+
+func TestBlueprintSpec_MarkPlanApproved(t *testing.T) {
+	// given
+	spec := &BlueprintSpec{Config: BlueprintConfiguration{ApprovedPlanHash: "1234"}}
+	// when
+	changed := spec.MarkPlanApproved()
+	changedAgain := spec.MarkPlanApproved()
+	// then
+	assert.True(t, changed)
+	assert.False(t, changedAgain)
+	assert.Empty(t, spec.Events)
+
+	condition := meta.FindStatusCondition(spec.Conditions, ConditionPlanApproved)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "Approved", condition.Reason)
+	assert.Equal(t, "Plan 1234 is approved.", condition.Message)
+}
+
+func TestBlueprintSpec_MarkRegistryUnreachable(t *testing.T) {
+	// given
+	spec := &BlueprintSpec{}
+	// when
+	changed := spec.MarkRegistryUnreachable(assert.AnError)
+	changedAgain := spec.MarkRegistryUnreachable(assert.AnError)
+	// then
+	assert.True(t, changed)
+	assert.False(t, changedAgain)
+	assert.Equal(t, []Event{RegistryUnreachableEvent{Error: assert.AnError}}, spec.Events)
+
+	condition := meta.FindStatusCondition(spec.Conditions, ConditionRegistryReachable)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Unreachable", condition.Reason)
+	assert.Equal(t, assert.AnError.Error(), condition.Message)
+}
+
+func TestBlueprintSpec_MarkRegistryReachable(t *testing.T) {
+	t.Run("should not add condition if registry was never unreachable", func(t *testing.T) {
+		spec := &BlueprintSpec{}
+
+		changed := spec.MarkRegistryReachable()
+
+		assert.False(t, changed)
+		assert.Empty(t, spec.Conditions)
+	})
+
+	// given
+	spec := &BlueprintSpec{}
+	spec.MarkRegistryUnreachable(assert.AnError)
+	spec.Events = nil
+	// when
+	changed := spec.MarkRegistryReachable()
+	changedAgain := spec.MarkRegistryReachable()
+	// then
+	assert.True(t, changed)
+	assert.False(t, changedAgain)
+	assert.Empty(t, spec.Events)
+
+	condition := meta.FindStatusCondition(spec.Conditions, ConditionRegistryReachable)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "Reachable", condition.Reason)
+}
+
+func TestBlueprintSpec_DetermineStateDiff_pruneDogus(t *testing.T) {
+	clusterState := ecosystem.EcosystemState{
+		InstalledDogus: map[cescommons.SimpleName]*ecosystem.DoguInstallation{
+			"nexus": {Name: officialNexus, Version: version3211, InstalledByBlueprint: "my-blueprint"},
+		},
+	}
+
+	t.Run("uninstall dogus installed by the blueprint in prune mode", func(t *testing.T) {
+		spec := &BlueprintSpec{Id: "my-blueprint", Config: BlueprintConfiguration{PruneDogus: true}}
+
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		require.NoError(t, err)
+		require.Len(t, spec.StateDiff.DoguDiffs, 1)
+		assert.Equal(t, []Action{ActionUninstall}, spec.StateDiff.DoguDiffs[0].NeededActions)
+		assert.Equal(t, DoguDiffReasonPruned, spec.StateDiff.DoguDiffs[0].Reason)
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionDogusPruned)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "DogusPruned", condition.Reason)
+		assert.Equal(t, "uninstall dogus [nexus]: "+DoguDiffReasonPruned, condition.Message)
+	})
+	t.Run("set pruned condition to false if no dogu gets pruned", func(t *testing.T) {
+		spec := &BlueprintSpec{
+			Id:         "my-blueprint",
+			Config:     BlueprintConfiguration{PruneDogus: true},
+			Conditions: []Condition{{Type: ConditionDogusPruned, Status: metav1.ConditionTrue, Reason: "DogusPruned"}},
+		}
+
+		err := spec.DetermineStateDiff(ecosystem.EcosystemState{}, nil, nil, nil, nil, false)
+
+		require.NoError(t, err)
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionDogusPruned)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "NoDogusPruned", condition.Reason)
+	})
+	t.Run("keep dogus without prune mode", func(t *testing.T) {
+		spec := &BlueprintSpec{
+			Id:         "my-blueprint",
+			Conditions: []Condition{{Type: ConditionDogusPruned, Status: metav1.ConditionFalse, Reason: "NoDogusPruned"}},
+		}
+
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		require.NoError(t, err)
+		assert.False(t, spec.StateDiff.DoguDiffs.HasChanges())
+		assert.Nil(t, meta.FindStatusCondition(spec.Conditions, ConditionDogusPruned))
+	})
+	t.Run("neither prune dogus nor remove owned config while a layer is skipped", func(t *testing.T) {
+		// given
+		val := libconfig.Value("value")
+		clusterStateWithConfig := ecosystem.EcosystemState{
+			InstalledDogus: clusterState.InstalledDogus,
+			GlobalConfig:   libconfig.CreateGlobalConfig(libconfig.Entries{"fqdn": val}),
+			ConfigByDogu: map[cescommons.SimpleName]libconfig.DoguConfig{
+				"nexus": libconfig.CreateDoguConfig("nexus", libconfig.Entries{"key1": val}),
+			},
+		}
+		ownership := ConfigOwnership{
+			DoguConfig:   []common.DoguConfigKey{{DoguName: "nexus", Key: "key1"}},
+			GlobalConfig: []common.GlobalConfigKey{"fqdn"},
+		}
+		layers := []BlueprintLayer{
+			{Id: "my-blueprint", Blueprint: Blueprint{Dogus: []Dogu{{Name: officialDogu1, Version: &version3211}}}},
+			// the nexus and its config are set by this layer, which is invalid because of the missing version
+			{Id: "team-b", Priority: 10, Blueprint: Blueprint{Dogus: []Dogu{{Name: officialNexus}}}},
+		}
+		spec := &BlueprintSpec{
+			Id:              "my-blueprint",
+			Layers:          layers,
+			ConfigOwnership: ownership,
+			Config:          BlueprintConfiguration{PruneDogus: true},
+		}
+		require.NoError(t, spec.MergeLayers())
+
+		// when
+		err := spec.DetermineStateDiff(clusterStateWithConfig, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, spec.StateDiff.HasChanges())
+		assert.Equal(t, ownership, spec.ConfigOwnership)
+
+		// when the layer is valid again
+		spec.Layers[1].Blueprint.Dogus[0].Version = &version3211
+		require.NoError(t, spec.MergeLayers())
+		err = spec.DetermineStateDiff(clusterStateWithConfig, nil, nil, nil, nil, false)
+
+		// then the blueprint has to remove its dogus and config itself, here the effective blueprint was not recalculated
+		require.NoError(t, err)
+		require.Len(t, spec.StateDiff.DoguDiffs, 1)
+		assert.Equal(t, DoguDiffReasonPruned, spec.StateDiff.DoguDiffs[0].Reason)
+		require.Len(t, spec.StateDiff.DoguConfigDiffs["nexus"], 1)
+		assert.Equal(t, ConfigActionRemove, spec.StateDiff.DoguConfigDiffs["nexus"][0].NeededAction)
+		require.Len(t, spec.StateDiff.GlobalConfigDiffs, 1)
+		assert.Equal(t, ConfigActionRemove, spec.StateDiff.GlobalConfigDiffs[0].NeededAction)
+	})
+}
@@ -3,6 +3,8 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
@@ -164,6 +166,18 @@ func (config Config) getDogusWithChangedConfigBySenisitivity(isSensitive bool) [
 	return dogus
 }
 
+// deepCopy copies the config entries, so that entries of the copy can be changed without changing this config.
+func (config Config) deepCopy() Config {
+	var dogus DoguConfig
+	if config.Dogus != nil {
+		dogus = make(DoguConfig, len(config.Dogus))
+		for doguName, entries := range config.Dogus {
+			dogus[doguName] = slices.Clone(entries)
+		}
+	}
+	return Config{Dogus: dogus, Global: slices.Clone(config.Global)}
+}
+
 func (config Config) IsEmpty() bool {
 	return len(config.Dogus) == 0 && len(config.Global) == 0
 }
@@ -218,6 +232,9 @@ func (config ConfigEntry) validate() error {
 	}
 
 	if config.Absent {
+		if strings.Contains(string(config.Key), "*") && !isValidConfigKeyPattern(config.Key) {
+			errs = append(errs, fmt.Errorf("key pattern %q is invalid: only a trailing %q after a key prefix is allowed", config.Key, configKeyPatternSuffix))
+		}
 		if config.Value != nil || config.SecretRef != nil {
 			errs = append(errs, fmt.Errorf("absent entries cannot have value or secretRef"))
 		}
@@ -239,6 +256,13 @@ func (config ConfigEntry) validate() error {
 	return errors.Join(errs...)
 }
 
+// isValidConfigKeyPattern checks if the key has the form prefix/* with a prefix without further wildcards,
+// so that a pattern cannot remove all config of a dogu.
+func isValidConfigKeyPattern(key libconfig.Key) bool {
+	prefix, found := strings.CutSuffix(string(key), configKeyPatternSuffix)
+	return found && prefix != "" && !strings.Contains(prefix, "*") && !strings.HasSuffix(prefix, "/")
+}
+
 func (config GlobalConfigEntries) validate() error {
 	var allErrs error
 	for _, entry := range config {
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"slices"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-registry-lib/config"
+)
+
+// ConfigOwnership contains the config keys which were set by applying a blueprint, like the last applied configuration of kubectl.
+// Owned keys which disappear from the blueprint get removed from the ecosystem.
+// Keys which were set by dogus or admins are never owned and therefore left alone.
+type ConfigOwnership struct {
+	DoguConfig          []common.DoguConfigKey
+	SensitiveDoguConfig []common.DoguConfigKey
+	GlobalConfig        []common.GlobalConfigKey
+}
+
+// NewConfigOwnership returns the ownership of all config keys which are set by the given config.
+func NewConfigOwnership(blueprintConfig Config) ConfigOwnership {
+	var ownership ConfigOwnership
+	for doguName, entries := range blueprintConfig.Dogus {
+		for _, entry := range entries {
+			if entry.Absent {
+				continue
+			}
+			key := common.DoguConfigKey{DoguName: doguName, Key: entry.Key}
+			if entry.Sensitive {
+				ownership.SensitiveDoguConfig = append(ownership.SensitiveDoguConfig, key)
+			} else {
+				ownership.DoguConfig = append(ownership.DoguConfig, key)
+			}
+		}
+	}
+	for _, entry := range blueprintConfig.Global {
+		if !entry.Absent {
+			ownership.GlobalConfig = append(ownership.GlobalConfig, entry.Key)
+		}
+	}
+	ownership.sort()
+	return ownership
+}
+
+// GetAppliedConfigOwnership returns the ownership after the state diff of the blueprint was applied.
+// The blueprint only takes the ownership of keys which it creates or updates. Keys which already had the expected
+// value may be set by an admin or a dogu, so they are not owned and never removed when they disappear from the blueprint.
+// Removed keys and owned keys which vanished from the blueprint and the ecosystem are not owned anymore.
+// Keys with EnforcementModeReport are never applied and therefore not owned, so that they are never removed either.
+func (spec *BlueprintSpec) GetAppliedConfigOwnership() ConfigOwnership {
+	modes := spec.Config.EnforcementModes
+	isReported := func(key common.DoguConfigKey) bool {
+		return modes.doguConfigMode(key) == EnforcementModeReport
+	}
+
+	owned := spec.ConfigOwnership.without(spec.vanishedOwnedConfig)
+	ownership := ConfigOwnership{
+		DoguConfig:          applyDoguConfigDiffsToOwnership(owned.DoguConfig, spec.StateDiff.DoguConfigDiffs),
+		SensitiveDoguConfig: applyDoguConfigDiffsToOwnership(owned.SensitiveDoguConfig, spec.StateDiff.SensitiveDoguConfigDiffs),
+		GlobalConfig:        owned.GlobalConfig,
+	}
+	for _, diff := range spec.StateDiff.GlobalConfigDiffs {
+		ownership.GlobalConfig = applyActionToOwnership(ownership.GlobalConfig, diff.Key, diff.NeededAction)
+	}
+
+	ownership.DoguConfig = slices.DeleteFunc(ownership.DoguConfig, isReported)
+	ownership.SensitiveDoguConfig = slices.DeleteFunc(ownership.SensitiveDoguConfig, isReported)
+	ownership.GlobalConfig = slices.DeleteFunc(ownership.GlobalConfig, func(key common.GlobalConfigKey) bool {
+		return modes.globalConfigMode(key) == EnforcementModeReport
+	})
+	ownership.sort()
+	return ownership
+}
+
+func applyDoguConfigDiffsToOwnership(owned []common.DoguConfigKey, diffsByDogu map[cescommons.SimpleName]DoguConfigDiffs) []common.DoguConfigKey {
+	owned = slices.Clone(owned)
+	for _, diffs := range diffsByDogu {
+		for _, diff := range diffs {
+			owned = applyActionToOwnership(owned, diff.Key, diff.NeededAction)
+		}
+	}
+	return owned
+}
+
+// applyActionToOwnership owns the key if it gets set and disowns it if it gets removed.
+func applyActionToOwnership[K comparable](owned []K, key K, action ConfigAction) []K {
+	switch action {
+	case ConfigActionSet:
+		if !slices.Contains(owned, key) {
+			return append(owned, key)
+		}
+	case ConfigActionRemove:
+		return slices.DeleteFunc(owned, func(ownedKey K) bool { return ownedKey == key })
+	}
+	return owned
+}
+
+func (ownership *ConfigOwnership) sort() {
+	slices.SortFunc(ownership.DoguConfig, compareDoguConfigKeys)
+	slices.SortFunc(ownership.SensitiveDoguConfig, compareDoguConfigKeys)
+	slices.Sort(ownership.GlobalConfig)
+}
+
+// without returns a copy of the ownership without the keys of the other ownership.
+func (ownership ConfigOwnership) without(other ConfigOwnership) ConfigOwnership {
+	return ConfigOwnership{
+		DoguConfig: slices.DeleteFunc(slices.Clone(ownership.DoguConfig), func(key common.DoguConfigKey) bool {
+			return slices.Contains(other.DoguConfig, key)
+		}),
+		SensitiveDoguConfig: slices.DeleteFunc(slices.Clone(ownership.SensitiveDoguConfig), func(key common.DoguConfigKey) bool {
+			return slices.Contains(other.SensitiveDoguConfig, key)
+		}),
+		GlobalConfig: slices.DeleteFunc(slices.Clone(ownership.GlobalConfig), func(key common.GlobalConfigKey) bool {
+			return slices.Contains(other.GlobalConfig, key)
+		}),
+	}
+}
+
+// Equal checks if both ownerships contain the same keys.
+func (ownership ConfigOwnership) Equal(other ConfigOwnership) bool {
+	return slices.Equal(ownership.DoguConfig, other.DoguConfig) &&
+		slices.Equal(ownership.SensitiveDoguConfig, other.SensitiveDoguConfig) &&
+		slices.Equal(ownership.GlobalConfig, other.GlobalConfig)
+}
+
+// GetDogusWithOwnedConfig returns all dogus with owned normal config.
+func (ownership ConfigOwnership) GetDogusWithOwnedConfig() []cescommons.SimpleName {
+	return getDogusOfKeys(ownership.DoguConfig)
+}
+
+// GetDogusWithOwnedSensitiveConfig returns all dogus with owned sensitive config.
+func (ownership ConfigOwnership) GetDogusWithOwnedSensitiveConfig() []cescommons.SimpleName {
+	return getDogusOfKeys(ownership.SensitiveDoguConfig)
+}
+
+func getDogusOfKeys(keys []common.DoguConfigKey) []cescommons.SimpleName {
+	var dogus []cescommons.SimpleName
+	for _, key := range keys {
+		if !slices.Contains(dogus, key.DoguName) {
+			dogus = append(dogus, key.DoguName)
+		}
+	}
+	return dogus
+}
+
+// determineDisownedConfigDiffs adds a removal to the state diff for every owned key, which is not in the blueprint anymore.
+// Owned keys which are neither in the blueprint nor in the ecosystem are disowned with the next apply, as there is nothing to remove.
+// The ConfigOwnership itself stays unchanged, as it is only saved after the apply.
+func (spec *BlueprintSpec) determineDisownedConfigDiffs(ecosystemState ecosystem.EcosystemState) {
+	effectiveConfig := spec.EffectiveBlueprint.Config
+	var vanished ConfigOwnership
+
+	removeDoguConfig := func(keys []common.DoguConfigKey, actualConfig map[cescommons.SimpleName]config.DoguConfig, isSensitive bool, diffsByDogu *map[cescommons.SimpleName]DoguConfigDiffs) []common.DoguConfigKey {
+		var vanishedKeys []common.DoguConfigKey
+		for _, key := range keys {
+			if isConfigKeyInBlueprint(ConfigEntries(effectiveConfig.Dogus[key.DoguName]), key.Key, isSensitive) {
+				continue
+			}
+			actualValue, exists := actualConfig[key.DoguName].Get(key.Key)
+			if !exists {
+				vanishedKeys = append(vanishedKeys, key)
+				continue
+			}
+			if *diffsByDogu == nil {
+				*diffsByDogu = map[cescommons.SimpleName]DoguConfigDiffs{}
+			}
+			(*diffsByDogu)[key.DoguName] = append((*diffsByDogu)[key.DoguName], newDoguConfigEntryDiff(key, &actualValue, true, nil, false))
+		}
+		return vanishedKeys
+	}
+	vanished.DoguConfig = removeDoguConfig(spec.ConfigOwnership.DoguConfig, ecosystemState.ConfigByDogu, false, &spec.StateDiff.DoguConfigDiffs)
+	vanished.SensitiveDoguConfig = removeDoguConfig(spec.ConfigOwnership.SensitiveDoguConfig, ecosystemState.SensitiveConfigByDogu, true, &spec.StateDiff.SensitiveDoguConfigDiffs)
+
+	for _, key := range spec.ConfigOwnership.GlobalConfig {
+		if isConfigKeyInBlueprint(ConfigEntries(effectiveConfig.Global), key, false) {
+			continue
+		}
+		actualValue, exists := ecosystemState.GlobalConfig.Get(key)
+		if !exists {
+			vanished.GlobalConfig = append(vanished.GlobalConfig, key)
+			continue
+		}
+		spec.StateDiff.GlobalConfigDiffs = append(spec.StateDiff.GlobalConfigDiffs, newGlobalConfigEntryDiff(key, &actualValue, true, nil, false))
+	}
+
+	spec.vanishedOwnedConfig = vanished
+}
+
+// isConfigKeyInBlueprint checks if the key has an entry with the same sensitivity in the blueprint config
+// or is removed by an absent entry with a key pattern.
+func isConfigKeyInBlueprint(entries ConfigEntries, key config.Key, isSensitive bool) bool {
+	return slices.ContainsFunc(entries, func(entry ConfigEntry) bool {
+		if entry.Sensitive != isSensitive {
+			return false
+		}
+		return entry.Key == key || (entry.isKeyPattern() && matchesConfigKeyPattern(entry.Key, key))
+	})
+}
@@ -0,0 +1,249 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfigOwnership(t *testing.T) {
+	// given
+	blueprintConfig := Config{
+		Dogus: DoguConfig{
+			dogu2: {{Key: "key1", Value: &val1}},
+			dogu1: {
+				{Key: "key2", Value: &val1},
+				{Key: "key1", Value: &val1},
+				{Key: "key3", Absent: true},
+				{Key: "secret", Sensitive: true, Value: &val2},
+			},
+		},
+		Global: GlobalConfigEntries{
+			{Key: "fqdn", Value: &val1},
+			{Key: "admin_group", Value: &val2},
+			{Key: "removed", Absent: true},
+		},
+	}
+
+	// when
+	ownership := NewConfigOwnership(blueprintConfig)
+
+	// then
+	assert.Equal(t, ConfigOwnership{
+		DoguConfig:          []common.DoguConfigKey{dogu1Key1, dogu1Key2, {DoguName: dogu2, Key: "key1"}},
+		SensitiveDoguConfig: []common.DoguConfigKey{{DoguName: dogu1, Key: "secret"}},
+		GlobalConfig:        []common.GlobalConfigKey{"admin_group", "fqdn"},
+	}, ownership)
+	assert.Equal(t, []cescommons.SimpleName{dogu1, dogu2}, ownership.GetDogusWithOwnedConfig())
+	assert.Equal(t, []cescommons.SimpleName{dogu1}, ownership.GetDogusWithOwnedSensitiveConfig())
+	assert.True(t, ownership.Equal(NewConfigOwnership(blueprintConfig)))
+	assert.False(t, ownership.Equal(ConfigOwnership{}))
+}
+
+func TestBlueprintSpec_GetAppliedConfigOwnership(t *testing.T) {
+	dogu1Key3 := common.DoguConfigKey{DoguName: dogu1, Key: "key3"}
+	dogu1Secret := common.DoguConfigKey{DoguName: dogu1, Key: "secret"}
+	dogu2Key1 := common.DoguConfigKey{DoguName: dogu2, Key: "key1"}
+	diffWithAction := func(key common.DoguConfigKey, action ConfigAction) DoguConfigEntryDiff {
+		return DoguConfigEntryDiff{Key: key, NeededAction: action}
+	}
+
+	t.Run("own created and updated keys and disown removed keys", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{
+			ConfigOwnership: ConfigOwnership{
+				DoguConfig:   []common.DoguConfigKey{dogu1Key3, dogu1Key2},
+				GlobalConfig: []common.GlobalConfigKey{"removed"},
+			},
+			StateDiff: StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+					dogu1: {
+						diffWithAction(dogu1Key1, ConfigActionSet),
+						diffWithAction(dogu1Key2, ConfigActionRemove),
+						// already owned keys stay owned
+						diffWithAction(dogu1Key3, ConfigActionNone),
+					},
+				},
+				SensitiveDoguConfigDiffs: map[cescommons.SimpleName]SensitiveDoguConfigDiffs{
+					dogu1: {diffWithAction(dogu1Secret, ConfigActionSet)},
+				},
+				GlobalConfigDiffs: GlobalConfigDiffs{
+					{Key: "fqdn", NeededAction: ConfigActionSet},
+					{Key: "removed", NeededAction: ConfigActionRemove},
+				},
+			},
+		}
+
+		// when
+		ownership := spec.GetAppliedConfigOwnership()
+
+		// then
+		assert.Equal(t, ConfigOwnership{
+			DoguConfig:          []common.DoguConfigKey{dogu1Key1, dogu1Key3},
+			SensitiveDoguConfig: []common.DoguConfigKey{dogu1Secret},
+			GlobalConfig:        []common.GlobalConfigKey{"fqdn"},
+		}, ownership)
+	})
+	t.Run("do not own keys which already had the expected value", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{
+			StateDiff: StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+					dogu1: {diffWithAction(dogu1Key1, ConfigActionNone)},
+				},
+				SensitiveDoguConfigDiffs: map[cescommons.SimpleName]SensitiveDoguConfigDiffs{
+					dogu1: {diffWithAction(dogu1Secret, ConfigActionNone)},
+				},
+				GlobalConfigDiffs: GlobalConfigDiffs{{Key: "fqdn", NeededAction: ConfigActionNone}},
+			},
+		}
+
+		// when
+		ownership := spec.GetAppliedConfigOwnership()
+
+		// then
+		assert.True(t, ownership.Equal(ConfigOwnership{}))
+	})
+	t.Run("do not own keys in report mode", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{
+			Config: BlueprintConfiguration{
+				EnforcementModes: EnforcementModes{
+					Dogus:        map[cescommons.SimpleName]EnforcementMode{dogu2: EnforcementModeReport},
+					DoguConfig:   map[common.DoguConfigKey]EnforcementMode{dogu1Key2: EnforcementModeReport},
+					GlobalConfig: map[common.GlobalConfigKey]EnforcementMode{"fqdn": EnforcementModeReport, "admin_group": EnforcementModeEnforce},
+				},
+			},
+			StateDiff: StateDiff{
+				DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+					dogu1: {diffWithAction(dogu1Key1, ConfigActionSet), diffWithAction(dogu1Key2, ConfigActionSet)},
+					dogu2: {diffWithAction(dogu2Key1, ConfigActionSet)},
+				},
+				GlobalConfigDiffs: GlobalConfigDiffs{
+					{Key: "fqdn", NeededAction: ConfigActionSet},
+					{Key: "admin_group", NeededAction: ConfigActionSet},
+				},
+			},
+		}
+
+		// when
+		ownership := spec.GetAppliedConfigOwnership()
+
+		// then
+		assert.Equal(t, ConfigOwnership{
+			DoguConfig:   []common.DoguConfigKey{dogu1Key1},
+			GlobalConfig: []common.GlobalConfigKey{"admin_group"},
+		}, ownership)
+	})
+}
+
+func TestBlueprintSpec_DetermineStateDiff_disownedConfig(t *testing.T) {
+	clusterState := ecosystem.EcosystemState{
+		GlobalConfig: config.CreateGlobalConfig(map[config.Key]config.Value{"fqdn": val1, "admin_group": val1}),
+		ConfigByDogu: map[cescommons.SimpleName]config.DoguConfig{
+			dogu1: config.CreateDoguConfig(dogu1, map[config.Key]config.Value{"key1": val1, "key2": val1, "cache/a": val1}),
+		},
+		SensitiveConfigByDogu: map[cescommons.SimpleName]config.DoguConfig{
+			dogu1: config.CreateDoguConfig(dogu1, map[config.Key]config.Value{"secret": val1}),
+		},
+	}
+
+	t.Run("remove owned config which is not in the blueprint anymore", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{Config: Config{
+				Dogus: DoguConfig{dogu1: {{Key: "key1", Value: &val1}}},
+			}},
+			ConfigOwnership: ConfigOwnership{
+				DoguConfig:          []common.DoguConfigKey{dogu1Key1, dogu1Key2},
+				SensitiveDoguConfig: []common.DoguConfigKey{{DoguName: dogu1, Key: "secret"}},
+				GlobalConfig:        []common.GlobalConfigKey{"fqdn"},
+			},
+		}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, spec.StateDiff.DoguConfigDiffs[dogu1], 1)
+		assert.Equal(t, dogu1Key2, spec.StateDiff.DoguConfigDiffs[dogu1][0].Key)
+		assert.Equal(t, ConfigActionRemove, spec.StateDiff.DoguConfigDiffs[dogu1][0].NeededAction)
+		require.Len(t, spec.StateDiff.SensitiveDoguConfigDiffs[dogu1], 1)
+		assert.Equal(t, ConfigActionRemove, spec.StateDiff.SensitiveDoguConfigDiffs[dogu1][0].NeededAction)
+		require.Len(t, spec.StateDiff.GlobalConfigDiffs, 1)
+		assert.Equal(t, common.GlobalConfigKey("fqdn"), spec.StateDiff.GlobalConfigDiffs[0].Key)
+		assert.Equal(t, ConfigActionRemove, spec.StateDiff.GlobalConfigDiffs[0].NeededAction)
+		// the keys stay owned until they are removed
+		assert.Equal(t, []common.DoguConfigKey{dogu1Key1, dogu1Key2}, spec.ConfigOwnership.DoguConfig)
+	})
+	t.Run("do not remove config which is not owned", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, spec.StateDiff.HasChanges())
+	})
+	t.Run("keep owned config which is still in the blueprint", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{Config: Config{
+				Dogus: DoguConfig{dogu1: {{Key: "secret", Sensitive: true, Value: &val1}}},
+			}},
+			ConfigOwnership: ConfigOwnership{SensitiveDoguConfig: []common.DoguConfigKey{{DoguName: dogu1, Key: "secret"}}},
+		}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, spec.StateDiff.HasChanges())
+		assert.Len(t, spec.ConfigOwnership.SensitiveDoguConfig, 1)
+	})
+	t.Run("remove owned config only once if it matches an absent key pattern", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{Config: Config{
+				Dogus: DoguConfig{dogu1: {{Key: "cache/*", Absent: true}}},
+			}},
+			ConfigOwnership: ConfigOwnership{DoguConfig: []common.DoguConfigKey{{DoguName: dogu1, Key: "cache/a"}}},
+		}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, spec.StateDiff.DoguConfigDiffs[dogu1], 1)
+		assert.Equal(t, ConfigActionRemove, spec.StateDiff.DoguConfigDiffs[dogu1][0].NeededAction)
+	})
+	t.Run("disown config which was already removed", func(t *testing.T) {
+		// given
+		spec := &BlueprintSpec{
+			ConfigOwnership: ConfigOwnership{
+				DoguConfig:   []common.DoguConfigKey{dogu1Key3},
+				GlobalConfig: []common.GlobalConfigKey{"removed"},
+			},
+		}
+
+		// when
+		err := spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, spec.StateDiff.HasChanges())
+		// the ownership is only changed by the apply
+		assert.Len(t, spec.ConfigOwnership.DoguConfig, 1)
+		assert.True(t, spec.GetAppliedConfigOwnership().Equal(ConfigOwnership{}))
+	})
+}
@@ -0,0 +1,337 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-registry-lib/config"
+)
+
+const (
+	templateSourceGlobal = "Global"
+	templateSourceDogus  = "Dogus"
+	templateSourceParams = "Params"
+)
+
+const (
+	// escapedTemplateStart stands for literal opening braces in config values with templates.
+	escapedTemplateStart = `\{{`
+	// escapedTemplateEnd stands for literal closing braces in config values with templates.
+	escapedTemplateEnd = `\}}`
+)
+
+// configTemplatePattern matches template expressions in config values like {{ .Global.fqdn }}
+// and the escape sequences for literal braces.
+var configTemplatePattern = regexp.MustCompile(`\\\{\{|\\\}\}|\{\{(.*?)\}\}`)
+
+// ConfigTemplatesEnabled checks if the config values of the blueprint are templates.
+// Templates are opt-in by declaring parameters, even an empty set of them,
+// so that config values with literal braces stay unchanged in all other blueprints.
+func (config BlueprintConfiguration) ConfigTemplatesEnabled() bool {
+	return config.Parameters != nil
+}
+
+func isEscapedTemplateBraces(match string) bool {
+	return match == escapedTemplateStart || match == escapedTemplateEnd
+}
+
+// configTemplateRef is a reference from a template expression to a blueprint parameter, a global config key or
+// the config key of a dogu.
+type configTemplateRef struct {
+	source string
+	dogu   cescommons.SimpleName
+	key    string
+}
+
+func (ref configTemplateRef) String() string {
+	if ref.source == templateSourceDogus {
+		return fmt.Sprintf(".%s.%s.%s", ref.source, ref.dogu, ref.key)
+	}
+	return fmt.Sprintf(".%s.%s", ref.source, ref.key)
+}
+
+// parseConfigTemplateRef parses template expressions in the form of
+// .Global.<key>, .Dogus.<dogu>.<key> or .Params.<name>.
+func parseConfigTemplateRef(expression string) (configTemplateRef, error) {
+	expression = strings.TrimSpace(expression)
+	source, path, _ := strings.Cut(strings.TrimPrefix(expression, "."), ".")
+	if !strings.HasPrefix(expression, ".") || path == "" {
+		return configTemplateRef{}, fmt.Errorf("template expression %q must look like .Global.<key>, .Dogus.<dogu>.<key> or .Params.<name>", expression)
+	}
+	switch source {
+	case templateSourceGlobal, templateSourceParams:
+		return configTemplateRef{source: source, key: path}, nil
+	case templateSourceDogus:
+		dogu, key, _ := strings.Cut(path, ".")
+		if dogu == "" || key == "" {
+			return configTemplateRef{}, fmt.Errorf("template expression %q must look like .Dogus.<dogu>.<key>", expression)
+		}
+		return configTemplateRef{source: source, dogu: cescommons.SimpleName(dogu), key: key}, nil
+	default:
+		return configTemplateRef{}, fmt.Errorf("template expression %q references the unknown source %q, use Global, Dogus or Params", expression, source)
+	}
+}
+
+// parseConfigTemplate returns all references in the given config value.
+func parseConfigTemplate(value string) ([]configTemplateRef, error) {
+	var refs []configTemplateRef
+	var errs []error
+	for _, match := range configTemplatePattern.FindAllStringSubmatch(value, -1) {
+		if isEscapedTemplateBraces(match[0]) {
+			continue
+		}
+		ref, err := parseConfigTemplateRef(match[1])
+		errs = append(errs, err)
+		if err == nil {
+			refs = append(refs, ref)
+		}
+	}
+	remainder := configTemplatePattern.ReplaceAllString(value, "")
+	if strings.Contains(remainder, "{{") || strings.Contains(remainder, "}}") {
+		errs = append(errs, fmt.Errorf("template in value %q is not closed correctly, escape literal braces with %s and %s", value, escapedTemplateStart, escapedTemplateEnd))
+	}
+	return refs, errors.Join(errs...)
+}
+
+// validateConfigTemplates checks the syntax of all templates in the config values and
+// that all referenced parameters are declared.
+// Without declared parameters, config values are no templates, so there is nothing to validate.
+func validateConfigTemplates(blueprintConfig Config, parameters map[string]string) error {
+	if parameters == nil {
+		return nil
+	}
+	var errs []error
+	validateEntries := func(description string, entries ConfigEntries) {
+		for _, entry := range entries {
+			if entry.Value == nil {
+				continue
+			}
+			refs, err := parseConfigTemplate(string(*entry.Value))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %q: %w", description, entry.Key, err))
+			}
+			for _, ref := range refs {
+				if _, declared := parameters[ref.key]; ref.source == templateSourceParams && !declared {
+					errs = append(errs, fmt.Errorf("%s %q references the undeclared parameter %q", description, entry.Key, ref.key))
+				}
+			}
+		}
+	}
+
+	validateEntries("global config", ConfigEntries(blueprintConfig.Global))
+	for _, doguName := range slices.Sorted(maps.Keys(blueprintConfig.Dogus)) {
+		validateEntries(fmt.Sprintf("config of dogu %q with key", doguName), ConfigEntries(blueprintConfig.Dogus[doguName]))
+	}
+	return errors.Join(errs...)
+}
+
+// HasTemplate checks if the value of the config entry contains a template or escaped braces, which get resolved with
+// the state diff. This is only relevant if config templates are enabled, see BlueprintConfiguration.ConfigTemplatesEnabled.
+func (config ConfigEntry) HasTemplate() bool {
+	return config.Value != nil && configTemplatePattern.MatchString(string(*config.Value))
+}
+
+// GetDogusReferencedByTemplates returns all dogus, whose config is referenced in templates of config values.
+func (config Config) GetDogusReferencedByTemplates() []cescommons.SimpleName {
+	var dogus []cescommons.SimpleName
+	collect := func(entries ConfigEntries) {
+		for _, entry := range entries {
+			if entry.Value == nil {
+				continue
+			}
+			// invalid templates are rejected by the static validation
+			refs, _ := parseConfigTemplate(string(*entry.Value))
+			for _, ref := range refs {
+				if ref.source == templateSourceDogus && !slices.Contains(dogus, ref.dogu) {
+					dogus = append(dogus, ref.dogu)
+				}
+			}
+		}
+	}
+	collect(ConfigEntries(config.Global))
+	for _, entries := range config.Dogus {
+		collect(ConfigEntries(entries))
+	}
+	slices.Sort(dogus)
+	return dogus
+}
+
+// configTemplateResolver resolves templates in config values.
+// References to config keys are resolved with the value in the blueprint first and the actual value in the ecosystem second.
+type configTemplateResolver struct {
+	parameters             map[string]string
+	blueprintEntries       map[configTemplateRef]*ConfigEntry
+	ecosystemState         ecosystem.EcosystemState
+	referencedDoguConfig   map[common.DoguConfigKey]common.DoguConfigValue
+	referencedGlobalConfig map[common.GlobalConfigKey]common.GlobalConfigValue
+	resolved               map[configTemplateRef]string
+	// resolving contains the references which are currently resolved to detect cycles.
+	resolving []configTemplateRef
+}
+
+// resolveConfigTemplates returns a copy of the blueprint config, in which all templates in the config values are
+// replaced with the referenced values and escaped braces with literal ones. The given config stays unchanged.
+// Without declared parameters, config values are no templates and the config is returned as it is.
+// returns an error if a template is invalid, contains cycles or references a value which does not exist.
+func resolveConfigTemplates(
+	blueprintConfig Config,
+	parameters map[string]string,
+	ecosystemState ecosystem.EcosystemState,
+	referencedDoguConfig map[common.DoguConfigKey]common.DoguConfigValue,
+	referencedGlobalConfig map[common.GlobalConfigKey]common.GlobalConfigValue,
+) (Config, error) {
+	if parameters == nil {
+		return blueprintConfig, nil
+	}
+	// the resolver writes the resolved values into the entries, which must not change the effective blueprint
+	blueprintConfig = blueprintConfig.deepCopy()
+	resolver := &configTemplateResolver{
+		parameters:             parameters,
+		blueprintEntries:       map[configTemplateRef]*ConfigEntry{},
+		ecosystemState:         ecosystemState,
+		referencedDoguConfig:   referencedDoguConfig,
+		referencedGlobalConfig: referencedGlobalConfig,
+		resolved:               map[configTemplateRef]string{},
+	}
+	for i, entry := range blueprintConfig.Global {
+		resolver.blueprintEntries[configTemplateRef{source: templateSourceGlobal, key: string(entry.Key)}] = &blueprintConfig.Global[i]
+	}
+	for doguName, entries := range blueprintConfig.Dogus {
+		for i, entry := range entries {
+			resolver.blueprintEntries[configTemplateRef{source: templateSourceDogus, dogu: doguName, key: string(entry.Key)}] = &entries[i]
+		}
+	}
+
+	// sort to get the same errors on every run
+	refs := slices.SortedFunc(maps.Keys(resolver.blueprintEntries), func(a, b configTemplateRef) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	var errs []error
+	for _, ref := range refs {
+		if !resolver.blueprintEntries[ref].HasTemplate() {
+			continue
+		}
+		_, err := resolver.resolveRef(ref)
+		errs = append(errs, err)
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		return Config{}, err
+	}
+
+	// set the values after resolving all templates, so that resolved values are not parsed again
+	for ref, value := range resolver.resolved {
+		resolvedValue := config.Value(value)
+		resolver.blueprintEntries[ref].Value = &resolvedValue
+	}
+	return blueprintConfig, nil
+}
+
+func (resolver *configTemplateResolver) resolveTemplate(value string) (string, error) {
+	_, err := parseConfigTemplate(value)
+	if err != nil {
+		return "", err
+	}
+	var errs []error
+	resolvedValue := configTemplatePattern.ReplaceAllStringFunc(value, func(expression string) string {
+		switch expression {
+		case escapedTemplateStart:
+			return "{{"
+		case escapedTemplateEnd:
+			return "}}"
+		}
+		// the syntax was checked before
+		ref, _ := parseConfigTemplateRef(configTemplatePattern.FindStringSubmatch(expression)[1])
+		referencedValue, err := resolver.resolveRef(ref)
+		errs = append(errs, err)
+		return referencedValue
+	})
+	return resolvedValue, errors.Join(errs...)
+}
+
+func (resolver *configTemplateResolver) resolveRef(ref configTemplateRef) (string, error) {
+	if value, resolved := resolver.resolved[ref]; resolved {
+		return value, nil
+	}
+	if index := slices.Index(resolver.resolving, ref); index >= 0 {
+		cycle := append(slices.Clone(resolver.resolving[index:]), ref)
+		return "", fmt.Errorf("config templates contain a cycle: %s", joinRefs(cycle))
+	}
+
+	if ref.source == templateSourceParams {
+		value, declared := resolver.parameters[ref.key]
+		if !declared {
+			return "", fmt.Errorf("parameter %q is not declared", ref.key)
+		}
+		return value, nil
+	}
+
+	entry, inBlueprint := resolver.blueprintEntries[ref]
+	if !inBlueprint {
+		return resolver.getActualValue(ref)
+	}
+	if entry.Absent {
+		return "", fmt.Errorf("cannot reference %s as it is absent in the blueprint", ref)
+	}
+	if entry.Sensitive || entry.SecretRef != nil {
+		return "", fmt.Errorf("cannot reference %s as sensitive config must not be copied into other config", ref)
+	}
+	if entry.ConfigRef != nil {
+		return resolver.getReferencedValue(ref)
+	}
+	if entry.Value == nil {
+		return "", fmt.Errorf("cannot reference %s as it has no value in the blueprint", ref)
+	}
+
+	resolver.resolving = append(resolver.resolving, ref)
+	value, err := resolver.resolveTemplate(string(*entry.Value))
+	resolver.resolving = resolver.resolving[:len(resolver.resolving)-1]
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve template of %s: %w", ref, err)
+	}
+	resolver.resolved[ref] = value
+	return value, nil
+}
+
+func (resolver *configTemplateResolver) getReferencedValue(ref configTemplateRef) (string, error) {
+	var value config.Value
+	var exists bool
+	if ref.source == templateSourceGlobal {
+		value, exists = resolver.referencedGlobalConfig[common.GlobalConfigKey(ref.key)]
+	} else {
+		value, exists = resolver.referencedDoguConfig[common.DoguConfigKey{DoguName: ref.dogu, Key: config.Key(ref.key)}]
+	}
+	if !exists {
+		return "", fmt.Errorf("cannot reference %s as its config map reference could not be loaded", ref)
+	}
+	return string(value), nil
+}
+
+func (resolver *configTemplateResolver) getActualValue(ref configTemplateRef) (string, error) {
+	var value config.Value
+	var exists bool
+	if ref.source == templateSourceGlobal {
+		value, exists = resolver.ecosystemState.GlobalConfig.Get(config.Key(ref.key))
+	} else {
+		value, exists = resolver.ecosystemState.ConfigByDogu[ref.dogu].Get(config.Key(ref.key))
+	}
+	if !exists {
+		return "", fmt.Errorf("cannot reference %s as it is neither in the blueprint nor in the ecosystem", ref)
+	}
+	return string(value), nil
+}
+
+func joinRefs(refs []configTemplateRef) string {
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.String())
+	}
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func templateValue(value string) *config.Value {
+	result := config.Value(value)
+	return &result
+}
+
+func Test_parseConfigTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []configTemplateRef
+		wantErr string
+	}{
+		{name: "no template", value: "plain value"},
+		{
+			name:  "global config and parameter",
+			value: "https://{{ .Global.fqdn }}/{{.Params.path}}",
+			want: []configTemplateRef{
+				{source: templateSourceGlobal, key: "fqdn"},
+				{source: templateSourceParams, key: "path"},
+			},
+		},
+		{
+			name:  "dogu config with slashes in key",
+			value: "{{ .Dogus.redmine.mail/from }}",
+			want:  []configTemplateRef{{source: templateSourceDogus, dogu: "redmine", key: "mail/from"}},
+		},
+		{name: "unknown source", value: "{{ .Env.HOME }}", wantErr: "template expression \".Env.HOME\" references the unknown source \"Env\""},
+		{name: "missing dot", value: "{{ Global.fqdn }}", wantErr: "template expression \"Global.fqdn\" must look like"},
+		{name: "missing dogu key", value: "{{ .Dogus.redmine }}", wantErr: "template expression \".Dogus.redmine\" must look like .Dogus.<dogu>.<key>"},
+		{name: "not closed", value: "{{ .Global.fqdn }", wantErr: "template in value \"{{ .Global.fqdn }\" is not closed correctly"},
+		{name: "literal braces", value: "}}", wantErr: "is not closed correctly, escape literal braces with \\{{ and \\}}"},
+		{
+			name:  "escaped braces",
+			value: `\{{ literal \}} {{ .Global.fqdn }}`,
+			want:  []configTemplateRef{{source: templateSourceGlobal, key: "fqdn"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := parseConfigTemplate(tt.value)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, refs)
+		})
+	}
+}
+
+func Test_validateConfigTemplates(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{{Key: "mail", Value: templateValue("admin@{{ .Params.mailDomain }}")}},
+			Dogus: DoguConfig{
+				"redmine": {{Key: "url", Value: templateValue("https://{{ .Global.fqdn }}/redmine")}},
+			},
+		}
+
+		err := validateConfigTemplates(blueprintConfig, map[string]string{"mailDomain": "example.com"})
+
+		assert.NoError(t, err)
+	})
+	t.Run("invalid templates and undeclared parameters", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{{Key: "mail", Value: templateValue("admin@{{ .Params.mailDomain }}")}},
+			Dogus: DoguConfig{
+				"redmine": {{Key: "url", Value: templateValue("https://{{ .Fqdn }}/redmine")}},
+			},
+		}
+
+		err := validateConfigTemplates(blueprintConfig, map[string]string{})
+
+		assert.ErrorContains(t, err, "global config \"mail\" references the undeclared parameter \"mailDomain\"")
+		assert.ErrorContains(t, err, "config of dogu \"redmine\" with key \"url\": template expression \".Fqdn\" must look like")
+	})
+	t.Run("no templates without parameters", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{{Key: "mail", Value: templateValue("admin@{{ .Params.mailDomain }}")}},
+			Dogus: DoguConfig{
+				"redmine": {{Key: "url", Value: templateValue("{{ not closed")}},
+			},
+		}
+
+		err := validateConfigTemplates(blueprintConfig, nil)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestConfig_GetDogusReferencedByTemplates(t *testing.T) {
+	blueprintConfig := Config{
+		Global: GlobalConfigEntries{{Key: "admin", Value: templateValue("{{ .Dogus.redmine.admin }}")}},
+		Dogus: DoguConfig{
+			"cas": {
+				{Key: "url", Value: templateValue("{{ .Dogus.redmine.url }} {{ .Dogus.nexus.url }}")},
+				{Key: "password", Sensitive: true, SecretRef: &SensitiveValueRef{SecretName: "secret", SecretKey: "password"}},
+			},
+		},
+	}
+
+	assert.Equal(t, []cescommons.SimpleName{"nexus", "redmine"}, blueprintConfig.GetDogusReferencedByTemplates())
+}
+
+func Test_resolveConfigTemplates(t *testing.T) {
+	globalEntries, _ := config.MapToEntries(map[string]any{"fqdn": "ces.example.com"})
+	ecosystemState := ecosystem.EcosystemState{
+		GlobalConfig: config.CreateGlobalConfig(globalEntries),
+		ConfigByDogu: map[cescommons.SimpleName]config.DoguConfig{
+			"nexus": config.CreateDoguConfig("nexus", map[config.Key]config.Value{"path": "nexus"}),
+		},
+	}
+	parameters := map[string]string{"mailDomain": "example.com"}
+
+	t.Run("resolve parameters, blueprint config, referenced config and actual config", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{
+				{Key: "mail/domain", Value: templateValue("{{ .Params.mailDomain }}")},
+				{Key: "logo", ConfigRef: &ConfigValueRef{ConfigMapName: "branding", ConfigMapKey: "logo"}},
+			},
+			Dogus: DoguConfig{
+				"redmine": {
+					{Key: "url", Value: templateValue("https://{{ .Global.fqdn }}/{{ .Dogus.nexus.path }}")},
+					{Key: "mail/from", Value: templateValue("redmine@{{ .Global.mail/domain }}")},
+					{Key: "logo", Value: templateValue("{{.Global.logo}}")},
+					{Key: "plain", Value: templateValue("value")},
+				},
+			},
+		}
+		referencedGlobalConfig := map[common.GlobalConfigKey]common.GlobalConfigValue{"logo": "logo.png"}
+
+		resolvedConfig, err := resolveConfigTemplates(blueprintConfig, parameters, ecosystemState, nil, referencedGlobalConfig)
+
+		require.NoError(t, err)
+		assert.Equal(t, templateValue("example.com"), resolvedConfig.Global[0].Value)
+		assert.Equal(t, templateValue("https://ces.example.com/nexus"), resolvedConfig.Dogus["redmine"][0].Value)
+		assert.Equal(t, templateValue("redmine@example.com"), resolvedConfig.Dogus["redmine"][1].Value)
+		assert.Equal(t, templateValue("logo.png"), resolvedConfig.Dogus["redmine"][2].Value)
+		assert.Equal(t, templateValue("value"), resolvedConfig.Dogus["redmine"][3].Value)
+		// the given config stays unchanged
+		assert.Equal(t, templateValue("{{ .Params.mailDomain }}"), blueprintConfig.Global[0].Value)
+		assert.Equal(t, templateValue("https://{{ .Global.fqdn }}/{{ .Dogus.nexus.path }}"), blueprintConfig.Dogus["redmine"][0].Value)
+	})
+	t.Run("replace escaped braces with literal ones", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{
+				{Key: "url", Value: templateValue(`\{{ {{ .Global.fqdn }} \}}`)},
+				{Key: "copy", Value: templateValue("{{ .Global.url }}")},
+			},
+		}
+
+		resolvedConfig, err := resolveConfigTemplates(blueprintConfig, parameters, ecosystemState, nil, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, templateValue("{{ ces.example.com }}"), resolvedConfig.Global[0].Value)
+		// resolved values are not parsed again
+		assert.Equal(t, templateValue("{{ ces.example.com }}"), resolvedConfig.Global[1].Value)
+	})
+	t.Run("keep values without parameters", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{{Key: "literal", Value: templateValue("{{ .Global.fqdn }} \\{{")}},
+		}
+
+		resolvedConfig, err := resolveConfigTemplates(blueprintConfig, nil, ecosystemState, nil, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, blueprintConfig, resolvedConfig)
+	})
+	t.Run("detect cycles", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{
+				{Key: "a", Value: templateValue("{{ .Dogus.redmine.b }}")},
+			},
+			Dogus: DoguConfig{
+				"redmine": {{Key: "b", Value: templateValue("x{{ .Global.a }}")}},
+			},
+		}
+
+		_, err := resolveConfigTemplates(blueprintConfig, parameters, ecosystemState, nil, nil)
+
+		assert.ErrorContains(t, err, "config templates contain a cycle: .Dogus.redmine.b -> .Global.a -> .Dogus.redmine.b")
+		assert.Equal(t, templateValue("{{ .Dogus.redmine.b }}"), blueprintConfig.Global[0].Value)
+	})
+	t.Run("reject references to sensitive, absent and missing config", func(t *testing.T) {
+		blueprintConfig := Config{
+			Global: GlobalConfigEntries{
+				{Key: "removed", Absent: true},
+				{Key: "missing", Value: templateValue("{{ .Global.unknown }}")},
+				{Key: "absent", Value: templateValue("{{ .Global.removed }}")},
+			},
+			Dogus: DoguConfig{
+				"redmine": {
+					{Key: "password", Sensitive: true, SecretRef: &SensitiveValueRef{SecretName: "secret", SecretKey: "password"}},
+					{Key: "copy", Value: templateValue("{{ .Dogus.redmine.password }}")},
+					{Key: "param", Value: templateValue("{{ .Params.unknown }}")},
+				},
+			},
+		}
+
+		_, err := resolveConfigTemplates(blueprintConfig, parameters, ecosystemState, nil, nil)
+
+		assert.ErrorContains(t, err, "cannot reference .Global.unknown as it is neither in the blueprint nor in the ecosystem")
+		assert.ErrorContains(t, err, "cannot reference .Global.removed as it is absent in the blueprint")
+		assert.ErrorContains(t, err, "cannot reference .Dogus.redmine.password as sensitive config must not be copied into other config")
+		assert.ErrorContains(t, err, "parameter \"unknown\" is not declared")
+	})
+}
+
+func TestConfigEntry_HasTemplate(t *testing.T) {
+	assert.True(t, ConfigEntry{Value: templateValue("https://{{ .Global.fqdn }}")}.HasTemplate())
+	assert.True(t, ConfigEntry{Value: templateValue(`\{{ literal \}}`)}.HasTemplate())
+	assert.False(t, ConfigEntry{Value: templateValue("https://ces.example.com")}.HasTemplate())
+	assert.False(t, ConfigEntry{Absent: true}.HasTemplate())
+}
@@ -267,6 +267,52 @@ func TestDoguConfig_validate(t *testing.T) {
 		err := config.validate("dogu1")
 		assert.NoError(t, err)
 	})
+	t.Run("key pattern for absent entry allowed", func(t *testing.T) {
+		config := DoguConfigEntries{
+			{
+				Key:    "ldap-mapper/*",
+				Absent: true,
+			},
+		}
+		err := config.validate("dogu1")
+		assert.NoError(t, err)
+	})
+	t.Run("wildcards in keys of present entries are no key patterns", func(t *testing.T) {
+		config := DoguConfigEntries{
+			{
+				Key:   "ldap-mapper/*",
+				Value: &confgiVal1,
+			},
+			{
+				Key:   "ldap-mapper/[a?",
+				Value: &confgiVal1,
+			},
+		}
+		err := config.validate("dogu1")
+		assert.NoError(t, err)
+	})
+	t.Run("question marks and brackets in keys of absent entries are no key patterns", func(t *testing.T) {
+		config := DoguConfigEntries{
+			{
+				Key:    "ldap-mapper/[a?",
+				Absent: true,
+			},
+		}
+		err := config.validate("dogu1")
+		assert.NoError(t, err)
+	})
+	t.Run("No invalid key pattern", func(t *testing.T) {
+		for _, key := range []string{"*", "/*", "ldap-mapper//*", "ldap-mapper*", "*/root", "ldap-*/*", "ldap-mapper/*/type"} {
+			config := DoguConfigEntries{
+				{
+					Key:    libconfig.Key(key),
+					Absent: true,
+				},
+			}
+			err := config.validate("dogu1")
+			assert.ErrorContains(t, err, fmt.Sprintf("key pattern %q is invalid: only a trailing \"/*\" after a key prefix is allowed", key))
+		}
+	})
 }
 
 func TestConfig_validate(t *testing.T) {
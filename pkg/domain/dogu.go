@@ -18,6 +18,15 @@ type Dogu struct {
 	// Version defines the version of the dogu that is to be installed. Must not be empty if the targetState is "present";
 	// otherwise it is optional and is not going to be interpreted.
 	Version *core.Version
+	// VersionConstraint defines a range of versions instead of an exact version, e.g. "~2.4".
+	// It gets resolved to the concrete Version of the effective blueprint with the remote dogu registry.
+	VersionConstraint *VersionConstraint
+	// AutoUpgradePolicy is set in the effective blueprint if the VersionConstraint is derived from an auto upgrade policy
+	// of the blueprint configuration.
+	AutoUpgradePolicy AutoUpgradePolicy
+	// AutoAdded is set in the effective blueprint if the dogu is not part of the blueprint, but was added as a missing
+	// dependency of another dogu, see BlueprintConfiguration.CompleteDependencies.
+	AutoAdded bool
 	// Absent defines if the dogu should be absent in the ecosystem. Defaults to false.
 	Absent bool
 	// MinVolumeSize is the minimum storage of the dogu. 0 indicates that the default size should be set.
@@ -29,6 +38,8 @@ type Dogu struct {
 	StorageClassName *string
 	// AdditionalMounts provides the possibility to mount additional data into the dogu.
 	AdditionalMounts []ecosystem.AdditionalMount
+	// ReverseProxyConfig contains the settings of the reverse proxy in front of the dogu, e.g. the maximum body size.
+	ReverseProxyConfig ecosystem.ReverseProxyConfig
 }
 
 // validate checks if the Dogu is semantically correct.
@@ -36,10 +47,14 @@ func (dogu Dogu) validate() error {
 	var errorList []error
 
 	emptyVersion := core.Version{}
-	if !dogu.Absent && (dogu.Version == nil || *dogu.Version == emptyVersion) {
+	if !dogu.Absent && (dogu.Version == nil || *dogu.Version == emptyVersion) && dogu.VersionConstraint == nil {
 		errorList = append(errorList, fmt.Errorf("dogu version must not be empty: %s", dogu.Name))
 	}
 	// minVolumeSize is already checked while unmarshalling json/yaml
+	maxBodySize := dogu.ReverseProxyConfig.MaxBodySize
+	if maxBodySize != nil && maxBodySize.Sign() < 0 {
+		errorList = append(errorList, fmt.Errorf("dogu reverse proxy max body size must not be negative: %s", dogu.Name))
+	}
 
 	for _, mount := range dogu.AdditionalMounts {
 		if mount.SourceType != ecosystem.DataSourceConfigMap && mount.SourceType != ecosystem.DataSourceSecret {
@@ -7,6 +7,7 @@ import (
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 var (
@@ -30,6 +31,26 @@ func Test_TargetDogu_validate_missingVersionOkayForAbsentDogu(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func Test_TargetDogu_validate_versionConstraintInsteadOfVersion(t *testing.T) {
+	constraint, err := ParseVersionConstraint("~1.2")
+	require.NoError(t, err)
+	dogu := Dogu{Name: officialDogu1, VersionConstraint: &constraint}
+
+	err = dogu.validate()
+
+	require.NoError(t, err)
+}
+
+func Test_TargetDogu_validate_errorOnNegativeMaxBodySize(t *testing.T) {
+	maxBodySize := resource.MustParse("-1Mi")
+	dogu := Dogu{Name: officialDogu1, Version: &version123, ReverseProxyConfig: ecosystem.ReverseProxyConfig{MaxBodySize: &maxBodySize}}
+
+	err := dogu.validate()
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "dogu reverse proxy max body size must not be negative")
+}
+
 func Test_TargetDogu_validate_defaultToPresentState(t *testing.T) {
 	dogu := Dogu{Name: officialDogu1, Version: &version123}
 
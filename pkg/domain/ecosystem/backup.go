@@ -0,0 +1,31 @@
+package ecosystem
+
+const (
+	BackupStatusNew        string = ""
+	BackupStatusInProgress string = "in progress"
+	BackupStatusCompleted  string = "completed"
+	BackupStatusFailed     string = "failed"
+)
+
+// Backup represents a backup of the whole ecosystem.
+type Backup struct {
+	// Name identifies the backup.
+	Name string
+	// Status defines the current state of the backup.
+	Status string
+}
+
+// NewBackup creates a new Backup which has not been started yet.
+func NewBackup(name string) *Backup {
+	return &Backup{Name: name, Status: BackupStatusNew}
+}
+
+// IsCompleted returns true if the backup finished successfully.
+func (b *Backup) IsCompleted() bool {
+	return b.Status == BackupStatusCompleted
+}
+
+// IsFailed returns true if the backup could not be completed.
+func (b *Backup) IsFailed() bool {
+	return b.Status == BackupStatusFailed
+}
@@ -0,0 +1,41 @@
+package ecosystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBackup(t *testing.T) {
+	backup := NewBackup("my-backup")
+
+	assert.Equal(t, &Backup{Name: "my-backup", Status: BackupStatusNew}, backup)
+	assert.False(t, backup.IsCompleted())
+	assert.False(t, backup.IsFailed())
+}
+
+func TestBackup_IsCompleted(t *testing.T) {
+	t.Run("is completed", func(t *testing.T) {
+		backup := Backup{Status: BackupStatusCompleted}
+		assert.True(t, backup.IsCompleted())
+		assert.False(t, backup.IsFailed())
+	})
+
+	t.Run("is not completed while in progress", func(t *testing.T) {
+		backup := Backup{Status: BackupStatusInProgress}
+		assert.False(t, backup.IsCompleted())
+	})
+}
+
+func TestBackup_IsFailed(t *testing.T) {
+	t.Run("is failed", func(t *testing.T) {
+		backup := Backup{Status: BackupStatusFailed}
+		assert.True(t, backup.IsFailed())
+		assert.False(t, backup.IsCompleted())
+	})
+
+	t.Run("is not failed while in progress", func(t *testing.T) {
+		backup := Backup{Status: BackupStatusInProgress}
+		assert.False(t, backup.IsFailed())
+	})
+}
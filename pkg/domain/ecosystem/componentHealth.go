@@ -0,0 +1,56 @@
+package ecosystem
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ComponentInstallation represents an installed k8s component of the ecosystem, e.g. the k8s-dogu-operator.
+type ComponentInstallation struct {
+	// Name is the name of the component, e.g. "k8s-dogu-operator".
+	Name string
+	// Health is the current health status of the component in the ecosystem
+	Health HealthStatus
+}
+
+// ComponentHealthResult is a snapshot of the health states of all components.
+type ComponentHealthResult struct {
+	ComponentsByStatus map[HealthStatus][]string
+}
+
+func (result ComponentHealthResult) getUnhealthyComponents() []string {
+	var unhealthyComponents []string
+	for healthState, componentNames := range result.ComponentsByStatus {
+		if healthState != AvailableHealthStatus {
+			unhealthyComponents = append(unhealthyComponents, componentNames...)
+		}
+	}
+	return unhealthyComponents
+}
+
+func (result ComponentHealthResult) String() string {
+	unhealthyComponents := result.getUnhealthyComponents()
+	slices.Sort(unhealthyComponents)
+	return fmt.Sprintf("%d component(s) are unhealthy: %s", len(unhealthyComponents), strings.Join(unhealthyComponents, ", "))
+}
+
+// CalculateComponentHealthResult collects the health states from ComponentInstallation and creates a ComponentHealthResult.
+func CalculateComponentHealthResult(components []*ComponentInstallation) ComponentHealthResult {
+	result := ComponentHealthResult{
+		ComponentsByStatus: map[HealthStatus][]string{},
+	}
+	for _, component := range components {
+		result.ComponentsByStatus[component.Health] = append(result.ComponentsByStatus[component.Health], component.Name)
+	}
+	return result
+}
+
+func (result ComponentHealthResult) AllHealthy() bool {
+	for healthState, componentNames := range result.ComponentsByStatus {
+		if healthState != AvailableHealthStatus && len(componentNames) != 0 {
+			return false
+		}
+	}
+	return true
+}
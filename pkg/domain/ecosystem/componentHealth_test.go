@@ -0,0 +1,53 @@
+package ecosystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateComponentHealthResult(t *testing.T) {
+	components := []*ComponentInstallation{
+		{Name: "k8s-dogu-operator", Health: AvailableHealthStatus},
+		{Name: "k8s-longhorn", Health: UnavailableHealthStatus},
+		{Name: "k8s-cert-manager", Health: PendingHealthStatus},
+	}
+
+	actual := CalculateComponentHealthResult(components)
+
+	assert.Equal(t, ComponentHealthResult{
+		ComponentsByStatus: map[HealthStatus][]string{
+			AvailableHealthStatus:   {"k8s-dogu-operator"},
+			UnavailableHealthStatus: {"k8s-longhorn"},
+			PendingHealthStatus:     {"k8s-cert-manager"},
+		},
+	}, actual)
+}
+
+func TestComponentHealthResult_String(t *testing.T) {
+	t.Run("should list unhealthy components sorted", func(t *testing.T) {
+		result := ComponentHealthResult{ComponentsByStatus: map[HealthStatus][]string{
+			AvailableHealthStatus:   {"k8s-dogu-operator"},
+			UnavailableHealthStatus: {"k8s-longhorn"},
+			PendingHealthStatus:     {"k8s-cert-manager"},
+		}}
+
+		assert.Equal(t, "2 component(s) are unhealthy: k8s-cert-manager, k8s-longhorn", result.String())
+	})
+	t.Run("should print no components", func(t *testing.T) {
+		assert.Equal(t, "0 component(s) are unhealthy: ", ComponentHealthResult{}.String())
+	})
+}
+
+func TestComponentHealthResult_AllHealthy(t *testing.T) {
+	t.Run("should be healthy if all components are available", func(t *testing.T) {
+		result := ComponentHealthResult{ComponentsByStatus: map[HealthStatus][]string{AvailableHealthStatus: {"k8s-dogu-operator"}}}
+
+		assert.True(t, result.AllHealthy())
+	})
+	t.Run("should be unhealthy if a component is pending", func(t *testing.T) {
+		result := ComponentHealthResult{ComponentsByStatus: map[HealthStatus][]string{PendingHealthStatus: {"k8s-longhorn"}}}
+
+		assert.False(t, result.AllHealthy())
+	})
+}
@@ -39,8 +39,14 @@ type DoguInstallation struct {
 	StorageClassName *string
 	// AdditionalMounts provides the possibility to mount additional data into the dogu.
 	AdditionalMounts []AdditionalMount
+	// ReverseProxyConfig contains the settings of the reverse proxy in front of the dogu.
+	ReverseProxyConfig ReverseProxyConfig
+	// InstalledByBlueprint contains the id of the blueprint which installed the dogu.
+	// It is empty if the dogu was installed in any other way, e.g. manually.
+	InstalledByBlueprint string
 }
 
+// ReverseProxyConfig contains the settings of the reverse proxy in front of a dogu.
 type ReverseProxyConfig struct {
 	MaxBodySize      *BodySize
 	RewriteTarget    RewriteTarget
@@ -57,6 +63,8 @@ type UpgradeConfig struct {
 	// same dogu which did reside in a different namespace. The remote dogu's version must be equal to or greater than
 	// the version of the local dogu.
 	AllowNamespaceSwitch bool `json:"allowNamespaceSwitch,omitempty"`
+	// ForceUpgrade allows to install the same or even a lower dogu version than the installed one.
+	ForceUpgrade bool `json:"forceUpgrade,omitempty"`
 }
 
 type DataSourceType string
@@ -89,7 +97,8 @@ func InstallDogu(
 	version *core.Version,
 	minVolumeSize *VolumeSize,
 	storageClassName *string,
-	additionalMounts []AdditionalMount) *DoguInstallation {
+	additionalMounts []AdditionalMount,
+	reverseProxyConfig ReverseProxyConfig) *DoguInstallation {
 
 	doguVersion := core.Version{}
 	if version != nil {
@@ -97,12 +106,13 @@ func InstallDogu(
 	}
 
 	return &DoguInstallation{
-		Name:             name,
-		Version:          doguVersion,
-		UpgradeConfig:    UpgradeConfig{AllowNamespaceSwitch: false},
-		MinVolumeSize:    minVolumeSize,
-		StorageClassName: storageClassName,
-		AdditionalMounts: additionalMounts,
+		Name:               name,
+		Version:            doguVersion,
+		UpgradeConfig:      UpgradeConfig{AllowNamespaceSwitch: false},
+		MinVolumeSize:      minVolumeSize,
+		StorageClassName:   storageClassName,
+		AdditionalMounts:   additionalMounts,
+		ReverseProxyConfig: reverseProxyConfig,
 	}
 }
 
@@ -127,6 +137,13 @@ func (dogu *DoguInstallation) Upgrade(newVersion *core.Version) {
 	}
 
 	dogu.UpgradeConfig.AllowNamespaceSwitch = false
+	dogu.UpgradeConfig.ForceUpgrade = false
+}
+
+// Downgrade sets the dogu to an older version. As the dogu would refuse this otherwise, the upgrade gets forced.
+func (dogu *DoguInstallation) Downgrade(newVersion *core.Version) {
+	dogu.Upgrade(newVersion)
+	dogu.UpgradeConfig.ForceUpgrade = true
 }
 
 func (dogu *DoguInstallation) SwitchNamespace(newNamespace cescommons.Namespace, isNamespaceSwitchAllowed bool) error {
@@ -146,6 +163,20 @@ func (dogu *DoguInstallation) UpdateAdditionalMounts(mounts []AdditionalMount) {
 	dogu.AdditionalMounts = mounts
 }
 
+// UpdateReverseProxyConfig only overwrites the settings which are set in the given config,
+// so that settings of an admin stay untouched.
+func (dogu *DoguInstallation) UpdateReverseProxyConfig(config ReverseProxyConfig) {
+	if config.MaxBodySize != nil {
+		dogu.ReverseProxyConfig.MaxBodySize = config.MaxBodySize
+	}
+	if config.RewriteTarget != "" {
+		dogu.ReverseProxyConfig.RewriteTarget = config.RewriteTarget
+	}
+	if config.AdditionalConfig != "" {
+		dogu.ReverseProxyConfig.AdditionalConfig = config.AdditionalConfig
+	}
+}
+
 func (dogu *DoguInstallation) SetReconciliationPaused(isPaused bool) {
 	dogu.PauseReconciliation = isPaused
 }
@@ -20,6 +20,7 @@ var (
 
 func TestInstallDogu(t *testing.T) {
 	volumeSize := resource.MustParse("1Gi")
+	bodySize := resource.MustParse("100Mi")
 	storageClassName := "example-storage-class"
 	dogu := InstallDogu(
 		postgresqlQualifiedName,
@@ -34,6 +35,7 @@ func TestInstallDogu(t *testing.T) {
 				Subfolder:  subfolder,
 			},
 		},
+		ReverseProxyConfig{MaxBodySize: &bodySize},
 	)
 	assert.Equal(t, &DoguInstallation{
 		Name:             postgresqlQualifiedName,
@@ -49,6 +51,7 @@ func TestInstallDogu(t *testing.T) {
 				Subfolder:  subfolder,
 			},
 		},
+		ReverseProxyConfig: ReverseProxyConfig{MaxBodySize: &bodySize},
 	}, dogu)
 }
 
@@ -90,6 +93,21 @@ func TestDoguInstallation_Upgrade(t *testing.T) {
 	}, dogu)
 }
 
+func TestDoguInstallation_Downgrade(t *testing.T) {
+	dogu := &DoguInstallation{
+		Name:    postgresqlQualifiedName,
+		Version: version1232,
+	}
+
+	dogu.Downgrade(&version1231)
+
+	assert.Equal(t, &DoguInstallation{
+		Name:          postgresqlQualifiedName,
+		Version:       version1231,
+		UpgradeConfig: UpgradeConfig{ForceUpgrade: true},
+	}, dogu)
+}
+
 func TestDoguInstallation_SwitchNamespace(t *testing.T) {
 	t.Run("all ok", func(t *testing.T) {
 		dogu := &DoguInstallation{
@@ -135,6 +153,21 @@ func TestDoguInstallation_UpdateMinVolumeSize(t *testing.T) {
 	})
 }
 
+func TestDoguInstallation_UpdateReverseProxyConfig(t *testing.T) {
+	t.Run("should only overwrite set settings", func(t *testing.T) {
+		// given
+		oldSize := resource.MustParse("1Mi")
+		newSize := resource.MustParse("10Mi")
+		dogu := DoguInstallation{ReverseProxyConfig: ReverseProxyConfig{MaxBodySize: &oldSize, AdditionalConfig: "admin"}}
+
+		// when
+		dogu.UpdateReverseProxyConfig(ReverseProxyConfig{MaxBodySize: &newSize, RewriteTarget: "/"})
+
+		// then
+		assert.Equal(t, ReverseProxyConfig{MaxBodySize: &newSize, RewriteTarget: "/", AdditionalConfig: "admin"}, dogu.ReverseProxyConfig)
+	})
+}
+
 func TestDoguInstallation_IsVersionUpToDate(t *testing.T) {
 	type fields struct {
 		Version          core.Version
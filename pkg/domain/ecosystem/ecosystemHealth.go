@@ -10,13 +10,18 @@ const (
 
 // HealthResult is a snapshot of the health states of all relevant parts of the running ecosystem.
 type HealthResult struct {
-	DoguHealth DoguHealthResult
+	DoguHealth      DoguHealthResult
+	ComponentHealth ComponentHealthResult
 }
 
 func (result HealthResult) String() string {
-	return result.DoguHealth.String()
+	// the component health is empty if it is ignored
+	if len(result.ComponentHealth.ComponentsByStatus) == 0 {
+		return result.DoguHealth.String()
+	}
+	return result.DoguHealth.String() + "\n  " + result.ComponentHealth.String()
 }
 
 func (result HealthResult) AllHealthy() bool {
-	return result.DoguHealth.AllHealthy()
+	return result.DoguHealth.AllHealthy() && result.ComponentHealth.AllHealthy()
 }
@@ -9,7 +9,8 @@ import (
 
 func TestHealthResult_String(t *testing.T) {
 	type fields struct {
-		DoguHealth DoguHealthResult
+		DoguHealth      DoguHealthResult
+		ComponentHealth ComponentHealthResult
 	}
 	tests := []struct {
 		name   string
@@ -30,11 +31,20 @@ func TestHealthResult_String(t *testing.T) {
 			},
 			want: "1 dogu(s) are unhealthy: nginx-ingress",
 		},
+		{
+			name: "should print dogu and component health results",
+			fields: fields{
+				DoguHealth:      DoguHealthResult{DogusByStatus: map[HealthStatus][]cescommons.SimpleName{AvailableHealthStatus: {"nginx-ingress"}}},
+				ComponentHealth: ComponentHealthResult{ComponentsByStatus: map[HealthStatus][]string{UnavailableHealthStatus: {"k8s-longhorn"}}},
+			},
+			want: "0 dogu(s) are unhealthy: \n  1 component(s) are unhealthy: k8s-longhorn",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := HealthResult{
-				DoguHealth: tt.fields.DoguHealth,
+				DoguHealth:      tt.fields.DoguHealth,
+				ComponentHealth: tt.fields.ComponentHealth,
 			}
 			assert.Equalf(t, tt.want, result.String(), "String()")
 		})
@@ -43,7 +53,8 @@ func TestHealthResult_String(t *testing.T) {
 
 func TestHealthResult_AllHealthy(t *testing.T) {
 	type fields struct {
-		DoguHealth DoguHealthResult
+		DoguHealth      DoguHealthResult
+		ComponentHealth ComponentHealthResult
 	}
 	tests := []struct {
 		name   string
@@ -64,11 +75,20 @@ func TestHealthResult_AllHealthy(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "should be unhealthy if components are unavailable",
+			fields: fields{
+				DoguHealth:      DoguHealthResult{DogusByStatus: map[HealthStatus][]cescommons.SimpleName{AvailableHealthStatus: {"nginx-ingress"}}},
+				ComponentHealth: ComponentHealthResult{ComponentsByStatus: map[HealthStatus][]string{UnavailableHealthStatus: {"k8s-longhorn"}}},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := HealthResult{
-				DoguHealth: tt.fields.DoguHealth,
+				DoguHealth:      tt.fields.DoguHealth,
+				ComponentHealth: tt.fields.ComponentHealth,
 			}
 			assert.Equalf(t, tt.want, result.AllHealthy(), "AllHealthy()")
 		})
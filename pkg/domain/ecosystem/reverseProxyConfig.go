@@ -4,6 +4,20 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
+// BodySize is the maximum size of a request body. 0 means that the size is not limited.
 type BodySize = resource.Quantity
 type RewriteTarget string
 type AdditionalConfig string
+
+// IsSatisfiedBy returns true if every setting of this config has the same value in the other config.
+// Settings which are not set in this config are ignored, as they are not managed by the blueprint.
+// Body sizes are compared by their value, so "1Mi" equals "1024Ki".
+func (r ReverseProxyConfig) IsSatisfiedBy(other ReverseProxyConfig) bool {
+	if r.MaxBodySize != nil && (other.MaxBodySize == nil || r.MaxBodySize.Cmp(*other.MaxBodySize) != 0) {
+		return false
+	}
+	if r.RewriteTarget != "" && r.RewriteTarget != other.RewriteTarget {
+		return false
+	}
+	return r.AdditionalConfig == "" || r.AdditionalConfig == other.AdditionalConfig
+}
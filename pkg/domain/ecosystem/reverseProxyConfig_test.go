@@ -0,0 +1,25 @@
+package ecosystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestReverseProxyConfig_IsSatisfiedBy(t *testing.T) {
+	size1Mi := resource.MustParse("1Mi")
+	size1024Ki := resource.MustParse("1024Ki")
+	size1M := resource.MustParse("1M")
+
+	assert.True(t, ReverseProxyConfig{}.IsSatisfiedBy(ReverseProxyConfig{}))
+	assert.True(t, ReverseProxyConfig{MaxBodySize: &size1Mi}.IsSatisfiedBy(ReverseProxyConfig{MaxBodySize: &size1024Ki}))
+	assert.True(t, ReverseProxyConfig{RewriteTarget: "/", AdditionalConfig: "more"}.IsSatisfiedBy(ReverseProxyConfig{RewriteTarget: "/", AdditionalConfig: "more"}))
+	// settings which are not set are not managed
+	assert.True(t, ReverseProxyConfig{}.IsSatisfiedBy(ReverseProxyConfig{MaxBodySize: &size1Mi, RewriteTarget: "/", AdditionalConfig: "more"}))
+	assert.True(t, ReverseProxyConfig{RewriteTarget: "/"}.IsSatisfiedBy(ReverseProxyConfig{RewriteTarget: "/", AdditionalConfig: "more"}))
+	assert.False(t, ReverseProxyConfig{MaxBodySize: &size1Mi}.IsSatisfiedBy(ReverseProxyConfig{MaxBodySize: &size1M}))
+	assert.False(t, ReverseProxyConfig{MaxBodySize: &size1Mi}.IsSatisfiedBy(ReverseProxyConfig{}))
+	assert.False(t, ReverseProxyConfig{RewriteTarget: "/"}.IsSatisfiedBy(ReverseProxyConfig{}))
+	assert.False(t, ReverseProxyConfig{AdditionalConfig: "more"}.IsSatisfiedBy(ReverseProxyConfig{AdditionalConfig: "other"}))
+}
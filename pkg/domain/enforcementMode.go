@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/util"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnforcementMode defines if the blueprint overwrites differences in the ecosystem or only reports them.
+type EnforcementMode string
+
+const (
+	// EnforcementModeEnforce applies the blueprint and overwrites every difference in the ecosystem. This is the default.
+	EnforcementModeEnforce EnforcementMode = "enforce"
+	// EnforcementModeReport does not change the ecosystem and only reports the differences as drift.
+	EnforcementModeReport EnforcementMode = "report"
+)
+
+// ConditionDrifted is only set if the blueprint has any target with EnforcementModeReport.
+const ConditionDrifted = "Drifted"
+
+// EnforcementModes contains the EnforcementMode of dogus and config entries, which differ from EnforcementModeEnforce.
+// The mode of a dogu applies to the dogu itself and its config.
+// The mode of a dogu config entry takes precedence over the mode of its dogu.
+type EnforcementModes struct {
+	Dogus        map[cescommons.SimpleName]EnforcementMode
+	DoguConfig   map[common.DoguConfigKey]EnforcementMode
+	GlobalConfig map[common.GlobalConfigKey]EnforcementMode
+}
+
+// Drift contains all differences between the blueprint and the ecosystem, which are only reported.
+// It contains no values, so that sensitive config can not be exposed.
+type Drift struct {
+	Dogus            []cescommons.SimpleName
+	DoguConfigKeys   []common.DoguConfigKey
+	GlobalConfigKeys []common.GlobalConfigKey
+}
+
+func (drift Drift) IsEmpty() bool {
+	return len(drift.Dogus) == 0 && len(drift.DoguConfigKeys) == 0 && len(drift.GlobalConfigKeys) == 0
+}
+
+func (drift Drift) String() string {
+	var parts []string
+	if len(drift.Dogus) > 0 {
+		parts = append(parts, fmt.Sprintf("dogus %v", drift.Dogus))
+	}
+	if len(drift.DoguConfigKeys) > 0 {
+		keys := util.Map(drift.DoguConfigKeys, func(key common.DoguConfigKey) string {
+			return fmt.Sprintf("%s/%s", key.DoguName, key.Key)
+		})
+		parts = append(parts, fmt.Sprintf("dogu config %v", keys))
+	}
+	if len(drift.GlobalConfigKeys) > 0 {
+		parts = append(parts, fmt.Sprintf("global config %v", drift.GlobalConfigKeys))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// HasReportMode checks if any dogu or config entry is only reported.
+func (modes EnforcementModes) HasReportMode() bool {
+	return slices.Contains(slices.Collect(maps.Values(modes.Dogus)), EnforcementModeReport) ||
+		slices.Contains(slices.Collect(maps.Values(modes.DoguConfig)), EnforcementModeReport) ||
+		slices.Contains(slices.Collect(maps.Values(modes.GlobalConfig)), EnforcementModeReport)
+}
+
+func (modes EnforcementModes) doguMode(doguName cescommons.SimpleName) EnforcementMode {
+	if mode, found := modes.Dogus[doguName]; found {
+		return mode
+	}
+	return EnforcementModeEnforce
+}
+
+func (modes EnforcementModes) doguConfigMode(key common.DoguConfigKey) EnforcementMode {
+	if mode, found := modes.DoguConfig[key]; found {
+		return mode
+	}
+	return modes.doguMode(key.DoguName)
+}
+
+func (modes EnforcementModes) globalConfigMode(key common.GlobalConfigKey) EnforcementMode {
+	if mode, found := modes.GlobalConfig[key]; found {
+		return mode
+	}
+	return EnforcementModeEnforce
+}
+
+// validate checks that all modes are known.
+func (modes EnforcementModes) validate() error {
+	var errs []error
+	checkMode := func(target string, mode EnforcementMode) {
+		if mode != EnforcementModeEnforce && mode != EnforcementModeReport {
+			errs = append(errs, fmt.Errorf("enforcement mode %q of %s must be one of %q, %q", mode, target, EnforcementModeEnforce, EnforcementModeReport))
+		}
+	}
+	for _, doguName := range slices.Sorted(maps.Keys(modes.Dogus)) {
+		checkMode(fmt.Sprintf("dogu %q", doguName), modes.Dogus[doguName])
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(modes.DoguConfig), compareDoguConfigKeys) {
+		checkMode(fmt.Sprintf("config key %q of dogu %q", key.Key, key.DoguName), modes.DoguConfig[key])
+	}
+	for _, key := range slices.Sorted(maps.Keys(modes.GlobalConfig)) {
+		checkMode(fmt.Sprintf("global config key %q", key), modes.GlobalConfig[key])
+	}
+	return errors.Join(errs...)
+}
+
+func compareDoguConfigKeys(a, b common.DoguConfigKey) int {
+	if a.DoguName != b.DoguName {
+		return strings.Compare(string(a.DoguName), string(b.DoguName))
+	}
+	return strings.Compare(string(a.Key), string(b.Key))
+}
+
+// separateDrift removes all changes of dogus and config entries with EnforcementModeReport from the state diff,
+// so that they are not applied, and returns them as Drift.
+func (modes EnforcementModes) separateDrift(stateDiff *StateDiff) Drift {
+	var drift Drift
+	for i, diff := range stateDiff.DoguDiffs {
+		if modes.doguMode(diff.DoguName) == EnforcementModeReport && diff.HasChanges() {
+			drift.Dogus = append(drift.Dogus, diff.DoguName)
+			// keep the actual state like for dogus which are not in the blueprint
+			stateDiff.DoguDiffs[i].Expected = diff.Actual
+			stateDiff.DoguDiffs[i].NeededActions = nil
+		}
+	}
+
+	stateDiff.DoguConfigDiffs = modes.separateDoguConfigDrift(stateDiff.DoguConfigDiffs, &drift)
+	stateDiff.SensitiveDoguConfigDiffs = modes.separateDoguConfigDrift(stateDiff.SensitiveDoguConfigDiffs, &drift)
+
+	var enforcedGlobalConfigDiffs GlobalConfigDiffs
+	for _, diff := range stateDiff.GlobalConfigDiffs {
+		if modes.globalConfigMode(diff.Key) == EnforcementModeReport {
+			drift.GlobalConfigKeys = append(drift.GlobalConfigKeys, diff.Key)
+			continue
+		}
+		enforcedGlobalConfigDiffs = append(enforcedGlobalConfigDiffs, diff)
+	}
+	stateDiff.GlobalConfigDiffs = enforcedGlobalConfigDiffs
+
+	slices.Sort(drift.Dogus)
+	slices.SortFunc(drift.DoguConfigKeys, compareDoguConfigKeys)
+	slices.Sort(drift.GlobalConfigKeys)
+	return drift
+}
+
+func (modes EnforcementModes) separateDoguConfigDrift(diffsByDogu map[cescommons.SimpleName]DoguConfigDiffs, drift *Drift) map[cescommons.SimpleName]DoguConfigDiffs {
+	var enforcedDiffsByDogu map[cescommons.SimpleName]DoguConfigDiffs
+	for doguName, diffs := range diffsByDogu {
+		var enforcedDiffs DoguConfigDiffs
+		for _, diff := range diffs {
+			if modes.doguConfigMode(diff.Key) == EnforcementModeReport {
+				drift.DoguConfigKeys = append(drift.DoguConfigKeys, diff.Key)
+				continue
+			}
+			enforcedDiffs = append(enforcedDiffs, diff)
+		}
+		if len(enforcedDiffs) > 0 {
+			if enforcedDiffsByDogu == nil {
+				enforcedDiffsByDogu = make(map[cescommons.SimpleName]DoguConfigDiffs)
+			}
+			enforcedDiffsByDogu[doguName] = enforcedDiffs
+		}
+	}
+	return enforcedDiffsByDogu
+}
+
+// reportDrift removes the drift from the state diff and reports it in the ConditionDrifted.
+// The condition is removed if no dogu or config entry is only reported.
+func (spec *BlueprintSpec) reportDrift() {
+	if !spec.Config.EnforcementModes.HasReportMode() {
+		meta.RemoveStatusCondition(&spec.Conditions, ConditionDrifted)
+		return
+	}
+
+	drift := spec.Config.EnforcementModes.separateDrift(&spec.StateDiff)
+	if drift.IsEmpty() {
+		meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+			Type:    ConditionDrifted,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoDrift",
+			Message: "the ecosystem matches the blueprint for all reported dogus and config",
+		})
+		return
+	}
+
+	conditionChanged := meta.SetStatusCondition(&spec.Conditions, metav1.Condition{
+		Type:    ConditionDrifted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DriftDetected",
+		Message: fmt.Sprintf("the ecosystem differs from the blueprint in %s", drift),
+	})
+	if conditionChanged {
+		spec.Events = append(spec.Events, DriftDetectedEvent{Drift: drift})
+	}
+}
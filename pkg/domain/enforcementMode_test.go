@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnforcementModes_validate(t *testing.T) {
+	t.Run("known modes", func(t *testing.T) {
+		modes := EnforcementModes{
+			Dogus:        map[cescommons.SimpleName]EnforcementMode{"redmine": EnforcementModeReport},
+			DoguConfig:   map[common.DoguConfigKey]EnforcementMode{{DoguName: "redmine", Key: "logging/root"}: EnforcementModeEnforce},
+			GlobalConfig: map[common.GlobalConfigKey]EnforcementMode{"fqdn": EnforcementModeReport},
+		}
+
+		assert.NoError(t, modes.validate())
+	})
+	t.Run("unknown modes", func(t *testing.T) {
+		modes := EnforcementModes{
+			Dogus:        map[cescommons.SimpleName]EnforcementMode{"redmine": "ignore"},
+			DoguConfig:   map[common.DoguConfigKey]EnforcementMode{{DoguName: "redmine", Key: "logging/root"}: "warn"},
+			GlobalConfig: map[common.GlobalConfigKey]EnforcementMode{"fqdn": ""},
+		}
+
+		err := modes.validate()
+
+		assert.ErrorContains(t, err, "enforcement mode \"ignore\" of dogu \"redmine\" must be one of \"enforce\", \"report\"")
+		assert.ErrorContains(t, err, "enforcement mode \"warn\" of config key \"logging/root\" of dogu \"redmine\"")
+		assert.ErrorContains(t, err, "enforcement mode \"\" of global config key \"fqdn\"")
+	})
+}
+
+func TestEnforcementModes_separateDrift(t *testing.T) {
+	// given
+	modes := EnforcementModes{
+		Dogus: map[cescommons.SimpleName]EnforcementMode{"dogu1": EnforcementModeReport},
+		DoguConfig: map[common.DoguConfigKey]EnforcementMode{
+			dogu1Key1:                        EnforcementModeEnforce,
+			{DoguName: "dogu2", Key: "key1"}: EnforcementModeReport,
+		},
+		GlobalConfig: map[common.GlobalConfigKey]EnforcementMode{"fqdn": EnforcementModeReport},
+	}
+	dogu1Key2 := common.DoguConfigKey{DoguName: dogu1, Key: "key2"}
+	dogu2Key1 := common.DoguConfigKey{DoguName: "dogu2", Key: "key1"}
+	stateDiff := StateDiff{
+		DoguDiffs: DoguDiffs{
+			{DoguName: "dogu1", Actual: DoguDiffState{Version: &version3211}, Expected: DoguDiffState{Version: &version3212}, NeededActions: []Action{ActionUpgrade}},
+			{DoguName: "dogu2", Actual: DoguDiffState{Version: &version3211}, Expected: DoguDiffState{Version: &version3212}, NeededActions: []Action{ActionUpgrade}},
+		},
+		DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+			dogu1: {
+				{Key: dogu1Key1, NeededAction: ConfigActionSet},
+				{Key: dogu1Key2, NeededAction: ConfigActionRemove},
+			},
+		},
+		SensitiveDoguConfigDiffs: map[cescommons.SimpleName]SensitiveDoguConfigDiffs{
+			"dogu2": {{Key: dogu2Key1, NeededAction: ConfigActionSet}},
+		},
+		GlobalConfigDiffs: GlobalConfigDiffs{
+			{Key: "fqdn", NeededAction: ConfigActionSet},
+			{Key: "admin_group", NeededAction: ConfigActionSet},
+		},
+	}
+
+	// when
+	drift := modes.separateDrift(&stateDiff)
+
+	// then
+	assert.Equal(t, Drift{
+		Dogus:            []cescommons.SimpleName{"dogu1"},
+		DoguConfigKeys:   []common.DoguConfigKey{dogu1Key2, dogu2Key1},
+		GlobalConfigKeys: []common.GlobalConfigKey{"fqdn"},
+	}, drift)
+	assert.Equal(t, "dogus [dogu1], dogu config [dogu1/key2 dogu2/key1], global config [fqdn]", drift.String())
+
+	require.Len(t, stateDiff.DoguDiffs, 2)
+	assert.False(t, stateDiff.DoguDiffs[0].HasChanges())
+	assert.Equal(t, stateDiff.DoguDiffs[0].Actual, stateDiff.DoguDiffs[0].Expected)
+	assert.True(t, stateDiff.DoguDiffs[1].HasChanges())
+	assert.Equal(t, map[cescommons.SimpleName]DoguConfigDiffs{dogu1: {{Key: dogu1Key1, NeededAction: ConfigActionSet}}}, stateDiff.DoguConfigDiffs)
+	assert.Nil(t, stateDiff.SensitiveDoguConfigDiffs)
+	assert.Equal(t, GlobalConfigDiffs{{Key: "admin_group", NeededAction: ConfigActionSet}}, stateDiff.GlobalConfigDiffs)
+}
+
+func TestBlueprintSpec_DetermineStateDiff_reportDrift(t *testing.T) {
+	newSpec := func() *BlueprintSpec {
+		return &BlueprintSpec{
+			EffectiveBlueprint: EffectiveBlueprint{Config: Config{
+				Global: GlobalConfigEntries{{Key: "fqdn", Value: &val1}},
+			}},
+			Config: BlueprintConfiguration{EnforcementModes: EnforcementModes{
+				GlobalConfig: map[common.GlobalConfigKey]EnforcementMode{"fqdn": EnforcementModeReport},
+			}},
+		}
+	}
+	determineStateDiff := func(spec *BlueprintSpec, actualFqdn string) error {
+		globalEntries, _ := config.MapToEntries(map[string]any{"fqdn": actualFqdn})
+		clusterState := ecosystem.EcosystemState{GlobalConfig: config.CreateGlobalConfig(globalEntries)}
+		return spec.DetermineStateDiff(clusterState, nil, nil, nil, nil, false)
+	}
+
+	t.Run("report drift only once", func(t *testing.T) {
+		spec := newSpec()
+
+		err := determineStateDiff(spec, "hotfix.example.com")
+		require.NoError(t, err)
+		err = determineStateDiff(spec, "hotfix.example.com")
+		require.NoError(t, err)
+
+		assert.False(t, spec.StateDiff.HasChanges())
+		condition := meta.FindStatusCondition(spec.Conditions, ConditionDrifted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "the ecosystem differs from the blueprint in global config [fqdn]", condition.Message)
+		assert.NotContains(t, condition.Message, "hotfix.example.com")
+		require.Len(t, spec.Events, 1)
+		assert.Equal(t, "drift is only reported and not corrected: global config [fqdn]", spec.Events[0].Message())
+	})
+	t.Run("no drift", func(t *testing.T) {
+		spec := newSpec()
+
+		err := determineStateDiff(spec, string(val1))
+		require.NoError(t, err)
+
+		assert.True(t, meta.IsStatusConditionFalse(spec.Conditions, ConditionDrifted))
+		assert.Empty(t, spec.Events)
+	})
+	t.Run("remove condition without report mode", func(t *testing.T) {
+		spec := newSpec()
+		spec.Config.EnforcementModes = EnforcementModes{}
+		spec.Conditions = []Condition{{Type: ConditionDrifted, Status: metav1.ConditionTrue}}
+
+		err := determineStateDiff(spec, "hotfix.example.com")
+		require.NoError(t, err)
+
+		assert.Nil(t, meta.FindStatusCondition(spec.Conditions, ConditionDrifted))
+		assert.True(t, spec.StateDiff.HasChanges())
+	})
+}
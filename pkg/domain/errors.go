@@ -31,8 +31,7 @@ type UnhealthyEcosystemError struct {
 }
 
 func (e *UnhealthyEcosystemError) Error() string {
-	unhealthyDogusText := e.healthResult.DoguHealth.String()
-	combinedMessage := fmt.Sprintf("%s - %s", e.Message, unhealthyDogusText)
+	combinedMessage := fmt.Sprintf("%s - %s", e.Message, e.healthResult.String())
 	if e.WrappedError != nil {
 		return fmt.Errorf("%s: %w", combinedMessage, e.WrappedError).Error()
 	}
@@ -61,27 +60,36 @@ func (e *DogusNotUpToDateError) Error() string {
 	return e.Message
 }
 
-// MultipleBlueprintsError indicates that there are multiple blueprint-resources in this namespace, which the controller cannot handle.
-type MultipleBlueprintsError struct {
+type StateDiffNotEmptyError struct {
 	Message string
 }
 
-func (e *MultipleBlueprintsError) Error() string {
+func (e *StateDiffNotEmptyError) Error() string {
 	return e.Message
 }
 
-type StateDiffNotEmptyError struct {
+type RestoreInProgressError struct {
 	Message string
 }
 
-func (e *StateDiffNotEmptyError) Error() string {
+func (e *RestoreInProgressError) Error() string {
 	return e.Message
 }
 
-type RestoreInProgressError struct {
+// BackupInProgressError indicates that the blueprint has to wait for a backup to complete.
+type BackupInProgressError struct {
 	Message string
 }
 
-func (e *RestoreInProgressError) Error() string {
+func (e *BackupInProgressError) Error() string {
+	return e.Message
+}
+
+// PlanNotApprovedError indicates that the blueprint has to wait for the approval of its plan.
+type PlanNotApprovedError struct {
+	Message string
+}
+
+func (e *PlanNotApprovedError) Error() string {
 	return e.Message
 }
@@ -3,10 +3,13 @@ package domain
 import (
 	"bytes"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/util"
 )
@@ -88,7 +91,11 @@ func (s StateDiffDeterminedEvent) Message() string {
 
 	doguMessage, doguAmount := getActionAmountMessage(amountActions)
 
-	return fmt.Sprintf("state diff determined:\n  %s\n  %d dogu actions (%s)", s.generateConfigChangeCounter(), doguAmount, doguMessage)
+	message := fmt.Sprintf("state diff determined:\n  %s\n  %d dogu actions (%s)", s.generateConfigChangeCounter(), doguAmount, doguMessage)
+	if prunedDogus := s.doguDiffs.getPrunedDogus(); len(prunedDogus) > 0 {
+		message += fmt.Sprintf("\n  pruned dogus %v: %s", prunedDogus, DoguDiffReasonPruned)
+	}
+	return message
 }
 
 func (s StateDiffDeterminedEvent) generateConfigChangeCounter() string {
@@ -119,7 +126,8 @@ func (s StateDiffDeterminedEvent) generateConfigChangeCounter() string {
 }
 
 type EcosystemHealthyEvent struct {
-	doguHealthIgnored bool
+	doguHealthIgnored      bool
+	componentHealthIgnored bool
 }
 
 func (d EcosystemHealthyEvent) Name() string {
@@ -127,7 +135,7 @@ func (d EcosystemHealthyEvent) Name() string {
 }
 
 func (d EcosystemHealthyEvent) Message() string {
-	return fmt.Sprintf("dogu health ignored: %t", d.doguHealthIgnored)
+	return fmt.Sprintf("dogu health ignored: %t, component health ignored: %t", d.doguHealthIgnored, d.componentHealthIgnored)
 }
 
 type EcosystemUnhealthyEvent struct {
@@ -165,6 +173,120 @@ func (e DogusAppliedEvent) Message() string {
 	return buffer.String()
 }
 
+type PreDowngradeBackupStartedEvent struct {
+	BackupName string
+	Downgrades DoguDiffs
+}
+
+func (e PreDowngradeBackupStartedEvent) Name() string {
+	return "PreDowngradeBackupStarted"
+}
+
+func (e PreDowngradeBackupStartedEvent) Message() string {
+	dogus := util.Map(e.Downgrades, func(diff DoguDiff) string {
+		return fmt.Sprintf("%q", diff.DoguName)
+	})
+	return fmt.Sprintf("started backup %q before downgrading dogus: %s", e.BackupName, strings.Join(dogus, ", "))
+}
+
+type RolloutWaveAppliedEvent struct {
+	Diffs        DoguDiffs
+	PendingWaves int
+}
+
+func (e RolloutWaveAppliedEvent) Name() string {
+	return "RolloutWaveApplied"
+}
+
+func (e RolloutWaveAppliedEvent) Message() string {
+	dogus := util.Map(e.Diffs, func(diff DoguDiff) string {
+		return fmt.Sprintf("%q", diff.DoguName)
+	})
+	return fmt.Sprintf("rollout wave applied with dogus %s, %d wave(s) pending", strings.Join(dogus, ", "), e.PendingWaves)
+}
+
+type PlanApprovalRequiredEvent struct {
+	Hash        string
+	ChangeCount int
+}
+
+func (e PlanApprovalRequiredEvent) Name() string {
+	return "PlanApprovalRequired"
+}
+
+func (e PlanApprovalRequiredEvent) Message() string {
+	return fmt.Sprintf("plan %s with %d change(s) needs to be approved", e.Hash, e.ChangeCount)
+}
+
+type DoguAutoUpgradeEvent struct {
+	DoguName         cescommons.SimpleName
+	BlueprintVersion core.Version
+	TargetVersion    core.Version
+	Policy           AutoUpgradePolicy
+}
+
+func (e DoguAutoUpgradeEvent) Name() string {
+	return "DoguAutoUpgrade"
+}
+
+func (e DoguAutoUpgradeEvent) Message() string {
+	return fmt.Sprintf("dogu %q gets upgraded to %s instead of %s from the blueprint due to auto upgrade policy %q",
+		e.DoguName, e.TargetVersion.Raw, e.BlueprintVersion.Raw, e.Policy)
+}
+
+// DependenciesAutoAddedEvent contains the dogus which were added to the effective blueprint as missing dependencies.
+type DependenciesAutoAddedEvent struct {
+	AddedDogus []Dogu
+}
+
+func (e DependenciesAutoAddedEvent) Name() string {
+	return "DependenciesAutoAdded"
+}
+
+func (e DependenciesAutoAddedEvent) Message() string {
+	return fmt.Sprintf("%d missing dependency dogu(s) added to the effective blueprint: %s", len(e.AddedDogus), formatDoguVersions(e.AddedDogus))
+}
+
+// BlueprintOutdatedEvent contains the dogus of the effective blueprint with newer releases in the remote dogu registry.
+type BlueprintOutdatedEvent struct {
+	OutdatedDogus []DoguUpdates
+}
+
+func (e BlueprintOutdatedEvent) Name() string {
+	return "BlueprintOutdated"
+}
+
+func (e BlueprintOutdatedEvent) Message() string {
+	outdatedDogus := util.Map(e.OutdatedDogus, DoguUpdates.String)
+	return fmt.Sprintf("%d dogu(s) have newer releases: %s", len(outdatedDogus), strings.Join(outdatedDogus, ", "))
+}
+
+// RegistryUnreachableEvent contains the error why no endpoint of the remote dogu registry was reachable.
+type RegistryUnreachableEvent struct {
+	Error error
+}
+
+func (e RegistryUnreachableEvent) Name() string {
+	return "RegistryUnreachable"
+}
+
+func (e RegistryUnreachableEvent) Message() string {
+	return fmt.Sprintf("remote dogu registry is unreachable: %s", e.Error)
+}
+
+// DriftDetectedEvent contains the differences between the blueprint and the ecosystem, which are only reported.
+type DriftDetectedEvent struct {
+	Drift Drift
+}
+
+func (e DriftDetectedEvent) Name() string {
+	return "DriftDetected"
+}
+
+func (e DriftDetectedEvent) Message() string {
+	return fmt.Sprintf("drift is only reported and not corrected: %s", e.Drift)
+}
+
 type DogusNotUpToDateEvent struct {
 	DogusNotUpToDate []cescommons.SimpleName
 }
@@ -244,3 +366,72 @@ func (e EcosystemConfigAppliedEvent) Name() string {
 func (e EcosystemConfigAppliedEvent) Message() string {
 	return "ecosystem config applied"
 }
+
+// BlueprintRevisionCreatedEvent informs about a stored revision with the previous values of the changed config entries.
+type BlueprintRevisionCreatedEvent struct {
+	Revision       int
+	ChangedEntries int
+}
+
+func (e BlueprintRevisionCreatedEvent) Name() string {
+	return "BlueprintRevisionCreated"
+}
+
+func (e BlueprintRevisionCreatedEvent) Message() string {
+	return fmt.Sprintf("stored revision %d with the previous values of %d changed config entries", e.Revision, e.ChangedEntries)
+}
+
+// ConfigRolledBackEvent informs about the restored config of an earlier revision.
+type ConfigRolledBackEvent struct {
+	// Revision is the new revision which records the rollback.
+	Revision       int
+	TargetRevision int
+	ChangedEntries int
+}
+
+func (e ConfigRolledBackEvent) Name() string {
+	return "ConfigRolledBack"
+}
+
+func (e ConfigRolledBackEvent) Message() string {
+	return fmt.Sprintf("restored the config of revision %d with %d changed config entries, stored as revision %d",
+		e.TargetRevision, e.ChangedEntries, e.Revision)
+}
+
+// BlueprintLayersMergedEvent shows which blueprint layer set the dogus and config entries of the merged blueprint.
+type BlueprintLayersMergedEvent struct {
+	Layers     []BlueprintLayer
+	Provenance BlueprintProvenance
+	// SkippedLayersError contains the errors of the invalid layers, which were not merged.
+	SkippedLayersError error
+}
+
+func (e BlueprintLayersMergedEvent) Name() string {
+	return "BlueprintLayersMerged"
+}
+
+func (e BlueprintLayersMergedEvent) Message() string {
+	layers := util.Map(slices.SortedFunc(slices.Values(e.Layers), compareLayers), func(layer BlueprintLayer) string {
+		return fmt.Sprintf("%q (priority %d)", layer.Id, layer.Priority)
+	})
+
+	var origins []string
+	for _, doguName := range slices.Sorted(maps.Keys(e.Provenance.Dogus)) {
+		origins = append(origins, fmt.Sprintf("dogu %q set by blueprint %q", doguName, e.Provenance.Dogus[doguName]))
+	}
+	doguConfigKeys := slices.SortedFunc(maps.Keys(e.Provenance.DoguConfig), func(a, b common.DoguConfigKey) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, key := range doguConfigKeys {
+		origins = append(origins, fmt.Sprintf("config %s set by blueprint %q", key, e.Provenance.DoguConfig[key]))
+	}
+	for _, key := range slices.Sorted(maps.Keys(e.Provenance.GlobalConfig)) {
+		origins = append(origins, fmt.Sprintf("global config key %q set by blueprint %q", key, e.Provenance.GlobalConfig[key]))
+	}
+
+	message := fmt.Sprintf("merged blueprints %s:\n  %s", strings.Join(layers, ", "), strings.Join(origins, "\n  "))
+	if e.SkippedLayersError != nil {
+		message += fmt.Sprintf("\n  skipped invalid blueprint layers: %s\n  dogus are not pruned and owned config is not removed until all layers are valid", e.SkippedLayersError)
+	}
+	return message
+}
@@ -1,10 +1,12 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,13 +28,92 @@ func TestEvents(t *testing.T) {
 			name:            "ecosystem healthy",
 			event:           EcosystemHealthyEvent{},
 			expectedName:    "EcosystemHealthy",
-			expectedMessage: "dogu health ignored: false",
+			expectedMessage: "dogu health ignored: false, component health ignored: false",
 		},
 		{
 			name:            "ignore dogu health",
 			event:           EcosystemHealthyEvent{doguHealthIgnored: true},
 			expectedName:    "EcosystemHealthy",
-			expectedMessage: "dogu health ignored: true",
+			expectedMessage: "dogu health ignored: true, component health ignored: false",
+		},
+		{
+			name:            "ignore component health",
+			event:           EcosystemHealthyEvent{componentHealthIgnored: true},
+			expectedName:    "EcosystemHealthy",
+			expectedMessage: "dogu health ignored: false, component health ignored: true",
+		},
+		{
+			name: "dogu auto upgrade",
+			event: DoguAutoUpgradeEvent{
+				DoguName:         "ldap",
+				BlueprintVersion: version3211,
+				TargetVersion:    version3213,
+				Policy:           AutoUpgradePatch,
+			},
+			expectedName:    "DoguAutoUpgrade",
+			expectedMessage: "dogu \"ldap\" gets upgraded to 3.2.1-3 instead of 3.2.1-1 from the blueprint due to auto upgrade policy \"patch\"",
+		},
+		{
+			name: "dependencies auto added",
+			event: DependenciesAutoAddedEvent{AddedDogus: []Dogu{
+				{Name: officialDogu1, Version: &version3211},
+				{Name: officialDogu2, Version: &version3213},
+			}},
+			expectedName:    "DependenciesAutoAdded",
+			expectedMessage: "2 missing dependency dogu(s) added to the effective blueprint: official/dogu1:3.2.1-1, official/dogu2:3.2.1-3",
+		},
+		{
+			name:            "registry unreachable",
+			event:           RegistryUnreachableEvent{Error: assert.AnError},
+			expectedName:    "RegistryUnreachable",
+			expectedMessage: "remote dogu registry is unreachable: " + assert.AnError.Error(),
+		},
+		{
+			name: "blueprint outdated",
+			event: BlueprintOutdatedEvent{OutdatedDogus: []DoguUpdates{
+				{Name: officialDogu1, Version: version3211, Patch: &version3213},
+			}},
+			expectedName:    "BlueprintOutdated",
+			expectedMessage: "1 dogu(s) have newer releases: official/dogu1:3.2.1-1 (patch 3.2.1-3)",
+		},
+		{
+			name: "drift detected",
+			event: DriftDetectedEvent{Drift: Drift{
+				Dogus:          []cescommons.SimpleName{"redmine"},
+				DoguConfigKeys: []common.DoguConfigKey{{DoguName: "ldap", Key: "logging/root"}},
+			}},
+			expectedName:    "DriftDetected",
+			expectedMessage: "drift is only reported and not corrected: dogus [redmine], dogu config [ldap/logging/root]",
+		},
+		{
+			name: "blueprint layers merged",
+			event: BlueprintLayersMergedEvent{
+				Layers: []BlueprintLayer{{Id: "team-a", Priority: 10}, {Id: "platform-base"}},
+				Provenance: BlueprintProvenance{
+					Dogus:        map[cescommons.SimpleName]string{"redmine": "team-a", "ldap": "platform-base"},
+					DoguConfig:   map[common.DoguConfigKey]string{{DoguName: "redmine", Key: "key1"}: "team-a"},
+					GlobalConfig: map[common.GlobalConfigKey]string{"fqdn": "platform-base"},
+				},
+			},
+			expectedName: "BlueprintLayersMerged",
+			expectedMessage: "merged blueprints \"platform-base\" (priority 0), \"team-a\" (priority 10):\n" +
+				"  dogu \"ldap\" set by blueprint \"platform-base\"\n" +
+				"  dogu \"redmine\" set by blueprint \"team-a\"\n" +
+				"  config key \"key1\" of dogu \"redmine\" set by blueprint \"team-a\"\n" +
+				"  global config key \"fqdn\" set by blueprint \"platform-base\"",
+		},
+		{
+			name: "blueprint layers merged with skipped layers",
+			event: BlueprintLayersMergedEvent{
+				Layers:             []BlueprintLayer{{Id: "platform-base"}},
+				Provenance:         BlueprintProvenance{Dogus: map[cescommons.SimpleName]string{"ldap": "platform-base"}},
+				SkippedLayersError: errors.New("blueprint layer \"team-b\" is invalid"),
+			},
+			expectedName: "BlueprintLayersMerged",
+			expectedMessage: "merged blueprints \"platform-base\" (priority 0):\n" +
+				"  dogu \"ldap\" set by blueprint \"platform-base\"\n" +
+				"  skipped invalid blueprint layers: blueprint layer \"team-b\" is invalid\n" +
+				"  dogus are not pruned and owned config is not removed until all layers are valid",
 		},
 		{
 			name:            "blueprint stopped",
@@ -113,6 +194,18 @@ func TestEvents(t *testing.T) {
 			expectedName:    "StateDiffDetermined",
 			expectedMessage: "state diff determined:\n  2 config changes (\"remove\": 1, \"set\": 1)\n  2 dogu actions (\"install\": 1, \"uninstall\": 1)",
 		},
+		{
+			name: "dogus pruned",
+			event: newStateDiffEvent(StateDiff{
+				DoguDiffs: DoguDiffs{
+					{DoguName: "redmine", NeededActions: []Action{ActionUninstall}, Reason: DoguDiffReasonPruned},
+					{DoguName: "nexus", NeededActions: []Action{ActionUninstall}},
+					{DoguName: "jenkins", NeededActions: []Action{ActionUninstall}, Reason: DoguDiffReasonPruned},
+				},
+			}),
+			expectedName:    "StateDiffDetermined",
+			expectedMessage: "state diff determined:\n  0 config changes ()\n  3 dogu actions (\"uninstall\": 3)\n  pruned dogus [jenkins redmine]: pruned, as the dogu was installed by the blueprint and is not part of it anymore",
+		},
 		{
 			name: "config references missing",
 			event: NewMissingConfigReferencesEvent(
@@ -166,6 +259,42 @@ func TestEvents(t *testing.T) {
 			expectedName:    "EcosystemConfigApplied",
 			expectedMessage: "ecosystem config applied",
 		},
+		{
+			name: "pre-downgrade backup started",
+			event: PreDowngradeBackupStartedEvent{
+				BackupName: "blueprint-downgrade-1234",
+				Downgrades: DoguDiffs{{DoguName: "ldap"}, {DoguName: "postgresql"}},
+			},
+			expectedName:    "PreDowngradeBackupStarted",
+			expectedMessage: "started backup \"blueprint-downgrade-1234\" before downgrading dogus: \"ldap\", \"postgresql\"",
+		},
+		{
+			name: "rollout wave applied",
+			event: RolloutWaveAppliedEvent{
+				Diffs:        DoguDiffs{{DoguName: "ldap"}, {DoguName: "postgresql"}},
+				PendingWaves: 2,
+			},
+			expectedName:    "RolloutWaveApplied",
+			expectedMessage: "rollout wave applied with dogus \"ldap\", \"postgresql\", 2 wave(s) pending",
+		},
+		{
+			name:            "plan approval required",
+			event:           PlanApprovalRequiredEvent{Hash: "1234", ChangeCount: 3},
+			expectedName:    "PlanApprovalRequired",
+			expectedMessage: "plan 1234 with 3 change(s) needs to be approved",
+		},
+		{
+			name:            "blueprint revision created",
+			event:           BlueprintRevisionCreatedEvent{Revision: 2, ChangedEntries: 5},
+			expectedName:    "BlueprintRevisionCreated",
+			expectedMessage: "stored revision 2 with the previous values of 5 changed config entries",
+		},
+		{
+			name:            "config rolled back",
+			event:           ConfigRolledBackEvent{Revision: 3, TargetRevision: 1, ChangedEntries: 4},
+			expectedName:    "ConfigRolledBack",
+			expectedMessage: "restored the config of revision 1 with 4 changed config entries, stored as revision 3",
+		},
 	}
 
 	for _, tt := range tests {
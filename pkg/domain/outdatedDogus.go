@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+// DoguUpdates contains the newest releases of a dogu in the remote dogu registry, which are newer than its version in the effective blueprint.
+// Each release is only contained in the most specific field, e.g. a new patch release is not contained in Minor.
+type DoguUpdates struct {
+	Name cescommons.QualifiedName
+	// Version is the version of the dogu in the effective blueprint.
+	Version core.Version
+	// Patch is the newest release with the same major and minor version.
+	Patch *core.Version
+	// Minor is the newest release with the same major version and a newer minor version.
+	Minor *core.Version
+	// Major is the newest release with a newer major version.
+	Major *core.Version
+}
+
+// NewDoguUpdates determines the newest patch, minor and major releases of the dogu in the given available versions.
+func NewDoguUpdates(name cescommons.QualifiedName, version core.Version, availableVersions []core.Version) DoguUpdates {
+	updates := DoguUpdates{Name: name, Version: version}
+	for _, available := range availableVersions {
+		if !available.IsNewerThan(version) {
+			continue
+		}
+		newestOfKind := &updates.Major
+		if available.Major == version.Major {
+			newestOfKind = &updates.Minor
+			if available.Minor == version.Minor {
+				newestOfKind = &updates.Patch
+			}
+		}
+		if *newestOfKind == nil || available.IsNewerThan(**newestOfKind) {
+			*newestOfKind = &available
+		}
+	}
+	return updates
+}
+
+// IsOutdated checks if there is any newer release of the dogu.
+func (updates DoguUpdates) IsOutdated() bool {
+	return updates.Patch != nil || updates.Minor != nil || updates.Major != nil
+}
+
+// String returns the version of the dogu with its newer releases, e.g. "official/redmine:5.1.3-1 (patch 5.1.3-2, major 6.0.0-1)".
+func (updates DoguUpdates) String() string {
+	var releases []string
+	if updates.Patch != nil {
+		releases = append(releases, "patch "+updates.Patch.Raw)
+	}
+	if updates.Minor != nil {
+		releases = append(releases, "minor "+updates.Minor.Raw)
+	}
+	if updates.Major != nil {
+		releases = append(releases, "major "+updates.Major.Raw)
+	}
+	if len(releases) == 0 {
+		return fmt.Sprintf("%s:%s", updates.Name, updates.Version.Raw)
+	}
+	return fmt.Sprintf("%s:%s (%s)", updates.Name, updates.Version.Raw, strings.Join(releases, ", "))
+}
+
+func (updates DoguUpdates) newerReleases() []core.Version {
+	var releases []core.Version
+	for _, release := range []*core.Version{updates.Patch, updates.Minor, updates.Major} {
+		if release != nil {
+			releases = append(releases, *release)
+		}
+	}
+	return releases
+}
+
+// OutdatedReport compares the dogus of the effective blueprint with the newest releases in the remote dogu registry.
+// It contains all checked dogus, including the dogus which are up to date.
+type OutdatedReport struct {
+	Dogus []DoguUpdates
+}
+
+// GetOutdatedDogus returns all dogus with newer releases.
+func (report OutdatedReport) GetOutdatedDogus() []DoguUpdates {
+	var outdatedDogus []DoguUpdates
+	for _, dogu := range report.Dogus {
+		if dogu.IsOutdated() {
+			outdatedDogus = append(outdatedDogus, dogu)
+		}
+	}
+	return outdatedDogus
+}
+
+// FallsBehind checks if the report contains any newer release, which is not contained in the previous report.
+// This is the case if new releases were published or if the blueprint was changed to older versions.
+func (report OutdatedReport) FallsBehind(previous OutdatedReport) bool {
+	for _, dogu := range report.GetOutdatedDogus() {
+		previousReleases := previous.getNewerReleases(dogu.Name.SimpleName)
+		for _, release := range dogu.newerReleases() {
+			if !slices.ContainsFunc(previousReleases, release.IsEqualTo) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (report OutdatedReport) getNewerReleases(doguName cescommons.SimpleName) []core.Version {
+	for _, dogu := range report.Dogus {
+		if dogu.Name.SimpleName == doguName {
+			return dogu.newerReleases()
+		}
+	}
+	return nil
+}
+
+// ReportOutdatedDogus publishes a BlueprintOutdatedEvent if the report falls behind the previous report,
+// so that admins only get notified about new releases once.
+func (spec *BlueprintSpec) ReportOutdatedDogus(previous OutdatedReport, report OutdatedReport) {
+	if report.FallsBehind(previous) {
+		spec.Events = append(spec.Events, BlueprintOutdatedEvent{OutdatedDogus: report.GetOutdatedDogus()})
+	}
+}
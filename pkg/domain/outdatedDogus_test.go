@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDoguUpdates(t *testing.T) {
+	t.Run("determine newest patch, minor and major release", func(t *testing.T) {
+		// given
+		availableVersions := []core.Version{
+			mustParseVersion(t, "3.2.1-1"),
+			mustParseVersion(t, "3.2.1-3"),
+			mustParseVersion(t, "3.2.2-1"),
+			mustParseVersion(t, "3.3.0-1"),
+			mustParseVersion(t, "3.4.1-2"),
+			mustParseVersion(t, "4.0.0-1"),
+			mustParseVersion(t, "5.1.0-1"),
+			mustParseVersion(t, "2.9.9-9"),
+		}
+
+		// when
+		updates := NewDoguUpdates(officialDogu1, version3211, availableVersions)
+
+		// then
+		patch := mustParseVersion(t, "3.2.2-1")
+		minor := mustParseVersion(t, "3.4.1-2")
+		major := mustParseVersion(t, "5.1.0-1")
+		assert.Equal(t, DoguUpdates{Name: officialDogu1, Version: version3211, Patch: &patch, Minor: &minor, Major: &major}, updates)
+		assert.True(t, updates.IsOutdated())
+		assert.Equal(t, "official/dogu1:3.2.1-1 (patch 3.2.2-1, minor 3.4.1-2, major 5.1.0-1)", updates.String())
+	})
+	t.Run("up to date without newer releases", func(t *testing.T) {
+		// when
+		updates := NewDoguUpdates(officialDogu1, version3213, []core.Version{version3211, version3213})
+
+		// then
+		assert.Equal(t, DoguUpdates{Name: officialDogu1, Version: version3213}, updates)
+		assert.False(t, updates.IsOutdated())
+		assert.Equal(t, "official/dogu1:3.2.1-3", updates.String())
+	})
+}
+
+func TestOutdatedReport_FallsBehind(t *testing.T) {
+	upToDate := OutdatedReport{Dogus: []DoguUpdates{{Name: officialDogu1, Version: version3213}}}
+	patchAvailable := OutdatedReport{Dogus: []DoguUpdates{{Name: officialDogu1, Version: version3211, Patch: &version3213}}}
+	newerPatch := mustParseVersion(t, "3.2.2-1")
+	newerPatchAvailable := OutdatedReport{Dogus: []DoguUpdates{{Name: officialDogu1, Version: version3211, Patch: &newerPatch}}}
+
+	tests := []struct {
+		name     string
+		report   OutdatedReport
+		previous OutdatedReport
+		want     bool
+	}{
+		{name: "up to date", report: upToDate, previous: OutdatedReport{}, want: false},
+		{name: "first report with new release", report: patchAvailable, previous: OutdatedReport{}, want: true},
+		{name: "new release", report: patchAvailable, previous: upToDate, want: true},
+		{name: "same release", report: patchAvailable, previous: patchAvailable, want: false},
+		{name: "even newer release", report: newerPatchAvailable, previous: patchAvailable, want: true},
+		{name: "updated to newest release", report: upToDate, previous: patchAvailable, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.report.FallsBehind(tt.previous))
+		})
+	}
+}
+
+func TestBlueprintSpec_ReportOutdatedDogus(t *testing.T) {
+	outdatedDogu := DoguUpdates{Name: officialDogu1, Version: version3211, Patch: &version3213}
+	report := OutdatedReport{Dogus: []DoguUpdates{outdatedDogu, {Name: officialDogu2, Version: version3213}}}
+
+	t.Run("publish event if blueprint falls behind", func(t *testing.T) {
+		spec := &BlueprintSpec{}
+
+		spec.ReportOutdatedDogus(OutdatedReport{}, report)
+
+		assert.Equal(t, []Event{BlueprintOutdatedEvent{OutdatedDogus: []DoguUpdates{outdatedDogu}}}, spec.Events)
+	})
+	t.Run("publish no event for known releases", func(t *testing.T) {
+		spec := &BlueprintSpec{}
+
+		spec.ReportOutdatedDogus(report, report)
+
+		assert.Empty(t, spec.Events)
+	})
+}
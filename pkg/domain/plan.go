@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Plan is the frozen list of changes of a StateDiff.
+// If the blueprint requires a plan approval, the changes only get applied after the hash of the plan was approved.
+type Plan struct {
+	BlueprintId string
+	// Changes contains one line per change, sorted to be independent of the order in the StateDiff.
+	Changes []string
+}
+
+// NewPlan creates the plan for all changes in the state diff.
+// Sensitive config values are only contained as HMAC with the given secret key, so that the plan can be shown to
+// everyone who approves it without revealing the values, not even to someone who guesses and hashes them.
+func NewPlan(blueprintId string, diff StateDiff, sensitiveValueKey []byte) (Plan, error) {
+	var changes []string
+	var errs []error
+	addChange := func(kind string, change any) {
+		serializedChange, err := json.Marshal(change)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		changes = append(changes, kind+" "+string(serializedChange))
+	}
+
+	for _, doguDiff := range diff.DoguDiffs {
+		if !doguDiff.HasChanges() {
+			continue
+		}
+		// the installed version changes while dogus are upgraded, but this is no change of the plan
+		doguDiff.Actual.InstalledVersion = nil
+		doguDiff.Expected.InstalledVersion = nil
+		addChange("dogu", doguDiff)
+	}
+	for _, configDiff := range diff.GlobalConfigDiffs {
+		if configDiff.NeededAction != ConfigActionNone {
+			addChange("globalConfig", configDiff)
+		}
+	}
+	for _, configDiffs := range diff.DoguConfigDiffs {
+		for _, configDiff := range configDiffs {
+			if configDiff.NeededAction != ConfigActionNone {
+				addChange("doguConfig", configDiff)
+			}
+		}
+	}
+	for _, configDiffs := range diff.SensitiveDoguConfigDiffs {
+		for _, configDiff := range configDiffs {
+			if configDiff.NeededAction != ConfigActionNone {
+				if len(sensitiveValueKey) == 0 {
+					errs = append(errs, errors.New("the key to hash sensitive config values is missing"))
+					continue
+				}
+				configDiff.Actual.Value = hashSensitiveValue(configDiff.Actual.Value, sensitiveValueKey)
+				configDiff.Expected.Value = hashSensitiveValue(configDiff.Expected.Value, sensitiveValueKey)
+				addChange("sensitiveDoguConfig", configDiff)
+			}
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return Plan{}, fmt.Errorf("cannot create plan for blueprint %q: %w", blueprintId, err)
+	}
+	slices.Sort(changes)
+	return Plan{BlueprintId: blueprintId, Changes: changes}, nil
+}
+
+func hashSensitiveValue(value *string, key []byte) *string {
+	if value == nil {
+		return nil
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(*value))
+	hashedValue := "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+	return &hashedValue
+}
+
+// Hash returns the content hash of the plan. This hash has to be approved before the plan gets applied.
+func (plan Plan) Hash() string {
+	hash := sha256.Sum256([]byte(plan.BlueprintId + "\n" + strings.Join(plan.Changes, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// Contains returns true if all changes of the other plan are part of this plan.
+// A plan which is partially applied is still contained in the original plan, but a drifted ecosystem leads to
+// changes which were not part of the original plan.
+func (plan Plan) Contains(other Plan) bool {
+	if plan.BlueprintId != other.BlueprintId {
+		return false
+	}
+	for _, change := range other.Changes {
+		if !slices.Contains(plan.Changes, change) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSensitiveValueKey = []byte("test-key")
+
+func TestNewPlan(t *testing.T) {
+	secret := "my-secret"
+	postgresqlUpgrade := DoguDiff{
+		DoguName:      "postgresql",
+		Actual:        DoguDiffState{Namespace: "official", Version: &version3211, InstalledVersion: &version3211},
+		Expected:      DoguDiffState{Namespace: "official", Version: &version3212},
+		NeededActions: []Action{ActionUpgrade},
+	}
+	diff := StateDiff{
+		DoguDiffs: DoguDiffs{
+			{DoguName: "ldap", Actual: DoguDiffState{Namespace: "official", Version: &version3211}},
+			postgresqlUpgrade,
+		},
+		GlobalConfigDiffs: GlobalConfigDiffs{
+			{Key: "fqdn", NeededAction: ConfigActionNone},
+			{Key: "admin_group", NeededAction: ConfigActionSet},
+		},
+		DoguConfigDiffs: map[cescommons.SimpleName]DoguConfigDiffs{
+			"ldap": {{Key: common.DoguConfigKey{DoguName: "ldap", Key: "container_config/memory_limit"}, NeededAction: ConfigActionRemove}},
+		},
+		SensitiveDoguConfigDiffs: map[cescommons.SimpleName]SensitiveDoguConfigDiffs{
+			"ldap": {{
+				Key:          common.DoguConfigKey{DoguName: "ldap", Key: "password"},
+				Expected:     DoguConfigValueState{Value: &secret, Exists: true},
+				NeededAction: ConfigActionSet,
+			}},
+		},
+	}
+
+	t.Run("contain only changes in sorted order", func(t *testing.T) {
+		plan, err := NewPlan("my-blueprint", diff, testSensitiveValueKey)
+
+		require.NoError(t, err)
+		assert.Equal(t, "my-blueprint", plan.BlueprintId)
+		require.Len(t, plan.Changes, 4)
+		assert.Contains(t, plan.Changes[0], "dogu ")
+		assert.Contains(t, plan.Changes[0], "\"DoguName\":\"postgresql\"")
+		assert.Contains(t, plan.Changes[1], "doguConfig ")
+		assert.Contains(t, plan.Changes[2], "globalConfig ")
+		assert.Contains(t, plan.Changes[2], "admin_group")
+		assert.Contains(t, plan.Changes[3], "sensitiveDoguConfig ")
+	})
+
+	t.Run("hash sensitive values with the key", func(t *testing.T) {
+		plan, err := NewPlan("my-blueprint", diff, testSensitiveValueKey)
+
+		require.NoError(t, err)
+		assert.NotContains(t, plan.Changes[3], secret)
+		assert.Contains(t, plan.Changes[3], "hmac-sha256:")
+		// the unkeyed hash of a guessed value must not match
+		assert.NotContains(t, plan.Changes[3], "186ef76e9d6a723ecb570d4d9c287487d001e5d35f7ed4a313350a407950318e")
+
+		otherKeyPlan, err := NewPlan("my-blueprint", diff, []byte("other-key"))
+		require.NoError(t, err)
+		assert.NotEqual(t, plan.Changes[3], otherKeyPlan.Changes[3])
+		samePlan, err := NewPlan("my-blueprint", diff, testSensitiveValueKey)
+		require.NoError(t, err)
+		assert.Equal(t, plan.Hash(), samePlan.Hash())
+	})
+
+	t.Run("fail on sensitive values without key", func(t *testing.T) {
+		_, err := NewPlan("my-blueprint", diff, nil)
+
+		assert.ErrorContains(t, err, "cannot create plan for blueprint \"my-blueprint\": the key to hash sensitive config values is missing")
+	})
+
+	t.Run("ignore installed version", func(t *testing.T) {
+		plan, err := NewPlan("my-blueprint", diff, testSensitiveValueKey)
+		require.NoError(t, err)
+
+		upgradingDiff := StateDiff{DoguDiffs: DoguDiffs{postgresqlUpgrade}}
+		upgradingDiff.DoguDiffs[0].Actual.InstalledVersion = &version3212
+		upgradingPlan, err := NewPlan("my-blueprint", upgradingDiff, testSensitiveValueKey)
+		require.NoError(t, err)
+
+		assert.True(t, plan.Contains(upgradingPlan))
+	})
+}
+
+func TestPlan_Hash(t *testing.T) {
+	plan := Plan{BlueprintId: "my-blueprint", Changes: []string{"a", "b"}}
+
+	assert.Len(t, plan.Hash(), 64)
+	assert.Equal(t, plan.Hash(), Plan{BlueprintId: "my-blueprint", Changes: []string{"a", "b"}}.Hash())
+	assert.NotEqual(t, plan.Hash(), Plan{BlueprintId: "my-blueprint", Changes: []string{"a"}}.Hash())
+	assert.NotEqual(t, plan.Hash(), Plan{BlueprintId: "other-blueprint", Changes: []string{"a", "b"}}.Hash())
+}
+
+func TestPlan_Contains(t *testing.T) {
+	plan := Plan{BlueprintId: "my-blueprint", Changes: []string{"a", "b"}}
+
+	assert.True(t, plan.Contains(plan))
+	assert.True(t, plan.Contains(Plan{BlueprintId: "my-blueprint", Changes: []string{"b"}}))
+	assert.True(t, plan.Contains(Plan{BlueprintId: "my-blueprint"}))
+	assert.False(t, plan.Contains(Plan{BlueprintId: "my-blueprint", Changes: []string{"b", "c"}}))
+	assert.False(t, plan.Contains(Plan{BlueprintId: "other-blueprint", Changes: []string{"a"}}))
+}
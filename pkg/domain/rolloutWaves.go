@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+)
+
+// RolloutWaves defines in which groups the dogu changes of a blueprint get applied.
+// The next wave only starts after all dogus are healthy and up to date again.
+// If no waves are defined, all dogu changes get applied at once.
+type RolloutWaves struct {
+	// ByDependencyDepth derives the waves from the dependencies between the dogus.
+	// Uninstalls form the first wave. After that, every wave contains the dogus whose changed dependencies
+	// were applied in the waves before.
+	ByDependencyDepth bool
+	// Explicit contains the dogu names of every wave in the order they should be applied.
+	// Changed dogus which are not part of any wave get applied in an additional last wave.
+	Explicit [][]cescommons.SimpleName
+}
+
+// IsEnabled returns true if the dogu changes should be applied in more than one wave.
+func (waves RolloutWaves) IsEnabled() bool {
+	return waves.ByDependencyDepth || len(waves.Explicit) > 0
+}
+
+// Validate checks that the waves are either derived from dependencies or explicit and that no dogu is part of more
+// than one explicit wave.
+func (waves RolloutWaves) Validate() error {
+	if waves.ByDependencyDepth && len(waves.Explicit) > 0 {
+		return errors.New("rollout waves cannot be derived from dependencies and be defined explicitly at the same time")
+	}
+
+	var errs []error
+	seen := map[cescommons.SimpleName]struct{}{}
+	for i, wave := range waves.Explicit {
+		if len(wave) == 0 {
+			errs = append(errs, fmt.Errorf("rollout wave %d is empty", i+1))
+		}
+		for _, doguName := range wave {
+			if _, found := seen[doguName]; found {
+				errs = append(errs, fmt.Errorf("dogu %q is part of more than one rollout wave", doguName))
+			}
+			seen[doguName] = struct{}{}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GroupIntoWaves returns the changed diffs grouped into the waves in which they should be applied.
+// Waves without changes are omitted, so the first wave is always the next one to apply.
+// Within a wave, the diffs are sorted like in SortByDependencies.
+// If no waves are enabled, all changes form one wave.
+// returns an error if the dependencies are cyclic.
+func (diffs DoguDiffs) GroupIntoWaves(waves RolloutWaves, dependencies map[cescommons.SimpleName][]cescommons.SimpleName) ([]DoguDiffs, error) {
+	sortedDiffs, err := diffs.SortByDependencies(dependencies)
+	if err != nil {
+		return nil, err
+	}
+	changes := slices.DeleteFunc(sortedDiffs, func(diff DoguDiff) bool {
+		return !diff.HasChanges()
+	})
+
+	var result []DoguDiffs
+	switch {
+	case waves.ByDependencyDepth:
+		// cannot fail anymore as cycles were already detected while sorting
+		order, _ := diffs.getDependencyOrder(dependencies)
+		result = groupByDependencyDepth(changes, diffs.getWaveIndexByDependencyDepth(order, dependencies))
+	case len(waves.Explicit) > 0:
+		result = groupByExplicitWaves(changes, waves.Explicit)
+	default:
+		result = []DoguDiffs{changes}
+	}
+
+	return slices.DeleteFunc(result, func(wave DoguDiffs) bool {
+		return len(wave) == 0
+	}), nil
+}
+
+// getWaveIndexByDependencyDepth calculates the wave for every changed dogu. Uninstalls are in the first wave.
+// Every other change comes one wave after the last changed dogu it depends on, also transitively over unchanged dogus.
+// order must contain the dogus of the diffs with dependencies first.
+func (diffs DoguDiffs) getWaveIndexByDependencyDepth(order []cescommons.SimpleName, dependencies map[cescommons.SimpleName][]cescommons.SimpleName) map[cescommons.SimpleName]int {
+	diffsByName := map[cescommons.SimpleName]DoguDiff{}
+	for _, diff := range diffs {
+		diffsByName[diff.DoguName] = diff
+	}
+
+	waveIndex := map[cescommons.SimpleName]int{}
+	// readyAfter contains the wave after which a dogu and all its dependencies are applied
+	readyAfter := map[cescommons.SimpleName]int{}
+	for _, doguName := range order {
+		diff := diffsByName[doguName]
+		if slices.Contains(diff.NeededActions, ActionUninstall) {
+			waveIndex[doguName] = 0
+			continue
+		}
+
+		dependenciesReadyAfter := 0
+		for _, dependency := range dependencies[doguName] {
+			if dependency != doguName {
+				dependenciesReadyAfter = max(dependenciesReadyAfter, readyAfter[dependency])
+			}
+		}
+
+		if diff.HasChanges() {
+			// the first wave is reserved for uninstalls
+			waveIndex[doguName] = dependenciesReadyAfter + 1
+			readyAfter[doguName] = dependenciesReadyAfter + 1
+		} else {
+			readyAfter[doguName] = dependenciesReadyAfter
+		}
+	}
+	return waveIndex
+}
+
+func groupByDependencyDepth(changes DoguDiffs, waveIndex map[cescommons.SimpleName]int) []DoguDiffs {
+	var result []DoguDiffs
+	for _, diff := range changes {
+		index := waveIndex[diff.DoguName]
+		for len(result) <= index {
+			result = append(result, DoguDiffs{})
+		}
+		result[index] = append(result[index], diff)
+	}
+	return result
+}
+
+func groupByExplicitWaves(changes DoguDiffs, explicitWaves [][]cescommons.SimpleName) []DoguDiffs {
+	result := make([]DoguDiffs, len(explicitWaves)+1)
+	for _, diff := range changes {
+		waveIndex := slices.IndexFunc(explicitWaves, func(wave []cescommons.SimpleName) bool {
+			return slices.Contains(wave, diff.DoguName)
+		})
+		if waveIndex < 0 {
+			// dogus without a wave come last
+			waveIndex = len(explicitWaves)
+		}
+		result[waveIndex] = append(result[waveIndex], diff)
+	}
+	return result
+}
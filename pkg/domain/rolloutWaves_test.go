@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRolloutWaves_IsEnabled(t *testing.T) {
+	assert.False(t, RolloutWaves{}.IsEnabled())
+	assert.True(t, RolloutWaves{ByDependencyDepth: true}.IsEnabled())
+	assert.True(t, RolloutWaves{Explicit: [][]cescommons.SimpleName{{"ldap"}}}.IsEnabled())
+}
+
+func TestRolloutWaves_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, RolloutWaves{}.Validate())
+		assert.NoError(t, RolloutWaves{ByDependencyDepth: true}.Validate())
+		assert.NoError(t, RolloutWaves{Explicit: [][]cescommons.SimpleName{{"ldap", "postgresql"}, {"cas"}}}.Validate())
+	})
+
+	t.Run("dependencies and explicit waves", func(t *testing.T) {
+		err := RolloutWaves{ByDependencyDepth: true, Explicit: [][]cescommons.SimpleName{{"ldap"}}}.Validate()
+
+		assert.ErrorContains(t, err, "rollout waves cannot be derived from dependencies and be defined explicitly at the same time")
+	})
+
+	t.Run("empty wave and dogu in multiple waves", func(t *testing.T) {
+		err := RolloutWaves{Explicit: [][]cescommons.SimpleName{{"ldap"}, {}, {"cas", "ldap"}}}.Validate()
+
+		assert.ErrorContains(t, err, "rollout wave 2 is empty")
+		assert.ErrorContains(t, err, "dogu \"ldap\" is part of more than one rollout wave")
+	})
+}
+
+func TestDoguDiffs_GroupIntoWaves(t *testing.T) {
+	install := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name, NeededActions: []Action{ActionInstall}}
+	}
+	upgrade := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name, NeededActions: []Action{ActionUpgrade}}
+	}
+	uninstall := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name, NeededActions: []Action{ActionUninstall}}
+	}
+	unchanged := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name}
+	}
+	dependencies := map[cescommons.SimpleName][]cescommons.SimpleName{
+		"redmine": {"postgresql", "cas"},
+		"cas":     {"ldap"},
+		"scm":     {"cas"},
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		waves, err := DoguDiffs{unchanged("ldap")}.GroupIntoWaves(RolloutWaves{ByDependencyDepth: true}, dependencies)
+
+		require.NoError(t, err)
+		assert.Empty(t, waves)
+	})
+
+	t.Run("one wave if waves are disabled", func(t *testing.T) {
+		diffs := DoguDiffs{upgrade("redmine"), unchanged("scm"), upgrade("ldap")}
+
+		waves, err := diffs.GroupIntoWaves(RolloutWaves{}, dependencies)
+
+		require.NoError(t, err)
+		assert.Equal(t, []DoguDiffs{{upgrade("ldap"), upgrade("redmine")}}, waves)
+	})
+
+	t.Run("by dependency depth", func(t *testing.T) {
+		diffs := DoguDiffs{install("redmine"), uninstall("plantuml"), upgrade("postgresql"), upgrade("cas"), upgrade("ldap"), install("scm")}
+
+		waves, err := diffs.GroupIntoWaves(RolloutWaves{ByDependencyDepth: true}, dependencies)
+
+		require.NoError(t, err)
+		expected := []DoguDiffs{
+			{uninstall("plantuml")},
+			{upgrade("ldap"), upgrade("postgresql")},
+			{upgrade("cas")},
+			{install("redmine"), install("scm")},
+		}
+		assert.Equal(t, expected, waves)
+	})
+
+	t.Run("by dependency depth over unchanged dogus", func(t *testing.T) {
+		diffs := DoguDiffs{upgrade("redmine"), unchanged("cas"), upgrade("ldap"), unchanged("postgresql")}
+
+		waves, err := diffs.GroupIntoWaves(RolloutWaves{ByDependencyDepth: true}, dependencies)
+
+		require.NoError(t, err)
+		assert.Equal(t, []DoguDiffs{{upgrade("ldap")}, {upgrade("redmine")}}, waves)
+	})
+
+	t.Run("explicit waves with remaining dogus in the last wave", func(t *testing.T) {
+		diffs := DoguDiffs{install("redmine"), upgrade("postgresql"), upgrade("cas"), upgrade("ldap"), install("scm")}
+		explicitWaves := RolloutWaves{Explicit: [][]cescommons.SimpleName{{"postgresql", "nginx"}, {"jenkins"}, {"redmine", "ldap"}}}
+
+		waves, err := diffs.GroupIntoWaves(explicitWaves, dependencies)
+
+		require.NoError(t, err)
+		expected := []DoguDiffs{
+			{upgrade("postgresql")},
+			{upgrade("ldap"), install("redmine")},
+			{upgrade("cas"), install("scm")},
+		}
+		assert.Equal(t, expected, waves)
+	})
+
+	t.Run("cyclic dependencies", func(t *testing.T) {
+		diffs := DoguDiffs{upgrade("cas"), upgrade("ldap")}
+		cyclicDependencies := map[cescommons.SimpleName][]cescommons.SimpleName{
+			"cas":  {"ldap"},
+			"ldap": {"cas"},
+		}
+
+		_, err := diffs.GroupIntoWaves(RolloutWaves{ByDependencyDepth: true}, cyclicDependencies)
+
+		assert.ErrorContains(t, err, "cyclic dependencies between [cas ldap]")
+	})
+}
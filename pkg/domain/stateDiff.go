@@ -32,6 +32,8 @@ const (
 	ActionUpdateDoguResourceMinVolumeSize = bpv3.DoguActionUpdateResourceMinVolumeSize
 	// ActionUpdateAdditionalMounts means the additional mounts should be updated for the dogu
 	ActionUpdateAdditionalMounts = bpv3.DoguActionUpdateAdditionalMounts
+	// ActionUpdateReverseProxyConfig means the reverse proxy config of the dogu needs to be updated
+	ActionUpdateReverseProxyConfig = bpv3.DoguActionUpdateReverseProxyConfig
 )
 
 func (diff StateDiff) HasChanges() bool {
@@ -22,23 +22,61 @@ func (diffs DoguDiffs) HasChanges() bool {
 	return false
 }
 
+// GetDowngrades returns all diffs which need a downgrade of the dogu.
+func (diffs DoguDiffs) GetDowngrades() DoguDiffs {
+	var downgrades DoguDiffs
+	for _, diff := range diffs {
+		if slices.Contains(diff.NeededActions, ActionDowngrade) {
+			downgrades = append(downgrades, diff)
+		}
+	}
+	return downgrades
+}
+
+// getPrunedDogus returns the sorted names of all dogus, which get uninstalled as they are pruned.
+func (diffs DoguDiffs) getPrunedDogus() []cescommons.SimpleName {
+	var pruned []cescommons.SimpleName
+	for _, diff := range diffs {
+		if diff.Reason == DoguDiffReasonPruned && diff.HasChanges() {
+			pruned = append(pruned, diff.DoguName)
+		}
+	}
+	slices.Sort(pruned)
+	return pruned
+}
+
+// GetDoguNames returns the names of the dogus in the order of the diffs.
+func (diffs DoguDiffs) GetDoguNames() []cescommons.SimpleName {
+	names := make([]cescommons.SimpleName, 0, len(diffs))
+	for _, diff := range diffs {
+		names = append(names, diff.DoguName)
+	}
+	return names
+}
+
+// DoguDiffReasonPruned is the reason of uninstalls of dogus, which were removed from the blueprint in prune mode.
+const DoguDiffReasonPruned = "pruned, as the dogu was installed by the blueprint and is not part of it anymore"
+
 // DoguDiff represents the Diff for a single expected Dogu to the current ecosystem.DoguInstallation.
 type DoguDiff struct {
 	DoguName      cescommons.SimpleName
 	Actual        DoguDiffState
 	Expected      DoguDiffState
 	NeededActions []Action
+	// Reason explains needed actions, which do not result from the blueprint directly.
+	Reason string
 }
 
 // DoguDiffState contains all fields to make a diff for dogus in respect to another DoguDiffState.
 type DoguDiffState struct {
-	Namespace        cescommons.Namespace
-	Version          *core.Version
-	InstalledVersion *core.Version
-	Absent           bool
-	MinVolumeSize    *ecosystem.VolumeSize
-	StorageClassName *string
-	AdditionalMounts []ecosystem.AdditionalMount
+	Namespace          cescommons.Namespace
+	Version            *core.Version
+	InstalledVersion   *core.Version
+	Absent             bool
+	MinVolumeSize      *ecosystem.VolumeSize
+	StorageClassName   *string
+	AdditionalMounts   []ecosystem.AdditionalMount
+	ReverseProxyConfig ecosystem.ReverseProxyConfig
 }
 
 func (diff DoguDiff) HasChanges() bool {
@@ -47,6 +85,16 @@ func (diff DoguDiff) HasChanges() bool {
 
 // String returns a string representation of the DoguDiff.
 func (diff *DoguDiff) String() string {
+	if diff.Reason != "" {
+		return fmt.Sprintf(
+			"{DoguName: %q, Actual: %s, Expected: %s, NeededActions: %q, Reason: %q}",
+			diff.DoguName,
+			diff.Actual.String(),
+			diff.Expected.String(),
+			diff.NeededActions,
+			diff.Reason,
+		)
+	}
 	return fmt.Sprintf(
 		"{DoguName: %q, Actual: %s, Expected: %s, NeededActions: %q}",
 		diff.DoguName,
@@ -75,8 +123,10 @@ func (diff *DoguDiffState) getSafeVersionString() string {
 }
 
 // determineDoguDiffs creates DoguDiffs for all dogus in the blueprint and all installed dogus as well.
+// Installed dogus, which are not in the blueprint, get uninstalled if they were installed by the blueprint with the
+// prunedBlueprintId. Pass an empty prunedBlueprintId to keep all installed dogus.
 // see determineDoguDiff for more information.
-func determineDoguDiffs(blueprintDogus []Dogu, installedDogus map[cescommons.SimpleName]*ecosystem.DoguInstallation) []DoguDiff {
+func determineDoguDiffs(blueprintDogus []Dogu, installedDogus map[cescommons.SimpleName]*ecosystem.DoguInstallation, prunedBlueprintId string) []DoguDiff {
 	var doguDiffs = map[cescommons.SimpleName]DoguDiff{}
 	for _, blueprintDogu := range blueprintDogus {
 		installedDogu := installedDogus[blueprintDogu.Name.SimpleName]
@@ -91,6 +141,12 @@ func determineDoguDiffs(blueprintDogus []Dogu, installedDogus map[cescommons.Sim
 		// Only create DoguDiff if the installed dogu is not found in the blueprint.
 		// If the installed dogu is in blueprint the DoguDiff was already determined above.
 		if !found {
+			if prunedBlueprintId != "" && installedDogu.InstalledByBlueprint == prunedBlueprintId {
+				prunedDiff := determineDoguDiff(&Dogu{Name: installedDogu.Name, Absent: true}, installedDogu)
+				prunedDiff.Reason = DoguDiffReasonPruned
+				doguDiffs[installedDogu.Name.SimpleName] = *prunedDiff
+				continue
+			}
 			determinedDoguDiff := determineDoguDiff(nil, installedDogu)
 			// only add changes to diff
 			if determinedDoguDiff != nil {
@@ -116,12 +172,13 @@ func determineDoguDiff(blueprintDogu *Dogu, installedDogu *ecosystem.DoguInstall
 	} else {
 		doguName = installedDogu.Name.SimpleName
 		actualState = DoguDiffState{
-			Namespace:        installedDogu.Name.Namespace,
-			Version:          &installedDogu.Version,
-			InstalledVersion: &installedDogu.InstalledVersion,
-			MinVolumeSize:    installedDogu.MinVolumeSize,
-			StorageClassName: installedDogu.StorageClassName,
-			AdditionalMounts: installedDogu.AdditionalMounts,
+			Namespace:          installedDogu.Name.Namespace,
+			Version:            &installedDogu.Version,
+			InstalledVersion:   &installedDogu.InstalledVersion,
+			MinVolumeSize:      installedDogu.MinVolumeSize,
+			StorageClassName:   installedDogu.StorageClassName,
+			AdditionalMounts:   installedDogu.AdditionalMounts,
+			ReverseProxyConfig: installedDogu.ReverseProxyConfig,
 		}
 	}
 
@@ -130,12 +187,13 @@ func determineDoguDiff(blueprintDogu *Dogu, installedDogu *ecosystem.DoguInstall
 	} else {
 		doguName = blueprintDogu.Name.SimpleName
 		expectedState = DoguDiffState{
-			Namespace:        blueprintDogu.Name.Namespace,
-			Version:          blueprintDogu.Version,
-			Absent:           blueprintDogu.Absent,
-			MinVolumeSize:    blueprintDogu.MinVolumeSize,
-			StorageClassName: blueprintDogu.StorageClassName,
-			AdditionalMounts: blueprintDogu.AdditionalMounts,
+			Namespace:          blueprintDogu.Name.Namespace,
+			Version:            blueprintDogu.Version,
+			Absent:             blueprintDogu.Absent,
+			MinVolumeSize:      blueprintDogu.MinVolumeSize,
+			StorageClassName:   blueprintDogu.StorageClassName,
+			AdditionalMounts:   blueprintDogu.AdditionalMounts,
+			ReverseProxyConfig: blueprintDogu.ReverseProxyConfig,
 		}
 	}
 
@@ -177,6 +235,7 @@ func getActionsForPresentDoguDiffs(expected DoguDiffState, actual DoguDiffState)
 
 	neededActions = appendActionForMinVolumeSize(neededActions, expected.MinVolumeSize, actual.MinVolumeSize)
 	neededActions = appendActionForAdditionalMounts(neededActions, expected.AdditionalMounts, actual.AdditionalMounts)
+	neededActions = appendActionForReverseProxyConfig(neededActions, expected.ReverseProxyConfig, actual.ReverseProxyConfig)
 
 	if expected.Version != nil && actual.Version != nil && expected.Version.IsNewerThan(*actual.Version) {
 		neededActions = append(neededActions, ActionUpgrade)
@@ -199,6 +258,14 @@ func appendActionForMinVolumeSize(actions []Action, expectedSize *ecosystem.Volu
 	return actions
 }
 
+func appendActionForReverseProxyConfig(actions []Action, expectedConfig ecosystem.ReverseProxyConfig, actualConfig ecosystem.ReverseProxyConfig) []Action {
+	// only the settings of the blueprint are compared, so that settings of an admin stay untouched
+	if !expectedConfig.IsSatisfiedBy(actualConfig) {
+		return append(actions, ActionUpdateReverseProxyConfig)
+	}
+	return actions
+}
+
 func appendActionForAdditionalMounts(actions []Action, expectedMounts []ecosystem.AdditionalMount, actualMounts []ecosystem.AdditionalMount) []Action {
 	if !areAdditionalMountsEqual(expectedMounts, actualMounts) {
 		return append(actions, ActionUpdateAdditionalMounts)
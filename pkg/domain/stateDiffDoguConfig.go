@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"maps"
+	"slices"
+	"strings"
+
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
 	"github.com/cloudogu/k8s-registry-lib/config"
@@ -71,7 +75,7 @@ func newDoguConfigEntryDiff(
 
 func determineDoguConfigDiffs(doguName cescommons.SimpleName, wantedConfig DoguConfigEntries, actualConfig map[cescommons.SimpleName]config.DoguConfig, isSensitive bool) DoguConfigDiffs {
 	var doguConfigDiff []DoguConfigEntryDiff
-	for _, expectedConfig := range wantedConfig {
+	for _, expectedConfig := range expandConfigKeyPatterns(wantedConfig, actualConfig[doguName]) {
 		if expectedConfig.Sensitive != isSensitive {
 			continue // skip if not match sensitivity
 		}
@@ -93,3 +97,51 @@ func determineDoguConfigDiffs(doguName cescommons.SimpleName, wantedConfig DoguC
 	}
 	return doguConfigDiff
 }
+
+// configKeyPatternSuffix marks the key of an absent entry as pattern for all keys below the key prefix, e.g. ldap-mapper/*.
+const configKeyPatternSuffix = "/*"
+
+// isKeyPattern checks if the entry removes all keys below a key prefix like ldap-mapper/* instead of a single config key.
+// Only absent entries can have key patterns, the keys of present entries are always single config keys.
+func (config ConfigEntry) isKeyPattern() bool {
+	return config.Absent && strings.HasSuffix(string(config.Key), configKeyPatternSuffix)
+}
+
+// matchesConfigKeyPattern checks if the key is below the key prefix of the pattern,
+// so that a pattern like ldap-mapper/* also matches the keys in deeper levels like ldap-mapper/backend/type.
+func matchesConfigKeyPattern(pattern config.Key, key config.Key) bool {
+	prefix := strings.TrimSuffix(string(pattern), "*")
+	return strings.HasPrefix(string(key), prefix)
+}
+
+// expandConfigKeyPatterns replaces the absent entries with key patterns with one absent entry
+// for every matching key in the actual config.
+// Keys which are explicitly in the blueprint config are not expanded, so that they can be kept or set.
+func expandConfigKeyPatterns(wantedConfig DoguConfigEntries, actualConfig config.DoguConfig) DoguConfigEntries {
+	if !slices.ContainsFunc(wantedConfig, ConfigEntry.isKeyPattern) {
+		return wantedConfig
+	}
+	explicitKeys := map[config.Key]bool{}
+	for _, entry := range wantedConfig {
+		if !entry.isKeyPattern() {
+			explicitKeys[entry.Key] = true
+		}
+	}
+	actualKeys := slices.Sorted(maps.Keys(actualConfig.GetAll()))
+
+	var expanded DoguConfigEntries
+	for _, entry := range wantedConfig {
+		if !entry.isKeyPattern() {
+			expanded = append(expanded, entry)
+			continue
+		}
+		for _, key := range actualKeys {
+			if !explicitKeys[key] && matchesConfigKeyPattern(entry.Key, key) {
+				// mark the key as explicit to not expand it again for overlapping patterns
+				explicitKeys[key] = true
+				expanded = append(expanded, ConfigEntry{Key: key, Absent: true, Sensitive: entry.Sensitive})
+			}
+		}
+	}
+	return expanded
+}
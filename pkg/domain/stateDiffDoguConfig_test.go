@@ -3,7 +3,11 @@ package domain
 import (
 	"testing"
 
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/common"
+	"github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDoguConfigDiffs_HasChanges(t *testing.T) {
@@ -70,3 +74,69 @@ func TestDoguConfigDiffs_HasChanges(t *testing.T) {
 		})
 	}
 }
+
+func Test_determineDoguConfigDiffs_keyPatterns(t *testing.T) {
+	actualConfig := map[cescommons.SimpleName]config.DoguConfig{
+		dogu1: config.CreateDoguConfig(dogu1, map[config.Key]config.Value{
+			"ldap-mapper/backend/type": "embedded",
+			"ldap-mapper/backend/host": "ldap",
+			"ldap-mapper/enabled":      "true",
+			"ldap-mapper-legacy":       "true",
+			"logging/root":             "INFO",
+		}),
+	}
+
+	t.Run("remove all matching keys individually", func(t *testing.T) {
+		wantedConfig := DoguConfigEntries{
+			{Key: "ldap-mapper/*", Absent: true},
+			{Key: "ldap-mapper/enabled", Value: &confgiVal1},
+		}
+
+		diffs := determineDoguConfigDiffs(dogu1, wantedConfig, actualConfig, false)
+
+		require.Len(t, diffs, 3)
+		assert.Equal(t, common.DoguConfigKey{DoguName: dogu1, Key: "ldap-mapper/backend/host"}, diffs[0].Key)
+		assert.Equal(t, ConfigActionRemove, diffs[0].NeededAction)
+		assert.Equal(t, common.DoguConfigKey{DoguName: dogu1, Key: "ldap-mapper/backend/type"}, diffs[1].Key)
+		assert.Equal(t, ConfigActionRemove, diffs[1].NeededAction)
+		assert.Equal(t, common.DoguConfigKey{DoguName: dogu1, Key: "ldap-mapper/enabled"}, diffs[2].Key)
+		assert.Equal(t, ConfigActionSet, diffs[2].NeededAction)
+	})
+	t.Run("ignore patterns of other sensitivity and without matches", func(t *testing.T) {
+		wantedConfig := DoguConfigEntries{
+			{Key: "ldap-mapper/*", Absent: true, Sensitive: true},
+			{Key: "unknown/*", Absent: true},
+		}
+
+		diffs := determineDoguConfigDiffs(dogu1, wantedConfig, actualConfig, false)
+
+		assert.Empty(t, diffs)
+	})
+	t.Run("set keys of present entries literally", func(t *testing.T) {
+		wantedConfig := DoguConfigEntries{
+			{Key: "ldap-mapper/*", Value: &confgiVal1},
+		}
+
+		diffs := determineDoguConfigDiffs(dogu1, wantedConfig, actualConfig, false)
+
+		require.Len(t, diffs, 1)
+		assert.Equal(t, common.DoguConfigKey{DoguName: dogu1, Key: "ldap-mapper/*"}, diffs[0].Key)
+		assert.Equal(t, ConfigActionSet, diffs[0].NeededAction)
+	})
+}
+
+func Test_matchesConfigKeyPattern(t *testing.T) {
+	assert.True(t, matchesConfigKeyPattern("ldap-mapper/*", "ldap-mapper/enabled"))
+	assert.True(t, matchesConfigKeyPattern("ldap-mapper/*", "ldap-mapper/backend/type"))
+	assert.True(t, matchesConfigKeyPattern("ldap-mapper/backend/*", "ldap-mapper/backend/type"))
+	assert.False(t, matchesConfigKeyPattern("ldap-mapper/*", "ldap-mapper"))
+	assert.False(t, matchesConfigKeyPattern("ldap-mapper/*", "ldap-mapper-legacy"))
+	assert.False(t, matchesConfigKeyPattern("ldap-mapper/*", "logging/root"))
+}
+
+func TestConfigEntry_isKeyPattern(t *testing.T) {
+	assert.True(t, ConfigEntry{Key: "ldap-mapper/*", Absent: true}.isKeyPattern())
+	assert.False(t, ConfigEntry{Key: "ldap-mapper/*", Value: &confgiVal1}.isKeyPattern())
+	assert.False(t, ConfigEntry{Key: "ldap-mapper/[ab]", Absent: true}.isKeyPattern())
+	assert.False(t, ConfigEntry{Key: "ldap-mapper/enabled", Absent: true}.isKeyPattern())
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+)
+
+// SortByDependencies returns the diffs in the order in which they can be applied one after another.
+// dependencies contains the dogu dependencies of every dogu in the diffs. Dependencies to dogus which are not part of
+// the diffs are ignored.
+//
+// Uninstalls come first and dogus get uninstalled before their dependencies.
+// All other changes follow with dependencies before the dogus which depend on them.
+// Diffs without changes come last. Dogus without a dependency relation are sorted by name.
+// returns an error if the dependencies are cyclic.
+func (diffs DoguDiffs) SortByDependencies(dependencies map[cescommons.SimpleName][]cescommons.SimpleName) (DoguDiffs, error) {
+	order, err := diffs.getDependencyOrder(dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	var uninstalls, changes, unchanged DoguDiffs
+	for _, doguName := range order {
+		for _, diff := range diffs {
+			if diff.DoguName != doguName {
+				continue
+			}
+			switch {
+			case slices.Contains(diff.NeededActions, ActionUninstall):
+				uninstalls = append(uninstalls, diff)
+			case diff.HasChanges():
+				changes = append(changes, diff)
+			default:
+				unchanged = append(unchanged, diff)
+			}
+		}
+	}
+	slices.Reverse(uninstalls)
+
+	sorted := make(DoguDiffs, 0, len(diffs))
+	sorted = append(sorted, uninstalls...)
+	sorted = append(sorted, changes...)
+	sorted = append(sorted, unchanged...)
+	return sorted, nil
+}
+
+// getDependencyOrder sorts the dogu names of the diffs topologically, so that dependencies come first.
+func (diffs DoguDiffs) getDependencyOrder(dependencies map[cescommons.SimpleName][]cescommons.SimpleName) ([]cescommons.SimpleName, error) {
+	missingDependencies := map[cescommons.SimpleName]map[cescommons.SimpleName]struct{}{}
+	for _, diff := range diffs {
+		missingDependencies[diff.DoguName] = map[cescommons.SimpleName]struct{}{}
+	}
+	for doguName, missing := range missingDependencies {
+		for _, dependency := range dependencies[doguName] {
+			_, isInDiffs := missingDependencies[dependency]
+			if isInDiffs && dependency != doguName {
+				missing[dependency] = struct{}{}
+			}
+		}
+	}
+
+	order := make([]cescommons.SimpleName, 0, len(missingDependencies))
+	for len(missingDependencies) > 0 {
+		var ready []cescommons.SimpleName
+		for doguName, missing := range missingDependencies {
+			if len(missing) == 0 {
+				ready = append(ready, doguName)
+			}
+		}
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("cannot determine the order to apply dogus as there are cyclic dependencies between %v", slices.Sorted(maps.Keys(missingDependencies)))
+		}
+
+		// only take the first dogu by name to get a stable order
+		next := slices.Min(ready)
+		order = append(order, next)
+		delete(missingDependencies, next)
+		for _, missing := range missingDependencies {
+			delete(missing, next)
+		}
+	}
+	return order, nil
+}
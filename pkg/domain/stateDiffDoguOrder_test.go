@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoguDiffs_SortByDependencies(t *testing.T) {
+	install := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name, NeededActions: []Action{ActionInstall}}
+	}
+	upgrade := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name, NeededActions: []Action{ActionUpgrade}}
+	}
+	uninstall := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name, NeededActions: []Action{ActionUninstall}}
+	}
+	unchanged := func(name cescommons.SimpleName) DoguDiff {
+		return DoguDiff{DoguName: name}
+	}
+
+	t.Run("empty diffs", func(t *testing.T) {
+		sorted, err := DoguDiffs{}.SortByDependencies(nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, sorted)
+	})
+
+	t.Run("install dependencies first", func(t *testing.T) {
+		diffs := DoguDiffs{install("redmine"), install("postgresql"), install("cas"), install("ldap")}
+		dependencies := map[cescommons.SimpleName][]cescommons.SimpleName{
+			"redmine": {"postgresql", "cas"},
+			"cas":     {"ldap"},
+		}
+
+		sorted, err := diffs.SortByDependencies(dependencies)
+
+		require.NoError(t, err)
+		assert.Equal(t, DoguDiffs{install("ldap"), install("cas"), install("postgresql"), install("redmine")}, sorted)
+	})
+
+	t.Run("consider transitive dependencies over unchanged dogus", func(t *testing.T) {
+		diffs := DoguDiffs{upgrade("redmine"), unchanged("cas"), upgrade("ldap")}
+		dependencies := map[cescommons.SimpleName][]cescommons.SimpleName{
+			"redmine": {"cas"},
+			"cas":     {"ldap"},
+			"ldap":    {"registrator"},
+		}
+
+		sorted, err := diffs.SortByDependencies(dependencies)
+
+		require.NoError(t, err)
+		assert.Equal(t, DoguDiffs{upgrade("ldap"), upgrade("redmine"), unchanged("cas")}, sorted)
+	})
+
+	t.Run("uninstall dependents first and before all other changes", func(t *testing.T) {
+		diffs := DoguDiffs{uninstall("postgresql"), install("scm"), uninstall("redmine"), uninstall("plantuml")}
+		dependencies := map[cescommons.SimpleName][]cescommons.SimpleName{
+			"redmine": {"postgresql"},
+		}
+
+		sorted, err := diffs.SortByDependencies(dependencies)
+
+		require.NoError(t, err)
+		assert.Equal(t, DoguDiffs{uninstall("redmine"), uninstall("postgresql"), uninstall("plantuml"), install("scm")}, sorted)
+	})
+
+	t.Run("ignore self dependencies", func(t *testing.T) {
+		diffs := DoguDiffs{install("ldap")}
+		dependencies := map[cescommons.SimpleName][]cescommons.SimpleName{
+			"ldap": {"ldap"},
+		}
+
+		sorted, err := diffs.SortByDependencies(dependencies)
+
+		require.NoError(t, err)
+		assert.Equal(t, DoguDiffs{install("ldap")}, sorted)
+	})
+
+	t.Run("fail on cyclic dependencies", func(t *testing.T) {
+		diffs := DoguDiffs{install("a"), install("b"), install("c"), install("d")}
+		dependencies := map[cescommons.SimpleName][]cescommons.SimpleName{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"a"},
+		}
+
+		_, err := diffs.SortByDependencies(dependencies)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "cannot determine the order to apply dogus as there are cyclic dependencies between [a b c]")
+	})
+}
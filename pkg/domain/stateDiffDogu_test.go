@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
 	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -27,6 +28,7 @@ func Test_determineDoguDiff(t *testing.T) {
 	}
 	quantity100M := resource.MustParse("100M")
 	quantity10M := resource.MustParse("10M")
+	volumeSize1Mi := resource.MustParse("1Mi")
 
 	tests := []struct {
 		name string
@@ -539,6 +541,79 @@ func Test_determineDoguDiff(t *testing.T) {
 				NeededActions: []Action{ActionUpdateAdditionalMounts},
 			},
 		},
+		{
+			name: "update reverse proxy config",
+			args: args{
+				blueprintDogu: &Dogu{
+					Name:    officialNexus,
+					Version: &version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+						MaxBodySize:      &quantity100M,
+						AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+					},
+				},
+				installedDogu: &ecosystem.DoguInstallation{
+					Name:               officialNexus,
+					Version:            version3211,
+					InstalledVersion:   version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{MaxBodySize: &quantity10M},
+				},
+			},
+			want: &DoguDiff{
+				DoguName: "nexus",
+				Actual: DoguDiffState{
+					Namespace:          officialNamespace,
+					Version:            &version3211,
+					InstalledVersion:   &version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{MaxBodySize: &quantity10M},
+				},
+				Expected: DoguDiffState{
+					Namespace: officialNamespace,
+					Version:   &version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+						MaxBodySize:      &quantity100M,
+						AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+					},
+				},
+				NeededActions: []Action{ActionUpdateReverseProxyConfig},
+			},
+		},
+		{
+			name: "no action for equal max body sizes in different units",
+			args: args{
+				blueprintDogu: &Dogu{
+					Name:               officialNexus,
+					Version:            &version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{MaxBodySize: resource.NewQuantity(1024*1024, resource.BinarySI)},
+				},
+				installedDogu: &ecosystem.DoguInstallation{
+					Name:               officialNexus,
+					Version:            version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{MaxBodySize: &volumeSize1Mi},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "no action for reverse proxy settings which are not in the blueprint",
+			args: args{
+				blueprintDogu: &Dogu{
+					Name:               officialNexus,
+					Version:            &version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{MaxBodySize: &volumeSize1Mi},
+				},
+				installedDogu: &ecosystem.DoguInstallation{
+					Name:    officialNexus,
+					Version: version3211,
+					ReverseProxyConfig: ecosystem.ReverseProxyConfig{
+						MaxBodySize:      &volumeSize1Mi,
+						RewriteTarget:    "/",
+						AdditionalConfig: ecosystem.AdditionalConfig(additionalConfig),
+					},
+				},
+			},
+			want: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -549,8 +624,9 @@ func Test_determineDoguDiff(t *testing.T) {
 
 func Test_determineDoguDiffs(t *testing.T) {
 	type args struct {
-		blueprintDogus []Dogu
-		installedDogus map[cescommons.SimpleName]*ecosystem.DoguInstallation
+		blueprintDogus    []Dogu
+		installedDogus    map[cescommons.SimpleName]*ecosystem.DoguInstallation
+		prunedBlueprintId string
 	}
 	tests := []struct {
 		name string
@@ -605,6 +681,66 @@ func Test_determineDoguDiffs(t *testing.T) {
 			},
 			want: []DoguDiff{},
 		},
+		{
+			name: "prune an installed dogu which was installed by the blueprint",
+			args: args{
+				installedDogus: map[cescommons.SimpleName]*ecosystem.DoguInstallation{
+					"nexus": {
+						Name:                 officialNexus,
+						Version:              version3211,
+						InstalledByBlueprint: "my-blueprint",
+					},
+				},
+				prunedBlueprintId: "my-blueprint",
+			},
+			want: []DoguDiff{
+				{
+					DoguName: "nexus",
+					Actual: DoguDiffState{
+						Namespace:        officialNamespace,
+						Version:          &version3211,
+						InstalledVersion: &core.Version{},
+					},
+					Expected: DoguDiffState{
+						Namespace: officialNamespace,
+						Absent:    true,
+					},
+					NeededActions: []Action{ActionUninstall},
+					Reason:        DoguDiffReasonPruned,
+				},
+			},
+		},
+		{
+			name: "do not prune dogus which were installed in another way",
+			args: args{
+				installedDogus: map[cescommons.SimpleName]*ecosystem.DoguInstallation{
+					"nexus": {
+						Name:    officialNexus,
+						Version: version3211,
+					},
+					"dogu1": {
+						Name:                 officialDogu1,
+						Version:              version3211,
+						InstalledByBlueprint: "other-blueprint",
+					},
+				},
+				prunedBlueprintId: "my-blueprint",
+			},
+			want: []DoguDiff{},
+		},
+		{
+			name: "do not prune without prune mode",
+			args: args{
+				installedDogus: map[cescommons.SimpleName]*ecosystem.DoguInstallation{
+					"nexus": {
+						Name:                 officialNexus,
+						Version:              version3211,
+						InstalledByBlueprint: "my-blueprint",
+					},
+				},
+			},
+			want: []DoguDiff{},
+		},
 		{
 			name: "an installed dogu which is also in the blueprint",
 			args: args{
@@ -644,11 +780,33 @@ func Test_determineDoguDiffs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, determineDoguDiffs(tt.args.blueprintDogus, tt.args.installedDogus), "determineDoguDiffs(%v, %v)", tt.args.blueprintDogus, tt.args.installedDogus)
+			assert.Equalf(t, tt.want, determineDoguDiffs(tt.args.blueprintDogus, tt.args.installedDogus, tt.args.prunedBlueprintId), "determineDoguDiffs(%v, %v, %v)", tt.args.blueprintDogus, tt.args.installedDogus, tt.args.prunedBlueprintId)
 		})
 	}
 }
 
+func TestDoguDiffs_GetDowngrades(t *testing.T) {
+	t.Run("no downgrades", func(t *testing.T) {
+		diffs := DoguDiffs{
+			{DoguName: "ldap", NeededActions: []Action{ActionUpgrade}},
+			{DoguName: "postgresql"},
+		}
+
+		assert.Empty(t, diffs.GetDowngrades())
+	})
+
+	t.Run("only return downgrades", func(t *testing.T) {
+		downgrade := DoguDiff{DoguName: "ldap", NeededActions: []Action{ActionDowngrade, ActionUpdateAdditionalMounts}}
+		diffs := DoguDiffs{
+			{DoguName: "cas", NeededActions: []Action{ActionUpgrade}},
+			downgrade,
+			{DoguName: "postgresql"},
+		}
+
+		assert.Equal(t, DoguDiffs{downgrade}, diffs.GetDowngrades())
+	})
+}
+
 func TestDoguDiff_String(t *testing.T) {
 	actual := DoguDiffState{
 		Namespace: "official",
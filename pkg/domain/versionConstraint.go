@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+// LatestPatchConstraint resolves to the newest available patch version of the installed major and minor version.
+const LatestPatchConstraint = "latest-patch"
+
+const tildeOperator = "~"
+
+// VersionConstraint describes a range of dogu versions instead of an exact version.
+// The newest available version matching the constraint gets installed.
+// Supported constraints are:
+//   - "~2.4", which allows all versions >=2.4 and <2.5; "~2" allows all versions >=2 and <3
+//   - one or more space separated comparisons like ">=1.3.0 <2" with the operators =, ==, <, >, <= and >=
+//   - "latest-patch", which allows all versions with the same major and minor version as the installed dogu
+type VersionConstraint struct {
+	// Raw is the constraint as written in the blueprint, e.g. "~2.4".
+	Raw         string
+	latestPatch bool
+	comparators []core.VersionComparator
+}
+
+// IsVersionConstraint returns true if the given raw version is a constraint and not an exact version.
+func IsVersionConstraint(raw string) bool {
+	return raw == LatestPatchConstraint || strings.ContainsAny(strings.TrimSpace(raw), "~<>= ")
+}
+
+// ParseVersionConstraint parses a raw version constraint, see VersionConstraint for the supported syntax.
+func ParseVersionConstraint(raw string) (VersionConstraint, error) {
+	constraint := VersionConstraint{Raw: raw}
+	if raw == LatestPatchConstraint {
+		constraint.latestPatch = true
+		return constraint, nil
+	}
+
+	terms := strings.Fields(raw)
+	if len(terms) == 0 {
+		return VersionConstraint{}, fmt.Errorf("version constraint must not be empty")
+	}
+
+	for _, term := range terms {
+		comparators, err := parseConstraintTerm(term)
+		if err != nil {
+			return VersionConstraint{}, fmt.Errorf("could not parse version constraint %q: %w", raw, err)
+		}
+		constraint.comparators = append(constraint.comparators, comparators...)
+	}
+	return constraint, nil
+}
+
+func parseConstraintTerm(term string) ([]core.VersionComparator, error) {
+	if strings.HasPrefix(term, tildeOperator) {
+		return parseTildeTerm(strings.TrimPrefix(term, tildeOperator))
+	}
+
+	comparator, err := core.ParseVersionComparator(term)
+	if err != nil {
+		return nil, err
+	}
+	// check the operator right away, so that an invalid constraint gets rejected while parsing the blueprint
+	_, err = comparator.Allows(core.Version{})
+	if err != nil {
+		return nil, err
+	}
+	return []core.VersionComparator{comparator}, nil
+}
+
+func parseTildeTerm(rawVersion string) ([]core.VersionComparator, error) {
+	version, err := core.ParseVersion(rawVersion)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(rawVersion, "<>=") {
+		return nil, fmt.Errorf("operator %q cannot be combined with other operators: %q", tildeOperator, tildeOperator+rawVersion)
+	}
+
+	upperBound := fmt.Sprintf("<%d", version.Major+1)
+	if strings.Contains(rawVersion, ".") {
+		upperBound = fmt.Sprintf("<%d.%d", version.Major, version.Minor+1)
+	}
+	lower, err := core.ParseVersionComparator(">=" + rawVersion)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := core.ParseVersionComparator(upperBound)
+	if err != nil {
+		return nil, err
+	}
+	return []core.VersionComparator{lower, upper}, nil
+}
+
+// RequiresInstalledVersion returns true if the constraint can only be resolved with the currently installed version.
+func (constraint VersionConstraint) RequiresInstalledVersion() bool {
+	return constraint.latestPatch
+}
+
+// Resolve returns the newest of the available versions which matches the constraint.
+// The installed version is only needed for constraints relative to the installed version, see RequiresInstalledVersion.
+// Returns an error if no available version matches the constraint.
+func (constraint VersionConstraint) Resolve(available []core.Version, installed *core.Version) (core.Version, error) {
+	if constraint.latestPatch && installed == nil {
+		return core.Version{}, fmt.Errorf("version constraint %q can only be used for installed dogus", constraint.Raw)
+	}
+
+	sortedVersions := slices.Clone(available)
+	sort.Sort(core.ByVersion(sortedVersions))
+
+	for _, version := range sortedVersions {
+		matches, err := constraint.allows(version, installed)
+		if err != nil {
+			return core.Version{}, err
+		}
+		if matches {
+			return version, nil
+		}
+	}
+	return core.Version{}, fmt.Errorf("no available version matches the version constraint %q", constraint.Raw)
+}
+
+func (constraint VersionConstraint) allows(version core.Version, installed *core.Version) (bool, error) {
+	if constraint.latestPatch {
+		return version.Major == installed.Major &&
+			version.Minor == installed.Minor &&
+			version.IsNewerOrEqualThan(*installed), nil
+	}
+
+	for _, comparator := range constraint.comparators {
+		allowed, err := comparator.Allows(version)
+		if err != nil {
+			return false, err
+		}
+		if !allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// String returns the constraint as written in the blueprint.
+func (constraint VersionConstraint) String() string {
+	return constraint.Raw
+}
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsVersionConstraint(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want bool
+	}{
+		{raw: "2.4.48-3", want: false},
+		{raw: "2.4", want: false},
+		{raw: "", want: false},
+		{raw: "~2.4", want: true},
+		{raw: ">=1.3.0 <2", want: true},
+		{raw: ">=1.3.0", want: true},
+		{raw: "latest-patch", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsVersionConstraint(tt.raw))
+		})
+	}
+}
+
+func TestParseVersionConstraint(t *testing.T) {
+	t.Run("should parse constraints", func(t *testing.T) {
+		for _, raw := range []string{"~2.4", "~2", "~2.4.1-2", ">=1.3.0 <2", "<=1.3.0", "==1.3.0", "latest-patch"} {
+			constraint, err := ParseVersionConstraint(raw)
+
+			require.NoError(t, err, raw)
+			assert.Equal(t, raw, constraint.Raw)
+		}
+	})
+
+	t.Run("should fail on empty constraint", func(t *testing.T) {
+		_, err := ParseVersionConstraint(" ")
+
+		assert.ErrorContains(t, err, "version constraint must not be empty")
+	})
+
+	t.Run("should fail on invalid constraints", func(t *testing.T) {
+		for _, raw := range []string{"~abc", "~>=2.4", ">=1.3.0 <abc", "=>1.3.0", "latest"} {
+			_, err := ParseVersionConstraint(raw)
+
+			assert.ErrorContains(t, err, "could not parse version constraint", raw)
+		}
+	})
+}
+
+func TestVersionConstraint_Resolve(t *testing.T) {
+	available := []core.Version{
+		mustParseVersion(t, "2.3.9-1"),
+		mustParseVersion(t, "2.4.48-3"),
+		mustParseVersion(t, "2.4.49-1"),
+		mustParseVersion(t, "2.5.0-1"),
+		mustParseVersion(t, "3.0.0-1"),
+	}
+	installed := mustParseVersion(t, "2.4.48-3")
+
+	tests := []struct {
+		constraint string
+		installed  *core.Version
+		want       string
+	}{
+		{constraint: "~2.4", want: "2.4.49-1"},
+		{constraint: "~2", want: "2.5.0-1"},
+		{constraint: "~2.3", want: "2.3.9-1"},
+		{constraint: ">=1.3.0 <2.5", want: "2.4.49-1"},
+		{constraint: ">2.4.49-1", want: "3.0.0-1"},
+		{constraint: "==2.4.48-3", want: "2.4.48-3"},
+		{constraint: "latest-patch", installed: &installed, want: "2.4.49-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			// given
+			constraint, err := ParseVersionConstraint(tt.constraint)
+			require.NoError(t, err)
+
+			// when
+			version, err := constraint.Resolve(available, tt.installed)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, version.Raw)
+		})
+	}
+
+	t.Run("should not change order of available versions", func(t *testing.T) {
+		constraint, err := ParseVersionConstraint("~2.4")
+		require.NoError(t, err)
+		unsorted := []core.Version{mustParseVersion(t, "2.4.1-1"), mustParseVersion(t, "2.4.2-1")}
+
+		_, err = constraint.Resolve(unsorted, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "2.4.1-1", unsorted[0].Raw)
+	})
+
+	t.Run("should fail if no version matches", func(t *testing.T) {
+		constraint, err := ParseVersionConstraint("~4.1")
+		require.NoError(t, err)
+
+		_, err = constraint.Resolve(available, nil)
+
+		assert.ErrorContains(t, err, "no available version matches the version constraint \"~4.1\"")
+	})
+
+	t.Run("should not downgrade with latest-patch", func(t *testing.T) {
+		constraint, err := ParseVersionConstraint("latest-patch")
+		require.NoError(t, err)
+		newerInstalled := mustParseVersion(t, "2.4.50-1")
+
+		_, err = constraint.Resolve(available, &newerInstalled)
+
+		assert.ErrorContains(t, err, "no available version matches the version constraint \"latest-patch\"")
+	})
+
+	t.Run("should fail with latest-patch if dogu is not installed", func(t *testing.T) {
+		constraint, err := ParseVersionConstraint("latest-patch")
+		require.NoError(t, err)
+		assert.True(t, constraint.RequiresInstalledVersion())
+
+		_, err = constraint.Resolve(available, nil)
+
+		assert.ErrorContains(t, err, "version constraint \"latest-patch\" can only be used for installed dogus")
+	})
+}
+
+func mustParseVersion(t *testing.T, raw string) core.Version {
+	t.Helper()
+	version, err := core.ParseVersion(raw)
+	require.NoError(t, err)
+	return version
+}
@@ -38,6 +38,13 @@ type DoguInstallationRepository interface {
 	Delete(ctx context.Context, doguName cescommons.SimpleName) error
 }
 
+// ComponentInstallationRepository gives read access to the installed k8s components of the ecosystem.
+type ComponentInstallationRepository interface {
+	// GetAll returns the installation info of all installed components or
+	//  - an InternalError if there is any other error.
+	GetAll(ctx context.Context) (map[string]*ecosystem.ComponentInstallation, error)
+}
+
 type BlueprintSpecRepository interface {
 	// GetById returns a BlueprintSpec identified by its ID or
 	// a NotFoundError if the BlueprintSpec was not found or
@@ -59,18 +66,35 @@ type BlueprintSpecRepository interface {
 	// returns a ConflictError if there were changes on the BlueprintSpec in the meantime or
 	// returns an InternalError if there is any other error
 	Update(ctx context.Context, blueprintSpec *domain.BlueprintSpec) error
+
+	// PublishEvents publishes the events of the given BlueprintSpec without updating it,
+	// e.g. for checks outside the reconciliation which must not interfere with it.
+	// returns a NotFoundError if the BlueprintSpec does not exist anymore or
+	// returns an InternalError if there is any other error
+	PublishEvents(ctx context.Context, blueprintSpec *domain.BlueprintSpec) error
 }
 
 type RemoteDoguRegistry interface {
 	// GetDogu returns the dogu specification for the given dogu and version or
 	// an NotFoundError indicating that there was no dogu spec found or
-	// an InternalError indicating that the caller has no fault.
+	// a RegistryAuthenticationError indicating that the registry rejected the credentials or
+	// an InternalError indicating that the caller has no fault, which wraps
+	// a RegistryUnreachableError if no endpoint of the registry is reachable.
 	GetDogu(ctx context.Context, qualifiedDoguVersion cescommons.QualifiedVersion) (*core.Dogu, error)
 
 	// GetDogus returns the all requested dogu specifications or
 	// an NotFoundError indicating that any dogu spec was not found or
-	// an InternalError indicating that the caller has no fault.
+	// a RegistryAuthenticationError indicating that the registry rejected the credentials or
+	// an InternalError indicating that the caller has no fault, which wraps
+	// a RegistryUnreachableError if no endpoint of the registry is reachable.
 	GetDogus(ctx context.Context, dogusToLoad []cescommons.QualifiedVersion) (map[cescommons.QualifiedName]*core.Dogu, error)
+
+	// GetVersionsOf returns all available versions of the given dogu or
+	// a NotFoundError indicating that there are no versions of the dogu or
+	// a RegistryAuthenticationError indicating that the registry rejected the credentials or
+	// an InternalError indicating that the caller has no fault, which wraps
+	// a RegistryUnreachableError if no endpoint of the registry is reachable.
+	GetVersionsOf(ctx context.Context, doguName cescommons.QualifiedName) ([]core.Version, error)
 }
 
 type DoguToLoad struct {
@@ -192,6 +216,74 @@ type RestoreRepository interface {
 	IsRestoreInProgress(ctx context.Context) (bool, error)
 }
 
+type BackupRepository interface {
+	// Get returns the ecosystem.Backup with the given name or
+	//  - a NotFoundError if the backup does not exist or
+	//  - an InternalError if there is any other error.
+	Get(ctx context.Context, name string) (*ecosystem.Backup, error)
+	// Create starts the given ecosystem.Backup or
+	//  - a ConflictError if there already is a backup with the same name or
+	//  - an InternalError if there is any other error.
+	Create(ctx context.Context, backup *ecosystem.Backup) error
+}
+
+type PlanRepository interface {
+	// Get returns the domain.Plan with the given hash or
+	//  - a NotFoundError if there is no plan with this hash or
+	//  - an InternalError if there is any other error.
+	Get(ctx context.Context, hash string) (domain.Plan, error)
+	// Create stores the given domain.Plan immutably and deletes the superseded plans of the same blueprint. It returns
+	//  - a ConflictError if the plan already exists or
+	//  - an InternalError if there is any other error.
+	Create(ctx context.Context, plan domain.Plan) error
+}
+
+type PlanKeyRepository interface {
+	// GetOrCreate returns the secret key to hash sensitive values in plans. The key gets created on first use.
+	// It can throw the following errors:
+	//  - an InternalError if the key cannot be loaded or created.
+	GetOrCreate(ctx context.Context) ([]byte, error)
+}
+
+type BlueprintRevisionRepository interface {
+	// GetAll returns all revisions of the blueprint with the given id, sorted by their number.
+	// The revisions only contain their number, the revision they rolled back to and their previous config, as
+	// the effective blueprint and the state diff are only stored for users.
+	// It can throw the following errors:
+	//  - an InternalError if there is any error.
+	GetAll(ctx context.Context, blueprintId string) ([]domain.BlueprintRevision, error)
+	// Create stores the given domain.BlueprintRevision immutably. Sensitive config values are stored in secrets.
+	// It can throw the following errors:
+	//  - a ConflictError if the revision already exists or
+	//  - an InternalError if there is any other error.
+	Create(ctx context.Context, revision domain.BlueprintRevision) error
+	// Delete removes the revision with the given number of the blueprint with the given id together with its
+	// sensitive config. Missing revisions are ignored.
+	// It can throw the following errors:
+	//  - an InternalError if there is any error.
+	Delete(ctx context.Context, blueprintId string, number int) error
+}
+
+type ConfigOwnershipRepository interface {
+	// Get returns the domain.ConfigOwnership of the blueprint with the given id or
+	//  - a NotFoundError if the blueprint was never applied before or
+	//  - an InternalError if there is any other error.
+	Get(ctx context.Context, blueprintId string) (domain.ConfigOwnership, error)
+	// Update stores the domain.ConfigOwnership of the blueprint with the given id, replacing the previous one, or
+	//  - an InternalError if there is any error.
+	Update(ctx context.Context, blueprintId string, ownership domain.ConfigOwnership) error
+}
+
+type OutdatedReportRepository interface {
+	// Get returns the last domain.OutdatedReport of the blueprint with the given id or
+	//  - a NotFoundError if the blueprint was never checked before or
+	//  - an InternalError if there is any other error.
+	Get(ctx context.Context, blueprintId string) (domain.OutdatedReport, error)
+	// Update stores the domain.OutdatedReport of the blueprint with the given id, replacing the previous one, or
+	//  - an InternalError if there is any error.
+	Update(ctx context.Context, blueprintId string, report domain.OutdatedReport) error
+}
+
 // NewNotFoundError creates a NotFoundError with a given message. The wrapped error may be nil. The error message must
 // omit the fmt.Errorf verb %w because this is done by NotFoundError.Error().
 func NewNotFoundError(wrappedError error, message string, msgArgs ...any) *NotFoundError {
@@ -253,6 +345,68 @@ func IsInternalError(err error) bool {
 	return errors.As(err, &internalError)
 }
 
+// NewRegistryUnreachableError creates a RegistryUnreachableError with a given message. The wrapped error may be nil.
+// The error message must omit the fmt.Errorf verb %w because this is done by RegistryUnreachableError.Error().
+func NewRegistryUnreachableError(wrappedError error, message string, msgArgs ...any) *RegistryUnreachableError {
+	return &RegistryUnreachableError{WrappedError: wrappedError, Message: fmt.Sprintf(message, msgArgs...)}
+}
+
+// RegistryUnreachableError indicates that no endpoint of the remote dogu registry was reachable.
+// Retrying immediately is not expected to help, as the endpoints are only retried after a while.
+type RegistryUnreachableError struct {
+	WrappedError error
+	Message      string
+}
+
+// Error marks the struct as an error.
+func (e *RegistryUnreachableError) Error() string {
+	if e.WrappedError != nil {
+		return fmt.Errorf("%s: %w", e.Message, e.WrappedError).Error()
+	}
+	return e.Message
+}
+
+// Unwrap is used to make it work with errors.Is, errors.As.
+func (e *RegistryUnreachableError) Unwrap() error {
+	return e.WrappedError
+}
+
+func IsRegistryUnreachableError(err error) bool {
+	var registryUnreachableError *RegistryUnreachableError
+	return errors.As(err, &registryUnreachableError)
+}
+
+// NewRegistryAuthenticationError creates a RegistryAuthenticationError with a given message. The wrapped error may be nil.
+// The error message must omit the fmt.Errorf verb %w because this is done by RegistryAuthenticationError.Error().
+func NewRegistryAuthenticationError(wrappedError error, message string, msgArgs ...any) *RegistryAuthenticationError {
+	return &RegistryAuthenticationError{WrappedError: wrappedError, Message: fmt.Sprintf(message, msgArgs...)}
+}
+
+// RegistryAuthenticationError indicates that the remote dogu registry rejected the configured credentials.
+// Retrying is not expected to help until the credentials get changed.
+type RegistryAuthenticationError struct {
+	WrappedError error
+	Message      string
+}
+
+// Error marks the struct as an error.
+func (e *RegistryAuthenticationError) Error() string {
+	if e.WrappedError != nil {
+		return fmt.Errorf("%s: %w", e.Message, e.WrappedError).Error()
+	}
+	return e.Message
+}
+
+// Unwrap is used to make it work with errors.Is, errors.As.
+func (e *RegistryAuthenticationError) Unwrap() error {
+	return e.WrappedError
+}
+
+func IsRegistryAuthenticationError(err error) bool {
+	var registryAuthenticationError *RegistryAuthenticationError
+	return errors.As(err, &registryAuthenticationError)
+}
+
 // ConflictError is a common error indicating that the aggregate was modified in the meantime.
 type ConflictError struct {
 	WrappedError error
@@ -107,6 +107,40 @@ func TestIsNotFoundError(t *testing.T) {
 	assert.False(t, IsNotFoundError(assert.AnError))
 }
 
+func TestRegistryUnreachableError_Error(t *testing.T) {
+	t.Run("without wrapped error", func(t *testing.T) {
+		actual := NewRegistryUnreachableError(nil, "no endpoint of %s reachable", "registry")
+		assert.Equal(t, "no endpoint of registry reachable", actual.Error())
+	})
+	t.Run("with wrapped error", func(t *testing.T) {
+		actual := NewRegistryUnreachableError(assert.AnError, "test")
+		assert.Equal(t, "test: "+assert.AnError.Error(), actual.Error())
+		assert.ErrorIs(t, actual, assert.AnError)
+	})
+}
+
+func TestIsRegistryUnreachableError(t *testing.T) {
+	assert.True(t, IsRegistryUnreachableError(NewInternalError(NewRegistryUnreachableError(nil, "test"), "test")))
+	assert.False(t, IsRegistryUnreachableError(NewInternalError(assert.AnError, "test")))
+}
+
+func TestRegistryAuthenticationError_Error(t *testing.T) {
+	t.Run("without wrapped error", func(t *testing.T) {
+		actual := NewRegistryAuthenticationError(nil, "endpoint %s rejected the credentials", "registry")
+		assert.Equal(t, "endpoint registry rejected the credentials", actual.Error())
+	})
+	t.Run("with wrapped error", func(t *testing.T) {
+		actual := NewRegistryAuthenticationError(assert.AnError, "test")
+		assert.Equal(t, "test: "+assert.AnError.Error(), actual.Error())
+		assert.ErrorIs(t, actual, assert.AnError)
+	})
+}
+
+func TestIsRegistryAuthenticationError(t *testing.T) {
+	assert.True(t, IsRegistryAuthenticationError(fmt.Errorf("test: %w", NewRegistryAuthenticationError(nil, "test"))))
+	assert.False(t, IsRegistryAuthenticationError(NewInternalError(assert.AnError, "test")))
+}
+
 func TestIsInternalError(t *testing.T) {
 	assert.True(t, IsInternalError(NewInternalError(assert.AnError, "test")))
 	assert.True(t, IsInternalError(fmt.Errorf("test: %w", NewInternalError(assert.AnError, "test"))))
@@ -196,6 +196,53 @@ func (_c *MockBlueprintSpecRepository_ListIds_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// PublishEvents provides a mock function with given fields: ctx, blueprintSpec
+func (_m *MockBlueprintSpecRepository) PublishEvents(ctx context.Context, blueprintSpec *domain.BlueprintSpec) error {
+	ret := _m.Called(ctx, blueprintSpec)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.BlueprintSpec) error); ok {
+		r0 = rf(ctx, blueprintSpec)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBlueprintSpecRepository_PublishEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishEvents'
+type MockBlueprintSpecRepository_PublishEvents_Call struct {
+	*mock.Call
+}
+
+// PublishEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blueprintSpec *domain.BlueprintSpec
+func (_e *MockBlueprintSpecRepository_Expecter) PublishEvents(ctx interface{}, blueprintSpec interface{}) *MockBlueprintSpecRepository_PublishEvents_Call {
+	return &MockBlueprintSpecRepository_PublishEvents_Call{Call: _e.mock.On("PublishEvents", ctx, blueprintSpec)}
+}
+
+func (_c *MockBlueprintSpecRepository_PublishEvents_Call) Run(run func(ctx context.Context, blueprintSpec *domain.BlueprintSpec)) *MockBlueprintSpecRepository_PublishEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.BlueprintSpec))
+	})
+	return _c
+}
+
+func (_c *MockBlueprintSpecRepository_PublishEvents_Call) Return(_a0 error) *MockBlueprintSpecRepository_PublishEvents_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBlueprintSpecRepository_PublishEvents_Call) RunAndReturn(run func(context.Context, *domain.BlueprintSpec) error) *MockBlueprintSpecRepository_PublishEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Update provides a mock function with given fields: ctx, blueprintSpec
 func (_m *MockBlueprintSpecRepository) Update(ctx context.Context, blueprintSpec *domain.BlueprintSpec) error {
 	ret := _m.Called(ctx, blueprintSpec)
@@ -142,6 +142,65 @@ func (_c *MockRemoteDoguRegistry_GetDogus_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// GetVersionsOf provides a mock function with given fields: ctx, doguName
+func (_m *MockRemoteDoguRegistry) GetVersionsOf(ctx context.Context, doguName dogu.QualifiedName) ([]core.Version, error) {
+	ret := _m.Called(ctx, doguName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVersionsOf")
+	}
+
+	var r0 []core.Version
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dogu.QualifiedName) ([]core.Version, error)); ok {
+		return rf(ctx, doguName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dogu.QualifiedName) []core.Version); ok {
+		r0 = rf(ctx, doguName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Version)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dogu.QualifiedName) error); ok {
+		r1 = rf(ctx, doguName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRemoteDoguRegistry_GetVersionsOf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVersionsOf'
+type MockRemoteDoguRegistry_GetVersionsOf_Call struct {
+	*mock.Call
+}
+
+// GetVersionsOf is a helper method to define mock.On call
+//   - ctx context.Context
+//   - doguName dogu.QualifiedName
+func (_e *MockRemoteDoguRegistry_Expecter) GetVersionsOf(ctx interface{}, doguName interface{}) *MockRemoteDoguRegistry_GetVersionsOf_Call {
+	return &MockRemoteDoguRegistry_GetVersionsOf_Call{Call: _e.mock.On("GetVersionsOf", ctx, doguName)}
+}
+
+func (_c *MockRemoteDoguRegistry_GetVersionsOf_Call) Run(run func(ctx context.Context, doguName dogu.QualifiedName)) *MockRemoteDoguRegistry_GetVersionsOf_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(dogu.QualifiedName))
+	})
+	return _c
+}
+
+func (_c *MockRemoteDoguRegistry_GetVersionsOf_Call) Return(_a0 []core.Version, _a1 error) *MockRemoteDoguRegistry_GetVersionsOf_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRemoteDoguRegistry_GetVersionsOf_Call) RunAndReturn(run func(context.Context, dogu.QualifiedName) ([]core.Version, error)) *MockRemoteDoguRegistry_GetVersionsOf_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockRemoteDoguRegistry creates a new instance of MockRemoteDoguRegistry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockRemoteDoguRegistry(t interface {
@@ -0,0 +1,87 @@
+package domainservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type ResolveDoguVersionsDomainUseCase struct {
+	remoteDoguRegistry RemoteDoguRegistry
+	doguRepository     DoguInstallationRepository
+}
+
+func NewResolveDoguVersionsDomainUseCase(remoteDoguRegistry RemoteDoguRegistry, doguRepository DoguInstallationRepository) *ResolveDoguVersionsDomainUseCase {
+	return &ResolveDoguVersionsDomainUseCase{
+		remoteDoguRegistry: remoteDoguRegistry,
+		doguRepository:     doguRepository,
+	}
+}
+
+// ResolveDoguVersions resolves the version constraints of all wanted dogus in the effective blueprint
+// to the newest matching version available in the remote dogu registry.
+// This functions returns the resolved versions by dogu name or
+// a domain.InvalidBlueprintError if a constraint cannot be resolved or
+// an InternalError if there is any other error, e.g. with the connection to the remote dogu registry
+func (useCase *ResolveDoguVersionsDomainUseCase) ResolveDoguVersions(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) (map[cescommons.SimpleName]core.Version, error) {
+	logger := log.FromContext(ctx).WithName("ResolveDoguVersionsDomainUseCase.ResolveDoguVersions")
+
+	resolvedVersions := map[cescommons.SimpleName]core.Version{}
+	var errorList []error
+	for _, dogu := range effectiveBlueprint.GetWantedDogus() {
+		if dogu.VersionConstraint == nil {
+			continue
+		}
+
+		availableVersions, err := useCase.remoteDoguRegistry.GetVersionsOf(ctx, dogu.Name)
+		if err != nil {
+			if IsNotFoundError(err) {
+				errorList = append(errorList, fmt.Errorf("remote dogu registry has no versions for dogu %q: %w", dogu.Name, err))
+				continue
+			}
+			return nil, &InternalError{WrappedError: err, Message: fmt.Sprintf("cannot load versions of dogu %q from remote registry", dogu.Name)}
+		}
+
+		installedVersion, err := useCase.getInstalledVersion(ctx, dogu)
+		if err != nil {
+			return nil, err
+		}
+
+		resolvedVersion, err := dogu.VersionConstraint.Resolve(availableVersions, installedVersion)
+		if err != nil {
+			errorList = append(errorList, fmt.Errorf("cannot resolve version of dogu %q: %w", dogu.Name, err))
+			continue
+		}
+		logger.V(2).Info(fmt.Sprintf("resolved version constraint %q of dogu %q to version %q", dogu.VersionConstraint, dogu.Name, resolvedVersion.Raw))
+		resolvedVersions[dogu.Name.SimpleName] = resolvedVersion
+	}
+
+	err := errors.Join(errorList...)
+	if err != nil {
+		return nil, &domain.InvalidBlueprintError{
+			WrappedError: err,
+			Message:      "cannot resolve dogu version constraints",
+		}
+	}
+	return resolvedVersions, nil
+}
+
+func (useCase *ResolveDoguVersionsDomainUseCase) getInstalledVersion(ctx context.Context, dogu domain.Dogu) (*core.Version, error) {
+	if !dogu.VersionConstraint.RequiresInstalledVersion() {
+		return nil, nil
+	}
+
+	installedDogu, err := useCase.doguRepository.GetByName(ctx, dogu.Name.SimpleName)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot load installed version of dogu %q: %w", dogu.Name, err)
+	}
+	return &installedDogu.InstalledVersion, nil
+}
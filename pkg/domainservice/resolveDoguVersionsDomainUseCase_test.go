@@ -0,0 +1,111 @@
+package domainservice
+
+import (
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain/ecosystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDoguVersionsDomainUseCase_ResolveDoguVersions(t *testing.T) {
+	availableVersions := []core.Version{version1_0_0_1, version2_0_0_1, version2_0_0_3}
+	tildeConstraint, _ := domain.ParseVersionConstraint("~2.0")
+	latestPatchConstraint, _ := domain.ParseVersionConstraint(domain.LatestPatchConstraint)
+
+	t.Run("should resolve version constraints of wanted dogus", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialRedmine).Return(availableVersions, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return(availableVersions, nil)
+		doguRepoMock := NewMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetByName(ctx, officialPostgres.SimpleName).
+			Return(&ecosystem.DoguInstallation{InstalledVersion: version2_0_0_1}, nil)
+		sut := NewResolveDoguVersionsDomainUseCase(registryMock, doguRepoMock)
+		effectiveBlueprint := domain.EffectiveBlueprint{Dogus: []domain.Dogu{
+			{Name: officialRedmine, VersionConstraint: &tildeConstraint},
+			{Name: officialPostgres, VersionConstraint: &latestPatchConstraint},
+			{Name: officialScm, Version: &version1_0_0_1},
+			{Name: officialNginx, VersionConstraint: &tildeConstraint, Absent: true},
+		}}
+
+		// when
+		resolvedVersions, err := sut.ResolveDoguVersions(ctx, effectiveBlueprint)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[cescommons.SimpleName]core.Version{
+			officialRedmine.SimpleName:  version2_0_0_3,
+			officialPostgres.SimpleName: version2_0_0_3,
+		}, resolvedVersions)
+	})
+
+	t.Run("should return invalid blueprint error for unresolvable constraints", func(t *testing.T) {
+		// given
+		unsatisfiableConstraint, _ := domain.ParseVersionConstraint(">=3")
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialRedmine).Return(availableVersions, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return(nil, &NotFoundError{Message: "not found"})
+		registryMock.EXPECT().GetVersionsOf(ctx, officialScm).Return(availableVersions, nil)
+		doguRepoMock := NewMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetByName(ctx, officialScm.SimpleName).Return(nil, &NotFoundError{Message: "not installed"})
+		sut := NewResolveDoguVersionsDomainUseCase(registryMock, doguRepoMock)
+		effectiveBlueprint := domain.EffectiveBlueprint{Dogus: []domain.Dogu{
+			{Name: officialRedmine, VersionConstraint: &unsatisfiableConstraint},
+			{Name: officialPostgres, VersionConstraint: &tildeConstraint},
+			{Name: officialScm, VersionConstraint: &latestPatchConstraint},
+		}}
+
+		// when
+		_, err := sut.ResolveDoguVersions(ctx, effectiveBlueprint)
+
+		// then
+		var invalidErr *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.ErrorContains(t, err, "cannot resolve dogu version constraints")
+		assert.ErrorContains(t, err, "cannot resolve version of dogu \"official/redmine\": no available version matches the version constraint \">=3\"")
+		assert.ErrorContains(t, err, "remote dogu registry has no versions for dogu \"official/postgres\"")
+		assert.ErrorContains(t, err, "version constraint \"latest-patch\" can only be used for installed dogus")
+	})
+
+	t.Run("should return internal error if versions cannot be loaded", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialRedmine).Return(nil, assert.AnError)
+		sut := NewResolveDoguVersionsDomainUseCase(registryMock, NewMockDoguInstallationRepository(t))
+		effectiveBlueprint := domain.EffectiveBlueprint{Dogus: []domain.Dogu{
+			{Name: officialRedmine, VersionConstraint: &tildeConstraint},
+		}}
+
+		// when
+		_, err := sut.ResolveDoguVersions(ctx, effectiveBlueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot load versions of dogu \"official/redmine\" from remote registry")
+	})
+
+	t.Run("should return error if installed dogu cannot be loaded", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialRedmine).Return(availableVersions, nil)
+		doguRepoMock := NewMockDoguInstallationRepository(t)
+		doguRepoMock.EXPECT().GetByName(ctx, officialRedmine.SimpleName).Return(nil, &InternalError{WrappedError: assert.AnError})
+		sut := NewResolveDoguVersionsDomainUseCase(registryMock, doguRepoMock)
+		effectiveBlueprint := domain.EffectiveBlueprint{Dogus: []domain.Dogu{
+			{Name: officialRedmine, VersionConstraint: &latestPatchConstraint},
+		}}
+
+		// when
+		_, err := sut.ResolveDoguVersions(ctx, effectiveBlueprint)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.True(t, IsInternalError(err))
+		assert.ErrorContains(t, err, "cannot load installed version of dogu \"official/redmine\"")
+	})
+}
@@ -0,0 +1,139 @@
+package domainservice
+
+import (
+	"context"
+	"fmt"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type SortDoguDiffsDomainUseCase struct {
+	remoteDoguRegistry RemoteDoguRegistry
+}
+
+func NewSortDoguDiffsDomainUseCase(remoteDoguRegistry RemoteDoguRegistry) *SortDoguDiffsDomainUseCase {
+	return &SortDoguDiffsDomainUseCase{
+		remoteDoguRegistry: remoteDoguRegistry,
+	}
+}
+
+// SortDoguDiffsByDependencies sorts the dogu diffs in the order in which they should be applied, see domain.DoguDiffs.SortByDependencies.
+// The dependencies are read from the dogu specifications of the expected dogu versions or, for uninstalls,
+// of the installed dogu versions. Uninstalled dogus without a dogu specification in the registry have no dependencies.
+// returns an InternalError if the dogu specifications could not be loaded or
+// an error if the dependencies are cyclic.
+func (useCase *SortDoguDiffsDomainUseCase) SortDoguDiffsByDependencies(ctx context.Context, diffs domain.DoguDiffs) (domain.DoguDiffs, error) {
+	dependencies, err := useCase.getDependencies(ctx, diffs)
+	if err != nil {
+		return nil, err
+	}
+
+	sortedDiffs, err := diffs.SortByDependencies(dependencies)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sort dogu diffs by their dependencies: %w", err)
+	}
+	return sortedDiffs, nil
+}
+
+// GroupDoguDiffsIntoWaves groups the changed dogu diffs into the rollout waves in which they should be applied,
+// see domain.DoguDiffs.GroupIntoWaves. The dependencies are read like in SortDoguDiffsByDependencies.
+// returns an InternalError if the dogu specifications could not be loaded or
+// an error if the dependencies are cyclic.
+func (useCase *SortDoguDiffsDomainUseCase) GroupDoguDiffsIntoWaves(ctx context.Context, diffs domain.DoguDiffs, waves domain.RolloutWaves) ([]domain.DoguDiffs, error) {
+	dependencies, err := useCase.getDependencies(ctx, diffs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupedDiffs, err := diffs.GroupIntoWaves(waves, dependencies)
+	if err != nil {
+		return nil, fmt.Errorf("cannot group dogu diffs into rollout waves: %w", err)
+	}
+	return groupedDiffs, nil
+}
+
+// getDependencies returns the dependencies of the dogus with changes. Dogus without changes are not applied, so their
+// dependencies do not influence the order.
+// The descriptors of the expected versions were already loaded by the dependency validation, so the cache of the
+// RemoteDoguRegistry returns them without requesting the dogu registry again.
+func (useCase *SortDoguDiffsDomainUseCase) getDependencies(ctx context.Context, diffs domain.DoguDiffs) (map[cescommons.SimpleName][]cescommons.SimpleName, error) {
+	logger := log.FromContext(ctx).WithName("SortDoguDiffsDomainUseCase.getDependencies")
+
+	var dogusToLoad []cescommons.QualifiedVersion
+	var dogusToUninstall []cescommons.QualifiedVersion
+	for _, diff := range diffs {
+		if !diff.HasChanges() {
+			continue
+		}
+		doguToLoad, found := getDoguSpecToLoad(diff)
+		if !found {
+			continue
+		}
+		if diff.Expected.Absent {
+			dogusToUninstall = append(dogusToUninstall, doguToLoad)
+		} else {
+			dogusToLoad = append(dogusToLoad, doguToLoad)
+		}
+	}
+
+	logger.V(2).Info("load dogu specifications to determine apply order...", "dogus", dogusToLoad, "uninstalledDogus", dogusToUninstall)
+	doguSpecs := map[cescommons.QualifiedName]*core.Dogu{}
+	if len(dogusToLoad) > 0 {
+		var err error
+		doguSpecs, err = useCase.remoteDoguRegistry.GetDogus(ctx, dogusToLoad)
+		if err != nil {
+			return nil, &InternalError{WrappedError: err, Message: "cannot load dogu specifications to determine the order to apply dogus"}
+		}
+	}
+
+	for _, doguToUninstall := range dogusToUninstall {
+		doguSpec, err := useCase.remoteDoguRegistry.GetDogu(ctx, doguToUninstall)
+		if err != nil {
+			if IsNotFoundError(err) {
+				// the installed version may be removed from the registry, the dogu is then uninstalled without dependencies
+				logger.V(1).Info(fmt.Sprintf("dogu specification of uninstalled dogu %q not found, uninstall it without dependencies", doguToUninstall.Name))
+				continue
+			}
+			return nil, &InternalError{WrappedError: err, Message: "cannot load dogu specifications to determine the order to apply dogus"}
+		}
+		doguSpecs[doguToUninstall.Name] = doguSpec
+	}
+
+	dependencies := map[cescommons.SimpleName][]cescommons.SimpleName{}
+	for qualifiedName, doguSpec := range doguSpecs {
+		dependencies[qualifiedName.SimpleName] = append(
+			getDoguDependencyNames(doguSpec.Dependencies),
+			getDoguDependencyNames(doguSpec.OptionalDependencies)...,
+		)
+	}
+	return dependencies, nil
+}
+
+// getDoguSpecToLoad returns the dogu version which is relevant for the dependencies of the diff.
+// This is the expected version or, if the dogu should be absent, the installed version.
+func getDoguSpecToLoad(diff domain.DoguDiff) (cescommons.QualifiedVersion, bool) {
+	state := diff.Expected
+	if state.Absent {
+		state = diff.Actual
+	}
+	if state.Absent || state.Version == nil {
+		return cescommons.QualifiedVersion{}, false
+	}
+	return cescommons.QualifiedVersion{
+		Name:    cescommons.QualifiedName{Namespace: state.Namespace, SimpleName: diff.DoguName},
+		Version: *state.Version,
+	}, true
+}
+
+func getDoguDependencyNames(dependencies []core.Dependency) []cescommons.SimpleName {
+	var result []cescommons.SimpleName
+	for _, dependency := range dependencies {
+		if dependency.Type == core.DependencyTypeDogu {
+			result = append(result, cescommons.SimpleName(core.GetSimpleDoguName(dependency.Name)))
+		}
+	}
+	return result
+}
@@ -0,0 +1,221 @@
+package domainservice
+
+import (
+	"context"
+	"testing"
+
+	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortDoguDiffsDomainUseCase_SortDoguDiffsByDependencies(t *testing.T) {
+	redmineInstall := domain.DoguDiff{
+		DoguName:      "redmine",
+		Actual:        domain.DoguDiffState{Absent: true},
+		Expected:      domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1},
+		NeededActions: []domain.Action{domain.ActionInstall},
+	}
+	postgresInstall := domain.DoguDiff{
+		DoguName:      "postgres",
+		Actual:        domain.DoguDiffState{Absent: true},
+		Expected:      domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1},
+		NeededActions: []domain.Action{domain.ActionInstall},
+	}
+	bluespiceUninstall := domain.DoguDiff{
+		DoguName:      "bluespice",
+		Actual:        domain.DoguDiffState{Namespace: helloworldNamespace, Version: &version1_0_0_1},
+		Expected:      domain.DoguDiffState{Namespace: helloworldNamespace, Version: &version1_0_0_1, Absent: true},
+		NeededActions: []domain.Action{domain.ActionUninstall},
+	}
+	mysqlUninstall := domain.DoguDiff{
+		DoguName:      "mysql",
+		Actual:        domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1},
+		Expected:      domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1, Absent: true},
+		NeededActions: []domain.Action{domain.ActionUninstall},
+	}
+	scmAbsent := domain.DoguDiff{
+		DoguName: "scm",
+		Actual:   domain.DoguDiffState{Absent: true},
+		Expected: domain.DoguDiffState{Namespace: officialNamespace, Absent: true},
+	}
+
+	ldapUnchanged := domain.DoguDiff{
+		DoguName: "ldap",
+		Actual:   domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1},
+		Expected: domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1},
+	}
+	officialMysql := cescommons.QualifiedName{Namespace: officialNamespace, SimpleName: "mysql"}
+
+	t.Run("sort by dependencies from dogu specs", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogus(ctx, mock.Anything).RunAndReturn(func(_ context.Context, dogusToLoad []cescommons.QualifiedVersion) (map[cescommons.QualifiedName]*core.Dogu, error) {
+			assert.ElementsMatch(t, []cescommons.QualifiedVersion{
+				{Name: officialRedmine, Version: version1_0_0_1},
+				{Name: officialPostgres, Version: version1_0_0_1},
+			}, dogusToLoad)
+			return map[cescommons.QualifiedName]*core.Dogu{
+				officialRedmine:  testDataDoguRegistry.dogus[officialRedmine]["1.0.0-1"],
+				officialPostgres: testDataDoguRegistry.dogus[officialPostgres]["1.0.0-1"],
+			}, nil
+		})
+		registryMock.EXPECT().GetDogu(ctx, cescommons.QualifiedVersion{Name: helloworldBluespice, Version: version1_0_0_1}).
+			Return(testDataDoguRegistry.dogus[helloworldBluespice]["1.0.0-1"], nil)
+		registryMock.EXPECT().GetDogu(ctx, cescommons.QualifiedVersion{Name: officialMysql, Version: version1_0_0_1}).
+			Return(&core.Dogu{Name: "official/mysql", Version: "1.0.0-1"}, nil)
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		sorted, err := sut.SortDoguDiffsByDependencies(ctx, domain.DoguDiffs{redmineInstall, scmAbsent, mysqlUninstall, ldapUnchanged, postgresInstall, bluespiceUninstall})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, domain.DoguDiffs{bluespiceUninstall, mysqlUninstall, postgresInstall, redmineInstall, ldapUnchanged, scmAbsent}, sorted)
+	})
+
+	t.Run("uninstall without dependencies if the dogu spec is not found", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogu(ctx, cescommons.QualifiedVersion{Name: officialMysql, Version: version1_0_0_1}).
+			Return(nil, NewNotFoundError(assert.AnError, "dogu not found"))
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		sorted, err := sut.SortDoguDiffsByDependencies(ctx, domain.DoguDiffs{mysqlUninstall, ldapUnchanged})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, domain.DoguDiffs{mysqlUninstall, ldapUnchanged}, sorted)
+	})
+
+	t.Run("fail to load dogu spec of uninstalled dogu", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogu(ctx, cescommons.QualifiedVersion{Name: officialMysql, Version: version1_0_0_1}).
+			Return(nil, assert.AnError)
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		_, err := sut.SortDoguDiffsByDependencies(ctx, domain.DoguDiffs{mysqlUninstall})
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		var internalError *InternalError
+		assert.ErrorAs(t, err, &internalError)
+	})
+
+	t.Run("fail to load dogu specs", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogus(ctx, mock.Anything).Return(nil, assert.AnError)
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		_, err := sut.SortDoguDiffsByDependencies(ctx, domain.DoguDiffs{redmineInstall})
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		var internalError *InternalError
+		assert.ErrorAs(t, err, &internalError)
+		assert.ErrorContains(t, err, "cannot load dogu specifications to determine the order to apply dogus")
+	})
+
+	t.Run("fail on cyclic dependencies", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogus(ctx, mock.Anything).Return(map[cescommons.QualifiedName]*core.Dogu{
+			officialRedmine: {
+				Name:         "official/redmine",
+				Dependencies: []core.Dependency{{Type: core.DependencyTypeDogu, Name: "postgres"}},
+			},
+			officialPostgres: {
+				Name:                 "official/postgres",
+				OptionalDependencies: []core.Dependency{{Type: core.DependencyTypeDogu, Name: "official/redmine"}},
+			},
+		}, nil)
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		_, err := sut.SortDoguDiffsByDependencies(ctx, domain.DoguDiffs{redmineInstall, postgresInstall})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "cannot sort dogu diffs by their dependencies")
+		assert.ErrorContains(t, err, "cyclic dependencies between [postgres redmine]")
+	})
+}
+
+func TestSortDoguDiffsDomainUseCase_GroupDoguDiffsIntoWaves(t *testing.T) {
+	redmineInstall := domain.DoguDiff{
+		DoguName:      "redmine",
+		Actual:        domain.DoguDiffState{Absent: true},
+		Expected:      domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1},
+		NeededActions: []domain.Action{domain.ActionInstall},
+	}
+	postgresInstall := domain.DoguDiff{
+		DoguName:      "postgres",
+		Actual:        domain.DoguDiffState{Absent: true},
+		Expected:      domain.DoguDiffState{Namespace: officialNamespace, Version: &version1_0_0_1},
+		NeededActions: []domain.Action{domain.ActionInstall},
+	}
+	dependencies := map[cescommons.QualifiedName]*core.Dogu{
+		officialRedmine: {
+			Name:         "official/redmine",
+			Dependencies: []core.Dependency{{Type: core.DependencyTypeDogu, Name: "postgres"}},
+		},
+		officialPostgres: {Name: "official/postgres"},
+	}
+
+	t.Run("group by dependency depth from dogu specs", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogus(ctx, mock.Anything).Return(dependencies, nil)
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		waves, err := sut.GroupDoguDiffsIntoWaves(ctx, domain.DoguDiffs{redmineInstall, postgresInstall}, domain.RolloutWaves{ByDependencyDepth: true})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []domain.DoguDiffs{{postgresInstall}, {redmineInstall}}, waves)
+	})
+
+	t.Run("fail to load dogu specs", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogus(ctx, mock.Anything).Return(nil, assert.AnError)
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		_, err := sut.GroupDoguDiffsIntoWaves(ctx, domain.DoguDiffs{redmineInstall}, domain.RolloutWaves{ByDependencyDepth: true})
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		var internalError *InternalError
+		assert.ErrorAs(t, err, &internalError)
+	})
+
+	t.Run("fail on cyclic dependencies", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		registryMock.EXPECT().GetDogus(ctx, mock.Anything).Return(map[cescommons.QualifiedName]*core.Dogu{
+			officialRedmine: dependencies[officialRedmine],
+			officialPostgres: {
+				Name:         "official/postgres",
+				Dependencies: []core.Dependency{{Type: core.DependencyTypeDogu, Name: "redmine"}},
+			},
+		}, nil)
+		sut := NewSortDoguDiffsDomainUseCase(registryMock)
+
+		// when
+		_, err := sut.GroupDoguDiffsIntoWaves(ctx, domain.DoguDiffs{redmineInstall, postgresInstall}, domain.RolloutWaves{ByDependencyDepth: true})
+
+		// then
+		assert.ErrorContains(t, err, "cannot group dogu diffs into rollout waves")
+		assert.ErrorContains(t, err, "cyclic dependencies between [postgres redmine]")
+	})
+}
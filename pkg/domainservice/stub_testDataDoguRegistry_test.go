@@ -130,3 +130,18 @@ func (registry stubRemoteDoguRegistry) GetDogus(ctx context.Context, dogusToLoad
 	}
 	return dogus, nil
 }
+
+func (registry stubRemoteDoguRegistry) GetVersionsOf(ctx context.Context, doguName cescommons.QualifiedName) ([]core.Version, error) {
+	var versions []core.Version
+	for _, dogu := range registry.dogus[doguName] {
+		version, err := dogu.GetVersion()
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, &NotFoundError{Message: fmt.Sprintf("no versions of dogu %s found", doguName)}
+	}
+	return versions, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/cesapp-lib/core"
@@ -17,6 +19,8 @@ const (
 	registratorDependencyName = "registrator"
 	casDependencyName         = "cas"
 	postfixDependencyName     = "postfix"
+
+	defaultDependencyNamespace cescommons.Namespace = "official"
 )
 
 type ValidateDependenciesDomainUseCase struct {
@@ -41,25 +45,10 @@ func NewValidateDependenciesDomainUseCase(remoteDoguRegistry RemoteDoguRegistry,
 func (useCase *ValidateDependenciesDomainUseCase) ValidateDependenciesForAllDogus(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) error {
 	logger := log.FromContext(ctx).WithName("ValidateDependenciesDomainUseCase.ValidateDependenciesForAllDogus")
 	wantedDogus := effectiveBlueprint.GetWantedDogus()
-	dogusToLoad := util.Map(wantedDogus, func(dogu domain.Dogu) cescommons.QualifiedVersion {
-		doguVersion := core.Version{}
-		if dogu.Version != nil {
-			doguVersion = *dogu.Version
-		}
-		return cescommons.QualifiedVersion{
-			Name:    dogu.Name,
-			Version: doguVersion,
-		}
-	})
 	logger.V(2).Info("load dogu specifications...", "wantedDogus", wantedDogus)
-	doguSpecsOfWantedDogus, err := useCase.remoteDoguRegistry.GetDogus(ctx, dogusToLoad)
+	doguSpecsOfWantedDogus, err := useCase.loadDoguSpecs(ctx, wantedDogus)
 	if err != nil {
-		var notFoundError *NotFoundError
-		if errors.As(err, &notFoundError) {
-			return &domain.InvalidBlueprintError{WrappedError: err, Message: "remote dogu registry has no dogu specification for at least one wanted dogu"}
-		} else { // should be InternalError
-			return &InternalError{WrappedError: err, Message: "cannot load dogu specifications from remote registry for dogu dependency validation"}
-		}
+		return err
 	}
 	logger.V(2).Info("dogu specifications loaded", "specs", doguSpecsOfWantedDogus)
 
@@ -82,6 +71,177 @@ func (useCase *ValidateDependenciesDomainUseCase) ValidateDependenciesForAllDogu
 	return err
 }
 
+// CompleteMissingDependencies determines the dogus which are missing in the effective blueprint as dependencies of other dogus,
+// including the dependencies of these missing dogus themselves.
+// Each missing dogu gets the newest version in the remote dogu registry, which satisfies the version requirements of all its dependents.
+// A missing dogu is searched in the namespace of its first dependent and in the official namespace afterward.
+// Dependencies which are absent in the effective blueprint are never added, so that the dependency validation rejects them.
+// This functions returns the missing dogus or
+// a domain.InvalidBlueprintError if a missing dependency cannot be resolved with the remote dogu registry or
+// an InternalError if there is any other error, e.g. with the connection to the remote dogu registry
+func (useCase *ValidateDependenciesDomainUseCase) CompleteMissingDependencies(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint) ([]domain.Dogu, error) {
+	logger := log.FromContext(ctx).WithName("ValidateDependenciesDomainUseCase.CompleteMissingDependencies")
+	var addedDogus []domain.Dogu
+	var unresolvableDependencies []cescommons.SimpleName
+	var errorList []error
+	versionRequirements := map[cescommons.SimpleName][]string{}
+
+	// the version requirements of added dogus can only narrow down the versions of other added dogus, so this terminates
+	dogusToCheck := effectiveBlueprint.GetWantedDogus()
+	for len(dogusToCheck) > 0 {
+		doguSpecs, err := useCase.loadDoguSpecs(ctx, dogusToCheck)
+		if err != nil {
+			return nil, err
+		}
+
+		var missingDependencies []cescommons.QualifiedName
+		for _, dogu := range dogusToCheck {
+			for _, dependency := range doguSpecs[dogu.Name].Dependencies {
+				dependencyName := cescommons.SimpleName(dependency.Name)
+				if !useCase.isCheckedDependency(ctx, dependency) || slices.Contains(unresolvableDependencies, dependencyName) {
+					continue
+				}
+				if _, found := domain.FindDoguByName(effectiveBlueprint.Dogus, dependencyName); found {
+					continue
+				}
+				versionRequirements[dependencyName] = append(versionRequirements[dependencyName], dependency.Version)
+				if !slices.ContainsFunc(missingDependencies, func(name cescommons.QualifiedName) bool { return name.SimpleName == dependencyName }) {
+					missingDependencies = append(missingDependencies, cescommons.QualifiedName{SimpleName: dependencyName, Namespace: dogu.Name.Namespace})
+				}
+			}
+		}
+
+		dogusToCheck = nil
+		for _, dependency := range missingDependencies {
+			addedIndex := slices.IndexFunc(addedDogus, func(dogu domain.Dogu) bool { return dogu.Name.SimpleName == dependency.SimpleName })
+			if addedIndex >= 0 {
+				dependency = addedDogus[addedIndex].Name
+			}
+			resolvedDogu, err := useCase.resolveMissingDependency(ctx, dependency, versionRequirements[dependency.SimpleName])
+			if err != nil {
+				if IsInternalError(err) {
+					return nil, err
+				}
+				unresolvableDependencies = append(unresolvableDependencies, dependency.SimpleName)
+				errorList = append(errorList, err)
+				continue
+			}
+
+			if addedIndex < 0 {
+				addedDogus = append(addedDogus, resolvedDogu)
+			} else if addedDogus[addedIndex].Version.Raw != resolvedDogu.Version.Raw {
+				addedDogus[addedIndex] = resolvedDogu
+			} else {
+				continue
+			}
+			logger.V(2).Info(fmt.Sprintf("resolved missing dependency %q to version %q", resolvedDogu.Name, resolvedDogu.Version.Raw))
+			dogusToCheck = append(dogusToCheck, resolvedDogu)
+		}
+	}
+
+	err := errors.Join(errorList...)
+	if err != nil {
+		return nil, &domain.InvalidBlueprintError{
+			WrappedError: err,
+			Message:      "cannot complete missing dogu dependencies",
+		}
+	}
+	return addedDogus, nil
+}
+
+// resolveMissingDependency returns the dependency in the newest version which satisfies all version requirements.
+// The dependency is searched in its namespace first and in the official namespace afterward.
+func (useCase *ValidateDependenciesDomainUseCase) resolveMissingDependency(ctx context.Context, dependency cescommons.QualifiedName, versionRequirements []string) (domain.Dogu, error) {
+	namespaces := []cescommons.Namespace{dependency.Namespace}
+	if dependency.Namespace != defaultDependencyNamespace {
+		namespaces = append(namespaces, defaultDependencyNamespace)
+	}
+
+	for _, namespace := range namespaces {
+		doguName := cescommons.QualifiedName{SimpleName: dependency.SimpleName, Namespace: namespace}
+		availableVersions, err := useCase.remoteDoguRegistry.GetVersionsOf(ctx, doguName)
+		if err != nil {
+			if IsNotFoundError(err) {
+				continue
+			}
+			return domain.Dogu{}, &InternalError{WrappedError: err, Message: fmt.Sprintf("cannot load versions of dogu %q from remote registry", doguName)}
+		}
+
+		version, err := findNewestVersion(availableVersions, versionRequirements)
+		if err != nil {
+			return domain.Dogu{}, fmt.Errorf("cannot resolve version of missing dependency %q: %w", doguName, err)
+		}
+		return domain.Dogu{Name: doguName, Version: &version}, nil
+	}
+	return domain.Dogu{}, fmt.Errorf("remote dogu registry has no versions for missing dependency %q", dependency.SimpleName)
+}
+
+// findNewestVersion returns the newest of the available versions, which satisfies all version requirements.
+// Empty version requirements are satisfied by every version.
+func findNewestVersion(availableVersions []core.Version, versionRequirements []string) (core.Version, error) {
+	var comparators []core.VersionComparator
+	for _, requirement := range versionRequirements {
+		if requirement == "" {
+			continue
+		}
+		comparator, err := core.ParseVersionComparator(requirement)
+		if err != nil {
+			return core.Version{}, fmt.Errorf("failed to parse version requirement %q: %w", requirement, err)
+		}
+		comparators = append(comparators, comparator)
+	}
+
+	sortedVersions := slices.Clone(availableVersions)
+	sort.Sort(core.ByVersion(sortedVersions))
+	for _, version := range sortedVersions {
+		allowed, err := allowsVersion(comparators, version)
+		if err != nil {
+			return core.Version{}, err
+		}
+		if allowed {
+			return version, nil
+		}
+	}
+	return core.Version{}, fmt.Errorf("no available version satisfies the version requirements %q", versionRequirements)
+}
+
+func allowsVersion(comparators []core.VersionComparator, version core.Version) (bool, error) {
+	for _, comparator := range comparators {
+		allowed, err := comparator.Allows(version)
+		if err != nil {
+			return false, fmt.Errorf("an error occurred when comparing the versions: %w", err)
+		}
+		if !allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// loadDoguSpecs loads the dogu specifications of the given dogus in their versions.
+func (useCase *ValidateDependenciesDomainUseCase) loadDoguSpecs(ctx context.Context, dogus []domain.Dogu) (map[cescommons.QualifiedName]*core.Dogu, error) {
+	dogusToLoad := util.Map(dogus, func(dogu domain.Dogu) cescommons.QualifiedVersion {
+		doguVersion := core.Version{}
+		if dogu.Version != nil {
+			doguVersion = *dogu.Version
+		}
+		return cescommons.QualifiedVersion{
+			Name:    dogu.Name,
+			Version: doguVersion,
+		}
+	})
+	doguSpecs, err := useCase.remoteDoguRegistry.GetDogus(ctx, dogusToLoad)
+	if err != nil {
+		var notFoundError *NotFoundError
+		if errors.As(err, &notFoundError) {
+			return nil, &domain.InvalidBlueprintError{WrappedError: err, Message: "remote dogu registry has no dogu specification for at least one wanted dogu"}
+		} else { // should be InternalError
+			return nil, &InternalError{WrappedError: err, Message: "cannot load dogu specifications from remote registry for dogu dependency validation"}
+		}
+	}
+	return doguSpecs, nil
+}
+
 func (useCase *ValidateDependenciesDomainUseCase) checkDoguDependencies(
 	ctx context.Context,
 	wantedDogus []domain.Dogu,
@@ -96,32 +256,7 @@ func (useCase *ValidateDependenciesDomainUseCase) checkDoguDependencies(
 			"check dependency %q in version %q...",
 			dependencyOfWantedDogu.Name, dependencyOfWantedDogu.Version,
 		))
-		if dependencyOfWantedDogu.Type != core.DependencyTypeDogu {
-			logger.V(1).Info(fmt.Sprintf(
-				"dogu has a dependency %q of type %q. At the moment only dogu dependencies are validated.",
-				dependencyOfWantedDogu.Name, dependencyOfWantedDogu.Type,
-			))
-			continue
-		}
-
-		// Ignore registrator dogu because this is only needed in single node version of the Cloudogu EcoSystem.
-		if dependencyOfWantedDogu.Name == registratorDependencyName {
-			continue
-		}
-
-		// Exception for the old nginx dependency from the single node Cloudogu EcoSystem.
-		// ingress-nginx dependency was replaced by the k8s-ces-gateway
-		if dependencyOfWantedDogu.Name == nginxDependencyName {
-			continue
-		}
-
-		// When auth registration is not needed, CAS should be installed as a component and is therefore not checked.
-		if useCase.authRegistrationEnabled && dependencyOfWantedDogu.Name == casDependencyName {
-			continue
-		}
-
-		// If the flag is set postfix is not checked, e.g. because it is installed as a component.
-		if useCase.disablePostfixDependencyCheck && dependencyOfWantedDogu.Name == postfixDependencyName {
+		if !useCase.isCheckedDependency(ctx, dependencyOfWantedDogu) {
 			continue
 		}
 
@@ -133,6 +268,40 @@ func (useCase *ValidateDependenciesDomainUseCase) checkDoguDependencies(
 	return err
 }
 
+// isCheckedDependency checks if the dependency has to be a dogu in the blueprint.
+func (useCase *ValidateDependenciesDomainUseCase) isCheckedDependency(ctx context.Context, dependency core.Dependency) bool {
+	logger := log.FromContext(ctx).WithName("ValidateDependenciesDomainUseCase.isCheckedDependency")
+	if dependency.Type != core.DependencyTypeDogu {
+		logger.V(1).Info(fmt.Sprintf(
+			"dogu has a dependency %q of type %q. At the moment only dogu dependencies are validated.",
+			dependency.Name, dependency.Type,
+		))
+		return false
+	}
+
+	// Ignore registrator dogu because this is only needed in single node version of the Cloudogu EcoSystem.
+	if dependency.Name == registratorDependencyName {
+		return false
+	}
+
+	// Exception for the old nginx dependency from the single node Cloudogu EcoSystem.
+	// ingress-nginx dependency was replaced by the k8s-ces-gateway
+	if dependency.Name == nginxDependencyName {
+		return false
+	}
+
+	// When auth registration is not needed, CAS should be installed as a component and is therefore not checked.
+	if useCase.authRegistrationEnabled && dependency.Name == casDependencyName {
+		return false
+	}
+
+	// If the flag is set postfix is not checked, e.g. because it is installed as a component.
+	if useCase.disablePostfixDependencyCheck && dependency.Name == postfixDependencyName {
+		return false
+	}
+	return true
+}
+
 func checkDoguDependency(
 	dependencyOfWantedDogu core.Dependency,
 	wantedDogus []domain.Dogu,
@@ -2,6 +2,8 @@ package domainservice
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	cescommons "github.com/cloudogu/ces-commons-lib/dogu"
@@ -287,3 +289,214 @@ func TestValidateDependenciesDomainUseCase_ValidateDependenciesForAllDogus_colle
 	assert.ErrorContains(t, err, "dependencies for dogu 'official/redmine' are not satisfied in blueprint: dependency 'postgres' in version '1.0.0-1' is not a present dogu in the effective blueprint")
 	assert.ErrorContains(t, err, "dependencies for dogu 'helloworld/bluespice' are not satisfied in blueprint: dependency 'official/mysql' in version '1.0.0-1' is not a present dogu in the effective blueprint")
 }
+
+func TestValidateDependenciesDomainUseCase_CompleteMissingDependencies(t *testing.T) {
+	redmineVersion := cescommons.QualifiedVersion{Name: officialRedmine, Version: version1_0_0_1}
+	redmineSpec := &core.Dogu{
+		Name:    "official/redmine",
+		Version: "1.0.0-1",
+		Dependencies: []core.Dependency{
+			{Type: core.DependencyTypeDogu, Name: "postgres", Version: "<=2.0.0-1"},
+			{Type: core.DependencyTypeDogu, Name: "nginx"},
+		},
+	}
+	availablePostgresVersions := []core.Version{version1_0_0_1, version2_0_0_3, version2_0_0_1}
+
+	t.Run("should add missing dependency in newest allowed version", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{redmineVersion}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialRedmine: redmineSpec}, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return(availablePostgresVersions, nil)
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{{Name: officialPostgres, Version: version2_0_0_1}}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialPostgres: {Name: "official/postgres", Version: "2.0.0-1"}}, nil)
+
+		// when
+		missingDogus, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: officialRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Dogu{{Name: officialPostgres, Version: &version2_0_0_1}}, missingDogus)
+	})
+
+	t.Run("should add dependencies of missing dependencies", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{redmineVersion}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialRedmine: redmineSpec}, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return(availablePostgresVersions, nil)
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{{Name: officialPostgres, Version: version2_0_0_1}}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialPostgres: {
+				Name:         "official/postgres",
+				Version:      "2.0.0-1",
+				Dependencies: []core.Dependency{{Type: core.DependencyTypeDogu, Name: "k8s-ces-control"}},
+			}}, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialK8sCesControl).Return([]core.Version{version1_26_3_2}, nil)
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{{Name: officialK8sCesControl, Version: version1_26_3_2}}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialK8sCesControl: {Name: "official/k8s-ces-control", Version: "1.26.3-2"}}, nil)
+
+		// when
+		missingDogus, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: officialRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Dogu{
+			{Name: officialPostgres, Version: &version2_0_0_1},
+			{Name: officialK8sCesControl, Version: &version1_26_3_2},
+		}, missingDogus)
+	})
+
+	t.Run("should search missing dependency in official namespace if it is not in the namespace of the dependent", func(t *testing.T) {
+		// given
+		premiumRedmine := cescommons.QualifiedName{Namespace: premiumNamespace, SimpleName: "redmine"}
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{{Name: premiumRedmine, Version: version1_0_0_1}}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{premiumRedmine: redmineSpec}, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, premiumPostgres).Return(nil, &NotFoundError{Message: "not found"})
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return([]core.Version{version1_0_0_1}, nil)
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{{Name: officialPostgres, Version: version1_0_0_1}}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialPostgres: {Name: "official/postgres", Version: "1.0.0-1"}}, nil)
+
+		// when
+		missingDogus, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: premiumRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Dogu{{Name: officialPostgres, Version: &version1_0_0_1}}, missingDogus)
+	})
+
+	t.Run("should fall back to the official namespace with the not found error of the dogu registry", func(t *testing.T) {
+		// given
+		premiumRedmine := cescommons.QualifiedName{Namespace: premiumNamespace, SimpleName: "redmine"}
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{{Name: premiumRedmine, Version: version1_0_0_1}}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{premiumRedmine: redmineSpec}, nil)
+		// the dogu registry wraps the error of the remote registry like this if it does not know the dogu
+		registryNotFoundErr := fmt.Errorf("failed to get versions of dogu %q: %w", premiumPostgres, errors.New("404 not found"))
+		registryMock.EXPECT().GetVersionsOf(ctx, premiumPostgres).
+			Return(nil, NewNotFoundError(registryNotFoundErr, "dogu %q could not be found", premiumPostgres))
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return([]core.Version{version1_0_0_1, version2_0_0_1}, nil)
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{{Name: officialPostgres, Version: version2_0_0_1}}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialPostgres: {Name: "official/postgres", Version: "2.0.0-1"}}, nil)
+
+		// when
+		missingDogus, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: premiumRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Dogu{{Name: officialPostgres, Version: &version2_0_0_1}}, missingDogus)
+	})
+
+	t.Run("should not add dependencies which are in the blueprint, even if absent", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{redmineVersion}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialRedmine: redmineSpec}, nil)
+
+		// when
+		missingDogus, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{
+				{Name: officialRedmine, Version: &version1_0_0_1},
+				{Name: officialPostgres, Absent: true},
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, missingDogus)
+	})
+
+	t.Run("should return invalid blueprint error if no version satisfies the requirements", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{redmineVersion}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialRedmine: redmineSpec}, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return([]core.Version{version2_0_0_3}, nil)
+
+		// when
+		_, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: officialRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		var invalidError *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "cannot complete missing dogu dependencies")
+		assert.ErrorContains(t, err, "cannot resolve version of missing dependency \"official/postgres\": no available version satisfies the version requirements [\"<=2.0.0-1\"]")
+	})
+
+	t.Run("should return invalid blueprint error if dependency is not in the registry", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{redmineVersion}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialRedmine: redmineSpec}, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return(nil, &NotFoundError{Message: "not found"})
+
+		// when
+		_, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: officialRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		var invalidError *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "remote dogu registry has no versions for missing dependency \"postgres\"")
+	})
+
+	t.Run("should return internal error if versions cannot be loaded", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{redmineVersion}).
+			Return(map[cescommons.QualifiedName]*core.Dogu{officialRedmine: redmineSpec}, nil)
+		registryMock.EXPECT().GetVersionsOf(ctx, officialPostgres).Return(nil, assert.AnError)
+
+		// when
+		_, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: officialRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		assert.True(t, IsInternalError(err))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should return internal error if dogu specifications cannot be loaded", func(t *testing.T) {
+		// given
+		registryMock := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDependenciesDomainUseCase(registryMock, false, false)
+
+		registryMock.EXPECT().GetDogus(ctx, []cescommons.QualifiedVersion{redmineVersion}).Return(nil, assert.AnError)
+
+		// when
+		_, err := useCase.CompleteMissingDependencies(ctx, domain.EffectiveBlueprint{
+			Dogus: []domain.Dogu{{Name: officialRedmine, Version: &version1_0_0_1}},
+		})
+
+		// then
+		assert.True(t, IsInternalError(err))
+	})
+}
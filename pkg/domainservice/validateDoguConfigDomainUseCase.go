@@ -0,0 +1,164 @@
+package domainservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The validation types of dogu configuration fields. They behave like the validators of the cesapp-lib,
+// which are not used directly as they come with the dependencies of the etcd registry.
+const (
+	// validationTypeOneOf allows one of the values of the validation.
+	validationTypeOneOf = "ONE_OF"
+	// validationTypeBinaryMeasurement allows integers with a binary unit, e.g. 512m.
+	validationTypeBinaryMeasurement = "BINARY_MEASUREMENT"
+	// validationTypeFloatPercentageHundred allows floats between 0 and 100 with one or two decimal places, e.g. 55.5.
+	validationTypeFloatPercentageHundred = "FLOAT_PERCENTAGE_HUNDRED"
+	// validationTypeRegex allows values which match the regular expression in the first value of the validation.
+	validationTypeRegex = "REGEX"
+)
+
+var (
+	binaryMeasurementPattern      = regexp.MustCompile(`^(\d{1,19})([bkmg])$`)
+	floatPercentageHundredPattern = regexp.MustCompile(`^(\d{1,3})\.(\d{1,2})$`)
+)
+
+type ValidateDoguConfigDomainUseCase struct {
+	remoteDoguRegistry RemoteDoguRegistry
+}
+
+func NewValidateDoguConfigDomainUseCase(remoteDoguRegistry RemoteDoguRegistry) *ValidateDoguConfigDomainUseCase {
+	return &ValidateDoguConfigDomainUseCase{
+		remoteDoguRegistry,
+	}
+}
+
+// ValidateDoguConfig checks the config of all dogus against the configuration fields described in the dogu specifications.
+// Config keys must be declared in the dogu specification, values must fulfill the declared validation and
+// keys which are declared as encrypted must be sensitive in the blueprint.
+// Absent config entries are not validated, so that unknown keys can be removed.
+// Values from references and templates are not validated as they are not known yet.
+// Values are only templates if templatesEnabled is set, see domain.BlueprintConfiguration.ConfigTemplatesEnabled.
+// This functions returns no error if everything is ok or
+// a domain.InvalidBlueprintError if there is invalid dogu config
+// an InternalError if there is any other error, e.g. with the connection to the remote dogu registry
+func (useCase *ValidateDoguConfigDomainUseCase) ValidateDoguConfig(ctx context.Context, effectiveBlueprint domain.EffectiveBlueprint, templatesEnabled bool) error {
+	logger := log.FromContext(ctx).WithName("ValidateDoguConfigDomainUseCase.ValidateDoguConfig")
+	dogusWithConfig := filterDogusWithConfig(effectiveBlueprint.GetWantedDogus(), effectiveBlueprint.Config.Dogus)
+	if len(dogusWithConfig) == 0 {
+		logger.V(2).Info("skip dogu config validation as no dogus have config")
+		return nil
+	}
+	logger.V(2).Info("load dogu specifications...", "dogusWithConfig", dogusWithConfig)
+	doguSpecs, err := loadDoguSpecifications(ctx, useCase.remoteDoguRegistry, dogusWithConfig)
+	if err != nil {
+		return err
+	}
+	logger.V(2).Info("dogu specifications loaded", "specs", doguSpecs)
+
+	var errorList []error
+	for _, wantedDogu := range dogusWithConfig {
+		doguSpec, found := doguSpecs[wantedDogu.Name]
+		if !found || doguSpec == nil {
+			errorList = append(errorList, fmt.Errorf("cannot validate config of dogu %q as its dogu specification was not found", wantedDogu.Name))
+			continue
+		}
+		for _, entry := range effectiveBlueprint.Config.Dogus[wantedDogu.Name.SimpleName] {
+			errorList = append(errorList, validateDoguConfigEntry(ctx, entry, doguSpec, templatesEnabled))
+		}
+	}
+	err = errors.Join(errorList...)
+	if err != nil {
+		err = &domain.InvalidBlueprintError{
+			WrappedError: err,
+			Message:      "dogu config is invalid in effective blueprint",
+		}
+	}
+	return err
+}
+
+func filterDogusWithConfig(dogus []domain.Dogu, doguConfig domain.DoguConfig) []domain.Dogu {
+	var result []domain.Dogu
+	for _, dogu := range dogus {
+		for _, entry := range doguConfig[dogu.Name.SimpleName] {
+			if !entry.Absent {
+				result = append(result, dogu)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func validateDoguConfigEntry(ctx context.Context, entry domain.ConfigEntry, doguSpec *core.Dogu, templatesEnabled bool) error {
+	if entry.Absent {
+		return nil
+	}
+	field, found := findConfigurationField(doguSpec.Configuration, string(entry.Key))
+	if !found {
+		return fmt.Errorf("config key %q of dogu %q is not declared in the dogu specification", entry.Key, doguSpec.Name)
+	}
+	if field.Encrypted && !entry.Sensitive {
+		return fmt.Errorf("config key %q of dogu %q is encrypted in the dogu specification and needs to be sensitive", entry.Key, doguSpec.Name)
+	}
+	if entry.Value == nil || (templatesEnabled && entry.HasTemplate()) || field.Validation.Type == "" {
+		return nil
+	}
+
+	err := checkConfigValue(ctx, field.Validation, string(*entry.Value))
+	if err != nil {
+		return fmt.Errorf("value of config key %q of dogu %q is invalid: %w", entry.Key, doguSpec.Name, err)
+	}
+	return nil
+}
+
+func findConfigurationField(fields []core.ConfigurationField, key string) (core.ConfigurationField, bool) {
+	for _, field := range fields {
+		if field.Name == key {
+			return field, true
+		}
+	}
+	return core.ConfigurationField{}, false
+}
+
+func checkConfigValue(ctx context.Context, validation core.ValidationDescriptor, value string) error {
+	switch validation.Type {
+	case validationTypeOneOf:
+		if !slices.Contains(validation.Values, value) {
+			return fmt.Errorf("%q should be one of %q", value, validation.Values)
+		}
+	case validationTypeBinaryMeasurement:
+		if !binaryMeasurementPattern.MatchString(value) {
+			return fmt.Errorf("%q should be an integer with one of the binary units b, k, m or g, e.g. 512m", value)
+		}
+	case validationTypeFloatPercentageHundred:
+		percentage, err := strconv.ParseFloat(value, 64)
+		if !floatPercentageHundredPattern.MatchString(value) || err != nil || percentage > 100 {
+			return fmt.Errorf("%q should be a float between 0 and 100 with one or two decimal places, e.g. 55.5", value)
+		}
+	case validationTypeRegex:
+		if len(validation.Values) == 0 {
+			return nil
+		}
+		pattern, err := regexp.Compile(validation.Values[0])
+		if err != nil {
+			log.FromContext(ctx).Info("ignore invalid regular expression in dogu specification", "pattern", validation.Values[0])
+			return nil
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("%q does not match %q", value, validation.Values[0])
+		}
+	default:
+		// newer dogus may use validation types, which are not known yet
+		log.FromContext(ctx).Info("ignore unknown validation type in dogu specification", "type", validation.Type)
+	}
+	return nil
+}
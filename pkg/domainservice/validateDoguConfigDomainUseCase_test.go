@@ -0,0 +1,174 @@
+package domainservice
+
+import (
+	"testing"
+
+	"github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/k8s-blueprint-operator/v2/pkg/domain"
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var redmineDoguName = dogu.QualifiedName{Namespace: "official", SimpleName: "redmine"}
+
+var doguSpecRedmineWithConfig = &core.Dogu{
+	Name:    "official/redmine",
+	Version: "5.1.3-1",
+	Configuration: []core.ConfigurationField{
+		{Name: "default_data/admin_password", Encrypted: true},
+		{Name: "mail/from", Validation: core.ValidationDescriptor{Type: "REGEX", Values: []string{`^\S+@\S+$`}}},
+		{Name: "cpu_core_limit", Validation: core.ValidationDescriptor{Type: "FLOAT_PERCENTAGE_HUNDRED"}},
+		{Name: "theme", Validation: core.ValidationDescriptor{Type: "FUTURE_TYPE"}},
+	},
+}
+
+func configValue(value string) *libconfig.Value {
+	result := libconfig.Value(value)
+	return &result
+}
+
+func TestValidateDoguConfigDomainUseCase_ValidateDoguConfig(t *testing.T) {
+	redmineVersion := core.Version{Raw: "5.1.3-1", Major: 5, Minor: 1, Patch: 3, Nano: 1}
+	wantedDogus := []domain.Dogu{
+		{Name: k8sNginxStatic, Version: &version1_26_3_2},
+		{Name: redmineDoguName, Version: &redmineVersion},
+	}
+	dogusToLoad := []dogu.QualifiedVersion{
+		{Name: k8sNginxStatic, Version: version1_26_3_2},
+		{Name: redmineDoguName, Version: redmineVersion},
+	}
+	doguSpecs := map[dogu.QualifiedName]*core.Dogu{
+		k8sNginxStatic:  doguSpecK8sNginxStatic,
+		redmineDoguName: doguSpecRedmineWithConfig,
+	}
+
+	t.Run("no dogus with config", func(t *testing.T) {
+		// given
+		registry := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDoguConfigDomainUseCase(registry)
+		blueprint := domain.EffectiveBlueprint{
+			Dogus: wantedDogus,
+			Config: domain.Config{Dogus: domain.DoguConfig{
+				"nginx-static": {{Key: "unknown", Absent: true}},
+				"absent-dogu":  {{Key: "unknown", Value: configValue("value")}},
+			}},
+		}
+
+		// when
+		err := useCase.ValidateDoguConfig(ctx, blueprint, false)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		// given
+		registry := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDoguConfigDomainUseCase(registry)
+		blueprint := domain.EffectiveBlueprint{
+			Dogus: wantedDogus,
+			Config: domain.Config{Dogus: domain.DoguConfig{
+				"nginx-static": {
+					{Key: "logging/root", Value: configValue("DEBUG")},
+					{Key: "container_config/memory_limit", Value: configValue("512m")},
+					{Key: "disable_access_log", Value: configValue("true")},
+					{Key: "unknown", Absent: true},
+				},
+				"redmine": {
+					{Key: "default_data/admin_password", Sensitive: true, SecretRef: &domain.SensitiveValueRef{SecretName: "secret", SecretKey: "password"}},
+					{Key: "mail/from", Value: configValue("redmine@{{ .Global.domain }}")},
+					{Key: "cpu_core_limit", Value: configValue("55.5")},
+					{Key: "theme", Value: configValue("anything")},
+				},
+			}},
+		}
+		registry.EXPECT().GetDogus(ctx, dogusToLoad).Return(doguSpecs, nil)
+
+		// when
+		err := useCase.ValidateDoguConfig(ctx, blueprint, true)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		// given
+		registry := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDoguConfigDomainUseCase(registry)
+		blueprint := domain.EffectiveBlueprint{
+			Dogus: wantedDogus,
+			Config: domain.Config{Dogus: domain.DoguConfig{
+				"nginx-static": {
+					{Key: "logging/rot", Value: configValue("DEBUG")},
+					{Key: "logging/root", Value: configValue("TRACE")},
+					{Key: "container_config/memory_limit", Value: configValue("512mb")},
+				},
+				"redmine": {
+					{Key: "default_data/admin_password", Value: configValue("admin")},
+					{Key: "mail/from", Value: configValue("redmine")},
+					{Key: "cpu_core_limit", Value: configValue("100.01")},
+				},
+			}},
+		}
+		registry.EXPECT().GetDogus(ctx, dogusToLoad).Return(doguSpecs, nil)
+
+		// when
+		err := useCase.ValidateDoguConfig(ctx, blueprint, false)
+
+		// then
+		var invalidError *domain.InvalidBlueprintError
+		require.ErrorAs(t, err, &invalidError)
+		assert.ErrorContains(t, err, "dogu config is invalid in effective blueprint")
+		assert.ErrorContains(t, err, "config key \"logging/rot\" of dogu \"k8s/nginx-static\" is not declared in the dogu specification")
+		assert.ErrorContains(t, err, "value of config key \"logging/root\" of dogu \"k8s/nginx-static\" is invalid: \"TRACE\" should be one of [\"WARN\" \"DEBUG\" \"INFO\" \"ERROR\"]")
+		assert.ErrorContains(t, err, "value of config key \"container_config/memory_limit\" of dogu \"k8s/nginx-static\" is invalid")
+		assert.ErrorContains(t, err, "config key \"default_data/admin_password\" of dogu \"official/redmine\" is encrypted in the dogu specification and needs to be sensitive")
+		assert.ErrorContains(t, err, "value of config key \"mail/from\" of dogu \"official/redmine\" is invalid: \"redmine\" does not match")
+		assert.ErrorContains(t, err, "value of config key \"cpu_core_limit\" of dogu \"official/redmine\" is invalid")
+	})
+
+	t.Run("validate values with braces if templates are disabled", func(t *testing.T) {
+		// given
+		registry := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDoguConfigDomainUseCase(registry)
+		blueprint := domain.EffectiveBlueprint{
+			Dogus: wantedDogus,
+			Config: domain.Config{Dogus: domain.DoguConfig{
+				"nginx-static": {{Key: "logging/root", Value: configValue("{{ .Params.logLevel }}")}},
+			}},
+		}
+		registry.EXPECT().GetDogus(ctx, mock.Anything).Return(doguSpecs, nil)
+
+		// when
+		errWithoutTemplates := useCase.ValidateDoguConfig(ctx, blueprint, false)
+		errWithTemplates := useCase.ValidateDoguConfig(ctx, blueprint, true)
+
+		// then
+		assert.ErrorContains(t, errWithoutTemplates, "value of config key \"logging/root\" of dogu \"k8s/nginx-static\" is invalid")
+		assert.NoError(t, errWithTemplates)
+	})
+
+	t.Run("error loading dogu specs", func(t *testing.T) {
+		// given
+		registry := NewMockRemoteDoguRegistry(t)
+		useCase := NewValidateDoguConfigDomainUseCase(registry)
+		blueprint := domain.EffectiveBlueprint{
+			Dogus: wantedDogus,
+			Config: domain.Config{Dogus: domain.DoguConfig{
+				"nginx-static": {{Key: "logging/root", Value: configValue("DEBUG")}},
+			}},
+		}
+		registry.EXPECT().GetDogus(ctx, dogusToLoad[:1]).Return(nil, assert.AnError)
+
+		// when
+		err := useCase.ValidateDoguConfig(ctx, blueprint, false)
+
+		// then
+		var internalError *InternalError
+		require.ErrorAs(t, err, &internalError)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}